@@ -0,0 +1,316 @@
+// Package app wires repositories, usecases, handlers and middleware together
+// once, from injected dependencies, and exposes the result as an http.Handler.
+package app
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/broadcast"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	areaRepository "github.com/pubestpubest/pos-backend/feature/area/repository"
+	areaUsecase "github.com/pubestpubest/pos-backend/feature/area/usecase"
+	auditRepository "github.com/pubestpubest/pos-backend/feature/audit/repository"
+	auditUsecase "github.com/pubestpubest/pos-backend/feature/audit/usecase"
+	authRepository "github.com/pubestpubest/pos-backend/feature/auth/repository"
+	authUsecase "github.com/pubestpubest/pos-backend/feature/auth/usecase"
+	categoryRepository "github.com/pubestpubest/pos-backend/feature/category/repository"
+	categoryUsecase "github.com/pubestpubest/pos-backend/feature/category/usecase"
+	exportUsecase "github.com/pubestpubest/pos-backend/feature/export/usecase"
+	journalRepository "github.com/pubestpubest/pos-backend/feature/journal/repository"
+	journalUsecase "github.com/pubestpubest/pos-backend/feature/journal/usecase"
+	menuItemRepository "github.com/pubestpubest/pos-backend/feature/menuItem/repository"
+	menuItemUsecase "github.com/pubestpubest/pos-backend/feature/menuItem/usecase"
+	menuScheduleRepository "github.com/pubestpubest/pos-backend/feature/menuSchedule/repository"
+	menuScheduleUsecase "github.com/pubestpubest/pos-backend/feature/menuSchedule/usecase"
+	modifierRepository "github.com/pubestpubest/pos-backend/feature/modifier/repository"
+	modifierUsecase "github.com/pubestpubest/pos-backend/feature/modifier/usecase"
+	modifierGroupRepository "github.com/pubestpubest/pos-backend/feature/modifierGroup/repository"
+	modifierGroupUsecase "github.com/pubestpubest/pos-backend/feature/modifierGroup/usecase"
+	orderRepository "github.com/pubestpubest/pos-backend/feature/order/repository"
+	orderUsecase "github.com/pubestpubest/pos-backend/feature/order/usecase"
+	overrideRepository "github.com/pubestpubest/pos-backend/feature/override/repository"
+	overrideUsecase "github.com/pubestpubest/pos-backend/feature/override/usecase"
+	paymentRepository "github.com/pubestpubest/pos-backend/feature/payment/repository"
+	paymentUsecase "github.com/pubestpubest/pos-backend/feature/payment/usecase"
+	permissionRepository "github.com/pubestpubest/pos-backend/feature/permission/repository"
+	permissionUsecase "github.com/pubestpubest/pos-backend/feature/permission/usecase"
+	reportRepository "github.com/pubestpubest/pos-backend/feature/report/repository"
+	reportUsecase "github.com/pubestpubest/pos-backend/feature/report/usecase"
+	roleRepository "github.com/pubestpubest/pos-backend/feature/role/repository"
+	roleUsecase "github.com/pubestpubest/pos-backend/feature/role/usecase"
+	tableRepository "github.com/pubestpubest/pos-backend/feature/table/repository"
+	tableUsecase "github.com/pubestpubest/pos-backend/feature/table/usecase"
+	userRepository "github.com/pubestpubest/pos-backend/feature/user/repository"
+	userUsecase "github.com/pubestpubest/pos-backend/feature/user/usecase"
+	voidReasonRepository "github.com/pubestpubest/pos-backend/feature/voidReason/repository"
+	voidReasonUsecase "github.com/pubestpubest/pos-backend/feature/voidReason/usecase"
+	"github.com/pubestpubest/pos-backend/metrics"
+	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/routes"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+// Repositories is the storage the application runs on
+type Repositories struct {
+	Transactor domain.Transactor
+
+	Area          domain.AreaRepository
+	Audit         domain.AuditRepository
+	Auth          domain.AuthRepository
+	Category      domain.CategoryRepository
+	Journal       domain.JournalRepository
+	MenuItem      domain.MenuItemRepository
+	MenuSchedule  domain.MenuScheduleRepository
+	Modifier      domain.ModifierRepository
+	ModifierGroup domain.ModifierGroupRepository
+	Order         domain.OrderRepository
+	Override      domain.OverrideRepository
+	Payment       domain.PaymentRepository
+	Permission    domain.PermissionRepository
+	Report        domain.ReportRepository
+	Role          domain.RoleRepository
+	Table         domain.TableRepository
+	User          domain.UserRepository
+	VoidReason    domain.VoidReasonRepository
+}
+
+// NewMemoryRepositories returns repositories that keep everything in store
+func NewMemoryRepositories(store *memory.Store) Repositories {
+	return Repositories{
+		Transactor:    store,
+		Area:          areaRepository.NewAreaMemoryRepository(store),
+		Audit:         auditRepository.NewAuditMemoryRepository(store),
+		Auth:          authRepository.NewAuthMemoryRepository(store),
+		Category:      categoryRepository.NewCategoryMemoryRepository(store),
+		Journal:       journalRepository.NewJournalMemoryRepository(store),
+		MenuItem:      menuItemRepository.NewMenuItemMemoryRepository(store),
+		MenuSchedule:  menuScheduleRepository.NewMenuScheduleMemoryRepository(store),
+		Modifier:      modifierRepository.NewModifierMemoryRepository(store),
+		ModifierGroup: modifierGroupRepository.NewModifierGroupMemoryRepository(store),
+		Order:         orderRepository.NewOrderMemoryRepository(store),
+		Override:      overrideRepository.NewOverrideMemoryRepository(store),
+		Payment:       paymentRepository.NewPaymentMemoryRepository(store),
+		Permission:    permissionRepository.NewPermissionMemoryRepository(store),
+		Report:        reportRepository.NewReportMemoryRepository(store),
+		Role:          roleRepository.NewRoleMemoryRepository(store),
+		Table:         tableRepository.NewTableMemoryRepository(store),
+		User:          userRepository.NewUserMemoryRepository(store),
+		VoidReason:    voidReasonRepository.NewVoidReasonMemoryRepository(store),
+	}
+}
+
+// NewPostgresRepositories returns the GORM repositories backed by db
+func NewPostgresRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Transactor:    database.NewTransactor(db),
+		Area:          areaRepository.NewAreaRepository(db),
+		Audit:         auditRepository.NewAuditRepository(db),
+		Auth:          authRepository.NewAuthRepository(db),
+		Category:      categoryRepository.NewCategoryRepository(db),
+		Journal:       journalRepository.NewJournalRepository(db),
+		MenuItem:      menuItemRepository.NewMenuItemRepository(db),
+		MenuSchedule:  menuScheduleRepository.NewMenuScheduleRepository(db),
+		Modifier:      modifierRepository.NewModifierRepository(db),
+		ModifierGroup: modifierGroupRepository.NewModifierGroupRepository(db),
+		Order:         orderRepository.NewOrderRepository(db),
+		Override:      overrideRepository.NewOverrideRepository(db),
+		Payment:       paymentRepository.NewPaymentRepository(db),
+		Permission:    permissionRepository.NewPermissionRepository(db),
+		Report:        reportRepository.NewReportRepository(db),
+		Role:          roleRepository.NewRoleRepository(db),
+		Table:         tableRepository.NewTableRepository(db),
+		User:          userRepository.NewUserRepository(db),
+		VoidReason:    voidReasonRepository.NewVoidReasonRepository(db),
+	}
+}
+
+type Usecases struct {
+	Area          domain.AreaUsecase
+	Audit         domain.AuditUsecase
+	Auth          domain.AuthUsecase
+	Category      domain.CategoryUsecase
+	Export        domain.ExportUsecase
+	Journal       domain.JournalUsecase
+	MenuItem      domain.MenuItemUsecase
+	MenuSchedule  domain.MenuScheduleUsecase
+	Modifier      domain.ModifierUsecase
+	ModifierGroup domain.ModifierGroupUsecase
+	Order         domain.OrderUsecase
+	Override      domain.OverrideUsecase
+	Payment       domain.PaymentUsecase
+	Permission    domain.PermissionUsecase
+	Report        domain.ReportUsecase
+	Role          domain.RoleUsecase
+	Table         domain.TableUsecase
+	User          domain.UserUsecase
+	VoidReason    domain.VoidReasonUsecase
+}
+
+// App is the wired application. Several can live in one process, each with
+// its own configuration and storage.
+type App struct {
+	Config       *config.Config
+	Repositories Repositories
+	Usecases     Usecases
+	Metrics      *metrics.Metrics
+	// API documents every /v1 route; the router refuses to start without it
+	API *openapi.Spec
+
+	auth         *middlewares.Auth
+	handler      *gin.Engine
+	availability *broadcast.Hub[uuid.UUID]
+}
+
+// New builds every usecase, the middleware and the router once. The usecases
+// report their business events to m, which also counts the open orders.
+func New(cfg *config.Config, repos Repositories, m *metrics.Metrics) (*App, error) {
+	calendar, err := cfg.Business.Calendar()
+	if err != nil {
+		return nil, errors.Wrap(err, "[app.New]")
+	}
+
+	// Availability changes reach the watchers of this app only
+	availability := broadcast.New[uuid.UUID]()
+	audit := auditUsecase.NewAuditUsecase(repos.Audit)
+	auth := authUsecase.NewAuthUsecase(repos.Auth, repos.Transactor, audit, m, cfg.Auth)
+	override := overrideUsecase.NewOverrideUsecase(repos.Override, auth, repos.Transactor, audit)
+	order := orderUsecase.NewOrderUsecase(repos.Order, override, repos.Transactor, audit, m, calendar, availability)
+	payment := paymentUsecase.NewPaymentUsecase(repos.Payment, repos.Transactor, audit, m, calendar)
+	report := reportUsecase.NewReportUsecase(repos.Report, calendar, cfg.Sales)
+
+	a := &App{
+		Config:       cfg,
+		Repositories: repos,
+		Usecases: Usecases{
+			Area:          areaUsecase.NewAreaUsecase(repos.Area, repos.Transactor, audit),
+			Audit:         audit,
+			Auth:          auth,
+			Category:      categoryUsecase.NewCategoryUsecase(repos.Category, repos.Transactor, audit),
+			Export:        exportUsecase.NewExportUsecase(order, payment, report, calendar),
+			Journal:       journalUsecase.NewJournalUsecase(repos.Journal, repos.Transactor, audit, calendar, cfg.Sales),
+			MenuItem:      menuItemUsecase.NewMenuItemUsecase(repos.MenuItem, repos.Transactor, audit, availability, calendar),
+			MenuSchedule:  menuScheduleUsecase.NewMenuScheduleUsecase(repos.MenuSchedule, repos.Transactor, audit),
+			Modifier:      modifierUsecase.NewModifierUsecase(repos.Modifier, repos.Transactor, audit),
+			ModifierGroup: modifierGroupUsecase.NewModifierGroupUsecase(repos.ModifierGroup, repos.Transactor, audit),
+			Order:         order,
+			Override:      override,
+			Payment:       payment,
+			Permission:    permissionUsecase.NewPermissionUsecase(repos.Permission),
+			Report:        report,
+			Role:          roleUsecase.NewRoleUsecase(repos.Role),
+			Table:         tableUsecase.NewTableUsecase(repos.Table, repos.Transactor, audit),
+			User:          userUsecase.NewUserUsecase(repos.User, repos.Transactor, audit),
+			VoidReason:    voidReasonUsecase.NewVoidReasonUsecase(repos.VoidReason, repos.Transactor, audit),
+		},
+		Metrics: m,
+		API: openapi.NewSpec(openapi.Info{
+			Title:       "POS API",
+			Description: "Restaurant point of sale: tables, orders, payments, staff and their permissions.",
+			Version:     "1",
+		}),
+		auth:         middlewares.NewAuth(auth),
+		availability: availability,
+	}
+	if err := m.RegisterOpenOrders(a.Usecases.Order.CountOpenOrdersByArea); err != nil {
+		return nil, errors.Wrap(err, "[app.New]: Error registering open orders metric")
+	}
+	handler, err := a.newRouter()
+	if err != nil {
+		return nil, err
+	}
+	a.handler = handler
+
+	return a, nil
+}
+
+// NewHandler builds the application on repos and returns its HTTP handler
+func NewHandler(cfg *config.Config, repos Repositories) (http.Handler, error) {
+	a, err := New(cfg, repos, metrics.New())
+	if err != nil {
+		return nil, err
+	}
+	return a.Handler(), nil
+}
+
+func (a *App) Handler() http.Handler {
+	return a.handler
+}
+
+// CloseStreams ends the open availability streams, which would otherwise keep
+// a graceful shutdown waiting until it times out
+func (a *App) CloseStreams() {
+	a.availability.Close()
+}
+
+// Routes lists the routes the handler serves
+func (a *App) Routes() gin.RoutesInfo {
+	return a.handler.Routes()
+}
+
+func (a *App) newRouter() (*gin.Engine, error) {
+	app := gin.New()
+
+	app.Use(middlewares.RequestID())
+	app.Use(middlewares.Tracing())
+	app.Use(middlewares.AccessLog())
+	app.Use(middlewares.Recovery())
+	app.Use(middlewares.RequestMetrics(a.Metrics))
+	app.Use(middlewares.CORSMiddleware(a.Config.HTTP))
+	app.Use(middlewares.RequestTimeout(a.Config.HTTP.RequestTimeout))
+
+	app.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ok",
+		})
+	})
+
+	app.GET("/metrics", gin.WrapH(a.Metrics.Handler()))
+
+	app.NoRoute(func(c *gin.Context) {
+		utils.RenderError(c, domain.NotFoundError("Route not found"))
+	})
+	app.NoMethod(func(c *gin.Context) {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{
+			"status": "method not allowed",
+		})
+	})
+
+	v1 := openapi.NewRouter(app.Group("/v1"), a.API, a.auth)
+	routes.AuthRoutes(v1, a.Usecases.Auth)
+	routes.AuditRoutes(v1, a.Usecases.Audit)
+	routes.CategoryRoutes(v1, a.Usecases.Category)
+	routes.ExportRoutes(v1, a.Usecases.Export, a.Config.HTTP.ExportTimeout)
+	routes.AreaRoutes(v1, a.Usecases.Area)
+	routes.JournalRoutes(v1, a.Usecases.Journal)
+	routes.ModifierRoutes(v1, a.Usecases.Modifier)
+	routes.ModifierGroupRoutes(v1, a.Usecases.ModifierGroup)
+	routes.OrderRoutes(v1, a.Usecases.Order)
+	routes.OverrideRoutes(v1, a.Usecases.Override)
+	routes.PaymentRoutes(v1, a.Usecases.Payment)
+	routes.RoleRoutes(v1, a.Usecases.Role)
+	routes.PermissionRoutes(v1, a.Usecases.Permission)
+	routes.ReportRoutes(v1, a.Usecases.Report)
+	routes.UserRoutes(v1, a.Usecases.User)
+	routes.MenuItemRoutes(v1, a.Usecases.MenuItem)
+	routes.MenuScheduleRoutes(v1, a.Usecases.MenuSchedule)
+	routes.TableRoutes(v1, a.Usecases.Table)
+	routes.VoidReasonRoutes(v1, a.Usecases.VoidReason)
+
+	doc, err := a.API.Document(app.Routes())
+	if err != nil {
+		return nil, errors.Wrap(err, "[app.New]: Error documenting routes")
+	}
+	if err := v1.Serve(doc); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
@@ -0,0 +1,404 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/app"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/metrics"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/seed"
+	"github.com/pubestpubest/pos-backend/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "secret12"
+
+// newTestApp wires the application on a memory store loaded with the
+// development seed, whose users all log in with testPassword
+func newTestApp(t *testing.T) *app.App {
+	t.Helper()
+	return newTestAppWith(t, func(*app.Repositories) {})
+}
+
+// newTestAppWith is newTestApp with the repositories changed by wrap first
+func newTestAppWith(t *testing.T, wrap func(repos *app.Repositories)) *app.App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Storage = config.StorageMemory
+	calendar, err := cfg.Business.Calendar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := memory.NewStore()
+	if err := seed.Memory(context.Background(), store, config.EnvDevelopment, string(hash), calendar); err != nil {
+		t.Fatal(err)
+	}
+
+	repos := app.NewMemoryRepositories(store)
+	wrap(&repos)
+	a, err := app.New(cfg, repos, metrics.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// client calls one handler in-process, signed in once login succeeds
+type client struct {
+	t       *testing.T
+	handler http.Handler
+	token   string
+}
+
+// do sends body as JSON and decodes the response into out, failing the test
+// unless the status is want
+func (c *client) do(method string, path string, body any, want int, out any) {
+	c.t.Helper()
+
+	rec := c.send(method, path, body)
+	if rec.Code != want {
+		c.t.Fatalf("%s %s: status %d, want %d: %s", method, path, rec.Code, want, rec.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			c.t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+}
+
+// send sends body as JSON and returns the response whatever its status. It
+// does not fail the test, so it may be called from other goroutines.
+func (c *client) send(method string, path string, body any) *httptest.ResponseRecorder {
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			c.t.Error(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &reader)
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (c *client) login(username string) {
+	c.t.Helper()
+
+	var auth struct {
+		Token string `json:"token"`
+	}
+	c.do(http.MethodPost, "/v1/auth/login", map[string]string{"username": username, "password": testPassword}, http.StatusOK, &auth)
+	c.token = auth.Token
+}
+
+type idPage struct {
+	Items []struct {
+		ID string `json:"id"`
+	} `json:"items"`
+}
+
+type order struct {
+	ID        string  `json:"id"`
+	Status    *string `json:"status"`
+	TotalBaht *int64  `json:"total_baht"`
+	Items     []struct {
+		ID       string `json:"id"`
+		Quantity int    `json:"quantity"`
+	} `json:"items"`
+}
+
+// openOrder signs in as the owner and opens an order on the first table
+func openOrder(t *testing.T, c *client) order {
+	t.Helper()
+
+	c.login("owner")
+	var me struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	c.do(http.MethodGet, "/v1/auth/me", nil, http.StatusOK, &me)
+	var tables idPage
+	c.do(http.MethodGet, "/v1/tables", nil, http.StatusOK, &tables)
+	if len(tables.Items) == 0 {
+		t.Fatal("no seeded tables")
+	}
+
+	var opened order
+	c.do(http.MethodPost, "/v1/orders", map[string]any{
+		"table_id":  tables.Items[0].ID,
+		"opened_by": me.User.ID,
+		"source":    constant.OrderSourceStaff,
+	}, http.StatusCreated, &opened)
+	return opened
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	a := newTestApp(t)
+	c := &client{t: t, handler: a.Handler()}
+
+	opened := openOrder(t, c)
+	var menu idPage
+	c.do(http.MethodGet, "/v1/menu-items", nil, http.StatusOK, &menu)
+	if len(menu.Items) == 0 {
+		t.Fatal("no seeded menu items")
+	}
+
+	var added order
+	c.do(http.MethodPost, "/v1/orders/"+opened.ID+"/items", map[string]any{
+		"menu_item_id": menu.Items[0].ID,
+		"quantity":     2,
+	}, http.StatusOK, &added)
+	if len(added.Items) != 1 || added.Items[0].Quantity != 2 {
+		t.Fatalf("items = %+v, want one line of 2", added.Items)
+	}
+	if added.TotalBaht == nil || *added.TotalBaht <= 0 {
+		t.Fatalf("total = %v, want the price of the item", added.TotalBaht)
+	}
+
+	c.do(http.MethodPost, "/v1/payments", map[string]any{
+		"order_id":    opened.ID,
+		"method":      constant.PaymentMethodCash,
+		"amount_baht": *added.TotalBaht,
+	}, http.StatusCreated, nil)
+
+	var closed order
+	c.do(http.MethodPut, "/v1/orders/"+opened.ID+"/close", nil, http.StatusOK, &closed)
+	if closed.Status == nil || *closed.Status != constant.OrderStatusPaid {
+		t.Fatalf("status = %v, want %s", closed.Status, constant.OrderStatusPaid)
+	}
+
+	// Closed orders take no more items
+	c.do(http.MethodPost, "/v1/orders/"+opened.ID+"/items", map[string]any{
+		"menu_item_id": menu.Items[0].ID,
+		"quantity":     1,
+	}, http.StatusUnprocessableEntity, nil)
+}
+
+// slowItemReads widens the window between reading an order item and writing
+// it, as a busy database would
+type slowItemReads struct {
+	domain.OrderRepository
+}
+
+func (r slowItemReads) GetOrderItemByID(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
+	orderItem, err := r.OrderRepository.GetOrderItemByID(ctx, id)
+	time.Sleep(20 * time.Millisecond)
+	return orderItem, err
+}
+
+func (r slowItemReads) GetOrderItemForUpdate(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
+	orderItem, err := r.OrderRepository.GetOrderItemForUpdate(ctx, id)
+	time.Sleep(20 * time.Millisecond)
+	return orderItem, err
+}
+
+func TestConcurrentCancelsReturnPortionsOnce(t *testing.T) {
+	a := newTestAppWith(t, func(repos *app.Repositories) {
+		repos.Order = slowItemReads{repos.Order}
+	})
+	c := &client{t: t, handler: a.Handler()}
+
+	opened := openOrder(t, c)
+	var menu idPage
+	c.do(http.MethodGet, "/v1/menu-items", nil, http.StatusOK, &menu)
+	if len(menu.Items) == 0 {
+		t.Fatal("no seeded menu items")
+	}
+	menuItemID := menu.Items[0].ID
+	c.do(http.MethodPut, "/v1/menu-items/"+menuItemID+"/availability", map[string]any{
+		"status":             constant.MenuItemAvailable,
+		"remaining_portions": 10,
+	}, http.StatusOK, nil)
+
+	var added order
+	c.do(http.MethodPost, "/v1/orders/"+opened.ID+"/items", map[string]any{
+		"menu_item_id": menuItemID,
+		"quantity":     3,
+	}, http.StatusOK, &added)
+	if len(added.Items) != 1 {
+		t.Fatalf("items = %+v, want one line", added.Items)
+	}
+
+	const cancels = 8
+	statuses := make(chan int, cancels)
+	var wg sync.WaitGroup
+	for range cancels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := c.send(http.MethodPut, "/v1/orders/"+opened.ID+"/items/"+added.Items[0].ID+"/cancel", map[string]string{"reason_code": "entered_in_error"})
+			statuses <- rec.Code
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	cancelled := 0
+	for status := range statuses {
+		switch status {
+		case http.StatusOK:
+			cancelled++
+		case http.StatusUnprocessableEntity:
+		default:
+			t.Errorf("cancel: status %d, want %d or %d", status, http.StatusOK, http.StatusUnprocessableEntity)
+		}
+	}
+	if cancelled != 1 {
+		t.Errorf("%d cancels succeeded, want 1", cancelled)
+	}
+
+	var availability struct {
+		Items []struct {
+			MenuItemID        string `json:"menu_item_id"`
+			RemainingPortions *int   `json:"remaining_portions"`
+		} `json:"items"`
+	}
+	c.do(http.MethodGet, "/v1/menu-items/availability", nil, http.StatusOK, &availability)
+	for _, a := range availability.Items {
+		if a.MenuItemID == menuItemID && utils.DerefInt(a.RemainingPortions) != 10 {
+			t.Errorf("remaining portions = %d, want 10", utils.DerefInt(a.RemainingPortions))
+		}
+	}
+}
+
+func TestAreaScheduleClosedWithoutArea(t *testing.T) {
+	c := &client{t: t, handler: newTestApp(t).Handler()}
+	c.login("owner")
+
+	var tables struct {
+		Items []struct {
+			ID     string `json:"id"`
+			AreaID string `json:"area_id"`
+		} `json:"items"`
+	}
+	c.do(http.MethodGet, "/v1/tables", nil, http.StatusOK, &tables)
+	var areas idPage
+	c.do(http.MethodGet, "/v1/areas", nil, http.StatusOK, &areas)
+	var menu idPage
+	c.do(http.MethodGet, "/v1/menu-items", nil, http.StatusOK, &menu)
+	if len(tables.Items) == 0 || len(areas.Items) < 2 || len(menu.Items) == 0 {
+		t.Fatal("not enough seeded tables, areas and menu items")
+	}
+
+	// Limit a menu item to an area other than the first table's, then take
+	// that table out of its area
+	table := tables.Items[0]
+	var other string
+	for _, area := range areas.Items {
+		if area.ID != table.AreaID {
+			other = area.ID
+			break
+		}
+	}
+	menuItemID := menu.Items[0].ID
+	c.do(http.MethodPost, "/v1/menu-schedules", map[string]any{
+		"name":         "Terrace only",
+		"menu_item_id": menuItemID,
+		"area_id":      other,
+		"days":         []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"},
+	}, http.StatusCreated, nil)
+	c.do(http.MethodDelete, "/v1/areas/"+table.AreaID, nil, http.StatusOK, nil)
+
+	var offered idPage
+	c.do(http.MethodGet, "/v1/menu-items/menu?table_id="+table.ID, nil, http.StatusOK, &offered)
+	for _, item := range offered.Items {
+		if item.ID == menuItemID {
+			t.Error("menu at a table outside any area lists an item limited to an area")
+		}
+	}
+
+	opened := openOrder(t, c)
+	c.do(http.MethodPost, "/v1/orders/"+opened.ID+"/items", map[string]any{
+		"menu_item_id": menuItemID,
+		"quantity":     1,
+	}, http.StatusUnprocessableEntity, nil)
+}
+
+func TestSharedModifierCountsTowardsEitherGroup(t *testing.T) {
+	c := &client{t: t, handler: newTestApp(t).Handler()}
+	opened := openOrder(t, c)
+
+	var menuItem, rice, egg struct {
+		ID string `json:"id"`
+	}
+	c.do(http.MethodPost, "/v1/menu-items", map[string]any{"name": "Basil stir-fry", "sku": "BASIL-TEST", "price_baht": 60}, http.StatusCreated, &menuItem)
+	c.do(http.MethodPost, "/v1/modifiers", map[string]any{"name": "Extra rice", "price_delta_baht": 10}, http.StatusCreated, &rice)
+	c.do(http.MethodPost, "/v1/modifiers", map[string]any{"name": "Fried egg", "price_delta_baht": 15}, http.StatusCreated, &egg)
+
+	// The egg is an optional extra first, and also the only choice of a
+	// required group sorted after it
+	c.do(http.MethodPost, "/v1/modifier-groups", map[string]any{
+		"name":          "Extras",
+		"sort_order":    0,
+		"options":       []map[string]any{{"modifier_id": rice.ID}, {"modifier_id": egg.ID}},
+		"menu_item_ids": []string{menuItem.ID},
+	}, http.StatusCreated, nil)
+	c.do(http.MethodPost, "/v1/modifier-groups", map[string]any{
+		"name":           "Topping",
+		"min_selections": 1,
+		"max_selections": 1,
+		"sort_order":     1,
+		"options":        []map[string]any{{"modifier_id": egg.ID}},
+		"menu_item_ids":  []string{menuItem.ID},
+	}, http.StatusCreated, nil)
+
+	var added order
+	c.do(http.MethodPost, "/v1/orders/"+opened.ID+"/items", map[string]any{
+		"menu_item_id": menuItem.ID,
+		"quantity":     1,
+		"modifier_ids": []string{egg.ID, rice.ID},
+	}, http.StatusOK, &added)
+	if added.TotalBaht == nil || *added.TotalBaht != 85 {
+		t.Errorf("total = %v, want 85", added.TotalBaht)
+	}
+
+	// Without the egg the topping is still missing
+	c.do(http.MethodPost, "/v1/orders/"+opened.ID+"/items", map[string]any{
+		"menu_item_id": menuItem.ID,
+		"quantity":     1,
+		"modifier_ids": []string{rice.ID},
+	}, http.StatusBadRequest, nil)
+}
+
+func TestAppsAreIndependent(t *testing.T) {
+	first := &client{t: t, handler: newTestApp(t).Handler()}
+	second := &client{t: t, handler: newTestApp(t).Handler()}
+
+	opened := openOrder(t, first)
+
+	second.login("owner")
+	second.do(http.MethodGet, "/v1/orders/"+opened.ID, nil, http.StatusNotFound, nil)
+	first.do(http.MethodGet, "/v1/orders/"+opened.ID, nil, http.StatusOK, nil)
+}
+
+func TestRequiresSession(t *testing.T) {
+	c := &client{t: t, handler: newTestApp(t).Handler()}
+
+	c.do(http.MethodGet, "/v1/orders", nil, http.StatusUnauthorized, nil)
+	c.token = "not-a-token"
+	c.do(http.MethodGet, "/v1/orders", nil, http.StatusUnauthorized, nil)
+}
@@ -0,0 +1,14 @@
+package app_test
+
+import "testing"
+
+// TestOpenAPIMatchesHandlers fails go test when a handler and its documented
+// operation disagree, as `openapi check` does
+func TestOpenAPIMatchesHandlers(t *testing.T) {
+	a := newTestApp(t)
+
+	// Tests run in the package directory; the handler source is the whole module
+	if err := a.API.Verify(a.Routes(), ".."); err != nil {
+		t.Fatal(err)
+	}
+}
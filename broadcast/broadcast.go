@@ -0,0 +1,72 @@
+// Package broadcast fans values out to every subscriber in the process. It
+// backs the live feeds of the API, such as menu item availability; a
+// deployment of several instances only reaches the clients of the instance
+// that published.
+package broadcast
+
+import "sync"
+
+// buffer is how many values a subscriber may fall behind by
+const buffer = 64
+
+// Hub delivers each published value to every current subscriber. A subscriber
+// that falls more than buffer values behind is dropped, its channel closed,
+// rather than holding up the publisher; it is expected to subscribe again and
+// catch up from the current state.
+type Hub[T any] struct {
+	mu          sync.Mutex
+	subscribers map[chan T]struct{}
+	closed      bool
+}
+
+func New[T any]() *Hub[T] {
+	return &Hub[T]{subscribers: make(map[chan T]struct{})}
+}
+
+// Publish sends v to every subscriber without blocking
+func (h *Hub[T]) Publish(v T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- v:
+		default:
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns a channel of the values published from now on and a
+// function that ends the subscription, closing the channel
+func (h *Hub[T]) Subscribe() (<-chan T, func()) {
+	ch := make(chan T, buffer)
+	h.mu.Lock()
+	if h.closed {
+		close(ch)
+	} else {
+		h.subscribers[ch] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close ends every subscription, closing their channels. Later subscriptions
+// get a channel that is already closed.
+func (h *Hub[T]) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for ch := range h.subscribers {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,60 @@
+// Package businessday dates sales by the restaurant's business day rather than
+// the calendar day. A business day starts at the cutoff time of day in the
+// restaurant's time zone, so a shift that runs past midnight stays on the day
+// it began.
+package businessday
+
+import "time"
+
+// Layout is how business dates are written in requests and responses
+const Layout = "2006-01-02"
+
+// Calendar maps instants to business dates
+type Calendar struct {
+	location *time.Location
+	cutoff   time.Duration
+}
+
+// New returns the calendar of a restaurant in location whose business day
+// turns over cutoff after midnight
+func New(location *time.Location, cutoff time.Duration) Calendar {
+	return Calendar{location: location, cutoff: cutoff}
+}
+
+// Location is the restaurant's time zone
+func (c Calendar) Location() *time.Location {
+	return c.location
+}
+
+// Cutoff is how long after midnight the business day turns over
+func (c Calendar) Cutoff() time.Duration {
+	return c.cutoff
+}
+
+// Date returns the business date t falls on, as midnight UTC so it is stored
+// and compared the same way whatever the time zone
+func (c Calendar) Date(t time.Time) time.Time {
+	local := t.In(c.location).Add(-c.cutoff)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Today returns the current business date
+func (c Calendar) Today() time.Time {
+	return c.Date(time.Now())
+}
+
+// Format writes a stored business date for a response; rows that predate
+// business dates have none
+func Format(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formatted := date.Format(Layout)
+	return &formatted
+}
+
+// Start returns the instant the business date opens, in UTC like the stored
+// timestamps; the day ends where the next one starts
+func (c Calendar) Start(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, c.location).Add(c.cutoff).UTC()
+}
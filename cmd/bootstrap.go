@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"flag"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/app"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/logging"
+	"github.com/pubestpubest/pos-backend/metrics"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// parseConfig adds the configuration flags to flags, parses args and loads
+// the configuration, then applies its logging and gin settings
+func parseConfig(flags *flag.FlagSet, args []string) (*config.Config, error) {
+	loader := config.NewLoader(flags)
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	level, _ := log.ParseLevel(cfg.LogLevel)
+	logging.Configure(cfg.LogFormat, level)
+
+	if cfg.IsDevelopment() {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	log.WithFields(cfg.Fields()).Debug("[parseConfig]: Loaded configuration")
+	log.Info("[parseConfig]: Run environment: ", cfg.Env)
+	return cfg, nil
+}
+
+// connect opens the database described by cfg
+func connect(cfg *config.Config) (*gorm.DB, error) {
+	if cfg.InMemory() {
+		return nil, errors.New("[connect]: This command needs a database and cannot run with --storage=memory")
+	}
+	db, err := database.ConnectDB(cfg.Database)
+	if err != nil {
+		return nil, errors.Wrap(err, "[connect]: Connect database PG error")
+	}
+	return db, nil
+}
+
+// newApp wires the application on the Postgres repositories backed by db
+func newApp(cfg *config.Config, db *gorm.DB) (*app.App, error) {
+	return app.New(cfg, app.NewPostgresRepositories(db), metrics.New())
+}
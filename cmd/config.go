@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
+)
+
+func runConfig(ctx context.Context, args []string) error {
+	verb, args, err := subcommand("config", args, "check")
+	if err != nil {
+		return err
+	}
+	cfg, err := parseConfig(newFlagSet("config "+verb, ""), args)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE")
+	for _, setting := range cfg.Settings() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", setting.Key, setting.Value, setting.Source)
+	}
+	tw.Flush()
+	fmt.Println()
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errors.Wrap(err, "[config check]: Error getting database handle")
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return errors.Wrap(err, "[config check]: Database is unreachable")
+	}
+	fmt.Println("database: reachable")
+
+	migrator, err := database.NewMigrator(db, cfg)
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+	fmt.Printf("migrations: %d known, %d pending\n", len(statuses), pending)
+
+	fmt.Println("config: ok")
+	return nil
+}
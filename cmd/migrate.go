@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pubestpubest/pos-backend/database"
+)
+
+func runMigrate(ctx context.Context, args []string) error {
+	verb, args, err := subcommand("migrate", args, "up", "down", "status")
+	if err != nil {
+		return err
+	}
+
+	usage := ""
+	if verb == "down" {
+		usage = "[--steps 1]"
+	}
+	flags := newFlagSet("migrate "+verb, usage)
+	steps := 1
+	if verb == "down" {
+		flags.IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	}
+	cfg, err := parseConfig(flags, args)
+	if err != nil {
+		return err
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	migrator, err := database.NewMigrator(db, cfg)
+	if err != nil {
+		return err
+	}
+
+	switch verb {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, migration := range rolledBack {
+			fmt.Printf("rolled back %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("nothing to roll back")
+		}
+	case "status":
+		return printMigrationStatus(ctx, migrator)
+	}
+	return nil
+}
+
+func printMigrationStatus(ctx context.Context, migrator *database.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT\tNOTE")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		note := ""
+		switch {
+		case status.Missing:
+			note = "no migration file in this build"
+		case status.Modified:
+			note = "file changed after it was applied"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", status.Version, status.Name, appliedAt, note)
+	}
+	tw.Flush()
+
+	drift, err := migrator.Drift(ctx)
+	if err != nil {
+		return err
+	}
+	if len(drift) > 0 {
+		fmt.Println()
+		fmt.Println("Schema drift:")
+		for _, difference := range drift {
+			fmt.Println("  " + difference)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/app"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/metrics"
+)
+
+// runOpenAPI prints the OpenAPI document, or checks it against the handler
+// source so a build fails when the two disagree. Neither needs a database or
+// any configuration: the application is wired on an empty memory store.
+func runOpenAPI(ctx context.Context, args []string) error {
+	verb, args, err := subcommand("openapi", args, "print", "check")
+	if err != nil {
+		return err
+	}
+	flags := newFlagSet("openapi "+verb, "[--source .]")
+	source := flags.String("source", ".", "module root holding the handler source, for check")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	cfg := config.Default()
+	cfg.Storage = config.StorageMemory
+	// Building the application fails when a /v1 route is not documented
+	a, err := app.New(cfg, app.NewMemoryRepositories(memory.NewStore()), metrics.New())
+	if err != nil {
+		return err
+	}
+	doc, err := a.API.Document(a.Routes())
+	if err != nil {
+		return err
+	}
+
+	if verb == "print" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return errors.Wrap(encoder.Encode(doc), "[openapi print]: Error writing document")
+	}
+
+	if err := a.API.Verify(a.Routes(), *source); err != nil {
+		return err
+	}
+	operations := 0
+	for _, item := range doc.Paths {
+		operations += len(item)
+	}
+	fmt.Printf("openapi: %d operations match their handlers\n", operations)
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package cmd implements the subcommands of the server binary.
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+)
+
+// errUsage marks a bad invocation; the usage has already been printed
+var errUsage = errors.New("usage error")
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "serve", args: "[--addr :8080] [--migrate=true]", summary: "Run the HTTP server", run: runServe},
+		{name: "migrate", args: "up | down [--steps 1] | status", summary: "Apply, roll back or list schema migrations", run: runMigrate},
+		{name: "seed", args: "[--env development]", summary: "Insert roles, permissions, default users and (in development) sample data", run: runSeed},
+		{name: "user", args: "create | reset-password | grant-role", summary: "Manage staff accounts", run: runUser},
+		{name: "sessions", args: "purge", summary: "Delete expired login sessions", run: runSessions},
+		{name: "config", args: "check", summary: "Validate configuration and database connectivity", run: runConfig},
+		{name: "openapi", args: "print | check [--source .]", summary: "Print the OpenAPI document or check it against the handlers", run: runOpenAPI},
+	}
+}
+
+// Execute runs the subcommand named by args[0], or serve when there is none,
+// and returns the process exit code
+func Execute(args []string) int {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != name {
+			continue
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := cmd.run(ctx, args); err != nil {
+			if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+				return 2
+			}
+			printError(err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	printUsage(os.Stderr)
+	return 2
+}
+
+func printError(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		return
+	}
+	fields := make([]string, 0, len(domainErr.Fields))
+	for field := range domainErr.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", field, domainErr.Fields[field])
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: pos-backend <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, cmd := range commands() {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	tw.Flush()
+}
+
+// newFlagSet returns a flag set that reports errors instead of exiting
+func newFlagSet(name string, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: pos-backend %s %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// subcommand splits "<verb> [flags]" and prints usage when the verb is missing or unknown
+func subcommand(name string, args []string, verbs ...string) (string, []string, error) {
+	if len(args) > 0 {
+		for _, verb := range verbs {
+			if args[0] == verb {
+				return verb, args[1:], nil
+			}
+		}
+		fmt.Fprintf(os.Stderr, "unknown %s command %q\n", name, args[0])
+	}
+	fmt.Fprintf(os.Stderr, "Usage: pos-backend %s <command>\n\nCommands:\n", name)
+	for _, verb := range verbs {
+		fmt.Fprintf(os.Stderr, "  %s\n", verb)
+	}
+	return "", nil, errUsage
+}
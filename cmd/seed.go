@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pubestpubest/pos-backend/seed"
+)
+
+func runSeed(ctx context.Context, args []string) error {
+	cfg, err := parseConfig(newFlagSet("seed", "[--env development]"), args)
+	if err != nil {
+		return err
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	calendar, err := cfg.Business.Calendar()
+	if err != nil {
+		return err
+	}
+
+	seedRunner := seed.Runner{
+		DB:       db,
+		Env:      cfg.Env,
+		Calendar: calendar,
+	}
+	if err := seedRunner.Run(ctx); err != nil {
+		return err
+	}
+
+	fmt.Printf("seeded %s data\n", cfg.Env)
+	return nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/app"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/metrics"
+	"github.com/pubestpubest/pos-backend/seed"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tracingFlushTimeout bounds how long exiting waits for buffered spans to be sent
+const tracingFlushTimeout = 5 * time.Second
+
+func runServe(ctx context.Context, args []string) error {
+	flags := newFlagSet("serve", "[--addr :8080] [--migrate=true] [--storage memory [--seed-password PASS]]")
+	migrate := flags.Bool("migrate", true, "apply pending migrations before serving")
+	seedPassword := flags.String("seed-password", "", "password of every seeded user with --storage=memory; generated when omitted")
+	cfg, err := parseConfig(flags, args)
+	if err != nil {
+		return err
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// ctx is already cancelled here, and the spans of the last requests still need sending
+		flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Error("[serve]: ", err)
+		}
+	}()
+
+	var a *app.App
+	cleanup := func() error { return nil }
+	if cfg.InMemory() {
+		a, err = newMemoryApp(ctx, cfg, *seedPassword)
+		if err != nil {
+			return err
+		}
+	} else {
+		db, err := connect(cfg)
+		if err != nil {
+			return err
+		}
+		if *migrate {
+			if err := database.MigrateDB(ctx, db, cfg); err != nil {
+				return err
+			}
+		}
+		a, err = newApp(cfg, db)
+		if err != nil {
+			return err
+		}
+		if err := a.Metrics.InstrumentDB(db); err != nil {
+			return err
+		}
+		if err := tracing.InstrumentDB(db); err != nil {
+			return err
+		}
+		cleanup = func() error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		}
+	}
+
+	server := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           a.Handler(),
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+	server.RegisterOnShutdown(a.CloseStreams)
+	return runServer(ctx, server, cfg.HTTP.ShutdownTimeout, cleanup)
+}
+
+// newMemoryApp wires the application on an in-memory store loaded with the
+// seed data for cfg.Env. Everything is lost when the process exits.
+func newMemoryApp(ctx context.Context, cfg *config.Config, password string) (*app.App, error) {
+	if password == "" {
+		generated, err := utils.GenerateToken(6)
+		if err != nil {
+			return nil, errors.Wrap(err, "[serve]: Error generating seed password")
+		}
+		password = generated
+		// Printed rather than logged, so it is not kept by log collectors
+		fmt.Fprintln(os.Stderr, "Seeded users log in with password", password)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.Wrap(err, "[serve]: Error hashing seed password")
+	}
+
+	calendar, err := cfg.Business.Calendar()
+	if err != nil {
+		return nil, err
+	}
+	store := memory.NewStore()
+	if err := seed.Memory(ctx, store, cfg.Env, string(hash), calendar); err != nil {
+		return nil, err
+	}
+	log.Warn("[serve]: Using memory storage; all data is lost on exit")
+
+	return app.New(cfg, app.NewMemoryRepositories(store), metrics.New())
+}
+
+// runServer serves until ctx is cancelled, then stops accepting connections
+// and gives in-flight requests up to timeout to finish before cleanup runs.
+// Request contexts are not derived from ctx, so a payment that is already
+// being processed is not cancelled by the signal.
+func runServer(ctx context.Context, server *http.Server, timeout time.Duration, cleanup func() error) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info("[serve]: Listening on ", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return errors.Wrap(err, "[serve]: Server stopped")
+	case <-ctx.Done():
+	}
+
+	log.Info("[serve]: Shutting down, waiting up to ", timeout, " for in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	shutdownErr := server.Shutdown(shutdownCtx)
+	if shutdownErr != nil {
+		// Requests still running past the deadline are cut off
+		log.Error("[serve]: Graceful shutdown incomplete: ", shutdownErr)
+		server.Close()
+	}
+	if err := cleanup(); err != nil {
+		log.Error("[serve]: Error closing database: ", err)
+	}
+	if shutdownErr != nil {
+		return errors.Wrap(shutdownErr, "[serve]: Error shutting down")
+	}
+
+	log.Info("[serve]: Stopped")
+	return nil
+}
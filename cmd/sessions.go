@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+)
+
+func runSessions(ctx context.Context, args []string) error {
+	verb, args, err := subcommand("sessions", args, "purge")
+	if err != nil {
+		return err
+	}
+	cfg, err := parseConfig(newFlagSet("sessions "+verb, ""), args)
+	if err != nil {
+		return err
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	a, err := newApp(cfg, db)
+	if err != nil {
+		return err
+	}
+	purged, err := a.Usecases.Auth.PurgeExpiredSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("purged %d expired sessions\n", purged)
+	return nil
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/app"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+func runUser(ctx context.Context, args []string) error {
+	verb, args, err := subcommand("user", args, "create", "reset-password", "grant-role")
+	if err != nil {
+		return err
+	}
+
+	switch verb {
+	case "create":
+		return runUserCreate(ctx, args)
+	case "reset-password":
+		return runUserResetPassword(ctx, args)
+	default:
+		return runUserGrantRole(ctx, args)
+	}
+}
+
+func runUserCreate(ctx context.Context, args []string) error {
+	flags := newFlagSet("user create", "--username NAME [--password PASS] [--full-name NAME] [--email EMAIL] [--phone PHONE] [--role ROLE]")
+	username := flags.String("username", "", "login name (required)")
+	password := flags.String("password", "", "password; read from stdin when omitted")
+	fullName := flags.String("full-name", "", "display name")
+	email := flags.String("email", "", "email address")
+	phone := flags.String("phone", "", "phone number")
+	role := flags.String("role", "", "role to grant, e.g. cashier or manager")
+	cfg, err := parseConfig(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := readPassword(password); err != nil {
+		return err
+	}
+
+	req := &request.UserCreateRequest{
+		Username: *username,
+		Password: *password,
+		FullName: optional(*fullName),
+		Email:    optional(*email),
+		Phone:    optional(*phone),
+		Status:   utils.Ptr(constant.UserStatusActive),
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return utils.BindingError(err, "Invalid user")
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	a, err := newApp(cfg, db)
+	if err != nil {
+		return err
+	}
+
+	// Create the user and grant the role together so a bad role name leaves nothing behind
+	var created string
+	err = a.Repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := a.Usecases.User.CreateUser(ctx, req)
+		if err != nil {
+			return err
+		}
+		created = user.ID.String()
+		if *role == "" {
+			return nil
+		}
+		return grantRole(ctx, a, user.Username, *role)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created user %s (%s)\n", *username, created)
+	return nil
+}
+
+func runUserResetPassword(ctx context.Context, args []string) error {
+	flags := newFlagSet("user reset-password", "--username NAME [--password PASS]")
+	username := flags.String("username", "", "login name (required)")
+	password := flags.String("password", "", "new password; read from stdin when omitted")
+	cfg, err := parseConfig(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := readPassword(password); err != nil {
+		return err
+	}
+
+	req := &request.ResetPasswordRequest{
+		Username:    *username,
+		NewPassword: *password,
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return utils.BindingError(err, "Invalid password reset")
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	a, err := newApp(cfg, db)
+	if err != nil {
+		return err
+	}
+	if err := a.Usecases.Auth.ResetPassword(ctx, req); err != nil {
+		return err
+	}
+
+	fmt.Printf("reset password for %s and signed out all of their sessions\n", *username)
+	return nil
+}
+
+func runUserGrantRole(ctx context.Context, args []string) error {
+	flags := newFlagSet("user grant-role", "--username NAME --role ROLE")
+	username := flags.String("username", "", "login name (required)")
+	role := flags.String("role", "", "role name (required)")
+	cfg, err := parseConfig(flags, args)
+	if err != nil {
+		return err
+	}
+	if *username == "" || *role == "" {
+		flags.Usage()
+		return errUsage
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	a, err := newApp(cfg, db)
+	if err != nil {
+		return err
+	}
+	if err := grantRole(ctx, a, *username, *role); err != nil {
+		return err
+	}
+
+	fmt.Printf("granted %s to %s\n", *role, *username)
+	return nil
+}
+
+// grantRole resolves the user and role by name and assigns the role
+func grantRole(ctx context.Context, a *app.App, username string, roleName string) error {
+	user, err := a.Repositories.Auth.GetUserByUsername(ctx, username)
+	if err != nil {
+		return errors.Wrap(err, "[grantRole]: Error getting user")
+	}
+
+	roles, err := a.Repositories.Role.GetAllRoles(ctx)
+	if err != nil {
+		return errors.Wrap(err, "[grantRole]: Error getting roles")
+	}
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role.Name == roleName {
+			return a.Usecases.User.AssignRoleToUser(ctx, user.ID, role.ID)
+		}
+		names = append(names, role.Name)
+	}
+
+	return domain.NotFoundError(fmt.Sprintf("Role %q not found; available roles: %s", roleName, strings.Join(names, ", ")))
+}
+
+// readPassword fills an empty password from the first line of stdin so it
+// does not have to appear in the shell history
+func readPassword(password *string) error {
+	if *password != "" {
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return errors.Wrap(err, "[readPassword]: Error reading password")
+	}
+	*password = strings.TrimRight(line, "\r\n")
+	return nil
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
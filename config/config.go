@@ -0,0 +1,227 @@
+// Package config loads the typed application configuration from defaults, a
+// dotenv file, the environment and command line flags, in that order of
+// precedence.
+package config
+
+import (
+	"fmt"
+	"time"
+	// TIMEZONE must load on hosts and images without a zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/logging"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+const (
+	// StorageDatabase keeps data in the database described by DatabaseConfig
+	StorageDatabase = "database"
+	// StorageMemory keeps data in process memory, seeded at startup and lost on exit
+	StorageMemory = "memory"
+)
+
+const (
+	// DriverPostgres connects to a PostgreSQL server
+	DriverPostgres = "postgres"
+	// DriverSQLite keeps the database in a single file, for one-terminal shops
+	// that cannot run a server
+	DriverSQLite = "sqlite"
+)
+
+const (
+	// TracingExporterNone records no spans
+	TracingExporterNone = "none"
+	// TracingExporterStdout prints finished spans, for local debugging
+	TracingExporterStdout = "stdout"
+	// TracingExporterFile appends finished spans to TracingConfig.File as JSON
+	TracingExporterFile = "file"
+	// TracingExporterOTLP sends spans to a collector over OTLP/HTTP
+	TracingExporterOTLP = "otlp"
+)
+
+type Config struct {
+	Env      string
+	LogLevel string
+	// LogFormat is logging.FormatText or logging.FormatJSON
+	LogFormat string
+	Storage   string
+	HTTP      HTTPConfig
+	Database  DatabaseConfig
+	Auth      AuthConfig
+	Business  BusinessConfig
+	Sales     SalesConfig
+	Tracing   TracingConfig
+
+	// sources records where each setting came from, keyed by environment name
+	sources map[string]string
+}
+
+type HTTPConfig struct {
+	Addr              string
+	CORSOrigins       []string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// RequestTimeout is the deadline put on each request's context, so slow
+	// queries are cancelled before WriteTimeout cuts the connection
+	RequestTimeout time.Duration
+	// ExportTimeout replaces RequestTimeout and WriteTimeout for exports,
+	// which stream for as long as they have rows; 0 means no limit
+	ExportTimeout time.Duration
+	// ShutdownTimeout is how long in-flight requests get to finish on SIGTERM
+	ShutdownTimeout time.Duration
+}
+
+type DatabaseConfig struct {
+	Driver string
+	// Host, Port, Username, Password and SSLMode are only used by Postgres
+	Host     string
+	Port     int
+	Username string
+	Password Secret
+	// Name is the database name, or the file path for SQLite
+	Name            string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	// LegacyTimeZone is the zone the server wrote timestamps in before they
+	// were stored in UTC. The migration that converts them reads it; empty
+	// means the business time zone.
+	LegacyTimeZone string
+}
+
+type AuthConfig struct {
+	SessionTTL time.Duration
+}
+
+type BusinessConfig struct {
+	// TimeZone is the restaurant's IANA time zone. Business dates, report
+	// hours and menu schedules are in it; timestamps are stored in UTC.
+	TimeZone string
+	// DayCutoff is the time of day the business day turns over. Sales before
+	// it count towards the previous day.
+	DayCutoff time.Duration
+}
+
+// Calendar returns the business day calendar the settings describe
+func (c BusinessConfig) Calendar() (businessday.Calendar, error) {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return businessday.Calendar{}, errors.Wrapf(err, "[config.Calendar]: Error loading time zone %q", c.TimeZone)
+	}
+	return businessday.New(location, c.DayCutoff), nil
+}
+
+type SalesConfig struct {
+	// VATPercent is the VAT included in menu prices; reports take it back out
+	// of what was charged
+	VATPercent float64
+}
+
+type TracingConfig struct {
+	Exporter    string
+	ServiceName string
+	// File is only used by the file exporter
+	File string
+	// OTLPEndpoint is the collector's OTLP/HTTP URL, e.g. http://localhost:4318;
+	// an http:// URL is sent without TLS
+	OTLPEndpoint string
+	// SampleRatio is the share of new traces recorded. Requests that continue a
+	// trace follow the caller's decision.
+	SampleRatio float64
+}
+
+// Default returns the configuration used for every setting that no source overrides
+func Default() *Config {
+	return &Config{
+		Env:       EnvDevelopment,
+		LogLevel:  "info",
+		LogFormat: logging.FormatText,
+		Storage:   StorageDatabase,
+		HTTP: HTTPConfig{
+			Addr:              ":8080",
+			CORSOrigins:       []string{"*"},
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			RequestTimeout:    25 * time.Second,
+			ExportTimeout:     10 * time.Minute,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			Port:            5432,
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Auth: AuthConfig{
+			SessionTTL: 24 * time.Hour,
+		},
+		Business: BusinessConfig{
+			TimeZone:  "Asia/Bangkok",
+			DayCutoff: 4 * time.Hour,
+		},
+		Sales: SalesConfig{
+			VATPercent: 7,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingExporterNone,
+			ServiceName: "pos-backend",
+			SampleRatio: 1,
+		},
+	}
+}
+
+func (c *Config) IsDevelopment() bool {
+	return c.Env == EnvDevelopment
+}
+
+func (c *Config) InMemory() bool {
+	return c.Storage == StorageMemory
+}
+
+// DSN returns the Postgres connection string
+func (c DatabaseConfig) DSN() string {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
+		c.Host, c.Port, c.Username, c.Password.Reveal(), c.Name)
+	if c.SSLMode != "" {
+		dsn += " sslmode=" + c.SSLMode
+	}
+	return dsn
+}
+
+// Secret is a string that never prints its value, so a config can be logged safely
+type Secret string
+
+const redacted = "******"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Reveal returns the actual value; only use it where the secret is consumed
+func (s Secret) Reveal() string {
+	return string(s)
+}
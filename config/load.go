@@ -0,0 +1,292 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+)
+
+// DefaultPath is the dotenv file read when --config is not given; it is optional
+const DefaultPath = "configs/.env"
+
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// setting binds one field of Config to its environment name and flag
+type setting struct {
+	key   string
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+	get   func(c *Config) string
+}
+
+var settings = []setting{
+	{key: "RUN_ENV", flag: "env", usage: "run environment: development, staging or production",
+		set: func(c *Config, v string) error { c.Env = v; return nil },
+		get: func(c *Config) string { return c.Env }},
+	{key: "LOG_LEVEL", flag: "log-level", usage: "minimum log level",
+		set: func(c *Config, v string) error { c.LogLevel = v; return nil },
+		get: func(c *Config) string { return c.LogLevel }},
+	{key: "LOG_FORMAT", flag: "log-format", usage: "log output: text, or json for log collectors",
+		set: func(c *Config, v string) error { c.LogFormat = v; return nil },
+		get: func(c *Config) string { return c.LogFormat }},
+	{key: "STORAGE", flag: "storage", usage: "where data is kept: database, or memory for demos and training",
+		set: func(c *Config, v string) error { c.Storage = v; return nil },
+		get: func(c *Config) string { return c.Storage }},
+	{key: "HTTP_ADDR", flag: "addr", usage: "address the HTTP server listens on",
+		set: func(c *Config, v string) error { c.HTTP.Addr = v; return nil },
+		get: func(c *Config) string { return c.HTTP.Addr }},
+	{key: "CORS_ORIGINS", flag: "cors-origins", usage: "comma separated origins allowed by CORS, or *",
+		set: func(c *Config, v string) error { c.HTTP.CORSOrigins = splitList(v); return nil },
+		get: func(c *Config) string { return strings.Join(c.HTTP.CORSOrigins, ",") }},
+	{key: "HTTP_READ_TIMEOUT", flag: "read-timeout", usage: "maximum time to read a request including its body",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.HTTP.ReadTimeout) },
+		get: func(c *Config) string { return c.HTTP.ReadTimeout.String() }},
+	{key: "HTTP_READ_HEADER_TIMEOUT", flag: "read-header-timeout", usage: "maximum time to read request headers",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.HTTP.ReadHeaderTimeout) },
+		get: func(c *Config) string { return c.HTTP.ReadHeaderTimeout.String() }},
+	{key: "HTTP_WRITE_TIMEOUT", flag: "write-timeout", usage: "maximum time to write a response",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.HTTP.WriteTimeout) },
+		get: func(c *Config) string { return c.HTTP.WriteTimeout.String() }},
+	{key: "HTTP_IDLE_TIMEOUT", flag: "idle-timeout", usage: "how long an idle keep-alive connection stays open",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.HTTP.IdleTimeout) },
+		get: func(c *Config) string { return c.HTTP.IdleTimeout.String() }},
+	{key: "HTTP_REQUEST_TIMEOUT", flag: "request-timeout", usage: "deadline for handling one request, 0 for none",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.HTTP.RequestTimeout) },
+		get: func(c *Config) string { return c.HTTP.RequestTimeout.String() }},
+	{key: "HTTP_EXPORT_TIMEOUT", flag: "export-timeout", usage: "how long an export may stream, 0 for no limit",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.HTTP.ExportTimeout) },
+		get: func(c *Config) string { return c.HTTP.ExportTimeout.String() }},
+	{key: "HTTP_SHUTDOWN_TIMEOUT", flag: "shutdown-timeout", usage: "how long in-flight requests may run after SIGTERM",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.HTTP.ShutdownTimeout) },
+		get: func(c *Config) string { return c.HTTP.ShutdownTimeout.String() }},
+	{key: "DATABASE_DRIVER", flag: "db-driver", usage: "database driver: postgres, or sqlite for a single terminal",
+		set: func(c *Config, v string) error { c.Database.Driver = v; return nil },
+		get: func(c *Config) string { return c.Database.Driver }},
+	{key: "DATABASE_HOST", flag: "db-host", usage: "database host",
+		set: func(c *Config, v string) error { c.Database.Host = v; return nil },
+		get: func(c *Config) string { return c.Database.Host }},
+	{key: "DATABASE_PORT", flag: "db-port", usage: "database port",
+		set: func(c *Config, v string) error { return parseInt(v, &c.Database.Port) },
+		get: func(c *Config) string { return strconv.Itoa(c.Database.Port) }},
+	{key: "DATABASE_USERNAME", flag: "db-user", usage: "database user",
+		set: func(c *Config, v string) error { c.Database.Username = v; return nil },
+		get: func(c *Config) string { return c.Database.Username }},
+	{key: "DATABASE_PASSWORD", flag: "db-password", usage: "database password",
+		set: func(c *Config, v string) error { c.Database.Password = Secret(v); return nil },
+		get: func(c *Config) string { return c.Database.Password.String() }},
+	{key: "DATABASE_NAME", flag: "db-name", usage: "database name, or the file path for sqlite",
+		set: func(c *Config, v string) error { c.Database.Name = v; return nil },
+		get: func(c *Config) string { return c.Database.Name }},
+	{key: "DATABASE_SSLMODE", flag: "db-sslmode", usage: "Postgres sslmode, e.g. disable or require",
+		set: func(c *Config, v string) error { c.Database.SSLMode = v; return nil },
+		get: func(c *Config) string { return c.Database.SSLMode }},
+	{key: "DATABASE_MAX_OPEN_CONNS", flag: "db-max-open-conns", usage: "maximum open database connections, 0 for unlimited",
+		set: func(c *Config, v string) error { return parseInt(v, &c.Database.MaxOpenConns) },
+		get: func(c *Config) string { return strconv.Itoa(c.Database.MaxOpenConns) }},
+	{key: "DATABASE_MAX_IDLE_CONNS", flag: "db-max-idle-conns", usage: "maximum idle database connections",
+		set: func(c *Config, v string) error { return parseInt(v, &c.Database.MaxIdleConns) },
+		get: func(c *Config) string { return strconv.Itoa(c.Database.MaxIdleConns) }},
+	{key: "DATABASE_CONN_MAX_LIFETIME", flag: "db-conn-max-lifetime", usage: "how long a database connection may be reused, e.g. 30m",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.Database.ConnMaxLifetime) },
+		get: func(c *Config) string { return c.Database.ConnMaxLifetime.String() }},
+	{key: "DATABASE_LEGACY_TIMEZONE", flag: "db-legacy-timezone", usage: "time zone the server ran in before timestamps were stored in UTC, if not TIMEZONE",
+		set: func(c *Config, v string) error { c.Database.LegacyTimeZone = v; return nil },
+		get: func(c *Config) string { return c.Database.LegacyTimeZone }},
+	{key: "SESSION_TTL", flag: "session-ttl", usage: "how long a login session lasts, e.g. 12h",
+		set: func(c *Config, v string) error { return parseDuration(v, &c.Auth.SessionTTL) },
+		get: func(c *Config) string { return c.Auth.SessionTTL.String() }},
+	{key: "TIMEZONE", flag: "timezone", usage: "restaurant time zone business dates are kept in, e.g. Asia/Bangkok",
+		set: func(c *Config, v string) error { c.Business.TimeZone = v; return nil },
+		get: func(c *Config) string { return c.Business.TimeZone }},
+	{key: "BUSINESS_DAY_CUTOFF", flag: "business-day-cutoff", usage: "time of day the business day turns over, e.g. 04:00",
+		set: func(c *Config, v string) error { return parseTimeOfDay(v, &c.Business.DayCutoff) },
+		get: func(c *Config) string { return formatTimeOfDay(c.Business.DayCutoff) }},
+	{key: "VAT_PERCENT", flag: "vat-percent", usage: "VAT included in menu prices, in percent",
+		set: func(c *Config, v string) error { return parseFloat(v, &c.Sales.VATPercent) },
+		get: func(c *Config) string { return strconv.FormatFloat(c.Sales.VATPercent, 'g', -1, 64) }},
+	{key: "TRACING_EXPORTER", flag: "tracing-exporter", usage: "where spans go: none, stdout, file or otlp",
+		set: func(c *Config, v string) error { c.Tracing.Exporter = v; return nil },
+		get: func(c *Config) string { return c.Tracing.Exporter }},
+	{key: "TRACING_SERVICE_NAME", flag: "tracing-service-name", usage: "service name spans are reported under",
+		set: func(c *Config, v string) error { c.Tracing.ServiceName = v; return nil },
+		get: func(c *Config) string { return c.Tracing.ServiceName }},
+	{key: "TRACING_FILE", flag: "tracing-file", usage: "file the file exporter appends spans to",
+		set: func(c *Config, v string) error { c.Tracing.File = v; return nil },
+		get: func(c *Config) string { return c.Tracing.File }},
+	{key: "TRACING_OTLP_ENDPOINT", flag: "tracing-otlp-endpoint", usage: "collector OTLP/HTTP URL, e.g. http://localhost:4318",
+		set: func(c *Config, v string) error { c.Tracing.OTLPEndpoint = v; return nil },
+		get: func(c *Config) string { return c.Tracing.OTLPEndpoint }},
+	{key: "TRACING_SAMPLE_RATIO", flag: "tracing-sample-ratio", usage: "share of new traces recorded, from 0 to 1",
+		set: func(c *Config, v string) error { return parseFloat(v, &c.Tracing.SampleRatio) },
+		get: func(c *Config) string { return strconv.FormatFloat(c.Tracing.SampleRatio, 'g', -1, 64) }},
+}
+
+// Setting is one resolved configuration value; secrets are already redacted
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// Loader registers the configuration flags on a command's flag set and
+// resolves the configuration once the flags are parsed
+type Loader struct {
+	flags  *flag.FlagSet
+	path   *string
+	values map[string]*string
+}
+
+// NewLoader adds --config and one flag per setting to flags; flags may be nil
+// to load from the file and environment only
+func NewLoader(flags *flag.FlagSet) *Loader {
+	loader := &Loader{flags: flags, values: make(map[string]*string)}
+	if flags == nil {
+		path := DefaultPath
+		loader.path = &path
+		return loader
+	}
+
+	loader.path = flags.String("config", DefaultPath, "dotenv file to read settings from")
+	for _, s := range settings {
+		loader.values[s.key] = flags.String(s.flag, "", s.usage+" (env "+s.key+")")
+	}
+	return loader
+}
+
+// Load resolves every setting, later sources overriding earlier ones:
+// defaults, the dotenv file, the environment, then flags. It reports every
+// invalid setting at once.
+func (l *Loader) Load() (*Config, error) {
+	visited := make(map[string]bool)
+	if l.flags != nil {
+		l.flags.Visit(func(f *flag.Flag) {
+			visited[f.Name] = true
+		})
+	}
+
+	file, err := godotenv.Read(*l.path)
+	if err != nil {
+		// The default file is optional; one named explicitly is not
+		if !errors.Is(err, os.ErrNotExist) || visited["config"] {
+			return nil, errors.Wrapf(err, "[config.Load]: Error reading %s", *l.path)
+		}
+		file = map[string]string{}
+	}
+
+	cfg := Default()
+	cfg.sources = make(map[string]string, len(settings))
+
+	var problems []string
+	for _, s := range settings {
+		value, source := "", SourceDefault
+		if v, ok := file[s.key]; ok && v != "" {
+			value, source = v, SourceFile
+		}
+		if v := os.Getenv(s.key); v != "" {
+			value, source = v, SourceEnv
+		}
+		if visited[s.flag] {
+			value, source = *l.values[s.key], SourceFlag
+		}
+
+		cfg.sources[s.key] = source
+		if source == SourceDefault {
+			continue
+		}
+		if err := s.set(cfg, strings.TrimSpace(value)); err != nil {
+			problems = append(problems, s.key+": "+err.Error())
+		}
+	}
+
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, errors.Errorf("[config.Load]: Invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return cfg, nil
+}
+
+// Settings lists every resolved setting with where it came from, with secrets redacted
+func (c *Config) Settings() []Setting {
+	resolved := make([]Setting, 0, len(settings))
+	for _, s := range settings {
+		source := c.sources[s.key]
+		if source == "" {
+			source = SourceDefault
+		}
+		resolved = append(resolved, Setting{Key: s.key, Value: s.get(c), Source: source})
+	}
+	return resolved
+}
+
+// Fields returns the redacted settings keyed by name, for structured logging
+func (c *Config) Fields() map[string]any {
+	fields := make(map[string]any, len(settings))
+	for _, s := range c.Settings() {
+		fields[s.Key] = s.Value
+	}
+	return fields
+}
+
+func parseInt(value string, target *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.Errorf("must be a whole number, got %q", value)
+	}
+	*target = n
+	return nil
+}
+
+func parseFloat(value string, target *float64) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return errors.Errorf("must be a number, got %q", value)
+	}
+	*target = f
+	return nil
+}
+
+func parseDuration(value string, target *time.Duration) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return errors.Errorf("must be a duration such as 30s, 15m or 24h, got %q", value)
+	}
+	*target = d
+	return nil
+}
+
+// parseTimeOfDay reads a 24-hour HH:MM clock time as the duration since midnight
+func parseTimeOfDay(value string, target *time.Duration) error {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return errors.Errorf("must be a time of day such as 04:00, got %q", value)
+	}
+	*target = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return nil
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pubestpubest/pos-backend/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+var validSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// validate returns one message per invalid setting, named by its environment key
+func (c *Config) validate() []string {
+	var problems []string
+	problem := func(key string, format string, args ...any) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch c.Env {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		problem("RUN_ENV", "must be %s, %s or %s, got %q", EnvDevelopment, EnvStaging, EnvProduction, c.Env)
+	}
+	if _, err := log.ParseLevel(c.LogLevel); err != nil {
+		problem("LOG_LEVEL", "must be one of debug, info, warn or error, got %q", c.LogLevel)
+	}
+	switch c.LogFormat {
+	case logging.FormatText, logging.FormatJSON:
+	default:
+		problem("LOG_FORMAT", "must be %s or %s, got %q", logging.FormatText, logging.FormatJSON, c.LogFormat)
+	}
+
+	switch c.Storage {
+	case StorageDatabase, StorageMemory:
+	default:
+		problem("STORAGE", "must be %s or %s, got %q", StorageDatabase, StorageMemory, c.Storage)
+	}
+
+	if _, port, err := net.SplitHostPort(c.HTTP.Addr); err != nil {
+		problem("HTTP_ADDR", "must be host:port or :port, got %q", c.HTTP.Addr)
+	} else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		problem("HTTP_ADDR", "has an invalid port %q", port)
+	}
+	if len(c.HTTP.CORSOrigins) == 0 {
+		problem("CORS_ORIGINS", "must list at least one origin, or *")
+	}
+	for _, origin := range c.HTTP.CORSOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			problem("CORS_ORIGINS", "origin %q must start with http:// or https://", origin)
+		}
+	}
+
+	timeouts := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.HTTP.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", c.HTTP.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.HTTP.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.HTTP.IdleTimeout},
+		{"HTTP_REQUEST_TIMEOUT", c.HTTP.RequestTimeout},
+		{"HTTP_EXPORT_TIMEOUT", c.HTTP.ExportTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			problem(timeout.key, "must not be negative")
+		}
+	}
+	if c.HTTP.WriteTimeout > 0 && c.HTTP.RequestTimeout > c.HTTP.WriteTimeout {
+		problem("HTTP_REQUEST_TIMEOUT", "must not exceed HTTP_WRITE_TIMEOUT (%s), or the connection closes before the error is sent", c.HTTP.WriteTimeout)
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		problem("HTTP_SHUTDOWN_TIMEOUT", "must be positive")
+	}
+
+	switch c.Database.Driver {
+	case DriverPostgres, DriverSQLite:
+	default:
+		problem("DATABASE_DRIVER", "must be %s or %s, got %q", DriverPostgres, DriverSQLite, c.Database.Driver)
+	}
+	// The connection settings are only needed when there is a database to connect
+	// to, and a SQLite file has no server or user
+	if c.Storage != StorageMemory {
+		if c.Database.Driver == DriverPostgres {
+			if c.Database.Host == "" {
+				problem("DATABASE_HOST", "is required")
+			}
+			if c.Database.Username == "" {
+				problem("DATABASE_USERNAME", "is required")
+			}
+		}
+		if c.Database.Name == "" {
+			problem("DATABASE_NAME", "is required")
+		}
+	}
+	if c.Database.Port < 1 || c.Database.Port > 65535 {
+		problem("DATABASE_PORT", "must be between 1 and 65535, got %d", c.Database.Port)
+	}
+	if c.Database.SSLMode != "" && !contains(validSSLModes, c.Database.SSLMode) {
+		problem("DATABASE_SSLMODE", "must be one of %s, got %q", strings.Join(validSSLModes, ", "), c.Database.SSLMode)
+	}
+	if c.Database.MaxOpenConns < 0 {
+		problem("DATABASE_MAX_OPEN_CONNS", "must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		problem("DATABASE_MAX_IDLE_CONNS", "must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problem("DATABASE_MAX_IDLE_CONNS", "must not exceed DATABASE_MAX_OPEN_CONNS (%d)", c.Database.MaxOpenConns)
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		problem("DATABASE_CONN_MAX_LIFETIME", "must not be negative")
+	}
+	if _, err := time.LoadLocation(c.Database.LegacyTimeZone); err != nil {
+		problem("DATABASE_LEGACY_TIMEZONE", "must be an IANA time zone such as Asia/Bangkok, got %q", c.Database.LegacyTimeZone)
+	}
+
+	if c.Auth.SessionTTL < time.Minute {
+		problem("SESSION_TTL", "must be at least 1m, got %s", c.Auth.SessionTTL)
+	}
+
+	if _, err := time.LoadLocation(c.Business.TimeZone); err != nil || c.Business.TimeZone == "" {
+		problem("TIMEZONE", "must be an IANA time zone such as Asia/Bangkok, got %q", c.Business.TimeZone)
+	}
+	if c.Business.DayCutoff < 0 || c.Business.DayCutoff >= 24*time.Hour {
+		problem("BUSINESS_DAY_CUTOFF", "must be a time of day from 00:00 to 23:59")
+	}
+	if c.Sales.VATPercent < 0 || c.Sales.VATPercent >= 100 {
+		problem("VAT_PERCENT", "must be at least 0 and below 100, got %g", c.Sales.VATPercent)
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterFile:
+		if c.Tracing.File == "" {
+			problem("TRACING_FILE", "is required with the %s exporter", TracingExporterFile)
+		}
+	case TracingExporterOTLP:
+		if u, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problem("TRACING_OTLP_ENDPOINT", "must be an http:// or https:// URL with the %s exporter, got %q", TracingExporterOTLP, c.Tracing.OTLPEndpoint)
+		}
+	default:
+		problem("TRACING_EXPORTER", "must be %s, %s, %s or %s, got %q", TracingExporterNone, TracingExporterStdout, TracingExporterFile, TracingExporterOTLP, c.Tracing.Exporter)
+	}
+	if c.Tracing.ServiceName == "" {
+		problem("TRACING_SERVICE_NAME", "must not be empty")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problem("TRACING_SAMPLE_RATIO", "must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	return problems
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package constant
+
+const (
+	AuditActionCreate          = "create"
+	AuditActionUpdate          = "update"
+	AuditActionDelete          = "delete"
+	AuditActionAddItem         = "add_item"
+	AuditActionCancelItem      = "cancel_item"
+	AuditActionUpdateQuantity  = "update_quantity"
+	AuditActionSend            = "send"
+	AuditActionDiscount        = "discount"
+	AuditActionClose           = "close"
+	AuditActionReopen          = "reopen"
+	AuditActionVoid            = "void"
+	AuditActionPay             = "pay"
+	AuditActionUpdateStatus    = "update_status"
+	AuditActionAssignRole      = "assign_role"
+	AuditActionChangePassword  = "change_password"
+	AuditActionResetPassword   = "reset_password"
+	AuditActionSetPin          = "set_pin"
+	AuditActionLock            = "lock"
+	AuditActionUnlock          = "unlock"
+	AuditActionIssueOverride   = "issue_override"
+	AuditActionExport          = "export"
+	AuditActionSetAvailability = "set_availability"
+)
+
+const (
+	AuditEntityArea          = "area"
+	AuditEntityCategory      = "category"
+	AuditEntityJournal       = "journal"
+	AuditEntityMenuItem      = "menu_item"
+	AuditEntityMenuSchedule  = "menu_schedule"
+	AuditEntityModifier      = "modifier"
+	AuditEntityModifierGroup = "modifier_group"
+	AuditEntityOrder         = "order"
+	AuditEntityOverride      = "override"
+	AuditEntityPayment       = "payment"
+	AuditEntityTable         = "table"
+	AuditEntityUser          = "user"
+	AuditEntityVoidReason    = "void_reason"
+)
+
+const (
+	AuditPermission = "audit.view"
+
+	AuditLogDefaultLimit = 100
+)
+
+// RequestIDHeader is stored with audit entries to tie them to a request
+const RequestIDHeader = "X-Request-ID"
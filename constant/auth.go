@@ -0,0 +1,36 @@
+package constant
+
+import "time"
+
+const (
+	LoginResultSuccess  = "success"
+	LoginResultFailure  = "failure"
+	LoginResultLocked   = "locked"
+	LoginResultBlocked  = "blocked"
+	LoginResultUnlocked = "unlocked"
+)
+
+// Reasons a request with a session token is rejected; rejected logins use their
+// login result instead
+const (
+	AuthFailureSessionInvalid = "session_invalid"
+	AuthFailureSessionExpired = "session_expired"
+	AuthFailureSessionLocked  = "session_locked"
+)
+
+const (
+	// Failed attempts are only counted inside this window
+	LoginFailureWindow = 15 * time.Minute
+
+	// Consecutive failures for one username before the account is locked
+	LoginMaxFailedAttempts = 5
+
+	// Failures allowed before exponential backoff starts, per username and per client IP
+	LoginBackoffThreshold   = 3
+	LoginIPBackoffThreshold = 10
+
+	LoginBackoffBase = time.Second
+	LoginBackoffMax  = 5 * time.Minute
+
+	LoginHistoryDefaultLimit = 50
+)
@@ -0,0 +1,38 @@
+package constant
+
+const (
+	// Permission needed to map accounts and export the accounting journal
+	JournalPermission = "journal.export"
+)
+
+// What a ledger account is mapped for. The payment purposes are the payment
+// methods themselves.
+const (
+	LedgerPurposeSales         = "sales"
+	LedgerPurposeVATOutput     = "vat_output"
+	LedgerPurposeServiceCharge = "service_charge"
+	LedgerPurposeDiscounts     = "discounts"
+	LedgerPurposeCash          = PaymentMethodCash
+	LedgerPurposeCard          = PaymentMethodCard
+	LedgerPurposePromptpay     = PaymentMethodPromptpay
+	LedgerPurposeRefunds       = "refunds"
+	// Payments taken over or short of what the day's orders charged
+	LedgerPurposeOverShort = "over_short"
+)
+
+// What a row of a business day's journal totals sums
+const (
+	JournalTotalOpen     = "open"
+	JournalTotalSubtotal = "subtotal"
+	JournalTotalTotal    = "total"
+	JournalTotalDiscount = "discount"
+	JournalTotalCategory = "category"
+	JournalTotalPayment  = "payment"
+	JournalTotalRefund   = "refund"
+)
+
+// Formats a journal entry can be downloaded in
+const (
+	JournalFormatJSON = "json"
+	JournalFormatCSV  = "csv"
+)
@@ -0,0 +1,25 @@
+package constant
+
+import "time"
+
+const (
+	// Permission needed to change the menu and when it is offered
+	MenuManagePermission = "menu.manage"
+	// Permission needed to mark menu items sold out or low on stock
+	MenuAvailabilityPermission = "menu.availability"
+)
+
+// How much of a menu item is left. An item whose counted portions have run
+// out is sold out whatever it was marked.
+const (
+	MenuItemAvailable = "available"
+	MenuItemLowStock  = "low_stock"
+	MenuItemSoldOut   = "sold_out"
+)
+
+// MenuAvailabilityEvent names the server-sent events of the availability stream
+const MenuAvailabilityEvent = "availability"
+
+// MenuAvailabilityKeepAlive is how often the availability stream sends a
+// comment while nothing changes, so proxies do not close it as idle
+const MenuAvailabilityKeepAlive = 15 * time.Second
@@ -10,3 +10,20 @@ const (
 	OrderSourceStaff    = "staff"
 	OrderSourceCustomer = "customer"
 )
+
+// Area reported for open orders whose table has no area
+const OrderAreaNone = "none"
+
+// What a void applies to
+const (
+	VoidKindOrder = "order"
+	VoidKindItem  = "item"
+)
+
+const (
+	// Permission needed to see the void report
+	VoidReportPermission = ReportPermission
+)
+
+// What an order list can expand on each summary
+const OrderExpandItems = "items"
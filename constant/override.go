@@ -0,0 +1,20 @@
+package constant
+
+import "time"
+
+const (
+	OverrideActionVoidOrder  = "order.void"
+	OverrideActionRemoveItem = "order.item_remove"
+	OverrideActionDiscount   = "order.discount"
+	OverrideActionReopen     = "order.reopen"
+)
+
+const (
+	// Permission the approving user must hold
+	OverridePermission = "order.override"
+
+	// Header the requesting terminal sends the one-time token in
+	OverrideTokenHeader = "X-Override-Token"
+
+	OverrideTokenTTL = 5 * time.Minute
+)
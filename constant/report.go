@@ -0,0 +1,20 @@
+package constant
+
+const (
+	// Permission needed to see any report
+	ReportPermission = "report.view"
+)
+
+// What a sales report groups paid orders by
+const (
+	SalesByDay           = "day"
+	SalesByHour          = "hour"
+	SalesByCategory      = "category"
+	SalesByMenuItem      = "menu_item"
+	SalesByModifier      = "modifier"
+	SalesByPaymentMethod = "payment_method"
+	SalesByArea          = "area"
+	SalesByTable         = "table"
+	SalesByStaff         = "staff"
+	SalesBySource        = "source"
+)
@@ -3,3 +3,10 @@ package constant
 const (
 	UserNotFound = "user not found"
 )
+
+const (
+	UserStatusActive = "active"
+	UserStatusLocked = "locked"
+)
+
+const UserManagePermission = "user.manage"
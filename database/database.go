@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/logging"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ConnectDB opens the database selected by cfg.Driver. Both drivers share the
+// GORM repositories, so anything driver specific is handled here.
+func ConnectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case config.DriverSQLite:
+		dialector = openSQLite(cfg.Name)
+	case config.DriverPostgres:
+		dialector = PostgresDialector(cfg.DSN())
+	default:
+		return nil, errors.Errorf("[ConnectDB]: Unknown database driver %q", cfg.Driver)
+	}
+
+	db, err := Open(dialector)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	log.Info("[database]: Connected to ", cfg.Driver, " database")
+
+	return db, nil
+}
+
+// Open opens dialector with the settings the repositories rely on. ConnectDB
+// uses it, and tests that bring their own connection string call it directly.
+func Open(dialector gorm.Dialector) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logging.GormLogger{},
+		// Timestamps are stored in UTC, so the ones GORM sets are read back unchanged
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		// Report constraint violations as gorm.ErrDuplicatedKey and gorm.ErrForeignKeyViolated
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if dialector.Name() == config.DriverSQLite {
+		if err := registerSQLiteDefaults(db); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// MigrateDB applies pending migrations and warns about any drift between the
+// models and the resulting schema
+func MigrateDB(ctx context.Context, db *gorm.DB, cfg *config.Config) error {
+	migrator, err := NewMigrator(db, cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return err
+	}
+	log.Info("[database]: Migrated database")
+
+	drift, err := migrator.Drift(ctx)
+	if err != nil {
+		return err
+	}
+	for _, difference := range drift {
+		log.Warn("[database]: Schema drift: ", difference)
+	}
+
+	return nil
+}
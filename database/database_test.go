@@ -0,0 +1,261 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/database/dbtest"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+func TestMigrationsMatchModels(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		migrator, err := database.NewMigrator(db, config.Default())
+		if err != nil {
+			t.Fatal(err)
+		}
+		drift, err := migrator.Drift(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, difference := range drift {
+			t.Error(difference)
+		}
+	})
+}
+
+func TestMigrationsRollBack(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		migrator, err := database.NewMigrator(db, config.Default())
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		down, err := migrator.Down(ctx, len(statuses))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(down) != len(statuses) {
+			t.Fatalf("rolled back %d migrations, want %d", len(down), len(statuses))
+		}
+		up, err := migrator.Up(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(up) != len(statuses) {
+			t.Fatalf("applied %d migrations, want %d", len(up), len(statuses))
+		}
+	})
+}
+
+func TestBusinessDateMigration(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		cfg := config.Default()
+		cfg.Business.TimeZone = "Asia/Bangkok"
+		cfg.Business.DayCutoff = 4 * time.Hour
+		migrator, err := database.NewMigrator(db, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Back to before 0003_business_dates
+		if _, err := migrator.Down(ctx, len(statuses)-2); err != nil {
+			t.Fatal(err)
+		}
+
+		// 01:30 on 20 March in Bangkok, written the way the server did before
+		// timestamps were stored in UTC
+		legacy := "2026-03-20 01:30:00"
+		if db.Dialector.Name() == config.DriverSQLite {
+			legacy += "+07:00"
+		}
+		id := uuid.New()
+		if err := db.Exec("INSERT INTO orders (id, status, created_at) VALUES (?, 'open', ?)", id, legacy).Error; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		var order models.Order
+		if err := db.First(&order, "id = ?", id).Error; err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2026, 3, 19, 18, 30, 0, 0, time.UTC); !order.CreatedAt.Equal(want) {
+			t.Errorf("created_at = %v, want %v", order.CreatedAt, want)
+		}
+		if order.BusinessDate == nil || order.BusinessDate.Format(time.DateOnly) != "2026-03-19" {
+			t.Errorf("business_date = %v, want 2026-03-19", order.BusinessDate)
+		}
+	})
+}
+
+func TestModifierGroupsMigration(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		migrator, err := database.NewMigrator(db, config.Default())
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Back to before 0007_modifier_groups
+		if _, err := migrator.Down(ctx, len(statuses)-6); err != nil {
+			t.Fatal(err)
+		}
+
+		menuItemID, modifierID := uuid.New(), uuid.New()
+		if err := db.Exec("INSERT INTO menu_items (id, name) VALUES (?, 'ข้าวผัด')", menuItemID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Exec("INSERT INTO modifiers (id, name) VALUES (?, 'ไข่ดาว')", modifierID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		// The item still takes the modifiers it could take before
+		var groups []models.ModifierGroup
+		if err := db.Preload("Options").Preload("MenuItems").Find(&groups).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(groups) != 1 {
+			t.Fatalf("%d modifier groups, want 1", len(groups))
+		}
+		group := groups[0]
+		if group.MinSelections != 0 || group.MaxSelections != nil {
+			t.Errorf("selections = %d to %v, want optional without a limit", group.MinSelections, group.MaxSelections)
+		}
+		if len(group.Options) != 1 || group.Options[0].ModifierID != modifierID {
+			t.Errorf("options = %+v, want the existing modifier", group.Options)
+		}
+		if len(group.MenuItems) != 1 || group.MenuItems[0].MenuItemID != menuItemID {
+			t.Errorf("menu items = %+v, want the existing item", group.MenuItems)
+		}
+	})
+}
+
+func TestGeneratedDefaults(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		// exported_at is only filled by its now() default, unlike created_at,
+		// which GORM sets itself
+		before := time.Now().Add(-time.Minute)
+		export := &models.JournalExport{BusinessDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+		if err := db.Create(export).Error; err != nil {
+			t.Fatal(err)
+		}
+		if export.ID == uuid.Nil {
+			t.Error("id was not generated")
+		}
+
+		var stored models.JournalExport
+		if err := db.First(&stored, "id = ?", export.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.ExportedAt.Before(before) {
+			t.Errorf("exported_at = %v, want the time of the insert", stored.ExportedAt)
+		}
+	})
+}
+
+func TestConstraintErrors(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		if err := db.Create(&models.User{Username: "cashier", PasswordHash: "hash"}).Error; err != nil {
+			t.Fatal(err)
+		}
+		err := db.Create(&models.User{Username: "cashier", PasswordHash: "hash"}).Error
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Errorf("duplicate username: %v, want %v", err, gorm.ErrDuplicatedKey)
+		}
+
+		err = db.Create(&models.OrderItem{OrderID: uuid.New(), MenuItemID: uuid.New()}).Error
+		if !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Errorf("missing parent: %v, want %v", err, gorm.ErrForeignKeyViolated)
+		}
+
+		// order_items.menu_item_id is ON DELETE RESTRICT
+		menuItem := &models.MenuItem{Name: utils.Ptr("ข้าวผัด"), PriceBaht: utils.Ptr(int64(60))}
+		order := &models.Order{Status: utils.Ptr("open")}
+		if err := db.Create(menuItem).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Create(order).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Create(&models.OrderItem{OrderID: order.ID, MenuItemID: menuItem.ID, Quantity: 1}).Error; err != nil {
+			t.Fatal(err)
+		}
+		err = db.Delete(&models.MenuItem{}, "id = ?", menuItem.ID).Error
+		if !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Errorf("restricted delete: %v, want %v", err, gorm.ErrForeignKeyViolated)
+		}
+
+		// order_items.order_id is ON DELETE CASCADE
+		if err := db.Delete(&models.Order{}, "id = ?", order.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		var items int64
+		if err := db.Model(&models.OrderItem{}).Where("order_id = ?", order.ID).Count(&items).Error; err != nil {
+			t.Fatal(err)
+		}
+		if items != 0 {
+			t.Errorf("%d items outlived their order", items)
+		}
+	})
+}
+
+func TestTransactionRollsBack(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		transactor := database.NewTransactor(db)
+		failure := errors.New("failure")
+
+		user := &models.User{Username: "cashier", PasswordHash: "hash"}
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := database.Conn(ctx, db).Create(user).Error; err != nil {
+				return err
+			}
+			// A nested call joins the outer transaction and sees its rows
+			return transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				var count int64
+				if err := database.Conn(ctx, db).Model(&models.User{}).Where("id = ?", user.ID).Count(&count).Error; err != nil {
+					return err
+				}
+				if count != 1 {
+					t.Errorf("nested transaction sees %d users, want 1", count)
+				}
+				return failure
+			})
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("err = %v, want %v", err, failure)
+		}
+
+		var count int64
+		if err := db.Model(&models.User{}).Where("id = ?", user.ID).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Error("rolled back user was kept")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+// Package dbtest opens empty, migrated databases for repository tests, so the
+// same test runs against every driver the GORM repositories support. SQLite
+// always runs, in a temporary file. Postgres runs only when PostgresDSNEnv
+// names a database the tests may create schemas in.
+package dbtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/database"
+	"gorm.io/gorm"
+)
+
+// PostgresDSNEnv holds the Postgres connection string, in keyword or URL form.
+// Each test gets its own schema, dropped when the test ends.
+const PostgresDSNEnv = "POS_TEST_POSTGRES_DSN"
+
+// Run runs fn once per driver as a subtest named after it, each time on a new
+// database holding every migration and no rows
+func Run(t *testing.T, fn func(t *testing.T, db *gorm.DB)) {
+	t.Helper()
+
+	t.Run(config.DriverSQLite, func(t *testing.T) {
+		fn(t, openSQLite(t))
+	})
+	t.Run(config.DriverPostgres, func(t *testing.T) {
+		dsn := os.Getenv(PostgresDSNEnv)
+		if dsn == "" {
+			t.Skipf("set %s to run against Postgres", PostgresDSNEnv)
+		}
+		fn(t, openPostgres(t, dsn))
+	})
+}
+
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverSQLite
+	cfg.Name = filepath.Join(t.TempDir(), "pos.db")
+	db, err := database.ConnectDB(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeOnCleanup(t, db)
+	migrate(t, db)
+	return db
+}
+
+func openPostgres(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
+	admin, err := database.Open(database.PostgresDialector(dsn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cleanups run last in first out, so admin is still open to drop the schema
+	closeOnCleanup(t, admin)
+
+	// Packages run their tests in parallel, so each test migrates its own schema
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+			t.Error(err)
+		}
+	})
+
+	db, err := database.Open(database.PostgresDialector(withSearchPath(dsn, schema)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeOnCleanup(t, db)
+	migrate(t, db)
+	return db
+}
+
+// withSearchPath makes schema the only one a connection to dsn sees
+func withSearchPath(dsn string, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&search_path=" + schema
+	}
+	return dsn + "?search_path=" + schema
+}
+
+func migrate(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	migrator, err := database.NewMigrator(db, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func closeOnCleanup(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+}
@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+// Select returns a copy of every row kept by keep, or of every row when keep is nil
+func Select[K comparable, V any](rows map[K]V, keep func(row *V) bool) []*V {
+	selected := make([]*V, 0, len(rows))
+	for _, row := range rows {
+		if keep != nil && !keep(&row) {
+			continue
+		}
+		selected = append(selected, &row)
+	}
+	return selected
+}
+
+// Any reports whether a row matches
+func Any[K comparable, V any](rows map[K]V, match func(row *V) bool) bool {
+	for _, row := range rows {
+		if match(&row) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort orders rows by the given comparisons, the first taking precedence
+func Sort[V any](rows []*V, comparisons ...func(a, b *V) int) {
+	slices.SortStableFunc(rows, func(a, b *V) int {
+		for _, compare := range comparisons {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// Limit keeps the first n rows; n <= 0 keeps them all
+func Limit[V any](rows []*V, n int) []*V {
+	if n > 0 && len(rows) > n {
+		return rows[:n]
+	}
+	return rows
+}
+
+// CompareString orders like ORDER BY ... ASC, with NULLs last
+func CompareString(a, b *string) int {
+	return compareNullable(a, b, strings.Compare)
+}
+
+// CompareInt orders like ORDER BY ... ASC, with NULLs last
+func CompareInt(a, b *int) int {
+	return compareNullable(a, b, cmp.Compare[int])
+}
+
+func compareNullable[T any](a, b *T, compare func(a, b T) int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return compare(*a, *b)
+}
+
+// EqualString compares nullable columns like a unique index: NULL never matches
+func EqualString(a, b *string) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// PermissionsOfRole returns the role's permissions by id, like Preload("Permissions")
+func (t *Tables) PermissionsOfRole(roleID int) []models.Permission {
+	var permissions []models.Permission
+	for key := range t.RolePermissions {
+		if key.RoleID != roleID {
+			continue
+		}
+		if permission, ok := t.Permissions[key.PermissionID]; ok {
+			permissions = append(permissions, permission)
+		}
+	}
+	slices.SortFunc(permissions, func(a, b models.Permission) int { return cmp.Compare(a.ID, b.ID) })
+	return permissions
+}
+
+// RolesOfUser returns the user's roles by id, like Preload("Roles")
+func (t *Tables) RolesOfUser(userID uuid.UUID) []models.Role {
+	var roles []models.Role
+	for key := range t.UserRoles {
+		if key.UserID != userID {
+			continue
+		}
+		if role, ok := t.Roles[key.RoleID]; ok {
+			roles = append(roles, role)
+		}
+	}
+	slices.SortFunc(roles, func(a, b models.Role) int { return cmp.Compare(a.ID, b.ID) })
+	return roles
+}
+
+// SchedulesOfCategory returns the category's menu schedules with their
+// exceptions, like Preload("Schedules.Exceptions")
+func (t *Tables) SchedulesOfCategory(categoryID uuid.UUID) []models.MenuSchedule {
+	return t.menuSchedules(func(s *models.MenuSchedule) bool { return s.CategoryID != nil && *s.CategoryID == categoryID })
+}
+
+// SchedulesOfMenuItem returns the menu item's schedules with their exceptions,
+// like Preload("Schedules.Exceptions")
+func (t *Tables) SchedulesOfMenuItem(menuItemID uuid.UUID) []models.MenuSchedule {
+	return t.menuSchedules(func(s *models.MenuSchedule) bool { return s.MenuItemID != nil && *s.MenuItemID == menuItemID })
+}
+
+func (t *Tables) menuSchedules(keep func(s *models.MenuSchedule) bool) []models.MenuSchedule {
+	var schedules []models.MenuSchedule
+	for _, schedule := range Select(t.MenuSchedules, keep) {
+		schedule.Exceptions = t.ExceptionsOfMenuSchedule(schedule.ID)
+		schedules = append(schedules, *schedule)
+	}
+	slices.SortFunc(schedules, func(a, b models.MenuSchedule) int { return t.CompareInserted(a.ID, b.ID) })
+	return schedules
+}
+
+// ExceptionsOfMenuSchedule returns the schedule's exceptions by date
+func (t *Tables) ExceptionsOfMenuSchedule(scheduleID uuid.UUID) []models.MenuScheduleException {
+	var exceptions []models.MenuScheduleException
+	for _, exception := range t.MenuScheduleExceptions {
+		if exception.ScheduleID == scheduleID {
+			exceptions = append(exceptions, exception)
+		}
+	}
+	slices.SortFunc(exceptions, func(a, b models.MenuScheduleException) int { return a.Date.Compare(b.Date) })
+	return exceptions
+}
+
+// DeleteMenuSchedules deletes the schedules matching match and their
+// exceptions, like ON DELETE CASCADE from what they schedule
+func (t *Tables) DeleteMenuSchedules(match func(s *models.MenuSchedule) bool) {
+	for id, schedule := range t.MenuSchedules {
+		if !match(&schedule) {
+			continue
+		}
+		delete(t.MenuSchedules, id)
+		for exceptionID, exception := range t.MenuScheduleExceptions {
+			if exception.ScheduleID == id {
+				delete(t.MenuScheduleExceptions, exceptionID)
+			}
+		}
+	}
+}
+
+// OptionsOfModifierGroup returns the group's options in order with their
+// modifiers, like Preload("Options.Modifier")
+func (t *Tables) OptionsOfModifierGroup(groupID uuid.UUID) []models.ModifierGroupOption {
+	var options []models.ModifierGroupOption
+	for _, option := range t.ModifierGroupOptions {
+		if option.ModifierGroupID != groupID {
+			continue
+		}
+		if modifier, ok := t.Modifiers[option.ModifierID]; ok {
+			option.Modifier = &modifier
+		}
+		options = append(options, option)
+	}
+	slices.SortFunc(options, func(a, b models.ModifierGroupOption) int { return cmp.Compare(a.SortOrder, b.SortOrder) })
+	return options
+}
+
+// ModifierGroupsOfMenuItem returns the menu item's group links with their
+// groups and options, like Preload("ModifierGroups.ModifierGroup.Options.Modifier")
+func (t *Tables) ModifierGroupsOfMenuItem(menuItemID uuid.UUID) []models.MenuItemModifierGroup {
+	var links []models.MenuItemModifierGroup
+	for _, link := range t.MenuItemModifierGroups {
+		if link.MenuItemID == menuItemID {
+			link.ModifierGroup = t.modifierGroup(link.ModifierGroupID)
+			links = append(links, link)
+		}
+	}
+	slices.SortFunc(links, func(a, b models.MenuItemModifierGroup) int {
+		return cmp.Compare(a.ModifierGroupID.String(), b.ModifierGroupID.String())
+	})
+	return links
+}
+
+// ModifierGroupsOfCategory returns the category's group links with their
+// groups and options, like Preload("ModifierGroups.ModifierGroup.Options.Modifier")
+func (t *Tables) ModifierGroupsOfCategory(categoryID uuid.UUID) []models.CategoryModifierGroup {
+	var links []models.CategoryModifierGroup
+	for _, link := range t.CategoryModifierGroups {
+		if link.CategoryID == categoryID {
+			link.ModifierGroup = t.modifierGroup(link.ModifierGroupID)
+			links = append(links, link)
+		}
+	}
+	slices.SortFunc(links, func(a, b models.CategoryModifierGroup) int {
+		return cmp.Compare(a.ModifierGroupID.String(), b.ModifierGroupID.String())
+	})
+	return links
+}
+
+func (t *Tables) modifierGroup(id uuid.UUID) *models.ModifierGroup {
+	group, ok := t.ModifierGroups[id]
+	if !ok {
+		return nil
+	}
+	group.Options = t.OptionsOfModifierGroup(id)
+	return &group
+}
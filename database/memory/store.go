@@ -0,0 +1,304 @@
+// Package memory holds every table in process memory so the API can run without
+// PostgreSQL. Repositories in each feature's memory.go query it through Read and
+// Write, and reproduce the database's defaults, unique constraints and foreign
+// key rules themselves.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type CategoryModifierGroupKey struct {
+	CategoryID      uuid.UUID
+	ModifierGroupID uuid.UUID
+}
+
+type MenuItemModifierGroupKey struct {
+	MenuItemID      uuid.UUID
+	ModifierGroupID uuid.UUID
+}
+
+type ModifierGroupOptionKey struct {
+	ModifierGroupID uuid.UUID
+	ModifierID      uuid.UUID
+}
+
+type OrderItemModifierKey struct {
+	OrderItemID uuid.UUID
+	ModifierID  uuid.UUID
+}
+
+type RolePermissionKey struct {
+	RoleID       int
+	PermissionID int
+}
+
+type UserRoleKey struct {
+	UserID uuid.UUID
+	RoleID int
+}
+
+// Tables has one map per table keyed by primary key. Rows are stored by value
+// without their associations; repositories fill those in on read and hand out
+// copies, so callers never share a row with the store. AuditLogs and
+// LoginAttempts only grow, and rows go into them through AppendAuditLog and
+// AppendLoginAttempt.
+type Tables struct {
+	Areas                  map[uuid.UUID]models.Area
+	AuditLogs              map[uuid.UUID]models.AuditLog
+	Categories             map[uuid.UUID]models.Category
+	CategoryModifierGroups map[CategoryModifierGroupKey]models.CategoryModifierGroup
+	DiningTables           map[uuid.UUID]models.DiningTable
+	JournalExports         map[uuid.UUID]models.JournalExport
+	JournalLines           map[uuid.UUID]models.JournalLine
+	LedgerAccounts         map[uuid.UUID]models.LedgerAccount
+	LoginAttempts          map[uuid.UUID]models.LoginAttempt
+	ManagerOverrides       map[uuid.UUID]models.ManagerOverride
+	MenuItemAvailability   map[uuid.UUID]models.MenuItemAvailability
+	MenuItemModifierGroups map[MenuItemModifierGroupKey]models.MenuItemModifierGroup
+	MenuItems              map[uuid.UUID]models.MenuItem
+	MenuScheduleExceptions map[uuid.UUID]models.MenuScheduleException
+	MenuSchedules          map[uuid.UUID]models.MenuSchedule
+	ModifierGroupOptions   map[ModifierGroupOptionKey]models.ModifierGroupOption
+	ModifierGroups         map[uuid.UUID]models.ModifierGroup
+	Modifiers              map[uuid.UUID]models.Modifier
+	OrderItemModifiers     map[OrderItemModifierKey]models.OrderItemModifier
+	OrderItems             map[uuid.UUID]models.OrderItem
+	Orders                 map[uuid.UUID]models.Order
+	Payments               map[uuid.UUID]models.Payment
+	Permissions            map[int]models.Permission
+	RolePermissions        map[RolePermissionKey]models.RolePermission
+	Roles                  map[int]models.Role
+	Sessions               map[uuid.UUID]models.Session
+	UserRoles              map[UserRoleKey]models.UserRole
+	Users                  map[uuid.UUID]models.User
+	VoidReasons            map[uuid.UUID]models.VoidReason
+
+	// Sequences for the serial primary keys
+	LastRoleID       int
+	LastPermissionID int
+
+	// inserted numbers rows in the order they were created, standing in for
+	// the insertion order Postgres tends to return unsorted rows in
+	inserted     map[uuid.UUID]uint64
+	lastInserted uint64
+
+	// The rows of the append-only tables in the order they were added, so a
+	// rollback removes them without a copy of the tables
+	auditLogIDs     []uuid.UUID
+	loginAttemptIDs []uuid.UUID
+}
+
+func newTables() *Tables {
+	return &Tables{
+		Areas:                  make(map[uuid.UUID]models.Area),
+		AuditLogs:              make(map[uuid.UUID]models.AuditLog),
+		Categories:             make(map[uuid.UUID]models.Category),
+		CategoryModifierGroups: make(map[CategoryModifierGroupKey]models.CategoryModifierGroup),
+		DiningTables:           make(map[uuid.UUID]models.DiningTable),
+		JournalExports:         make(map[uuid.UUID]models.JournalExport),
+		JournalLines:           make(map[uuid.UUID]models.JournalLine),
+		LedgerAccounts:         make(map[uuid.UUID]models.LedgerAccount),
+		LoginAttempts:          make(map[uuid.UUID]models.LoginAttempt),
+		ManagerOverrides:       make(map[uuid.UUID]models.ManagerOverride),
+		MenuItemAvailability:   make(map[uuid.UUID]models.MenuItemAvailability),
+		MenuItemModifierGroups: make(map[MenuItemModifierGroupKey]models.MenuItemModifierGroup),
+		MenuItems:              make(map[uuid.UUID]models.MenuItem),
+		MenuScheduleExceptions: make(map[uuid.UUID]models.MenuScheduleException),
+		MenuSchedules:          make(map[uuid.UUID]models.MenuSchedule),
+		ModifierGroupOptions:   make(map[ModifierGroupOptionKey]models.ModifierGroupOption),
+		ModifierGroups:         make(map[uuid.UUID]models.ModifierGroup),
+		Modifiers:              make(map[uuid.UUID]models.Modifier),
+		OrderItemModifiers:     make(map[OrderItemModifierKey]models.OrderItemModifier),
+		OrderItems:             make(map[uuid.UUID]models.OrderItem),
+		Orders:                 make(map[uuid.UUID]models.Order),
+		Payments:               make(map[uuid.UUID]models.Payment),
+		Permissions:            make(map[int]models.Permission),
+		RolePermissions:        make(map[RolePermissionKey]models.RolePermission),
+		Roles:                  make(map[int]models.Role),
+		Sessions:               make(map[uuid.UUID]models.Session),
+		UserRoles:              make(map[UserRoleKey]models.UserRole),
+		Users:                  make(map[uuid.UUID]models.User),
+		VoidReasons:            make(map[uuid.UUID]models.VoidReason),
+		inserted:               make(map[uuid.UUID]uint64),
+	}
+}
+
+// clone copies the maps that change in place so a transaction can be rolled
+// back by swapping the copy in. The append-only maps, which grow with the
+// store's history, are shared instead; rollBack removes what was added to them.
+func (t *Tables) clone() *Tables {
+	return &Tables{
+		Areas:                  cloneMap(t.Areas),
+		AuditLogs:              t.AuditLogs,
+		Categories:             cloneMap(t.Categories),
+		CategoryModifierGroups: cloneMap(t.CategoryModifierGroups),
+		DiningTables:           cloneMap(t.DiningTables),
+		JournalExports:         cloneMap(t.JournalExports),
+		JournalLines:           cloneMap(t.JournalLines),
+		LedgerAccounts:         cloneMap(t.LedgerAccounts),
+		LoginAttempts:          t.LoginAttempts,
+		ManagerOverrides:       cloneMap(t.ManagerOverrides),
+		MenuItemAvailability:   cloneMap(t.MenuItemAvailability),
+		MenuItemModifierGroups: cloneMap(t.MenuItemModifierGroups),
+		MenuItems:              cloneMap(t.MenuItems),
+		MenuScheduleExceptions: cloneMap(t.MenuScheduleExceptions),
+		MenuSchedules:          cloneMap(t.MenuSchedules),
+		ModifierGroupOptions:   cloneMap(t.ModifierGroupOptions),
+		ModifierGroups:         cloneMap(t.ModifierGroups),
+		Modifiers:              cloneMap(t.Modifiers),
+		OrderItemModifiers:     cloneMap(t.OrderItemModifiers),
+		OrderItems:             cloneMap(t.OrderItems),
+		Orders:                 cloneMap(t.Orders),
+		Payments:               cloneMap(t.Payments),
+		Permissions:            cloneMap(t.Permissions),
+		RolePermissions:        cloneMap(t.RolePermissions),
+		Roles:                  cloneMap(t.Roles),
+		Sessions:               cloneMap(t.Sessions),
+		UserRoles:              cloneMap(t.UserRoles),
+		Users:                  cloneMap(t.Users),
+		VoidReasons:            cloneMap(t.VoidReasons),
+		LastRoleID:             t.LastRoleID,
+		LastPermissionID:       t.LastPermissionID,
+		inserted:               t.inserted,
+		lastInserted:           t.lastInserted,
+		auditLogIDs:            t.auditLogIDs,
+		loginAttemptIDs:        t.loginAttemptIDs,
+	}
+}
+
+// rollBack removes from the shared maps the rows added since t was cloned
+// from current
+func (t *Tables) rollBack(current *Tables) {
+	for _, id := range current.auditLogIDs[len(t.auditLogIDs):] {
+		delete(t.AuditLogs, id)
+	}
+	for _, id := range current.loginAttemptIDs[len(t.loginAttemptIDs):] {
+		delete(t.LoginAttempts, id)
+	}
+	if current.lastInserted > t.lastInserted {
+		for id, n := range t.inserted {
+			if n > t.lastInserted {
+				delete(t.inserted, id)
+			}
+		}
+	}
+}
+
+// AppendAuditLog adds row to AuditLogs
+func (t *Tables) AppendAuditLog(row models.AuditLog) {
+	t.AuditLogs[row.ID] = row
+	t.auditLogIDs = append(t.auditLogIDs, row.ID)
+}
+
+// AppendLoginAttempt adds row to LoginAttempts
+func (t *Tables) AppendLoginAttempt(row models.LoginAttempt) {
+	t.LoginAttempts[row.ID] = row
+	t.loginAttemptIDs = append(t.loginAttemptIDs, row.ID)
+}
+
+// Inserted records that the row with id was just created
+func (t *Tables) Inserted(id uuid.UUID) {
+	if _, ok := t.inserted[id]; !ok {
+		t.lastInserted++
+		t.inserted[id] = t.lastInserted
+	}
+}
+
+// CompareInserted orders rows by when they were created
+func (t *Tables) CompareInserted(a, b uuid.UUID) int {
+	return cmp.Compare(t.inserted[a], t.inserted[b])
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// Store is a thread-safe set of tables. It is also the Transactor for the
+// memory repositories.
+//
+// Writers are serialised: a transaction holds the write lock from its first
+// statement until it commits, so rolling back never discards another request's
+// changes. Readers are not blocked by an open transaction and may see its
+// uncommitted rows.
+type Store struct {
+	writeMu sync.Mutex
+	mu      sync.RWMutex
+	tables  *Tables
+}
+
+type txKey struct{}
+
+func NewStore() *Store {
+	return &Store{tables: newTables()}
+}
+
+func (s *Store) inTransaction(ctx context.Context) bool {
+	tx, ok := ctx.Value(txKey{}).(*Store)
+	return ok && tx == s
+}
+
+// Read runs fn with the tables locked for reading. fn must not keep references
+// to the maps after it returns.
+func (s *Store) Read(ctx context.Context, fn func(t *Tables) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.tables)
+}
+
+// Write runs fn with the tables locked for writing. Like a single SQL statement,
+// fn must check every constraint before it changes anything.
+func (s *Store) Write(ctx context.Context, fn func(t *Tables) error) error {
+	if !s.inTransaction(ctx) {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.tables)
+}
+
+// WithinTransaction runs fn so that all of its writes are discarded if it
+// returns an error or panics. A nested call joins the outer transaction.
+//
+// The rollback snapshot copies every table but the append-only ones, so each
+// transaction costs time and memory in proportion to the menu, staff and
+// orders in the store, however little it writes. That is fine for demos,
+// training and tests, not for a day of real trading.
+func (s *Store) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.RLock()
+	snapshot := s.tables.clone()
+	s.mu.RUnlock()
+
+	committed := false
+	defer func() {
+		if !committed {
+			s.mu.Lock()
+			snapshot.rollBack(s.tables)
+			s.tables = snapshot
+			s.mu.Unlock()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, s)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
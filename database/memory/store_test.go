@@ -0,0 +1,67 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+func TestRollBackRemovesAppendedRows(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	kept := uuid.New()
+	if err := store.Write(ctx, func(t *memory.Tables) error {
+		t.AppendAuditLog(models.AuditLog{ID: kept})
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("failure")
+	err := store.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := store.Write(ctx, func(t *memory.Tables) error {
+			t.AppendAuditLog(models.AuditLog{ID: uuid.New()})
+			t.AppendLoginAttempt(models.LoginAttempt{ID: uuid.New()})
+			return nil
+		}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("err = %v, want %v", err, failure)
+	}
+
+	// Rows appended after the rollback are kept by the next one
+	added := uuid.New()
+	if err := store.WithinTransaction(ctx, func(ctx context.Context) error {
+		return store.Write(ctx, func(t *memory.Tables) error {
+			t.AppendAuditLog(models.AuditLog{ID: added})
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Read(ctx, func(tables *memory.Tables) error {
+		if len(tables.AuditLogs) != 2 {
+			t.Errorf("%d audit logs, want 2", len(tables.AuditLogs))
+		}
+		for _, id := range []uuid.UUID{kept, added} {
+			if _, ok := tables.AuditLogs[id]; !ok {
+				t.Errorf("audit log %s was lost", id)
+			}
+		}
+		if len(tables.LoginAttempts) != 0 {
+			t.Errorf("%d login attempts, want the rolled back one gone", len(tables.LoginAttempts))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
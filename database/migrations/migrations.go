@@ -0,0 +1,12 @@
+// Package migrations holds the versioned schema changes applied by
+// database.Migrator. Each driver has its own directory, named after its GORM
+// dialector, with files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql. Every migration is written once per driver with
+// the same version and name; versions are applied in ascending order and a
+// migration must never be edited once it has shipped.
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,215 @@
+-- Baseline schema. IF NOT EXISTS lets databases created by the old AutoMigrate
+-- boot adopt this migration without changes.
+
+CREATE TABLE IF NOT EXISTS users (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    username      varchar NOT NULL,
+    password_hash text NOT NULL,
+    pin_hash      text,
+    full_name     varchar,
+    email         varchar,
+    phone         varchar,
+    status        varchar,
+    created_at    timestamp DEFAULT now(),
+    updated_at    timestamp DEFAULT now(),
+    CONSTRAINT uni_users_username UNIQUE (username)
+);
+COMMENT ON COLUMN users.pin_hash IS 'short numeric PIN for approvals at the terminal';
+COMMENT ON COLUMN users.status IS 'active, locked';
+
+CREATE TABLE IF NOT EXISTS roles (
+    id   bigserial PRIMARY KEY,
+    name varchar NOT NULL,
+    CONSTRAINT uni_roles_name UNIQUE (name)
+);
+COMMENT ON COLUMN roles.name IS 'cashier, waiter, kitchen, manager, owner';
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          bigserial PRIMARY KEY,
+    code        varchar NOT NULL,
+    description text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_code ON permissions (code);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       bigint NOT NULL REFERENCES roles (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission_id bigint NOT NULL REFERENCES permissions (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    role_id bigint NOT NULL REFERENCES roles (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS areas (
+    id   uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name varchar
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_areas_name ON areas (name);
+
+CREATE TABLE IF NOT EXISTS dining_tables (
+    id      uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    area_id uuid REFERENCES areas (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    name    varchar,
+    seats   bigint,
+    status  varchar,
+    qr_slug varchar,
+    CONSTRAINT uni_dining_tables_qr_slug UNIQUE (qr_slug)
+);
+COMMENT ON COLUMN dining_tables.status IS 'free, occupied, needs_pay';
+COMMENT ON COLUMN dining_tables.qr_slug IS 'unguessable slug used in QR URLs';
+
+CREATE TABLE IF NOT EXISTS categories (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name          varchar,
+    display_order bigint
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+CREATE TABLE IF NOT EXISTS menu_items (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_id uuid REFERENCES categories (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    name        varchar,
+    sku         varchar,
+    price_baht  bigint,
+    active      boolean DEFAULT true,
+    image_url   text,
+    CONSTRAINT uni_menu_items_sku UNIQUE (sku)
+);
+
+CREATE TABLE IF NOT EXISTS modifiers (
+    id               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name             varchar,
+    price_delta_baht bigint DEFAULT 0,
+    note             text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_modifiers_name ON modifiers (name);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    table_id      uuid REFERENCES dining_tables (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    opened_by     uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    source        varchar,
+    status        varchar,
+    subtotal_baht bigint,
+    discount_baht bigint,
+    total_baht    bigint,
+    note          text,
+    created_at    timestamp DEFAULT now(),
+    closed_at     timestamp,
+    void_reason   varchar,
+    voided_by     uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    voided_at     timestamp
+);
+COMMENT ON COLUMN orders.opened_by IS 'nullable if customer-originated is allowed';
+COMMENT ON COLUMN orders.source IS 'staff, customer';
+COMMENT ON COLUMN orders.status IS 'open, paid, void';
+COMMENT ON COLUMN orders.void_reason IS 'void reason code';
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id              uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id        uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    menu_item_id    uuid NOT NULL REFERENCES menu_items (id) ON UPDATE RESTRICT ON DELETE RESTRICT,
+    quantity        bigint,
+    unit_price_baht bigint,
+    line_total_baht bigint,
+    note            text,
+    sent_at         timestamp,
+    cancelled_at    timestamp,
+    cancelled_by    uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    cancel_reason   varchar
+);
+COMMENT ON COLUMN order_items.sent_at IS 'when the item was sent to the kitchen';
+COMMENT ON COLUMN order_items.cancel_reason IS 'void reason code';
+
+CREATE TABLE IF NOT EXISTS order_item_modifiers (
+    order_item_id    uuid NOT NULL REFERENCES order_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_id      uuid NOT NULL REFERENCES modifiers (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    price_delta_baht bigint,
+    PRIMARY KEY (order_item_id, modifier_id)
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id     uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    method       varchar,
+    amount_baht  bigint,
+    currency     varchar(3) DEFAULT 'THB',
+    provider     varchar,
+    provider_ref varchar,
+    status       varchar,
+    created_at   timestamp DEFAULT now()
+);
+COMMENT ON COLUMN payments.method IS 'cash, card, promptpay';
+COMMENT ON COLUMN payments.status IS 'succeeded, pending, failed';
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    token      varchar NOT NULL,
+    expires_at timestamp NOT NULL,
+    created_at timestamp DEFAULT now(),
+    CONSTRAINT uni_sessions_token UNIQUE (token)
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_token ON sessions (token);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    username   varchar NOT NULL,
+    user_id    uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    client_ip  varchar NOT NULL,
+    result     varchar NOT NULL,
+    created_at timestamp DEFAULT now()
+);
+COMMENT ON COLUMN login_attempts.result IS 'success, failure, locked, blocked, unlocked';
+CREATE INDEX IF NOT EXISTS idx_login_attempts_username ON login_attempts (username);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_id ON login_attempts (user_id);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_client_ip ON login_attempts (client_ip);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_created_at ON login_attempts (created_at);
+
+CREATE TABLE IF NOT EXISTS manager_overrides (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    token         varchar NOT NULL,
+    action        varchar NOT NULL,
+    order_id      uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    order_item_id uuid,
+    requested_by  uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    approved_by   uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    reason        text NOT NULL,
+    expires_at    timestamp NOT NULL,
+    used_at       timestamp,
+    created_at    timestamp DEFAULT now(),
+    CONSTRAINT uni_manager_overrides_token UNIQUE (token)
+);
+COMMENT ON COLUMN manager_overrides.action IS 'order.void, order.item_remove, order.discount, order.reopen';
+CREATE INDEX IF NOT EXISTS idx_manager_overrides_order_id ON manager_overrides (order_id);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id     uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    action       varchar NOT NULL,
+    entity_type  varchar NOT NULL,
+    entity_id    varchar NOT NULL,
+    before_state jsonb,
+    after_state  jsonb,
+    client_ip    varchar,
+    request_id   varchar,
+    created_at   timestamp DEFAULT now()
+);
+COMMENT ON COLUMN audit_logs.before_state IS 'snapshot before the change';
+COMMENT ON COLUMN audit_logs.after_state IS 'snapshot after the change';
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+CREATE TABLE IF NOT EXISTS void_reasons (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    code          varchar NOT NULL,
+    label         varchar,
+    active        boolean DEFAULT true,
+    display_order bigint
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_void_reasons_code ON void_reasons (code);
@@ -0,0 +1,6 @@
+-- Order lists page on (created_at, id) and sum each order's items and
+-- payments in correlated subqueries.
+
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_id ON orders (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
@@ -0,0 +1,29 @@
+DROP INDEX IF EXISTS idx_payments_business_date;
+DROP INDEX IF EXISTS idx_orders_business_date;
+ALTER TABLE payments DROP COLUMN IF EXISTS business_date;
+ALTER TABLE orders DROP COLUMN IF EXISTS business_date;
+
+-- Timestamps go back to the zone the server ran in
+UPDATE users SET
+    created_at = created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}',
+    updated_at = updated_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
+UPDATE orders SET
+    created_at = created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}',
+    closed_at = closed_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}',
+    voided_at = voided_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
+UPDATE order_items SET
+    sent_at = sent_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}',
+    cancelled_at = cancelled_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
+UPDATE payments SET
+    created_at = created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
+UPDATE sessions SET
+    expires_at = expires_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}',
+    created_at = created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
+UPDATE login_attempts SET
+    created_at = created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
+UPDATE manager_overrides SET
+    expires_at = expires_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}',
+    used_at = used_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}',
+    created_at = created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
+UPDATE audit_logs SET
+    created_at = created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{legacy_time_zone}}';
@@ -0,0 +1,43 @@
+-- Timestamps are stored in UTC from here on. Rows written before are in the
+-- zone the server ran in, the legacy_time_zone parameter, and are converted.
+--
+-- Orders and payments are dated by the business day they happened on. Existing
+-- rows are dated from their converted timestamp with the configured time zone
+-- and cutoff.
+
+UPDATE users SET
+    created_at = created_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC',
+    updated_at = updated_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+UPDATE orders SET
+    created_at = created_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC',
+    closed_at = closed_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC',
+    voided_at = voided_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+UPDATE order_items SET
+    sent_at = sent_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC',
+    cancelled_at = cancelled_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+UPDATE payments SET
+    created_at = created_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+UPDATE sessions SET
+    expires_at = expires_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC',
+    created_at = created_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+UPDATE login_attempts SET
+    created_at = created_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+UPDATE manager_overrides SET
+    expires_at = expires_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC',
+    used_at = used_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC',
+    created_at = created_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+UPDATE audit_logs SET
+    created_at = created_at AT TIME ZONE '{{legacy_time_zone}}' AT TIME ZONE 'UTC';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS business_date date;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS business_date date;
+
+UPDATE orders
+SET business_date = CAST(created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{time_zone}}' - INTERVAL '{{day_cutoff_seconds}} seconds' AS date)
+WHERE business_date IS NULL;
+UPDATE payments
+SET business_date = CAST(created_at AT TIME ZONE 'UTC' AT TIME ZONE '{{time_zone}}' - INTERVAL '{{day_cutoff_seconds}} seconds' AS date)
+WHERE business_date IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_business_date ON orders (business_date);
+CREATE INDEX IF NOT EXISTS idx_payments_business_date ON payments (business_date);
@@ -0,0 +1,35 @@
+-- Accounting journal: the chart-of-accounts mapping and the business days
+-- whose journal entry was exported, with the lines as exported.
+
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    purpose     varchar NOT NULL,
+    category_id uuid REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    code        varchar NOT NULL,
+    name        varchar
+);
+COMMENT ON COLUMN ledger_accounts.purpose IS 'sales, vat_output, service_charge, discounts, cash, card, promptpay, refunds, over_short';
+COMMENT ON COLUMN ledger_accounts.category_id IS 'sales of this category only';
+
+CREATE TABLE IF NOT EXISTS journal_exports (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_date date NOT NULL,
+    exported_at   timestamp DEFAULT now(),
+    exported_by   uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    debit_baht    bigint NOT NULL,
+    credit_baht   bigint NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_exports_business_date ON journal_exports (business_date);
+
+CREATE TABLE IF NOT EXISTS journal_lines (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    export_id    uuid NOT NULL REFERENCES journal_exports (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    line_no      bigint NOT NULL,
+    purpose      varchar NOT NULL,
+    account_code varchar NOT NULL,
+    account_name varchar,
+    description  varchar NOT NULL,
+    debit_baht   bigint NOT NULL,
+    credit_baht  bigint NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_export_id ON journal_lines (export_id);
@@ -0,0 +1,12 @@
+-- Menu item availability: items marked sold out or low on stock, and the
+-- portions left of items whose portions are counted.
+
+CREATE TABLE IF NOT EXISTS menu_item_availabilities (
+    menu_item_id       uuid PRIMARY KEY REFERENCES menu_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    status             varchar NOT NULL,
+    remaining_portions bigint,
+    updated_at         timestamp DEFAULT now(),
+    updated_by         uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL
+);
+COMMENT ON COLUMN menu_item_availabilities.status IS 'available, low_stock or sold_out';
+COMMENT ON COLUMN menu_item_availabilities.remaining_portions IS 'portions left, taken as items are ordered; null when not counted';
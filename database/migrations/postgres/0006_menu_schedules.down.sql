@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS menu_schedule_exceptions;
+DROP TABLE IF EXISTS menu_schedules;
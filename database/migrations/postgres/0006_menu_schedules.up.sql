@@ -0,0 +1,28 @@
+-- Day-part menus: schedules putting a category or a menu item on the menu at
+-- certain times, optionally in one area, and the dates they are opened or
+-- closed for whatever their times say.
+
+CREATE TABLE IF NOT EXISTS menu_schedules (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name         varchar,
+    category_id  uuid REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    menu_item_id uuid REFERENCES menu_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    area_id      uuid REFERENCES areas (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    weekdays     bigint NOT NULL,
+    start_minute bigint,
+    end_minute   bigint
+);
+CREATE INDEX IF NOT EXISTS idx_menu_schedules_category_id ON menu_schedules (category_id);
+CREATE INDEX IF NOT EXISTS idx_menu_schedules_menu_item_id ON menu_schedules (menu_item_id);
+COMMENT ON COLUMN menu_schedules.area_id IS 'open in this area only';
+COMMENT ON COLUMN menu_schedules.weekdays IS 'bit n set to open on time.Weekday n, Sunday being 0';
+COMMENT ON COLUMN menu_schedules.start_minute IS 'minutes after midnight the window opens; null for all day';
+COMMENT ON COLUMN menu_schedules.end_minute IS 'minutes after midnight the window closes, the next day when not after the start';
+
+CREATE TABLE IF NOT EXISTS menu_schedule_exceptions (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    schedule_id uuid NOT NULL REFERENCES menu_schedules (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    date        date NOT NULL,
+    open        boolean NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_menu_schedule_exceptions_schedule_id ON menu_schedule_exceptions (schedule_id);
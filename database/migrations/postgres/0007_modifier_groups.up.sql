@@ -0,0 +1,46 @@
+-- Modifier groups: modifiers offered as one choice with selection rules, and
+-- the menu items and categories offering them.
+
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name           varchar,
+    min_selections bigint NOT NULL DEFAULT 0,
+    max_selections bigint,
+    sort_order     bigint NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_modifier_groups_name ON modifier_groups (name);
+COMMENT ON COLUMN modifier_groups.max_selections IS 'null for no limit';
+
+CREATE TABLE IF NOT EXISTS modifier_group_options (
+    modifier_group_id uuid NOT NULL REFERENCES modifier_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_id       uuid NOT NULL REFERENCES modifiers (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    sort_order        bigint NOT NULL DEFAULT 0,
+    is_default        boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (modifier_group_id, modifier_id)
+);
+COMMENT ON COLUMN modifier_group_options.is_default IS 'selected when an order item names no modifiers';
+
+CREATE TABLE IF NOT EXISTS menu_item_modifier_groups (
+    menu_item_id      uuid NOT NULL REFERENCES menu_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_group_id uuid NOT NULL REFERENCES modifier_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (menu_item_id, modifier_group_id)
+);
+
+CREATE TABLE IF NOT EXISTS category_modifier_groups (
+    category_id       uuid NOT NULL REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_group_id uuid NOT NULL REFERENCES modifier_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (category_id, modifier_group_id)
+);
+
+-- Any modifier could go on any item before groups. Existing items keep that
+-- through one optional group of every modifier, until groups are configured.
+INSERT INTO modifier_groups (name)
+SELECT 'Modifiers' WHERE EXISTS (SELECT 1 FROM modifiers);
+INSERT INTO modifier_group_options (modifier_group_id, modifier_id, sort_order)
+SELECT g.id, m.id, ROW_NUMBER() OVER (ORDER BY m.name) - 1
+FROM modifier_groups g CROSS JOIN modifiers m
+WHERE g.name = 'Modifiers';
+INSERT INTO menu_item_modifier_groups (menu_item_id, modifier_group_id)
+SELECT i.id, g.id
+FROM menu_items i CROSS JOIN modifier_groups g
+WHERE g.name = 'Modifiers';
@@ -0,0 +1,203 @@
+-- Baseline schema, the SQLite counterpart of postgres/0001_initial_schema.
+-- Column types are the ones GORM's SQLite dialector reports for the models so
+-- that drift detection stays quiet. SQLite has no gen_random_uuid() or now(),
+-- so ids and timestamps declared with those defaults are generated by the
+-- application (see database.registerSQLiteDefaults). Column comments live in
+-- the Postgres schema only: GORM reads this DDL back from sqlite_master and
+-- its parser does not skip them.
+
+CREATE TABLE IF NOT EXISTS users (
+    id            uuid NOT NULL PRIMARY KEY,
+    username      varchar NOT NULL,
+    password_hash text NOT NULL,
+    pin_hash      text,
+    full_name     varchar,
+    email         varchar,
+    phone         varchar,
+    status        varchar,
+    created_at    timestamp,
+    updated_at    timestamp,
+    CONSTRAINT uni_users_username UNIQUE (username)
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id   integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name varchar NOT NULL,
+    CONSTRAINT uni_roles_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    code        varchar NOT NULL,
+    description text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_code ON permissions (code);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       integer NOT NULL REFERENCES roles (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission_id integer NOT NULL REFERENCES permissions (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    role_id integer NOT NULL REFERENCES roles (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS areas (
+    id   uuid NOT NULL PRIMARY KEY,
+    name varchar
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_areas_name ON areas (name);
+
+CREATE TABLE IF NOT EXISTS dining_tables (
+    id      uuid NOT NULL PRIMARY KEY,
+    area_id uuid REFERENCES areas (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    name    varchar,
+    seats   integer,
+    status  varchar,
+    qr_slug varchar,
+    CONSTRAINT uni_dining_tables_qr_slug UNIQUE (qr_slug)
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id            uuid NOT NULL PRIMARY KEY,
+    name          varchar,
+    display_order integer
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+CREATE TABLE IF NOT EXISTS menu_items (
+    id          uuid NOT NULL PRIMARY KEY,
+    category_id uuid REFERENCES categories (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    name        varchar,
+    sku         varchar,
+    price_baht  integer,
+    active      numeric DEFAULT true,
+    image_url   text,
+    CONSTRAINT uni_menu_items_sku UNIQUE (sku)
+);
+
+CREATE TABLE IF NOT EXISTS modifiers (
+    id               uuid NOT NULL PRIMARY KEY,
+    name             varchar,
+    price_delta_baht integer DEFAULT 0,
+    note             text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_modifiers_name ON modifiers (name);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id            uuid NOT NULL PRIMARY KEY,
+    table_id      uuid REFERENCES dining_tables (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    opened_by     uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    source        varchar,
+    status        varchar,
+    subtotal_baht integer,
+    discount_baht integer,
+    total_baht    integer,
+    note          text,
+    created_at    timestamp,
+    closed_at     timestamp,
+    void_reason   varchar,
+    voided_by     uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    voided_at     timestamp
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id              uuid NOT NULL PRIMARY KEY,
+    order_id        uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    menu_item_id    uuid NOT NULL REFERENCES menu_items (id) ON UPDATE RESTRICT ON DELETE RESTRICT,
+    quantity        integer,
+    unit_price_baht integer,
+    line_total_baht integer,
+    note            text,
+    sent_at         timestamp,
+    cancelled_at    timestamp,
+    cancelled_by    uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    cancel_reason   varchar
+);
+
+CREATE TABLE IF NOT EXISTS order_item_modifiers (
+    order_item_id    uuid NOT NULL REFERENCES order_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_id      uuid NOT NULL REFERENCES modifiers (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    price_delta_baht integer,
+    PRIMARY KEY (order_item_id, modifier_id)
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id           uuid NOT NULL PRIMARY KEY,
+    order_id     uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    method       varchar,
+    amount_baht  integer,
+    currency     varchar(3) DEFAULT 'THB',
+    provider     varchar,
+    provider_ref varchar,
+    status       varchar,
+    created_at   timestamp
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id         uuid NOT NULL PRIMARY KEY,
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    token      varchar NOT NULL,
+    expires_at timestamp NOT NULL,
+    created_at timestamp,
+    CONSTRAINT uni_sessions_token UNIQUE (token)
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_token ON sessions (token);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id         uuid NOT NULL PRIMARY KEY,
+    username   varchar NOT NULL,
+    user_id    uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    client_ip  varchar NOT NULL,
+    result     varchar NOT NULL,
+    created_at timestamp
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_username ON login_attempts (username);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_id ON login_attempts (user_id);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_client_ip ON login_attempts (client_ip);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_created_at ON login_attempts (created_at);
+
+CREATE TABLE IF NOT EXISTS manager_overrides (
+    id            uuid NOT NULL PRIMARY KEY,
+    token         varchar NOT NULL,
+    action        varchar NOT NULL,
+    order_id      uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    order_item_id uuid,
+    requested_by  uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    approved_by   uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    reason        text NOT NULL,
+    expires_at    timestamp NOT NULL,
+    used_at       timestamp,
+    created_at    timestamp,
+    CONSTRAINT uni_manager_overrides_token UNIQUE (token)
+);
+CREATE INDEX IF NOT EXISTS idx_manager_overrides_order_id ON manager_overrides (order_id);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id           uuid NOT NULL PRIMARY KEY,
+    actor_id     uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    action       varchar NOT NULL,
+    entity_type  varchar NOT NULL,
+    entity_id    varchar NOT NULL,
+    before_state jsonb,
+    after_state  jsonb,
+    client_ip    varchar,
+    request_id   varchar,
+    created_at   timestamp
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+CREATE TABLE IF NOT EXISTS void_reasons (
+    id            uuid NOT NULL PRIMARY KEY,
+    code          varchar NOT NULL,
+    label         varchar,
+    active        numeric DEFAULT true,
+    display_order integer
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_void_reasons_code ON void_reasons (code);
@@ -0,0 +1,5 @@
+-- Timestamps stay in UTC; each one still carries its offset
+DROP INDEX IF EXISTS idx_payments_business_date;
+DROP INDEX IF EXISTS idx_orders_business_date;
+ALTER TABLE payments DROP COLUMN business_date;
+ALTER TABLE orders DROP COLUMN business_date;
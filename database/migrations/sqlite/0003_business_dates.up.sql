@@ -0,0 +1,45 @@
+-- Timestamps are stored in UTC from here on. The driver writes a time as text
+-- with its offset, so rows written before are unambiguous, but text only
+-- compares in order within one zone, and they are rewritten in UTC.
+--
+-- Orders and payments are dated by the business day they happened on. SQLite
+-- has no time zone database, so existing rows are dated with the configured
+-- zone's current UTC offset less the cutoff. Dates are written the way the
+-- driver writes a time, so they compare with the dates it binds.
+
+UPDATE users SET
+    created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+    updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00';
+UPDATE orders SET
+    created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+    closed_at = strftime('%Y-%m-%d %H:%M:%f', closed_at) || '+00:00',
+    voided_at = strftime('%Y-%m-%d %H:%M:%f', voided_at) || '+00:00';
+UPDATE order_items SET
+    sent_at = strftime('%Y-%m-%d %H:%M:%f', sent_at) || '+00:00',
+    cancelled_at = strftime('%Y-%m-%d %H:%M:%f', cancelled_at) || '+00:00';
+UPDATE payments SET
+    created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00';
+UPDATE sessions SET
+    expires_at = strftime('%Y-%m-%d %H:%M:%f', expires_at) || '+00:00',
+    created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00';
+UPDATE login_attempts SET
+    created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00';
+UPDATE manager_overrides SET
+    expires_at = strftime('%Y-%m-%d %H:%M:%f', expires_at) || '+00:00',
+    used_at = strftime('%Y-%m-%d %H:%M:%f', used_at) || '+00:00',
+    created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00';
+UPDATE audit_logs SET
+    created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00';
+
+ALTER TABLE orders ADD COLUMN business_date date;
+ALTER TABLE payments ADD COLUMN business_date date;
+
+UPDATE orders
+SET business_date = date(created_at, '{{day_offset_seconds}} seconds') || ' 00:00:00+00:00'
+WHERE business_date IS NULL AND created_at IS NOT NULL;
+UPDATE payments
+SET business_date = date(created_at, '{{day_offset_seconds}} seconds') || ' 00:00:00+00:00'
+WHERE business_date IS NULL AND created_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_business_date ON orders (business_date);
+CREATE INDEX IF NOT EXISTS idx_payments_business_date ON payments (business_date);
@@ -0,0 +1,33 @@
+-- Accounting journal: the chart-of-accounts mapping and the business days
+-- whose journal entry was exported, with the lines as exported.
+
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id          uuid NOT NULL PRIMARY KEY,
+    purpose     varchar NOT NULL,
+    category_id uuid REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    code        varchar NOT NULL,
+    name        varchar
+);
+
+CREATE TABLE IF NOT EXISTS journal_exports (
+    id            uuid NOT NULL PRIMARY KEY,
+    business_date date NOT NULL,
+    exported_at   timestamp,
+    exported_by   uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL,
+    debit_baht    integer NOT NULL,
+    credit_baht   integer NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_exports_business_date ON journal_exports (business_date);
+
+CREATE TABLE IF NOT EXISTS journal_lines (
+    id           uuid NOT NULL PRIMARY KEY,
+    export_id    uuid NOT NULL REFERENCES journal_exports (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    line_no      integer NOT NULL,
+    purpose      varchar NOT NULL,
+    account_code varchar NOT NULL,
+    account_name varchar,
+    description  varchar NOT NULL,
+    debit_baht   integer NOT NULL,
+    credit_baht  integer NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_export_id ON journal_lines (export_id);
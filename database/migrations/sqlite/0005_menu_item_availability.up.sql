@@ -0,0 +1,10 @@
+-- Menu item availability: items marked sold out or low on stock, and the
+-- portions left of items whose portions are counted.
+
+CREATE TABLE IF NOT EXISTS menu_item_availabilities (
+    menu_item_id       uuid NOT NULL PRIMARY KEY REFERENCES menu_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    status             varchar NOT NULL,
+    remaining_portions integer,
+    updated_at         timestamp,
+    updated_by         uuid REFERENCES users (id) ON UPDATE SET NULL ON DELETE SET NULL
+);
@@ -0,0 +1,24 @@
+-- Day-part menus: schedules putting a category or a menu item on the menu at
+-- certain times, optionally in one area, and the dates they are opened or
+-- closed for whatever their times say.
+
+CREATE TABLE IF NOT EXISTS menu_schedules (
+    id           uuid NOT NULL PRIMARY KEY,
+    name         varchar,
+    category_id  uuid REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    menu_item_id uuid REFERENCES menu_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    area_id      uuid REFERENCES areas (id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    weekdays     integer NOT NULL,
+    start_minute integer,
+    end_minute   integer
+);
+CREATE INDEX IF NOT EXISTS idx_menu_schedules_category_id ON menu_schedules (category_id);
+CREATE INDEX IF NOT EXISTS idx_menu_schedules_menu_item_id ON menu_schedules (menu_item_id);
+
+CREATE TABLE IF NOT EXISTS menu_schedule_exceptions (
+    id          uuid NOT NULL PRIMARY KEY,
+    schedule_id uuid NOT NULL REFERENCES menu_schedules (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    date        date NOT NULL,
+    open        numeric NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_menu_schedule_exceptions_schedule_id ON menu_schedule_exceptions (schedule_id);
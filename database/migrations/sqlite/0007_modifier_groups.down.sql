@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS category_modifier_groups;
+DROP TABLE IF EXISTS menu_item_modifier_groups;
+DROP TABLE IF EXISTS modifier_group_options;
+DROP TABLE IF EXISTS modifier_groups;
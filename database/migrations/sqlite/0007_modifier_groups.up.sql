@@ -0,0 +1,46 @@
+-- Modifier groups: modifiers offered as one choice with selection rules, and
+-- the menu items and categories offering them.
+
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id             uuid NOT NULL PRIMARY KEY,
+    name           varchar,
+    min_selections integer NOT NULL DEFAULT 0,
+    max_selections integer,
+    sort_order     integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_modifier_groups_name ON modifier_groups (name);
+
+CREATE TABLE IF NOT EXISTS modifier_group_options (
+    modifier_group_id uuid NOT NULL REFERENCES modifier_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_id       uuid NOT NULL REFERENCES modifiers (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    sort_order        integer NOT NULL DEFAULT 0,
+    is_default        numeric NOT NULL DEFAULT false,
+    PRIMARY KEY (modifier_group_id, modifier_id)
+);
+
+CREATE TABLE IF NOT EXISTS menu_item_modifier_groups (
+    menu_item_id      uuid NOT NULL REFERENCES menu_items (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_group_id uuid NOT NULL REFERENCES modifier_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (menu_item_id, modifier_group_id)
+);
+
+CREATE TABLE IF NOT EXISTS category_modifier_groups (
+    category_id       uuid NOT NULL REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    modifier_group_id uuid NOT NULL REFERENCES modifier_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (category_id, modifier_group_id)
+);
+
+-- Any modifier could go on any item before groups. Existing items keep that
+-- through one optional group of every modifier, until groups are configured.
+INSERT INTO modifier_groups (id, name)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-'
+    || substr('89ab', 1 + abs(random()) % 4, 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))), 'Modifiers'
+WHERE EXISTS (SELECT 1 FROM modifiers);
+INSERT INTO modifier_group_options (modifier_group_id, modifier_id, sort_order)
+SELECT g.id, m.id, ROW_NUMBER() OVER (ORDER BY m.name) - 1
+FROM modifier_groups g CROSS JOIN modifiers m
+WHERE g.name = 'Modifiers';
+INSERT INTO menu_item_modifier_groups (menu_item_id, modifier_group_id)
+SELECT i.id, g.id
+FROM menu_items i CROSS JOIN modifier_groups g
+WHERE g.name = 'Modifiers';
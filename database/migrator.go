@@ -0,0 +1,516 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/database/migrations"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// migrationLockKey is the pg_advisory_lock key held while migrating so that
+// instances booting together do not race each other
+const migrationLockKey int64 = 0x706f732d6d696772
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// migrationParamPattern matches a {{name}} parameter in a migration script
+var migrationParamPattern = regexp.MustCompile(`\{\{([a-z0-9_]+)\}\}`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	// Modified is set when the applied migration no longer matches its file
+	Modified bool
+	// Missing is set when an applied migration has no file in this build
+	Missing bool
+}
+
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false;column:version"`
+	Name      string    `gorm:"type:varchar;not null;column:name"`
+	Checksum  string    `gorm:"type:varchar;not null;column:checksum"`
+	AppliedAt time.Time `gorm:"type:timestamp;not null;default:now();column:applied_at"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	params     map[string]string
+}
+
+// NewMigrator loads the migrations written for db's driver. Data migrations
+// that depend on the installation read cfg through parameters.
+func NewMigrator(db *gorm.DB, cfg *config.Config) (*Migrator, error) {
+	params, err := migrationParams(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "[NewMigrator]: Error reading migration parameters")
+	}
+	source, err := fs.Sub(migrations.FS, db.Dialector.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "[NewMigrator]: Error loading migrations")
+	}
+	loaded, err := LoadMigrations(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "[NewMigrator]: Error loading migrations")
+	}
+	return &Migrator{db: db, migrations: loaded, params: params}, nil
+}
+
+// migrationParams are written into migration scripts in place of {{name}}:
+//   - time_zone and day_cutoff_seconds are the business day settings
+//   - day_offset_seconds is the zone's current UTC offset less the cutoff,
+//     for SQLite, which has no time zone database
+//   - legacy_time_zone is the zone timestamps were written in before they
+//     were stored in UTC
+func migrationParams(cfg *config.Config) (map[string]string, error) {
+	location, err := time.LoadLocation(cfg.Business.TimeZone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[migrationParams]: Error loading time zone %q", cfg.Business.TimeZone)
+	}
+	legacyTimeZone := cfg.Database.LegacyTimeZone
+	if legacyTimeZone == "" {
+		legacyTimeZone = cfg.Business.TimeZone
+	}
+	cutoff := int(cfg.Business.DayCutoff / time.Second)
+	_, offset := time.Now().In(location).Zone()
+
+	return map[string]string{
+		"time_zone":          cfg.Business.TimeZone,
+		"day_cutoff_seconds": strconv.Itoa(cutoff),
+		"day_offset_seconds": fmt.Sprintf("%+d", offset-cutoff),
+		"legacy_time_zone":   legacyTimeZone,
+	}, nil
+}
+
+// expand writes the parameters into script. Values are escaped for a string
+// literal, so scripts quote them.
+func (m *Migrator) expand(script string) (string, error) {
+	var unknown []string
+	expanded := migrationParamPattern.ReplaceAllStringFunc(script, func(match string) string {
+		name := migrationParamPattern.FindStringSubmatch(match)[1]
+		value, ok := m.params[name]
+		if !ok {
+			unknown = append(unknown, name)
+			return match
+		}
+		return strings.ReplaceAll(value, "'", "''")
+	})
+	if len(unknown) > 0 {
+		return "", errors.Errorf("[Migrator.expand]: Unknown parameters %s", strings.Join(unknown, ", "))
+	}
+	return expanded, nil
+}
+
+// LoadMigrations reads <version>_<name>.up.sql / .down.sql pairs from source,
+// sorted by version. A migration without a down file cannot be rolled back.
+func LoadMigrations(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "[LoadMigrations]: Error reading migrations")
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, errors.Errorf("[LoadMigrations]: Invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[LoadMigrations]: Invalid migration version in %q", entry.Name())
+		}
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "[LoadMigrations]: Error reading %q", entry.Name())
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, errors.Errorf("[LoadMigrations]: Migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	loaded := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" {
+			return nil, errors.Errorf("[LoadMigrations]: Migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		loaded = append(loaded, *migration)
+	}
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].Version < loaded[j].Version
+	})
+
+	return loaded, nil
+}
+
+// Up applies every pending migration in version order, each in its own
+// transaction, and returns the ones it applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		history, err := m.history(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(history); err != nil {
+			return err
+		}
+
+		var latest int64
+		for version := range history {
+			latest = max(latest, version)
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := history[migration.Version]; ok {
+				continue
+			}
+			if migration.Version < latest {
+				return errors.Errorf("[Migrator.Up]: Migration %d_%s is older than applied version %d", migration.Version, migration.Name, latest)
+			}
+
+			script, err := m.expand(migration.Up)
+			if err != nil {
+				return errors.Wrapf(err, "[Migrator.Up]: Error applying migration %d_%s", migration.Version, migration.Name)
+			}
+			err = conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(script).Error; err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{
+					Version:  migration.Version,
+					Name:     migration.Name,
+					Checksum: migration.Checksum,
+				}).Error
+			})
+			if err != nil {
+				return errors.Wrapf(err, "[Migrator.Up]: Error applying migration %d_%s", migration.Version, migration.Name)
+			}
+
+			log.Infof("[Migrator.Up]: Applied migration %d_%s", migration.Version, migration.Name)
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return applied, err
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the latest steps applied migrations, newest first, and
+// returns the ones it rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, errors.New("[Migrator.Down]: Steps must be at least 1")
+	}
+
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		history, err := m.history(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(history); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := history[migration.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return errors.Errorf("[Migrator.Down]: Migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+			}
+
+			script, err := m.expand(migration.Down)
+			if err != nil {
+				return errors.Wrapf(err, "[Migrator.Down]: Error rolling back migration %d_%s", migration.Version, migration.Name)
+			}
+			err = conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(script).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, migration.Version).Error
+			})
+			if err != nil {
+				return errors.Wrapf(err, "[Migrator.Down]: Error rolling back migration %d_%s", migration.Version, migration.Name)
+			}
+
+			log.Infof("[Migrator.Down]: Rolled back migration %d_%s", migration.Version, migration.Name)
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return rolledBack, err
+	}
+
+	return rolledBack, nil
+}
+
+// Status lists every known migration, applied or not, together with applied
+// versions that have no file in this build
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn := m.db.WithContext(ctx)
+	history := map[int64]schemaMigration{}
+	if conn.Migrator().HasTable(schemaMigration{}.TableName()) {
+		var err error
+		if history, err = m.history(conn); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := history[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = row.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	for version, row := range history {
+		if known[version] {
+			continue
+		}
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Name:      row.Name,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Drift compares the GORM models with the live schema and describes every
+// missing or unexpected table and column, and every column whose type or
+// nullability differs. An empty result means the schema matches the models.
+func (m *Migrator) Drift(ctx context.Context) ([]string, error) {
+	db := m.db.WithContext(ctx)
+	migrator := db.Migrator()
+
+	var drift []string
+	managed := map[string]bool{schemaMigration{}.TableName(): true}
+	for _, model := range Models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, errors.Wrap(err, "[Migrator.Drift]: Error parsing model")
+		}
+		table := stmt.Schema.Table
+		managed[table] = true
+
+		if !migrator.HasTable(table) {
+			drift = append(drift, fmt.Sprintf("table %s is missing", table))
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(table)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[Migrator.Drift]: Error reading columns of %s", table)
+		}
+		live := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, columnType := range columnTypes {
+			live[columnType.Name()] = columnType
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			columnType, ok := live[field.DBName]
+			if !ok {
+				drift = append(drift, fmt.Sprintf("column %s.%s is missing", table, field.DBName))
+				continue
+			}
+			delete(live, field.DBName)
+
+			want := normalizeColumnType(db.Dialector.DataTypeOf(field))
+			got := normalizeColumnType(columnType.DatabaseTypeName())
+			if want != got {
+				drift = append(drift, fmt.Sprintf("column %s.%s is %s, model expects %s", table, field.DBName, got, want))
+			}
+			if nullable, ok := columnType.Nullable(); ok {
+				notNull := field.NotNull || field.PrimaryKey
+				if nullable == notNull {
+					drift = append(drift, fmt.Sprintf("column %s.%s nullability differs from model (nullable=%t)", table, field.DBName, nullable))
+				}
+			}
+		}
+		for name := range live {
+			drift = append(drift, fmt.Sprintf("column %s.%s has no model field", table, name))
+		}
+	}
+
+	tables, err := migrator.GetTables()
+	if err != nil {
+		return nil, errors.Wrap(err, "[Migrator.Drift]: Error listing tables")
+	}
+	for _, table := range tables {
+		// SQLite keeps its own bookkeeping, such as sqlite_sequence, in the same list
+		if !managed[table] && !strings.HasPrefix(table, "sqlite_") {
+			drift = append(drift, fmt.Sprintf("table %s has no model", table))
+		}
+	}
+
+	sort.Strings(drift)
+	return drift, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock.
+// SQLite has no advisory locks; each migration's transaction takes the
+// database's write lock instead, and a racing instance fails to record a
+// version that is already applied.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if conn.Dialector.Name() != config.DriverPostgres {
+			if err := ensureVersionTable(conn); err != nil {
+				return err
+			}
+			return fn(conn)
+		}
+
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return errors.Wrap(err, "[Migrator.withLock]: Error acquiring migration lock")
+		}
+		defer func() {
+			// Unlock even if ctx was cancelled, otherwise the pooled connection keeps the lock
+			if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", migrationLockKey).Error; err != nil {
+				log.Error("[Migrator.withLock]: Error releasing migration lock: ", err)
+			}
+		}()
+
+		if err := ensureVersionTable(conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func ensureVersionTable(db *gorm.DB) error {
+	err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       varchar NOT NULL,
+		checksum   varchar NOT NULL,
+		applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`).Error
+	if err != nil {
+		return errors.Wrap(err, "[ensureVersionTable]: Error creating schema_migrations")
+	}
+	return nil
+}
+
+func (m *Migrator) history(db *gorm.DB) (map[int64]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "[Migrator.history]: Error reading schema_migrations")
+	}
+
+	history := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		history[row.Version] = row
+	}
+	return history, nil
+}
+
+// verify refuses to touch a schema whose applied migrations were edited or
+// come from a newer build
+func (m *Migrator) verify(history map[int64]schemaMigration) error {
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, row := range history {
+		migration, ok := known[version]
+		if !ok {
+			return errors.Errorf("[Migrator.verify]: Applied migration %d_%s is unknown to this build", version, row.Name)
+		}
+		if migration.Checksum != row.Checksum {
+			return errors.Errorf("[Migrator.verify]: Migration %d_%s was modified after it was applied", version, migration.Name)
+		}
+	}
+	return nil
+}
+
+var columnTypeAliases = map[string]string{
+	"bigint":            "int8",
+	"bigserial":         "int8",
+	"integer":           "int4",
+	"int":               "int4",
+	"serial":            "int4",
+	"smallint":          "int2",
+	"smallserial":       "int2",
+	"boolean":           "bool",
+	"decimal":           "numeric",
+	"character varying": "varchar",
+}
+
+// normalizeColumnType maps a model type and a Postgres udt_name or SQLite
+// declared type to the same spelling, ignoring length, precision and the
+// constraints SQLite's dialector appends to an auto-increment key
+func normalizeColumnType(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	if i := strings.Index(columnType, " primary key"); i >= 0 {
+		columnType = columnType[:i]
+	}
+	if i := strings.Index(columnType, "("); i >= 0 {
+		columnType = strings.TrimSpace(columnType[:i])
+	}
+	if alias, ok := columnTypeAliases[columnType]; ok {
+		return alias
+	}
+	return columnType
+}
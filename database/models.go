@@ -0,0 +1,37 @@
+package database
+
+import "github.com/pubestpubest/pos-backend/models"
+
+// Models lists every persisted model; the migrator compares them against the
+// live schema to detect drift
+var Models = []any{
+	&models.User{},
+	&models.Role{},
+	&models.Permission{},
+	&models.Area{},
+	&models.DiningTable{},
+	&models.Category{},
+	&models.MenuItem{},
+	&models.Modifier{},
+	&models.Order{},
+	&models.OrderItem{},
+	&models.OrderItemModifier{},
+	&models.Payment{},
+	&models.RolePermission{},
+	&models.UserRole{},
+	&models.Session{},
+	&models.LoginAttempt{},
+	&models.ManagerOverride{},
+	&models.AuditLog{},
+	&models.VoidReason{},
+	&models.LedgerAccount{},
+	&models.JournalExport{},
+	&models.JournalLine{},
+	&models.MenuItemAvailability{},
+	&models.MenuSchedule{},
+	&models.MenuScheduleException{},
+	&models.ModifierGroup{},
+	&models.ModifierGroupOption{},
+	&models.MenuItemModifierGroup{},
+	&models.CategoryModifierGroup{},
+}
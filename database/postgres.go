@@ -1,50 +1,52 @@
 package database
 
 import (
-	"fmt"
-	"os"
+	"strings"
+	"time"
 
-	"github.com/pubestpubest/pos-backend/models"
-	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/gorm/clause"
 )
 
-var DB *gorm.DB
-
-func ConnectDB(runEnv string) (err error) {
-	connectionString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s",
-		os.Getenv("DATABASE_HOST"),
-		os.Getenv("DATABASE_PORT"),
-		os.Getenv("DATABASE_USERNAME"),
-		os.Getenv("DATABASE_PASSWORD"),
-		os.Getenv("DATABASE_NAME"),
-	)
+// PostgresDialector returns the dialector for the database at dsn, given in
+// keyword or URL form. Timestamp columns carry no time zone, so the session is
+// pinned to UTC for its now() defaults, and every time is bound in UTC.
+func PostgresDialector(dsn string) gorm.Dialector {
+	return postgresDialector{Dialector: &postgres.Dialector{Config: &postgres.Config{DSN: withDSNParam(dsn, "TimeZone", "UTC")}}}
+}
 
-	db, err := gorm.Open(postgres.Open(connectionString), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
-	log.Info("[database]: Connected to database")
+type postgresDialector struct {
+	*postgres.Dialector
+}
 
-	db.AutoMigrate(
-		&models.User{},
-		&models.Role{},
-		&models.Permission{},
-		&models.Area{},
-		&models.DiningTable{},
-		&models.Category{},
-		&models.MenuItem{},
-		&models.Modifier{},
-		&models.Order{},
-		&models.OrderItem{},
-		&models.OrderItemModifier{},
-		&models.Payment{},
-		&models.RolePermission{},
-		&models.UserRole{},
-		&models.Session{},
-	)
-	log.Info("[database]: Migrated database")
+// BindVarTo also moves a bound time to UTC. The driver writes the wall clock
+// of a time into a timestamp column and drops its zone.
+func (d postgresDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v any) {
+	d.Dialector.BindVarTo(writer, stmt, v)
+	bindUTC(stmt)
+}
 
-	DB = db
+// bindUTC moves the variable just bound to stmt to UTC if it is a time
+func bindUTC(stmt *gorm.Statement) {
+	last := len(stmt.Vars) - 1
+	switch t := stmt.Vars[last].(type) {
+	case time.Time:
+		stmt.Vars[last] = t.UTC()
+	case *time.Time:
+		if t != nil {
+			stmt.Vars[last] = t.UTC()
+		}
+	}
+}
 
-	return err
+// withDSNParam adds key=value to dsn
+func withDSNParam(dsn string, key string, value string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " " + key + "=" + value
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + key + "=" + value
+	}
+	return dsn + "?" + key + "=" + value
 }
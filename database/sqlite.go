@@ -0,0 +1,104 @@
+package database
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqlitePragmas are applied to every connection:
+//   - foreign_keys makes SQLite enforce the REFERENCES clauses, which it
+//     ignores by default, so deletes fail or cascade as they do on Postgres
+//   - journal_mode WAL lets the API read while a transaction is writing
+//   - busy_timeout makes a writer wait for the lock instead of failing
+var sqlitePragmas = []string{"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"}
+
+// openSQLite returns the dialector for the database file at path. SQLite has
+// no SELECT ... FOR UPDATE, and GORM drops the clause for it. Transactions are
+// begun IMMEDIATE instead, taking the database's single write lock up front,
+// which serialises writers at least as strictly as row locks would.
+func openSQLite(path string) gorm.Dialector {
+	query := url.Values{}
+	for _, pragma := range sqlitePragmas {
+		query.Add("_pragma", pragma)
+	}
+	query.Set("_txlock", "immediate")
+	return sqliteDialector{Dialector: sqlite.Dialector{DSN: "file:" + path + "?" + query.Encode()}}
+}
+
+type sqliteDialector struct {
+	sqlite.Dialector
+}
+
+// Translate also reports ON DELETE RESTRICT as gorm.ErrForeignKeyViolated.
+// SQLite enforces RESTRICT like a trigger and fails with
+// SQLITE_CONSTRAINT_TRIGGER, which the dialector leaves untranslated.
+func (d sqliteDialector) Translate(err error) error {
+	var sqliteErr *gosqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_TRIGGER &&
+		strings.Contains(sqliteErr.Error(), "FOREIGN KEY constraint failed") {
+		return gorm.ErrForeignKeyViolated
+	}
+	return d.Dialector.Translate(err)
+}
+
+// BindVarTo also moves a bound time to UTC. The driver stores a time as text
+// with its offset, and text only compares in order within one zone.
+func (d sqliteDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v any) {
+	d.Dialector.BindVarTo(writer, stmt, v)
+	bindUTC(stmt)
+}
+
+// sqliteDefaults generates the column defaults that the models declare as
+// Postgres functions. GORM leaves a zero field with a default out of the
+// INSERT so the database can fill it, but SQLite has neither function.
+var sqliteDefaults = map[string]func() any{
+	"gen_random_uuid()": func() any { return uuid.New() },
+	"now()":             func() any { return time.Now() },
+}
+
+func registerSQLiteDefaults(db *gorm.DB) error {
+	err := db.Callback().Create().Before("gorm:create").Register("pos:sqlite_defaults", func(db *gorm.DB) {
+		if db.Statement.Schema == nil {
+			return
+		}
+
+		switch value := db.Statement.ReflectValue; value.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				setSQLiteDefaults(db, value.Index(i))
+			}
+		case reflect.Struct:
+			setSQLiteDefaults(db, value)
+		}
+	})
+	if err != nil {
+		return errors.Wrap(err, "[registerSQLiteDefaults]: Error registering callback")
+	}
+	return nil
+}
+
+func setSQLiteDefaults(db *gorm.DB, row reflect.Value) {
+	row = reflect.Indirect(row)
+	for _, field := range db.Statement.Schema.FieldsWithDefaultDBValue {
+		generate, ok := sqliteDefaults[field.DefaultValue]
+		if !ok {
+			continue
+		}
+		if _, zero := field.ValueOf(db.Statement.Context, row); !zero {
+			continue
+		}
+		if err := field.Set(db.Statement.Context, row, generate()); err != nil {
+			db.AddError(errors.Wrapf(err, "[setSQLiteDefaults]: Error setting default for %s", field.Name))
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
@@ -9,17 +11,17 @@ import (
 
 // Area domain - manages dining areas/sections
 type AreaUsecase interface {
-	GetAllAreas() ([]*response.AreaResponse, error)
-	GetAreaByID(id uuid.UUID) (*response.AreaResponse, error)
-	CreateArea(req *request.AreaRequest) (*response.AreaResponse, error)
-	UpdateArea(id uuid.UUID, req *request.AreaRequest) (*response.AreaResponse, error)
-	DeleteArea(id uuid.UUID) error
+	GetAllAreas(ctx context.Context) (*response.Page[*response.AreaResponse], error)
+	GetAreaByID(ctx context.Context, id uuid.UUID) (*response.AreaResponse, error)
+	CreateArea(ctx context.Context, req *request.AreaRequest) (*response.AreaResponse, error)
+	UpdateArea(ctx context.Context, id uuid.UUID, req *request.AreaRequest) (*response.AreaResponse, error)
+	DeleteArea(ctx context.Context, id uuid.UUID) error
 }
 
 type AreaRepository interface {
-	GetAllAreas() ([]*models.Area, error)
-	GetAreaByID(id uuid.UUID) (*models.Area, error)
-	CreateArea(area *models.Area) error
-	UpdateArea(area *models.Area) error
-	DeleteArea(id uuid.UUID) error
+	GetAllAreas(ctx context.Context) ([]*models.Area, error)
+	GetAreaByID(ctx context.Context, id uuid.UUID) (*models.Area, error)
+	CreateArea(ctx context.Context, area *models.Area) error
+	UpdateArea(ctx context.Context, area *models.Area) error
+	DeleteArea(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// Audit domain - records who changed what, with before and after snapshots
+type AuditUsecase interface {
+	Record(ctx context.Context, action string, entityType string, entityID string, before any, after any) error
+	GetAuditLogs(ctx context.Context, req *request.AuditLogQuery) (*response.Page[*response.AuditLogResponse], error)
+}
+
+type AuditRepository interface {
+	CreateAuditLog(ctx context.Context, entry *models.AuditLog) error
+	GetAuditLogs(ctx context.Context, query *request.AuditLogQuery, page PageParams) (Page[models.AuditLog], error)
+}
+
+// Actor identifies who is making a request
+type Actor struct {
+	UserID    *uuid.UUID
+	ClientIP  string
+	RequestID string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, or an empty actor for system calls
+func ActorFromContext(ctx context.Context) *Actor {
+	if actor, ok := ctx.Value(actorKey{}).(*Actor); ok {
+		return actor
+	}
+	return &Actor{}
+}
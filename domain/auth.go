@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
@@ -9,21 +12,34 @@ import (
 
 // Auth domain - manages authentication and authorization
 type AuthUsecase interface {
-	Login(req *request.LoginRequest) (*response.AuthResponse, error)
-	Logout(token string) error
-	ChangePassword(userID uuid.UUID, req *request.ChangePasswordRequest) error
-	VerifyPermission(userID uuid.UUID, permissionCode string) (bool, error)
-	GetUserPermissions(userID uuid.UUID) ([]string, error)
-	GetUserByToken(token string) (*models.User, error)
+	Login(ctx context.Context, req *request.LoginRequest, clientIP string) (*response.AuthResponse, error)
+	Logout(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *request.ChangePasswordRequest) error
+	SetPin(ctx context.Context, userID uuid.UUID, req *request.SetPinRequest) error
+	VerifyCredentials(ctx context.Context, username string, secret string, clientIP string) (*models.User, error)
+	VerifyPermission(ctx context.Context, userID uuid.UUID, permissionCode string) (bool, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetUserByToken(ctx context.Context, token string) (*models.User, error)
+	UnlockUser(ctx context.Context, userID uuid.UUID) error
+	ResetPassword(ctx context.Context, req *request.ResetPasswordRequest) error
+	PurgeExpiredSessions(ctx context.Context) (int64, error)
+	GetLoginHistory(ctx context.Context, userID uuid.UUID, query *request.LoginHistoryQuery) (*response.Page[*response.LoginAttemptResponse], error)
 }
 
 type AuthRepository interface {
-	GetUserByUsername(username string) (*models.User, error)
-	GetUserWithRolesAndPermissions(id uuid.UUID) (*models.User, error)
-	GetUserPermissions(userID uuid.UUID) ([]string, error)
-	UpdatePassword(userID uuid.UUID, passwordHash string) error
-	CreateSession(session *models.Session) error
-	GetSessionByToken(token string) (*models.Session, error)
-	DeleteSession(token string) error
-	CleanupExpiredSessions() error
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetUserWithRolesAndPermissions(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	UpdatePin(ctx context.Context, userID uuid.UUID, pinHash string) error
+	UpdateUserStatus(ctx context.Context, userID uuid.UUID, status string) error
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSessionByToken(ctx context.Context, token string) (*models.Session, error)
+	DeleteSession(ctx context.Context, token string) error
+	DeleteSessionsByUser(ctx context.Context, userID uuid.UUID) error
+	CleanupExpiredSessions(ctx context.Context) (int64, error)
+	CreateLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error
+	GetFailedLoginsByUsername(ctx context.Context, username string, since time.Time) ([]*models.LoginAttempt, error)
+	GetFailedLoginsByIP(ctx context.Context, clientIP string, since time.Time) ([]*models.LoginAttempt, error)
+	GetLoginAttemptsByUser(ctx context.Context, userID uuid.UUID, page PageParams) (Page[models.LoginAttempt], error)
 }
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
@@ -9,17 +11,17 @@ import (
 
 // Category domain - manages menu categories
 type CategoryUsecase interface {
-	GetAllCategories() ([]*response.CategoryResponse, error)
-	GetCategoryByID(id uuid.UUID) (*response.CategoryResponse, error)
-	CreateCategory(req *request.CategoryRequest) (*response.CategoryResponse, error)
-	UpdateCategory(id uuid.UUID, req *request.CategoryRequest) (*response.CategoryResponse, error)
-	DeleteCategory(id uuid.UUID) error
+	GetAllCategories(ctx context.Context) (*response.Page[*response.CategoryResponse], error)
+	GetCategoryByID(ctx context.Context, id uuid.UUID) (*response.CategoryResponse, error)
+	CreateCategory(ctx context.Context, req *request.CategoryRequest) (*response.CategoryResponse, error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, req *request.CategoryRequest) (*response.CategoryResponse, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
 }
 
 type CategoryRepository interface {
-	GetAllCategories() ([]*models.Category, error)
-	GetCategoryByID(id uuid.UUID) (*models.Category, error)
-	CreateCategory(category *models.Category) error
-	UpdateCategory(category *models.Category) error
-	DeleteCategory(id uuid.UUID) error
+	GetAllCategories(ctx context.Context) ([]*models.Category, error)
+	GetCategoryByID(ctx context.Context, id uuid.UUID) (*models.Category, error)
+	CreateCategory(ctx context.Context, category *models.Category) error
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,74 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifies a failure so the delivery layer can pick a status code
+// without looking at the message.
+type ErrorKind string
+
+const (
+	ErrorKindNotFound           ErrorKind = "not_found"
+	ErrorKindConflict           ErrorKind = "conflict"
+	ErrorKindValidation         ErrorKind = "validation_failed"
+	ErrorKindUnauthorized       ErrorKind = "unauthorized"
+	ErrorKindForbidden          ErrorKind = "forbidden"
+	ErrorKindPreconditionFailed ErrorKind = "precondition_failed"
+	ErrorKindTooManyRequests    ErrorKind = "too_many_requests"
+	ErrorKindTimeout            ErrorKind = "timeout"
+	ErrorKindInternal           ErrorKind = "internal"
+)
+
+// Error is a failure the client can act on. Repositories and usecases return it,
+// usually wrapped with errors.Wrap for the log trail; the message is safe to show.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Fields  map[string]string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func NewError(kind ErrorKind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func NotFoundError(message string) *Error {
+	return NewError(ErrorKindNotFound, message)
+}
+
+func ConflictError(message string) *Error {
+	return NewError(ErrorKindConflict, message)
+}
+
+// ValidationError reports bad input; fields maps each offending field to its problem
+func ValidationError(message string, fields map[string]string) *Error {
+	return &Error{Kind: ErrorKindValidation, Message: message, Fields: fields}
+}
+
+func UnauthorizedError(message string) *Error {
+	return NewError(ErrorKindUnauthorized, message)
+}
+
+func ForbiddenError(message string) *Error {
+	return NewError(ErrorKindForbidden, message)
+}
+
+// PreconditionFailedError reports an action that is not allowed in the entity's current state
+func PreconditionFailedError(message string) *Error {
+	return NewError(ErrorKindPreconditionFailed, message)
+}
+
+func TooManyRequestsError(message string) *Error {
+	return NewError(ErrorKindTooManyRequests, message)
+}
+
+// ErrorKindOf returns the kind of the first Error in err's chain, or internal
+func ErrorKindOf(err error) ErrorKind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	return ErrorKindInternal
+}
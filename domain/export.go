@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/spreadsheet"
+)
+
+// Export domain - writes orders, payments, voids and reports as spreadsheets.
+// Each export walks the same pages the list APIs serve, so it holds one page
+// in memory however long its range. It writes nothing before the first page
+// has loaded; an error returned after that has cut the file short.
+type ExportUsecase interface {
+	ExportOrders(ctx context.Context, query *request.OrderExportQuery, w spreadsheet.Writer) error
+	ExportPayments(ctx context.Context, query *request.PaymentExportQuery, w spreadsheet.Writer) error
+	ExportVoids(ctx context.Context, query *request.VoidExportQuery, w spreadsheet.Writer) error
+	ExportSalesReport(ctx context.Context, query *request.SalesReportExportQuery, w spreadsheet.Writer) error
+}
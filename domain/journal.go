@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// Journal domain - posts each business day's sales and payments to the chart of
+// accounts as one balanced journal entry, exported once per day
+type JournalUsecase interface {
+	GetLedgerAccounts(ctx context.Context) (*response.Page[*response.LedgerAccountResponse], error)
+	ReplaceLedgerAccounts(ctx context.Context, req *request.LedgerAccountsRequest) (*response.Page[*response.LedgerAccountResponse], error)
+	// GetJournalEntry builds the entry of a business date without exporting it,
+	// or returns the exported one
+	GetJournalEntry(ctx context.Context, date time.Time) (*response.JournalEntryResponse, error)
+	// ExportJournalEntry builds and records the entry of a business date that
+	// is over and has not been exported yet
+	ExportJournalEntry(ctx context.Context, date time.Time) (*response.JournalEntryResponse, error)
+	GetJournalExports(ctx context.Context, query *request.JournalExportListQuery) (*response.Page[*response.JournalExportResponse], error)
+}
+
+type JournalRepository interface {
+	GetLedgerAccounts(ctx context.Context) ([]*models.LedgerAccount, error)
+	// ReplaceLedgerAccounts deletes every account and creates accounts in their place
+	ReplaceLedgerAccounts(ctx context.Context, accounts []*models.LedgerAccount) error
+	// GetJournalTotals sums the paid orders of a business date, their items by
+	// category and their succeeded payments by method, and counts the orders
+	// of the date still open
+	GetJournalTotals(ctx context.Context, date time.Time) ([]*models.JournalTotal, error)
+	// GetJournalExport returns the export of a business date with its lines,
+	// or a not found error
+	GetJournalExport(ctx context.Context, date time.Time) (*models.JournalExport, error)
+	CreateJournalExport(ctx context.Context, export *models.JournalExport) error
+	GetJournalExports(ctx context.Context, page PageParams) (Page[models.JournalExport], error)
+}
@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
@@ -9,19 +12,60 @@ import (
 
 // MenuItem domain - manages menu items and their available modifiers
 type MenuItemUsecase interface {
-	GetAllMenuItems() ([]*response.MenuItemResponse, error)
-	GetMenuItemByID(id uuid.UUID) (*response.MenuItemResponse, error)
-	CreateMenuItem(req *request.MenuItemRequest) (*response.MenuItemResponse, error)
-	UpdateMenuItem(id uuid.UUID, req *request.MenuItemRequest) (*response.MenuItemResponse, error)
-	DeleteMenuItem(id uuid.UUID) error
-	GetAvailableModifiers() ([]*response.ModifierResponse, error)
+	GetAllMenuItems(ctx context.Context, query *request.MenuItemListQuery) (*response.Page[*response.MenuItemResponse], error)
+	GetMenuItemByID(ctx context.Context, id uuid.UUID) (*response.MenuItemResponse, error)
+	CreateMenuItem(ctx context.Context, req *request.MenuItemRequest) (*response.MenuItemResponse, error)
+	UpdateMenuItem(ctx context.Context, id uuid.UUID, req *request.MenuItemRequest) (*response.MenuItemResponse, error)
+	DeleteMenuItem(ctx context.Context, id uuid.UUID) error
+	GetAvailableModifiers(ctx context.Context) (*response.Page[*response.ModifierResponse], error)
+	// GetMenu lists the active items the schedules put on the menu at the time
+	// and place asked for
+	GetMenu(ctx context.Context, query *request.MenuQuery) (*response.Page[*response.MenuItemResponse], error)
+	GetAvailability(ctx context.Context) (*response.Page[*response.MenuItemAvailabilityResponse], error)
+	SetAvailability(ctx context.Context, id uuid.UUID, req *request.MenuItemAvailabilityRequest) (*response.MenuItemAvailabilityResponse, error)
+	// WatchAvailability sends the availability of every item, then that of each
+	// item as it changes, until ctx is done or send fails. It calls keepAlive
+	// while there is nothing to send.
+	WatchAvailability(ctx context.Context, send func(*response.MenuItemAvailabilityResponse) error, keepAlive func() error) error
 }
 
 type MenuItemRepository interface {
-	GetAllMenuItems() ([]*models.MenuItem, error)
-	GetMenuItemByID(id uuid.UUID) (*models.MenuItem, error)
-	CreateMenuItem(menuItem *models.MenuItem) error
-	UpdateMenuItem(menuItem *models.MenuItem) error
-	DeleteMenuItem(id uuid.UUID) error
-	GetAllModifiers() ([]*models.Modifier, error)
+	// GetMenuItems loads one page of the menu items matching query, with their
+	// category, schedules, modifier groups and availability
+	GetMenuItems(ctx context.Context, query *request.MenuItemListQuery, page PageParams) (Page[models.MenuItem], error)
+	// GetMenuItemByID loads a menu item with its category, schedules, modifier
+	// groups and availability
+	GetMenuItemByID(ctx context.Context, id uuid.UUID) (*models.MenuItem, error)
+	// GetMenu loads every active menu item by name, with its category,
+	// schedules, modifier groups and availability
+	GetMenu(ctx context.Context) ([]*models.MenuItem, error)
+	GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error)
+	CreateMenuItem(ctx context.Context, menuItem *models.MenuItem) error
+	UpdateMenuItem(ctx context.Context, menuItem *models.MenuItem) error
+	DeleteMenuItem(ctx context.Context, id uuid.UUID) error
+	GetAllModifiers(ctx context.Context) ([]*models.Modifier, error)
+	// GetAvailability loads the menu items with ids, or every one when ids is
+	// nil, sorted by name with their availability
+	GetAvailability(ctx context.Context, ids []uuid.UUID) ([]*models.MenuItem, error)
+	// SaveAvailability creates or replaces the availability of a menu item
+	SaveAvailability(ctx context.Context, availability *models.MenuItemAvailability) error
+}
+
+// AvailabilityFeed carries the ids of menu items whose availability changed
+// to whoever is watching
+type AvailabilityFeed interface {
+	Publish(menuItemID uuid.UUID)
+	Subscribe() (<-chan uuid.UUID, func())
+}
+
+// MenuItemStatus is whether an item with availability a can be ordered: sold
+// out when so marked or when its counted portions have run out
+func MenuItemStatus(a *models.MenuItemAvailability) string {
+	switch {
+	case a == nil:
+		return constant.MenuItemAvailable
+	case a.RemainingPortions != nil && *a.RemainingPortions == 0:
+		return constant.MenuItemSoldOut
+	}
+	return a.Status
 }
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// MenuSchedule domain - day-part menus: when, and where, categories and menu
+// items are on the menu
+type MenuScheduleUsecase interface {
+	GetMenuSchedules(ctx context.Context, query *request.MenuScheduleListQuery) (*response.Page[*response.MenuScheduleResponse], error)
+	GetMenuScheduleByID(ctx context.Context, id uuid.UUID) (*response.MenuScheduleResponse, error)
+	CreateMenuSchedule(ctx context.Context, req *request.MenuScheduleRequest) (*response.MenuScheduleResponse, error)
+	UpdateMenuSchedule(ctx context.Context, id uuid.UUID, req *request.MenuScheduleRequest) (*response.MenuScheduleResponse, error)
+	DeleteMenuSchedule(ctx context.Context, id uuid.UUID) error
+}
+
+type MenuScheduleRepository interface {
+	// GetMenuSchedules loads the schedules matching query with their exceptions
+	GetMenuSchedules(ctx context.Context, query *request.MenuScheduleListQuery) ([]*models.MenuSchedule, error)
+	GetMenuScheduleByID(ctx context.Context, id uuid.UUID) (*models.MenuSchedule, error)
+	// CreateMenuSchedule creates a schedule with its exceptions
+	CreateMenuSchedule(ctx context.Context, schedule *models.MenuSchedule) error
+	// UpdateMenuSchedule saves a schedule and replaces its exceptions
+	UpdateMenuSchedule(ctx context.Context, schedule *models.MenuSchedule) error
+	DeleteMenuSchedule(ctx context.Context, id uuid.UUID) error
+}
+
+// MenuItemOffered reports whether the schedules of a menu item and of its
+// category, loaded with their exceptions, both put it on the menu at a time in
+// the restaurant's zone and area. Without an area, schedules limited to one are
+// closed.
+func MenuItemOffered(menuItem *models.MenuItem, at time.Time, areaID *uuid.UUID) bool {
+	open := func(s *models.MenuSchedule) bool {
+		return (s.AreaID == nil || areaID != nil && *s.AreaID == *areaID) && MenuScheduleOpen(s, at)
+	}
+	return offered(menuItem, open)
+}
+
+// MenuItemOfferedInAnyArea reports whether a menu item is on the menu at a time
+// in the restaurant's zone, whatever areas its schedules are limited to
+func MenuItemOfferedInAnyArea(menuItem *models.MenuItem, at time.Time) bool {
+	open := func(s *models.MenuSchedule) bool {
+		return MenuScheduleOpen(s, at)
+	}
+	return offered(menuItem, open)
+}
+
+// offered reports whether both the category's schedules and the item's own
+// put a menu item on the menu
+func offered(menuItem *models.MenuItem, open func(*models.MenuSchedule) bool) bool {
+	if menuItem.Category != nil && !scheduled(menuItem.Category.Schedules, open) {
+		return false
+	}
+	return scheduled(menuItem.Schedules, open)
+}
+
+// scheduled reports whether any of schedules is open, or there are none
+func scheduled(schedules []models.MenuSchedule, open func(*models.MenuSchedule) bool) bool {
+	if len(schedules) == 0 {
+		return true
+	}
+	for i := range schedules {
+		if open(&schedules[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// MenuScheduleOpen reports whether s is open at a time in the restaurant's zone,
+// leaving its area to the caller. An exception for that date decides alone.
+// Otherwise the window must be open and have opened on one of the schedule's
+// weekdays: after midnight, an overnight window belongs to the day before.
+func MenuScheduleOpen(s *models.MenuSchedule, at time.Time) bool {
+	date := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	for _, exception := range s.Exceptions {
+		if exception.Date.Equal(date) {
+			return exception.Open
+		}
+	}
+
+	day := at.Weekday()
+	if s.StartMinute != nil && s.EndMinute != nil {
+		minute := at.Hour()*60 + at.Minute()
+		start, end := *s.StartMinute, *s.EndMinute
+		switch {
+		case start < end && minute >= start && minute < end:
+		case start >= end && minute >= start:
+		case start >= end && minute < end:
+			day = (day + 6) % 7
+		default:
+			return false
+		}
+	}
+	return s.Weekdays&(1<<day) != 0
+}
@@ -0,0 +1,16 @@
+package domain
+
+// Metrics receives the business events the usecases report, so operators can
+// watch throughput alongside latency and errors. Events are reported after the
+// change has been committed.
+type Metrics interface {
+	OrderOpened(source string)
+	// OrderClosed is reported when an order is paid or voided
+	OrderClosed(status string)
+	OrderReopened()
+	// Voided is reported for a voided order or a cancelled item, with its reason code
+	Voided(kind string, reasonCode string)
+	PaymentRecorded(method string, status string, amountBaht int64)
+	// AuthFailed is reported for every rejected login or session, by reason
+	AuthFailed(reason string)
+}
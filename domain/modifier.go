@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
@@ -9,17 +11,17 @@ import (
 
 // Modifier domain - manages menu item modifiers (add-ons, customizations)
 type ModifierUsecase interface {
-	GetAllModifiers() ([]*response.ModifierResponse, error)
-	GetModifierByID(id uuid.UUID) (*response.ModifierResponse, error)
-	CreateModifier(req *request.ModifierRequest) (*response.ModifierResponse, error)
-	UpdateModifier(id uuid.UUID, req *request.ModifierRequest) (*response.ModifierResponse, error)
-	DeleteModifier(id uuid.UUID) error
+	GetAllModifiers(ctx context.Context) (*response.Page[*response.ModifierResponse], error)
+	GetModifierByID(ctx context.Context, id uuid.UUID) (*response.ModifierResponse, error)
+	CreateModifier(ctx context.Context, req *request.ModifierRequest) (*response.ModifierResponse, error)
+	UpdateModifier(ctx context.Context, id uuid.UUID, req *request.ModifierRequest) (*response.ModifierResponse, error)
+	DeleteModifier(ctx context.Context, id uuid.UUID) error
 }
 
 type ModifierRepository interface {
-	GetAllModifiers() ([]*models.Modifier, error)
-	GetModifierByID(id uuid.UUID) (*models.Modifier, error)
-	CreateModifier(modifier *models.Modifier) error
-	UpdateModifier(modifier *models.Modifier) error
-	DeleteModifier(id uuid.UUID) error
+	GetAllModifiers(ctx context.Context) ([]*models.Modifier, error)
+	GetModifierByID(ctx context.Context, id uuid.UUID) (*models.Modifier, error)
+	CreateModifier(ctx context.Context, modifier *models.Modifier) error
+	UpdateModifier(ctx context.Context, modifier *models.Modifier) error
+	DeleteModifier(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// ModifierGroup domain - modifiers offered on menu items as choices with
+// selection rules
+type ModifierGroupUsecase interface {
+	GetAllModifierGroups(ctx context.Context) (*response.Page[*response.ModifierGroupResponse], error)
+	GetModifierGroupByID(ctx context.Context, id uuid.UUID) (*response.ModifierGroupResponse, error)
+	CreateModifierGroup(ctx context.Context, req *request.ModifierGroupRequest) (*response.ModifierGroupResponse, error)
+	UpdateModifierGroup(ctx context.Context, id uuid.UUID, req *request.ModifierGroupRequest) (*response.ModifierGroupResponse, error)
+	DeleteModifierGroup(ctx context.Context, id uuid.UUID) error
+}
+
+type ModifierGroupRepository interface {
+	// GetAllModifierGroups loads every group with its options, their
+	// modifiers and its links, by sort order then name
+	GetAllModifierGroups(ctx context.Context) ([]*models.ModifierGroup, error)
+	GetModifierGroupByID(ctx context.Context, id uuid.UUID) (*models.ModifierGroup, error)
+	// CreateModifierGroup creates a group with its options and links
+	CreateModifierGroup(ctx context.Context, group *models.ModifierGroup) error
+	// UpdateModifierGroup saves a group and replaces its options and links
+	UpdateModifierGroup(ctx context.Context, group *models.ModifierGroup) error
+	DeleteModifierGroup(ctx context.Context, id uuid.UUID) error
+}
+
+// MenuItemModifierGroups returns the groups a menu item offers, those linked to
+// it and to its category, by sort order then name. The links must be loaded
+// with their groups, options and modifiers.
+func MenuItemModifierGroups(menuItem *models.MenuItem) []*models.ModifierGroup {
+	var groups []*models.ModifierGroup
+	add := func(group *models.ModifierGroup) {
+		if group != nil && !slices.ContainsFunc(groups, func(g *models.ModifierGroup) bool { return g.ID == group.ID }) {
+			groups = append(groups, group)
+		}
+	}
+	for _, link := range menuItem.ModifierGroups {
+		add(link.ModifierGroup)
+	}
+	if menuItem.Category != nil {
+		for _, link := range menuItem.Category.ModifierGroups {
+			add(link.ModifierGroup)
+		}
+	}
+	slices.SortFunc(groups, func(a, b *models.ModifierGroup) int {
+		return cmp.Or(cmp.Compare(a.SortOrder, b.SortOrder), cmp.Compare(groupName(a), groupName(b)))
+	})
+	return groups
+}
+
+// SelectModifiers checks the modifiers chosen for a menu item against its
+// groups and returns them in the groups' order. A modifier offered by several
+// groups counts towards one of them, picked so that every group's limits hold
+// if they can. Choosing nothing at all (nil) chooses the groups' defaults.
+func SelectModifiers(groups []*models.ModifierGroup, modifierIDs []uuid.UUID) ([]*models.Modifier, error) {
+	chosen := make(map[uuid.UUID]bool, len(modifierIDs))
+	for _, id := range modifierIDs {
+		if chosen[id] {
+			return nil, ValidationError("A modifier is chosen twice", map[string]string{"modifier_ids": fmt.Sprintf("%s is chosen twice", id)})
+		}
+		chosen[id] = true
+	}
+	if modifierIDs == nil {
+		for _, group := range groups {
+			for _, option := range group.Options {
+				if option.IsDefault && !chosen[option.ModifierID] {
+					chosen[option.ModifierID] = true
+					modifierIDs = append(modifierIDs, option.ModifierID)
+				}
+			}
+		}
+	}
+
+	// offers lists the groups each chosen modifier can count towards
+	offers := make(map[uuid.UUID][]int, len(chosen))
+	for i, group := range groups {
+		for _, option := range group.Options {
+			id := option.ModifierID
+			if chosen[id] && option.Modifier != nil && !slices.Contains(offers[id], i) {
+				offers[id] = append(offers[id], i)
+			}
+		}
+	}
+	for _, id := range modifierIDs {
+		if len(offers[id]) == 0 {
+			return nil, ValidationError("Modifier is not offered on this menu item", map[string]string{"modifier_ids": fmt.Sprintf("%s is not offered", id)})
+		}
+	}
+
+	// Fill every group's minimum first, then place the rest within the
+	// maximums. Either way a modifier may move to another group of its own to
+	// make room, so no group already placed loses a selection.
+	a := assignment{offers: offers, members: make([][]uuid.UUID, len(groups)), group: make(map[uuid.UUID]int, len(chosen))}
+	belowMin := func(i int) bool { return len(a.members[i]) < groups[i].MinSelections }
+	belowMax := func(i int) bool {
+		return groups[i].MaxSelections == nil || len(a.members[i]) < *groups[i].MaxSelections
+	}
+	for _, id := range modifierIDs {
+		a.place(id, belowMin, make([]bool, len(groups)))
+	}
+	for i, group := range groups {
+		if len(a.members[i]) < group.MinSelections {
+			name := groupName(group)
+			return nil, ValidationError(fmt.Sprintf("Choose at least %d of %s", group.MinSelections, name), map[string]string{"modifier_ids": "too few chosen from " + name})
+		}
+	}
+	for _, id := range modifierIDs {
+		if _, ok := a.group[id]; ok {
+			continue
+		}
+		if !a.place(id, belowMax, make([]bool, len(groups))) {
+			group := groups[offers[id][0]]
+			name := groupName(group)
+			return nil, ValidationError(fmt.Sprintf("Choose at most %d of %s", *group.MaxSelections, name), map[string]string{"modifier_ids": "too many chosen from " + name})
+		}
+	}
+
+	var modifiers []*models.Modifier
+	for i, group := range groups {
+		for _, option := range group.Options {
+			if g, ok := a.group[option.ModifierID]; ok && g == i {
+				modifiers = append(modifiers, option.Modifier)
+			}
+		}
+	}
+	return modifiers, nil
+}
+
+// assignment tracks which group each chosen modifier counts towards
+type assignment struct {
+	offers  map[uuid.UUID][]int
+	members [][]uuid.UUID
+	group   map[uuid.UUID]int
+}
+
+// place puts a modifier in one of its groups with room, or moves a modifier out
+// of a full one into another with room to make it, visiting each group once
+func (a *assignment) place(id uuid.UUID, room func(int) bool, visited []bool) bool {
+	for _, i := range a.offers[id] {
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+		if room(i) {
+			a.move(id, i)
+			return true
+		}
+		for _, other := range a.members[i] {
+			if a.place(other, room, visited) {
+				a.move(id, i)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// move takes a modifier out of its group, if any, and puts it in group i
+func (a *assignment) move(id uuid.UUID, i int) {
+	if from, ok := a.group[id]; ok {
+		a.members[from] = slices.DeleteFunc(a.members[from], func(other uuid.UUID) bool { return other == id })
+	}
+	a.group[id] = i
+	a.members[i] = append(a.members[i], id)
+}
+
+func groupName(group *models.ModifierGroup) string {
+	if group.Name == nil {
+		return ""
+	}
+	return *group.Name
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
@@ -9,33 +12,55 @@ import (
 
 // Order domain - manages customer orders and order items
 type OrderUsecase interface {
-	GetAllOrders() ([]*response.OrderResponse, error)
-	GetOrderByID(id uuid.UUID) (*response.OrderResponse, error)
-	GetOrdersByTable(tableID uuid.UUID) ([]*response.OrderResponse, error)
-	GetOpenOrders() ([]*response.OrderResponse, error)
-	CreateOrder(req *request.OrderCreateRequest) (*response.OrderResponse, error)
-	AddItemToOrder(orderID uuid.UUID, req *request.AddOrderItemRequest) (*response.OrderResponse, error)
-	RemoveItemFromOrder(orderID uuid.UUID, itemID uuid.UUID) (*response.OrderResponse, error)
-	UpdateOrderItemQuantity(orderID uuid.UUID, itemID uuid.UUID, quantity int) (*response.OrderResponse, error)
-	CloseOrder(id uuid.UUID) (*response.OrderResponse, error)
-	VoidOrder(id uuid.UUID) error
+	GetAllOrders(ctx context.Context, query *request.OrderListQuery) (*response.Page[*response.OrderSummaryResponse], error)
+	GetOrderByID(ctx context.Context, id uuid.UUID) (*response.OrderResponse, error)
+	GetOrdersByTable(ctx context.Context, tableID uuid.UUID, query *request.OrderListQuery) (*response.Page[*response.OrderSummaryResponse], error)
+	GetOpenOrders(ctx context.Context, query *request.OrderListQuery) (*response.Page[*response.OrderSummaryResponse], error)
+	CreateOrder(ctx context.Context, req *request.OrderCreateRequest) (*response.OrderResponse, error)
+	AddItemToOrder(ctx context.Context, orderID uuid.UUID, req *request.AddOrderItemRequest) (*response.OrderResponse, error)
+	CancelOrderItem(ctx context.Context, orderID uuid.UUID, itemID uuid.UUID, req *request.CancelOrderItemRequest, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error)
+	UpdateOrderItemQuantity(ctx context.Context, orderID uuid.UUID, itemID uuid.UUID, quantity int, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error)
+	SendOrderToKitchen(ctx context.Context, id uuid.UUID) (*response.OrderResponse, error)
+	ApplyDiscount(ctx context.Context, id uuid.UUID, req *request.ApplyDiscountRequest, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error)
+	CloseOrder(ctx context.Context, id uuid.UUID) (*response.OrderResponse, error)
+	ReopenOrder(ctx context.Context, id uuid.UUID, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error)
+	VoidOrder(ctx context.Context, id uuid.UUID, req *request.VoidOrderRequest, actorID uuid.UUID, overrideToken string) error
+	GetVoidReport(ctx context.Context, req *request.VoidReportQuery) (*response.VoidReportResponse, error)
+	GetVoids(ctx context.Context, query *request.VoidListQuery) (*response.Page[*response.VoidResponse], error)
+	CountOpenOrdersByArea(ctx context.Context) (map[string]int64, error)
 }
 
 type OrderRepository interface {
-	GetAllOrders() ([]*models.Order, error)
-	GetOrderByID(id uuid.UUID) (*models.Order, error)
-	GetOrderWithItems(id uuid.UUID) (*models.Order, error)
-	GetOrdersByTable(tableID uuid.UUID) ([]*models.Order, error)
-	GetOrdersByStatus(status string) ([]*models.Order, error)
-	CreateOrder(order *models.Order) error
-	UpdateOrder(order *models.Order) error
-	CreateOrderItem(item *models.OrderItem) error
-	UpdateOrderItem(item *models.OrderItem) error
-	DeleteOrderItem(id uuid.UUID) error
-	GetOrderItemByID(id uuid.UUID) (*models.OrderItem, error)
-	GetMenuItemByID(id uuid.UUID) (*models.MenuItem, error)
-	GetModifierByID(id uuid.UUID) (*models.Modifier, error)
-	CreateOrderItemModifier(modifier *models.OrderItemModifier) error
-	DeleteOrderItemModifiers(orderItemID uuid.UUID) error
-	GetTableByID(id uuid.UUID) (*models.DiningTable, error)
+	// GetOrderSummaries loads one page of the orders matching query as summaries
+	GetOrderSummaries(ctx context.Context, query *request.OrderListQuery, page PageParams) (Page[models.OrderSummary], error)
+	// GetItemsOfOrders loads the items of the orders with their menu item and modifiers
+	GetItemsOfOrders(ctx context.Context, orderIDs []uuid.UUID) ([]*models.OrderItem, error)
+	GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error)
+	// GetOrderForUpdate loads an order and locks it until the transaction ends,
+	// so changes computed from it cannot overwrite a concurrent one
+	GetOrderForUpdate(ctx context.Context, id uuid.UUID) (*models.Order, error)
+	GetOrderWithItems(ctx context.Context, id uuid.UUID) (*models.Order, error)
+	CreateOrder(ctx context.Context, order *models.Order) error
+	UpdateOrder(ctx context.Context, order *models.Order) error
+	CreateOrderItem(ctx context.Context, item *models.OrderItem) error
+	UpdateOrderItem(ctx context.Context, item *models.OrderItem) error
+	MarkOrderItemsSent(ctx context.Context, orderID uuid.UUID, sentAt time.Time) error
+	GetOrderItemByID(ctx context.Context, id uuid.UUID) (*models.OrderItem, error)
+	// GetOrderItemForUpdate loads an order item like GetOrderItemByID and locks
+	// it until the transaction ends
+	GetOrderItemForUpdate(ctx context.Context, id uuid.UUID) (*models.OrderItem, error)
+	// GetMenuItemByID loads a menu item with its category's schedules and
+	// modifier groups, its own and its availability
+	GetMenuItemByID(ctx context.Context, id uuid.UUID) (*models.MenuItem, error)
+	// AdjustPortions adds delta to the portions left of a menu item, reporting
+	// whether its portions are counted at all. Taking more than are left fails
+	// and changes nothing.
+	AdjustPortions(ctx context.Context, menuItemID uuid.UUID, delta int) (bool, error)
+	CreateOrderItemModifier(ctx context.Context, modifier *models.OrderItemModifier) error
+	GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error)
+	GetVoidReasonByCode(ctx context.Context, code string) (*models.VoidReason, error)
+	GetVoidTotals(ctx context.Context, from time.Time, to time.Time) ([]*models.VoidTotal, error)
+	// GetVoids loads one page of the voided orders and cancelled items matching filter
+	GetVoids(ctx context.Context, filter *request.VoidFilter, page PageParams) (Page[models.Void], error)
+	CountOpenOrdersByArea(ctx context.Context) ([]*models.AreaOrderCount, error)
 }
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// Override domain - manager approvals for sensitive order actions
+type OverrideUsecase interface {
+	IssueOverride(ctx context.Context, requestedBy uuid.UUID, clientIP string, req *request.OverrideRequest) (*response.OverrideResponse, error)
+	ConsumeOverride(ctx context.Context, token string, action string, orderID uuid.UUID, orderItemID *uuid.UUID, actorID uuid.UUID) (*models.ManagerOverride, error)
+	GetOverridesByOrder(ctx context.Context, orderID uuid.UUID) (*response.Page[*response.OverrideResponse], error)
+}
+
+type OverrideRepository interface {
+	CreateOverride(ctx context.Context, override *models.ManagerOverride) error
+	GetOverrideByToken(ctx context.Context, token string) (*models.ManagerOverride, error)
+	MarkOverrideUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	GetOverridesByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.ManagerOverride, error)
+	GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error)
+	GetOrderItemByID(ctx context.Context, id uuid.UUID) (*models.OrderItem, error)
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// PageParams selects one page of a sorted collection. Usecases build it with
+// pagination.Parse and repositories load the page with pagination.Find.
+type PageParams struct {
+	// Sort is the field to order by, without its direction
+	Sort  string
+	Desc  bool
+	Limit int
+	// After is the cursor the page starts after, nil for the first page
+	After *PageCursor
+}
+
+// PageCursor points at the last row of a page
+type PageCursor struct {
+	Sort  string          `json:"s"`
+	Desc  bool            `json:"d,omitempty"`
+	Value json.RawMessage `json:"v"`
+	ID    uuid.UUID       `json:"id"`
+}
+
+// Page is one page of rows; NextCursor is nil on the last page
+type Page[T any] struct {
+	Items      []*T
+	NextCursor *string
+}
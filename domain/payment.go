@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
@@ -9,19 +11,19 @@ import (
 
 // Payment domain - manages order payments
 type PaymentUsecase interface {
-	GetAllPayments() ([]*response.PaymentResponse, error)
-	GetPaymentByID(id uuid.UUID) (*response.PaymentResponse, error)
-	GetPaymentsByOrder(orderID uuid.UUID) ([]*response.PaymentResponse, error)
-	ProcessPayment(req *request.PaymentRequest) (*response.PaymentResponse, error)
-	GetPaymentMethods() ([]*response.PaymentMethodResponse, error)
+	GetAllPayments(ctx context.Context, query *request.PaymentListQuery) (*response.Page[*response.PaymentResponse], error)
+	GetPaymentByID(ctx context.Context, id uuid.UUID) (*response.PaymentResponse, error)
+	GetPaymentsByOrder(ctx context.Context, orderID uuid.UUID, query *request.PaymentListQuery) (*response.Page[*response.PaymentResponse], error)
+	ProcessPayment(ctx context.Context, req *request.PaymentRequest) (*response.PaymentResponse, error)
+	GetPaymentMethods(ctx context.Context) (*response.Page[*response.PaymentMethodResponse], error)
 }
 
 type PaymentRepository interface {
-	GetAllPayments() ([]*models.Payment, error)
-	GetPaymentByID(id uuid.UUID) (*models.Payment, error)
-	GetPaymentsByOrder(orderID uuid.UUID) ([]*models.Payment, error)
-	CreatePayment(payment *models.Payment) error
-	UpdatePayment(payment *models.Payment) error
-	GetTotalPaidForOrder(orderID uuid.UUID) (int64, error)
-	GetOrderByID(id uuid.UUID) (*models.Order, error)
+	// GetPayments loads one page of the payments matching query, with their order
+	GetPayments(ctx context.Context, query *request.PaymentListQuery, page PageParams) (Page[models.Payment], error)
+	GetPaymentByID(ctx context.Context, id uuid.UUID) (*models.Payment, error)
+	CreatePayment(ctx context.Context, payment *models.Payment) error
+	UpdatePayment(ctx context.Context, payment *models.Payment) error
+	GetTotalPaidForOrder(ctx context.Context, orderID uuid.UUID) (int64, error)
+	GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error)
 }
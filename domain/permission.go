@@ -1,15 +1,17 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/response"
 )
 
 // Permission domain - manages access permissions (mostly read-only, permissions are seeded)
 type PermissionUsecase interface {
-	GetAllPermissions() ([]*response.PermissionResponse, error)
+	GetAllPermissions(ctx context.Context) (*response.Page[*response.PermissionResponse], error)
 }
 
 type PermissionRepository interface {
-	GetAllPermissions() ([]*models.Permission, error)
+	GetAllPermissions(ctx context.Context) ([]*models.Permission, error)
 }
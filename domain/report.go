@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// Report domain - summarises paid orders over a date range
+type ReportUsecase interface {
+	GetSalesReport(ctx context.Context, req *request.SalesReportQuery) (*response.SalesReportResponse, error)
+}
+
+type ReportRepository interface {
+	// GetSalesTotals sums the paid orders of the business dates from through to
+	// by one of the constant.SalesBy groups, or into a single row when by is
+	// empty. Hours are keyed by the UTC hour the order was paid in, as
+	// "2006-01-02 15".
+	GetSalesTotals(ctx context.Context, by string, from time.Time, to time.Time) ([]*models.SalesTotal, error)
+}
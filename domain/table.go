@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/response"
@@ -8,13 +10,13 @@ import (
 
 // Table domain - manages dining tables
 type TableUsecase interface {
-	GetAllTables() ([]*response.TableResponse, error)
-	GetTableByID(id uuid.UUID) (*response.TableResponse, error)
-	UpdateTableStatus(id uuid.UUID, status string) error
+	GetAllTables(ctx context.Context) (*response.Page[*response.TableResponse], error)
+	GetTableByID(ctx context.Context, id uuid.UUID) (*response.TableResponse, error)
+	UpdateTableStatus(ctx context.Context, id uuid.UUID, status string) error
 }
 
 type TableRepository interface {
-	GetAllTables() ([]*models.DiningTable, error)
-	GetTableByID(id uuid.UUID) (*models.DiningTable, error)
-	UpdateTable(table *models.DiningTable) error
+	GetAllTables(ctx context.Context) ([]*models.DiningTable, error)
+	GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error)
+	UpdateTable(ctx context.Context, table *models.DiningTable) error
 }
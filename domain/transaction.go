@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// Transactor runs fn inside one database transaction. Repository calls made with the
+// ctx handed to fn take part in that transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
@@ -9,18 +11,18 @@ import (
 
 // User domain - manages staff/employee users
 type UserUsecase interface {
-	GetAllUsers() ([]*response.UserResponse, error)
-	GetUserByID(id uuid.UUID) (*response.UserResponse, error)
-	CreateUser(req *request.UserCreateRequest) (*response.UserResponse, error)
-	UpdateUser(id uuid.UUID, req *request.UserUpdateRequest) (*response.UserResponse, error)
-	AssignRoleToUser(userID uuid.UUID, roleID int) error
+	GetAllUsers(ctx context.Context, query *request.UserListQuery) (*response.Page[*response.UserResponse], error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*response.UserResponse, error)
+	CreateUser(ctx context.Context, req *request.UserCreateRequest) (*response.UserResponse, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, req *request.UserUpdateRequest) (*response.UserResponse, error)
+	AssignRoleToUser(ctx context.Context, userID uuid.UUID, roleID int) error
 }
 
 type UserRepository interface {
-	GetAllUsers() ([]*models.User, error)
-	GetUserByID(id uuid.UUID) (*models.User, error)
-	CreateUser(user *models.User) error
-	UpdateUser(user *models.User) error
-	GetUserWithRoles(id uuid.UUID) (*models.User, error)
-	AssignRole(userRole *models.UserRole) error
+	GetUsers(ctx context.Context, query *request.UserListQuery, page PageParams) (Page[models.User], error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	UpdateUser(ctx context.Context, user *models.User) error
+	GetUserWithRoles(ctx context.Context, id uuid.UUID) (*models.User, error)
+	AssignRole(ctx context.Context, userRole *models.UserRole) error
 }
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// VoidReason domain - manages the reason codes offered for voids and item cancellations
+type VoidReasonUsecase interface {
+	GetAllVoidReasons(ctx context.Context) (*response.Page[*response.VoidReasonResponse], error)
+	CreateVoidReason(ctx context.Context, req *request.VoidReasonRequest) (*response.VoidReasonResponse, error)
+	UpdateVoidReason(ctx context.Context, id uuid.UUID, req *request.VoidReasonRequest) (*response.VoidReasonResponse, error)
+}
+
+type VoidReasonRepository interface {
+	GetAllVoidReasons(ctx context.Context) ([]*models.VoidReason, error)
+	GetVoidReasonByID(ctx context.Context, id uuid.UUID) (*models.VoidReason, error)
+	CreateVoidReason(ctx context.Context, reason *models.VoidReason) error
+	UpdateVoidReason(ctx context.Context, reason *models.VoidReason) error
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type areaHandler struct {
@@ -21,11 +21,9 @@ func NewAreaHandler(areaUsecase domain.AreaUsecase) *areaHandler {
 }
 
 func (h *areaHandler) GetAllAreas(c *gin.Context) {
-	areas, err := h.areaUsecase.GetAllAreas()
+	areas, err := h.areaUsecase.GetAllAreas(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[AreaHandler.GetAllAreas]: Error getting areas")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[AreaHandler.GetAllAreas]: Error getting areas"))
 		return
 	}
 	c.JSON(http.StatusOK, areas)
@@ -34,15 +32,13 @@ func (h *areaHandler) GetAllAreas(c *gin.Context) {
 func (h *areaHandler) GetAreaByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid area ID", nil))
 		return
 	}
 
-	area, err := h.areaUsecase.GetAreaByID(id)
+	area, err := h.areaUsecase.GetAreaByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[AreaHandler.GetAreaByID]: Error getting area")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[AreaHandler.GetAreaByID]: Error getting area"))
 		return
 	}
 	c.JSON(http.StatusOK, area)
@@ -51,15 +47,13 @@ func (h *areaHandler) GetAreaByID(c *gin.Context) {
 func (h *areaHandler) CreateArea(c *gin.Context) {
 	var req request.AreaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	area, err := h.areaUsecase.CreateArea(&req)
+	area, err := h.areaUsecase.CreateArea(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[AreaHandler.CreateArea]: Error creating area")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[AreaHandler.CreateArea]: Error creating area"))
 		return
 	}
 	c.JSON(http.StatusCreated, area)
@@ -68,21 +62,19 @@ func (h *areaHandler) CreateArea(c *gin.Context) {
 func (h *areaHandler) UpdateArea(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid area ID", nil))
 		return
 	}
 
 	var req request.AreaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	area, err := h.areaUsecase.UpdateArea(id, &req)
+	area, err := h.areaUsecase.UpdateArea(c.Request.Context(), id, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[AreaHandler.UpdateArea]: Error updating area")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[AreaHandler.UpdateArea]: Error updating area"))
 		return
 	}
 	c.JSON(http.StatusOK, area)
@@ -91,15 +83,13 @@ func (h *areaHandler) UpdateArea(c *gin.Context) {
 func (h *areaHandler) DeleteArea(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid area ID", nil))
 		return
 	}
 
-	if err := h.areaUsecase.DeleteArea(id); err != nil {
-		err = errors.Wrap(err, "[AreaHandler.DeleteArea]: Error deleting area")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+	if err := h.areaUsecase.DeleteArea(c.Request.Context(), id); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[AreaHandler.DeleteArea]: Error deleting area"))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Area deleted successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Area deleted successfully"})
 }
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type areaMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewAreaMemoryRepository(store *memory.Store) domain.AreaRepository {
+	return &areaMemoryRepository{store: store}
+}
+
+func (r *areaMemoryRepository) GetAllAreas(ctx context.Context) ([]*models.Area, error) {
+	var areas []*models.Area
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		areas = memory.Select(t.Areas, nil)
+		memory.Sort(areas, func(a, b *models.Area) int { return memory.CompareString(a.Name, b.Name) })
+		return nil
+	})
+	return areas, err
+}
+
+func (r *areaMemoryRepository) GetAreaByID(ctx context.Context, id uuid.UUID) (*models.Area, error) {
+	var area models.Area
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Areas[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Area not found"), "[AreaMemoryRepository.GetAreaByID]")
+		}
+		area = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &area, nil
+}
+
+func (r *areaMemoryRepository) CreateArea(ctx context.Context, area *models.Area) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if area.ID == uuid.Nil {
+			area.ID = uuid.New()
+		}
+		return r.save(t, area, "[AreaMemoryRepository.CreateArea]")
+	})
+}
+
+func (r *areaMemoryRepository) UpdateArea(ctx context.Context, area *models.Area) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, area, "[AreaMemoryRepository.UpdateArea]")
+	})
+}
+
+func (r *areaMemoryRepository) save(t *memory.Tables, area *models.Area, op string) error {
+	if memory.Any(t.Areas, func(other *models.Area) bool {
+		return other.ID != area.ID && memory.EqualString(other.Name, area.Name)
+	}) {
+		return errors.Wrap(domain.ConflictError("An area with this name already exists"), op)
+	}
+
+	row := *area
+	row.Tables = nil
+	t.Areas[row.ID] = row
+	return nil
+}
+
+// DeleteArea refuses to delete an area menu schedules are limited to, like ON
+// DELETE RESTRICT, and takes its tables out of it, like ON DELETE SET NULL
+func (r *areaMemoryRepository) DeleteArea(ctx context.Context, id uuid.UUID) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.MenuSchedules, func(s *models.MenuSchedule) bool { return s.AreaID != nil && *s.AreaID == id }) {
+			return errors.Wrap(domain.ConflictError("Area is used by menu schedules"), "[AreaMemoryRepository.DeleteArea]")
+		}
+		delete(t.Areas, id)
+		for tableID, table := range t.DiningTables {
+			if table.AreaID != nil && *table.AreaID == id {
+				table.AreaID = nil
+				t.DiningTables[tableID] = table
+			}
+		}
+		return nil
+	})
+}
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"gorm.io/gorm"
@@ -16,41 +19,50 @@ func NewAreaRepository(db *gorm.DB) domain.AreaRepository {
 	return &areaRepository{db: db}
 }
 
-func (r *areaRepository) GetAllAreas() ([]*models.Area, error) {
+func (r *areaRepository) GetAllAreas(ctx context.Context) ([]*models.Area, error) {
 	var areas []*models.Area
-	if err := r.db.Order("name ASC").Find(&areas).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Order("name ASC").Find(&areas).Error; err != nil {
 		return nil, errors.Wrap(err, "[AreaRepository.GetAllAreas]: Error querying database")
 	}
 	return areas, nil
 }
 
-func (r *areaRepository) GetAreaByID(id uuid.UUID) (*models.Area, error) {
+func (r *areaRepository) GetAreaByID(ctx context.Context, id uuid.UUID) (*models.Area, error) {
 	var area models.Area
-	if err := r.db.Where("id = ?", id).First(&area).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&area).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[AreaRepository.GetAreaByID]: Area not found")
+			return nil, errors.Wrap(domain.NotFoundError("Area not found"), "[AreaRepository.GetAreaByID]")
 		}
 		return nil, errors.Wrap(err, "[AreaRepository.GetAreaByID]: Error querying database")
 	}
 	return &area, nil
 }
 
-func (r *areaRepository) CreateArea(area *models.Area) error {
-	if err := r.db.Create(area).Error; err != nil {
+func (r *areaRepository) CreateArea(ctx context.Context, area *models.Area) error {
+	if err := database.Conn(ctx, r.db).Create(area).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("An area with this name already exists"), "[AreaRepository.CreateArea]")
+		}
 		return errors.Wrap(err, "[AreaRepository.CreateArea]: Error creating area")
 	}
 	return nil
 }
 
-func (r *areaRepository) UpdateArea(area *models.Area) error {
-	if err := r.db.Save(area).Error; err != nil {
+func (r *areaRepository) UpdateArea(ctx context.Context, area *models.Area) error {
+	if err := database.Conn(ctx, r.db).Save(area).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("An area with this name already exists"), "[AreaRepository.UpdateArea]")
+		}
 		return errors.Wrap(err, "[AreaRepository.UpdateArea]: Error updating area")
 	}
 	return nil
 }
 
-func (r *areaRepository) DeleteArea(id uuid.UUID) error {
-	if err := r.db.Where("id = ?", id).Delete(&models.Area{}).Error; err != nil {
+func (r *areaRepository) DeleteArea(ctx context.Context, id uuid.UUID) error {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).Delete(&models.Area{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return errors.Wrap(domain.ConflictError("Area is used by menu schedules"), "[AreaRepository.DeleteArea]")
+		}
 		return errors.Wrap(err, "[AreaRepository.DeleteArea]: Error deleting area")
 	}
 	return nil
@@ -1,25 +1,34 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
 type areaUsecase struct {
 	areaRepository domain.AreaRepository
+	transactor     domain.Transactor
+	auditUsecase   domain.AuditUsecase
 }
 
-func NewAreaUsecase(areaRepository domain.AreaRepository) domain.AreaUsecase {
-	return &areaUsecase{areaRepository: areaRepository}
+func NewAreaUsecase(areaRepository domain.AreaRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.AreaUsecase {
+	return &areaUsecase{areaRepository: areaRepository, transactor: transactor, auditUsecase: auditUsecase}
 }
 
-func (u *areaUsecase) GetAllAreas() ([]*response.AreaResponse, error) {
-	areas, err := u.areaRepository.GetAllAreas()
+func (u *areaUsecase) GetAllAreas(ctx context.Context) (*response.Page[*response.AreaResponse], error) {
+	ctx, span := tracing.Start(ctx, "AreaUsecase.GetAllAreas")
+	defer span.End()
+
+	areas, err := u.areaRepository.GetAllAreas(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "[AreaUsecase.GetAllAreas]: Error getting areas")
 	}
@@ -32,11 +41,14 @@ func (u *areaUsecase) GetAllAreas() ([]*response.AreaResponse, error) {
 		}
 	}
 
-	return areaResponses, nil
+	return response.SinglePage(areaResponses), nil
 }
 
-func (u *areaUsecase) GetAreaByID(id uuid.UUID) (*response.AreaResponse, error) {
-	area, err := u.areaRepository.GetAreaByID(id)
+func (u *areaUsecase) GetAreaByID(ctx context.Context, id uuid.UUID) (*response.AreaResponse, error) {
+	ctx, span := tracing.Start(ctx, "AreaUsecase.GetAreaByID")
+	defer span.End()
+
+	area, err := u.areaRepository.GetAreaByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[AreaUsecase.GetAreaByID]: Error getting area")
 	}
@@ -47,51 +59,98 @@ func (u *areaUsecase) GetAreaByID(id uuid.UUID) (*response.AreaResponse, error)
 	}, nil
 }
 
-func (u *areaUsecase) CreateArea(req *request.AreaRequest) (*response.AreaResponse, error) {
+func (u *areaUsecase) CreateArea(ctx context.Context, req *request.AreaRequest) (*response.AreaResponse, error) {
+	ctx, span := tracing.Start(ctx, "AreaUsecase.CreateArea")
+	defer span.End()
+
 	area := &models.Area{
 		Name: &req.Name,
 	}
 
-	if err := u.areaRepository.CreateArea(area); err != nil {
-		return nil, errors.Wrap(err, "[AreaUsecase.CreateArea]: Error creating area")
+	var areaResponse *response.AreaResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.areaRepository.CreateArea(ctx, area); err != nil {
+			return errors.Wrap(err, "[AreaUsecase.CreateArea]: Error creating area")
+		}
+
+		areaResponse = &response.AreaResponse{
+			ID:   area.ID,
+			Name: req.Name,
+		}
+
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityArea, area.ID.String(), nil, areaResponse); err != nil {
+			return errors.Wrap(err, "[AreaUsecase.CreateArea]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.AreaResponse{
-		ID:   area.ID,
-		Name: req.Name,
-	}, nil
+	return areaResponse, nil
 }
 
-func (u *areaUsecase) UpdateArea(id uuid.UUID, req *request.AreaRequest) (*response.AreaResponse, error) {
+func (u *areaUsecase) UpdateArea(ctx context.Context, id uuid.UUID, req *request.AreaRequest) (*response.AreaResponse, error) {
+	ctx, span := tracing.Start(ctx, "AreaUsecase.UpdateArea")
+	defer span.End()
+
 	// Get existing area
-	area, err := u.areaRepository.GetAreaByID(id)
+	area, err := u.areaRepository.GetAreaByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[AreaUsecase.UpdateArea]: Area not found")
 	}
 
+	before := &response.AreaResponse{
+		ID:   area.ID,
+		Name: utils.DerefString(area.Name),
+	}
+
 	// Update fields
 	area.Name = &req.Name
 
-	if err := u.areaRepository.UpdateArea(area); err != nil {
-		return nil, errors.Wrap(err, "[AreaUsecase.UpdateArea]: Error updating area")
-	}
-
-	return &response.AreaResponse{
+	areaResponse := &response.AreaResponse{
 		ID:   area.ID,
 		Name: req.Name,
-	}, nil
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.areaRepository.UpdateArea(ctx, area); err != nil {
+			return errors.Wrap(err, "[AreaUsecase.UpdateArea]: Error updating area")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityArea, area.ID.String(), before, areaResponse); err != nil {
+			return errors.Wrap(err, "[AreaUsecase.UpdateArea]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return areaResponse, nil
 }
 
-func (u *areaUsecase) DeleteArea(id uuid.UUID) error {
+func (u *areaUsecase) DeleteArea(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "AreaUsecase.DeleteArea")
+	defer span.End()
+
 	// Check if area exists
-	_, err := u.areaRepository.GetAreaByID(id)
+	area, err := u.areaRepository.GetAreaByID(ctx, id)
 	if err != nil {
 		return errors.Wrap(err, "[AreaUsecase.DeleteArea]: Area not found")
 	}
 
-	if err := u.areaRepository.DeleteArea(id); err != nil {
-		return errors.Wrap(err, "[AreaUsecase.DeleteArea]: Error deleting area")
+	before := &response.AreaResponse{
+		ID:   area.ID,
+		Name: utils.DerefString(area.Name),
 	}
 
-	return nil
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.areaRepository.DeleteArea(ctx, id); err != nil {
+			return errors.Wrap(err, "[AreaUsecase.DeleteArea]: Error deleting area")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionDelete, constant.AuditEntityArea, id.String(), before, nil); err != nil {
+			return errors.Wrap(err, "[AreaUsecase.DeleteArea]: Error recording audit log")
+		}
+		return nil
+	})
 }
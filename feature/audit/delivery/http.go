@@ -0,0 +1,34 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type auditHandler struct {
+	auditUsecase domain.AuditUsecase
+}
+
+func NewAuditHandler(auditUsecase domain.AuditUsecase) *auditHandler {
+	return &auditHandler{auditUsecase: auditUsecase}
+}
+
+func (h *auditHandler) GetAuditLogs(c *gin.Context) {
+	var req request.AuditLogQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	entries, err := h.auditUsecase.GetAuditLogs(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[AuditHandler.GetAuditLogs]: Error getting audit logs"))
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
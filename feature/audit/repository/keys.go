@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+)
+
+// auditLogKeys are the fields audit entries can be sorted by
+var auditLogKeys = pagination.Keys[models.AuditLog]{
+	IDColumn: "id",
+	ID:       func(e *models.AuditLog) uuid.UUID { return e.ID },
+	Sorts: map[string]pagination.Key[models.AuditLog]{
+		"created_at": {Expr: "created_at", Value: func(e *models.AuditLog) any { return e.CreatedAt }},
+	},
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"gorm.io/gorm"
+)
+
+type auditMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewAuditMemoryRepository(store *memory.Store) domain.AuditRepository {
+	return &auditMemoryRepository{store: store}
+}
+
+func (r *auditMemoryRepository) CreateAuditLog(ctx context.Context, entry *models.AuditLog) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if entry.ActorID != nil {
+			if _, ok := t.Users[*entry.ActorID]; !ok {
+				return errors.Wrap(gorm.ErrForeignKeyViolated, "[AuditMemoryRepository.CreateAuditLog]: Error creating audit log")
+			}
+		}
+
+		if entry.ID == uuid.Nil {
+			entry.ID = uuid.New()
+		}
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = time.Now()
+		}
+		row := *entry
+		row.Actor = nil
+		t.AppendAuditLog(row)
+		return nil
+	})
+}
+
+func (r *auditMemoryRepository) GetAuditLogs(ctx context.Context, query *request.AuditLogQuery, page domain.PageParams) (domain.Page[models.AuditLog], error) {
+	var entries domain.Page[models.AuditLog]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		rows := memory.Select(t.AuditLogs, func(e *models.AuditLog) bool {
+			switch {
+			case query.ActorID != "" && (e.ActorID == nil || e.ActorID.String() != query.ActorID):
+				return false
+			case query.Action != "" && e.Action != query.Action:
+				return false
+			case query.EntityType != "" && e.EntityType != query.EntityType:
+				return false
+			case query.EntityID != "" && e.EntityID != query.EntityID:
+				return false
+			case query.From != nil && e.CreatedAt.Before(*query.From):
+				return false
+			case query.To != nil && !e.CreatedAt.Before(*query.To):
+				return false
+			}
+			return true
+		})
+
+		var err error
+		if entries, err = pagination.Slice(rows, page, auditLogKeys); err != nil {
+			return errors.Wrap(err, "[AuditMemoryRepository.GetAuditLogs]")
+		}
+		for _, entry := range entries.Items {
+			if entry.ActorID == nil {
+				continue
+			}
+			if actor, ok := t.Users[*entry.ActorID]; ok {
+				entry.Actor = &actor
+			}
+		}
+		return nil
+	})
+	return entries, err
+}
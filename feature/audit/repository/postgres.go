@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) CreateAuditLog(ctx context.Context, entry *models.AuditLog) error {
+	if err := database.Conn(ctx, r.db).Create(entry).Error; err != nil {
+		return errors.Wrap(err, "[AuditRepository.CreateAuditLog]: Error creating audit log")
+	}
+	return nil
+}
+
+func (r *auditRepository) GetAuditLogs(ctx context.Context, query *request.AuditLogQuery, page domain.PageParams) (domain.Page[models.AuditLog], error) {
+	db := database.Conn(ctx, r.db).Preload("Actor")
+	if query.ActorID != "" {
+		db = db.Where("actor_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.EntityType != "" {
+		db = db.Where("entity_type = ?", query.EntityType)
+	}
+	if query.EntityID != "" {
+		db = db.Where("entity_id = ?", query.EntityID)
+	}
+	if query.From != nil {
+		db = db.Where("created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("created_at < ?", *query.To)
+	}
+
+	entries, err := pagination.Find(db, page, auditLogKeys)
+	if err != nil {
+		return domain.Page[models.AuditLog]{}, errors.Wrap(err, "[AuditRepository.GetAuditLogs]")
+	}
+	return entries, nil
+}
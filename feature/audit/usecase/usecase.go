@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type auditUsecase struct {
+	auditRepository domain.AuditRepository
+}
+
+func NewAuditUsecase(auditRepository domain.AuditRepository) domain.AuditUsecase {
+	return &auditUsecase{auditRepository: auditRepository}
+}
+
+// Record writes an audit entry for the actor in ctx. Call it with the ctx of the
+// surrounding transaction so the entry commits or rolls back with the change.
+func (u *auditUsecase) Record(ctx context.Context, action string, entityType string, entityID string, before any, after any) error {
+	ctx, span := tracing.Start(ctx, "AuditUsecase.Record")
+	defer span.End()
+
+	beforeState, err := snapshot(before)
+	if err != nil {
+		return errors.Wrap(err, "[AuditUsecase.Record]: Error encoding before snapshot")
+	}
+	afterState, err := snapshot(after)
+	if err != nil {
+		return errors.Wrap(err, "[AuditUsecase.Record]: Error encoding after snapshot")
+	}
+
+	actor := domain.ActorFromContext(ctx)
+	entry := &models.AuditLog{
+		ActorID:     actor.UserID,
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		BeforeState: beforeState,
+		AfterState:  afterState,
+	}
+	if actor.ClientIP != "" {
+		entry.ClientIP = &actor.ClientIP
+	}
+	if actor.RequestID != "" {
+		entry.RequestID = &actor.RequestID
+	}
+
+	if err := u.auditRepository.CreateAuditLog(ctx, entry); err != nil {
+		return errors.Wrap(err, "[AuditUsecase.Record]: Error creating audit log")
+	}
+	return nil
+}
+
+func (u *auditUsecase) GetAuditLogs(ctx context.Context, req *request.AuditLogQuery) (*response.Page[*response.AuditLogResponse], error) {
+	ctx, span := tracing.Start(ctx, "AuditUsecase.GetAuditLogs")
+	defer span.End()
+
+	if req.Limit == 0 {
+		req.Limit = constant.AuditLogDefaultLimit
+	}
+
+	page, err := pagination.Parse(req.PageQuery, "", "-created_at")
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuditUsecase.GetAuditLogs]: Invalid page")
+	}
+
+	entries, err := u.auditRepository.GetAuditLogs(ctx, req, page)
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuditUsecase.GetAuditLogs]: Error getting audit logs")
+	}
+
+	auditLogResponses := make([]*response.AuditLogResponse, len(entries.Items))
+	for i, entry := range entries.Items {
+		auditLogResponses[i] = &response.AuditLogResponse{
+			ID:          entry.ID,
+			ActorID:     entry.ActorID,
+			Action:      entry.Action,
+			EntityType:  entry.EntityType,
+			EntityID:    entry.EntityID,
+			BeforeState: rawJSON(entry.BeforeState),
+			AfterState:  rawJSON(entry.AfterState),
+			ClientIP:    utils.DerefString(entry.ClientIP),
+			RequestID:   utils.DerefString(entry.RequestID),
+			CreatedAt:   entry.CreatedAt,
+		}
+		if entry.Actor != nil {
+			auditLogResponses[i].ActorName = utils.DerefString(entry.Actor.FullName)
+		}
+	}
+
+	return &response.Page[*response.AuditLogResponse]{Items: auditLogResponses, NextCursor: entries.NextCursor}, nil
+}
+
+// snapshot encodes v as JSON, leaving the column NULL when there is nothing to record
+func snapshot(v any) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// Typed nil pointers encode as null
+	if string(data) == "null" {
+		return nil, nil
+	}
+	return utils.Ptr(string(data)), nil
+}
+
+func rawJSON(s *string) json.RawMessage {
+	if s == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(*s)
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type authHandler struct {
@@ -23,15 +23,13 @@ func NewAuthHandler(authUsecase domain.AuthUsecase) *authHandler {
 func (h *authHandler) Login(c *gin.Context) {
 	var req request.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	authResponse, err := h.authUsecase.Login(&req)
+	authResponse, err := h.authUsecase.Login(c.Request.Context(), &req, c.ClientIP())
 	if err != nil {
-		err = errors.Wrap(err, "[AuthHandler.Login]: Error logging in")
-		log.Warn(err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[AuthHandler.Login]: Error logging in"))
 		return
 	}
 
@@ -41,7 +39,7 @@ func (h *authHandler) Login(c *gin.Context) {
 func (h *authHandler) Logout(c *gin.Context) {
 	token := c.GetHeader("Authorization")
 	if token == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Authorization header required"})
+		utils.RenderError(c, domain.ValidationError("Authorization header required", nil))
 		return
 	}
 
@@ -50,66 +48,117 @@ func (h *authHandler) Logout(c *gin.Context) {
 		token = token[7:]
 	}
 
-	if err := h.authUsecase.Logout(token); err != nil {
-		err = errors.Wrap(err, "[AuthHandler.Logout]: Error logging out")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+	if err := h.authUsecase.Logout(c.Request.Context(), token); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[AuthHandler.Logout]: Error logging out"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Logged out successfully"})
 }
 
 func (h *authHandler) ChangePassword(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
 		return
 	}
 
 	var req request.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	if err := h.authUsecase.ChangePassword(userID.(uuid.UUID), &req); err != nil {
-		err = errors.Wrap(err, "[AuthHandler.ChangePassword]: Error changing password")
-		log.Warn(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": utils.StandardError(err)})
+	if err := h.authUsecase.ChangePassword(c.Request.Context(), userID.(uuid.UUID), &req); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[AuthHandler.ChangePassword]: Error changing password"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Password changed successfully"})
+}
+
+func (h *authHandler) SetPin(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
+		return
+	}
+
+	var req request.SetPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	if err := h.authUsecase.SetPin(c.Request.Context(), userID.(uuid.UUID), &req); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[AuthHandler.SetPin]: Error setting PIN"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "PIN set successfully"})
 }
 
 func (h *authHandler) GetMe(c *gin.Context) {
 	// Get user from context (set by auth middleware)
 	user, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
 		return
 	}
 
 	// Get user permissions
-	userID := c.GetString("userID")
-	uuid, err := uuid.Parse(userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
 		return
 	}
 
-	permissions, err := h.authUsecase.GetUserPermissions(uuid)
+	permissions, err := h.authUsecase.GetUserPermissions(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		err = errors.Wrap(err, "[AuthHandler.GetMe]: Error getting permissions")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[AuthHandler.GetMe]: Error getting permissions"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user":        user,
-		"permissions": permissions,
+	c.JSON(http.StatusOK, response.MeResponse{
+		User:        user.(response.UserResponse),
+		Permissions: permissions,
 	})
 }
+
+func (h *authHandler) UnlockUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid user ID", nil))
+		return
+	}
+
+	if err := h.authUsecase.UnlockUser(c.Request.Context(), id); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[AuthHandler.UnlockUser]: Error unlocking user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "User unlocked successfully"})
+}
+
+func (h *authHandler) GetLoginHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid user ID", nil))
+		return
+	}
+
+	var req request.LoginHistoryQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	history, err := h.authUsecase.GetLoginHistory(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[AuthHandler.GetLoginHistory]: Error getting login history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
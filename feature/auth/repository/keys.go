@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+)
+
+// loginAttemptKeys are the fields login attempts can be sorted by
+var loginAttemptKeys = pagination.Keys[models.LoginAttempt]{
+	IDColumn: "id",
+	ID:       func(a *models.LoginAttempt) uuid.UUID { return a.ID },
+	Sorts: map[string]pagination.Key[models.LoginAttempt]{
+		"created_at": {Expr: "created_at", Value: func(a *models.LoginAttempt) any { return a.CreatedAt }},
+	},
+}
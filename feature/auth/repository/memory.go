@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"gorm.io/gorm"
+)
+
+type authMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewAuthMemoryRepository(store *memory.Store) domain.AuthRepository {
+	return &authMemoryRepository{store: store}
+}
+
+func (r *authMemoryRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user *models.User
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		users := memory.Select(t.Users, func(u *models.User) bool { return u.Username == username })
+		if len(users) == 0 {
+			return errors.Wrap(domain.NotFoundError("User not found"), "[AuthMemoryRepository.GetUserByUsername]")
+		}
+		user = users[0]
+		return nil
+	})
+	return user, err
+}
+
+func (r *authMemoryRepository) GetUserWithRolesAndPermissions(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Users[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("User not found"), "[AuthMemoryRepository.GetUserWithRolesAndPermissions]")
+		}
+		user = found
+		user.Roles = t.RolesOfUser(id)
+		for i := range user.Roles {
+			user.Roles[i].Permissions = t.PermissionsOfRole(user.Roles[i].ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *authMemoryRepository) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var permissions []string
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		for _, role := range t.RolesOfUser(userID) {
+			for _, permission := range t.PermissionsOfRole(role.ID) {
+				if !slices.Contains(permissions, permission.Code) {
+					permissions = append(permissions, permission.Code)
+				}
+			}
+		}
+		slices.Sort(permissions)
+		return nil
+	})
+	return permissions, err
+}
+
+func (r *authMemoryRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	return r.updateUser(ctx, userID, func(user *models.User) { user.PasswordHash = passwordHash })
+}
+
+func (r *authMemoryRepository) UpdatePin(ctx context.Context, userID uuid.UUID, pinHash string) error {
+	return r.updateUser(ctx, userID, func(user *models.User) { user.PinHash = &pinHash })
+}
+
+func (r *authMemoryRepository) UpdateUserStatus(ctx context.Context, userID uuid.UUID, status string) error {
+	return r.updateUser(ctx, userID, func(user *models.User) { user.Status = &status })
+}
+
+// updateUser changes one column, and like an UPDATE ... WHERE id = ? it is not
+// an error when the user does not exist
+func (r *authMemoryRepository) updateUser(ctx context.Context, userID uuid.UUID, update func(user *models.User)) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		user, ok := t.Users[userID]
+		if !ok {
+			return nil
+		}
+		update(&user)
+		user.UpdatedAt = time.Now()
+		t.Users[userID] = user
+		return nil
+	})
+}
+
+func (r *authMemoryRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.Sessions, func(other *models.Session) bool { return other.Token == session.Token }) {
+			return errors.Wrap(gorm.ErrDuplicatedKey, "[AuthMemoryRepository.CreateSession]: Error creating session")
+		}
+		if _, ok := t.Users[session.UserID]; !ok {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, "[AuthMemoryRepository.CreateSession]: Error creating session")
+		}
+
+		if session.ID == uuid.Nil {
+			session.ID = uuid.New()
+		}
+		if session.CreatedAt.IsZero() {
+			session.CreatedAt = time.Now()
+		}
+		row := *session
+		row.User = nil
+		t.Sessions[row.ID] = row
+		return nil
+	})
+}
+
+func (r *authMemoryRepository) GetSessionByToken(ctx context.Context, token string) (*models.Session, error) {
+	var session *models.Session
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		sessions := memory.Select(t.Sessions, func(s *models.Session) bool { return s.Token == token })
+		if len(sessions) == 0 {
+			return errors.Wrap(domain.NotFoundError("Session not found"), "[AuthMemoryRepository.GetSessionByToken]")
+		}
+		session = sessions[0]
+		if user, ok := t.Users[session.UserID]; ok {
+			session.User = &user
+		}
+		return nil
+	})
+	return session, err
+}
+
+func (r *authMemoryRepository) DeleteSession(ctx context.Context, token string) error {
+	_, err := r.deleteSessions(ctx, func(s *models.Session) bool { return s.Token == token })
+	return err
+}
+
+func (r *authMemoryRepository) DeleteSessionsByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.deleteSessions(ctx, func(s *models.Session) bool { return s.UserID == userID })
+	return err
+}
+
+func (r *authMemoryRepository) CleanupExpiredSessions(ctx context.Context) (int64, error) {
+	now := time.Now()
+	return r.deleteSessions(ctx, func(s *models.Session) bool { return s.ExpiresAt.Before(now) })
+}
+
+func (r *authMemoryRepository) deleteSessions(ctx context.Context, match func(s *models.Session) bool) (int64, error) {
+	var deleted int64
+	err := r.store.Write(ctx, func(t *memory.Tables) error {
+		for id, session := range t.Sessions {
+			if match(&session) {
+				delete(t.Sessions, id)
+				deleted++
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *authMemoryRepository) CreateLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if attempt.UserID != nil {
+			if _, ok := t.Users[*attempt.UserID]; !ok {
+				return errors.Wrap(gorm.ErrForeignKeyViolated, "[AuthMemoryRepository.CreateLoginAttempt]: Error creating login attempt")
+			}
+		}
+
+		if attempt.ID == uuid.Nil {
+			attempt.ID = uuid.New()
+		}
+		if attempt.CreatedAt.IsZero() {
+			attempt.CreatedAt = time.Now()
+		}
+		row := *attempt
+		row.User = nil
+		t.AppendLoginAttempt(row)
+		return nil
+	})
+}
+
+// GetFailedLoginsByUsername returns failures since the given time that happened after
+// the last successful login or unlock, newest first.
+func (r *authMemoryRepository) GetFailedLoginsByUsername(ctx context.Context, username string, since time.Time) ([]*models.LoginAttempt, error) {
+	var attempts []*models.LoginAttempt
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		lastReset := since
+		for _, attempt := range t.LoginAttempts {
+			reset := attempt.Result == constant.LoginResultSuccess || attempt.Result == constant.LoginResultUnlocked
+			if attempt.Username == username && reset && attempt.CreatedAt.After(lastReset) {
+				lastReset = attempt.CreatedAt
+			}
+		}
+
+		attempts = memory.Select(t.LoginAttempts, func(a *models.LoginAttempt) bool {
+			return a.Username == username && a.Result == constant.LoginResultFailure && a.CreatedAt.After(lastReset)
+		})
+		memory.Sort(attempts, newestAttemptFirst)
+		return nil
+	})
+	return attempts, err
+}
+
+func (r *authMemoryRepository) GetFailedLoginsByIP(ctx context.Context, clientIP string, since time.Time) ([]*models.LoginAttempt, error) {
+	var attempts []*models.LoginAttempt
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		attempts = memory.Select(t.LoginAttempts, func(a *models.LoginAttempt) bool {
+			return a.ClientIP == clientIP && a.Result == constant.LoginResultFailure && a.CreatedAt.After(since)
+		})
+		memory.Sort(attempts, newestAttemptFirst)
+		return nil
+	})
+	return attempts, err
+}
+
+func (r *authMemoryRepository) GetLoginAttemptsByUser(ctx context.Context, userID uuid.UUID, page domain.PageParams) (domain.Page[models.LoginAttempt], error) {
+	var attempts domain.Page[models.LoginAttempt]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		rows := memory.Select(t.LoginAttempts, func(a *models.LoginAttempt) bool {
+			return a.UserID != nil && *a.UserID == userID
+		})
+
+		var err error
+		attempts, err = pagination.Slice(rows, page, loginAttemptKeys)
+		return errors.Wrap(err, "[AuthMemoryRepository.GetLoginAttemptsByUser]")
+	})
+	return attempts, err
+}
+
+func newestAttemptFirst(a, b *models.LoginAttempt) int {
+	return b.CreatedAt.Compare(a.CreatedAt)
+}
@@ -1,10 +1,16 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
 	"gorm.io/gorm"
 )
 
@@ -16,32 +22,32 @@ func NewAuthRepository(db *gorm.DB) domain.AuthRepository {
 	return &authRepository{db: db}
 }
 
-func (r *authRepository) GetUserByUsername(username string) (*models.User, error) {
+func (r *authRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[AuthRepository.GetUserByUsername]: User not found")
+			return nil, errors.Wrap(domain.NotFoundError("User not found"), "[AuthRepository.GetUserByUsername]")
 		}
 		return nil, errors.Wrap(err, "[AuthRepository.GetUserByUsername]: Error querying database")
 	}
 	return &user, nil
 }
 
-func (r *authRepository) GetUserWithRolesAndPermissions(id uuid.UUID) (*models.User, error) {
+func (r *authRepository) GetUserWithRolesAndPermissions(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.Preload("Roles.Permissions").Where("id = ?", id).First(&user).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("Roles.Permissions").Where("id = ?", id).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[AuthRepository.GetUserWithRolesAndPermissions]: User not found")
+			return nil, errors.Wrap(domain.NotFoundError("User not found"), "[AuthRepository.GetUserWithRolesAndPermissions]")
 		}
 		return nil, errors.Wrap(err, "[AuthRepository.GetUserWithRolesAndPermissions]: Error querying database")
 	}
 	return &user, nil
 }
 
-func (r *authRepository) GetUserPermissions(userID uuid.UUID) ([]string, error) {
+func (r *authRepository) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
 	var permissions []string
 
-	err := r.db.Table("permissions").
+	err := database.Conn(ctx, r.db).Table("permissions").
 		Select("DISTINCT permissions.code").
 		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
 		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
@@ -55,41 +61,106 @@ func (r *authRepository) GetUserPermissions(userID uuid.UUID) ([]string, error)
 	return permissions, nil
 }
 
-func (r *authRepository) UpdatePassword(userID uuid.UUID, passwordHash string) error {
-	if err := r.db.Model(&models.User{}).Where("id = ?", userID).Update("password_hash", passwordHash).Error; err != nil {
+func (r *authRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	if err := database.Conn(ctx, r.db).Model(&models.User{}).Where("id = ?", userID).Update("password_hash", passwordHash).Error; err != nil {
 		return errors.Wrap(err, "[AuthRepository.UpdatePassword]: Error updating password")
 	}
 	return nil
 }
 
-func (r *authRepository) CreateSession(session *models.Session) error {
-	if err := r.db.Create(session).Error; err != nil {
+func (r *authRepository) UpdatePin(ctx context.Context, userID uuid.UUID, pinHash string) error {
+	if err := database.Conn(ctx, r.db).Model(&models.User{}).Where("id = ?", userID).Update("pin_hash", pinHash).Error; err != nil {
+		return errors.Wrap(err, "[AuthRepository.UpdatePin]: Error updating PIN")
+	}
+	return nil
+}
+
+func (r *authRepository) UpdateUserStatus(ctx context.Context, userID uuid.UUID, status string) error {
+	if err := database.Conn(ctx, r.db).Model(&models.User{}).Where("id = ?", userID).Update("status", status).Error; err != nil {
+		return errors.Wrap(err, "[AuthRepository.UpdateUserStatus]: Error updating user status")
+	}
+	return nil
+}
+
+func (r *authRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	if err := database.Conn(ctx, r.db).Create(session).Error; err != nil {
 		return errors.Wrap(err, "[AuthRepository.CreateSession]: Error creating session")
 	}
 	return nil
 }
 
-func (r *authRepository) GetSessionByToken(token string) (*models.Session, error) {
+func (r *authRepository) GetSessionByToken(ctx context.Context, token string) (*models.Session, error) {
 	var session models.Session
-	if err := r.db.Preload("User").Where("token = ?", token).First(&session).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("User").Where("token = ?", token).First(&session).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[AuthRepository.GetSessionByToken]: Session not found")
+			return nil, errors.Wrap(domain.NotFoundError("Session not found"), "[AuthRepository.GetSessionByToken]")
 		}
 		return nil, errors.Wrap(err, "[AuthRepository.GetSessionByToken]: Error querying database")
 	}
 	return &session, nil
 }
 
-func (r *authRepository) DeleteSession(token string) error {
-	if err := r.db.Where("token = ?", token).Delete(&models.Session{}).Error; err != nil {
+func (r *authRepository) DeleteSession(ctx context.Context, token string) error {
+	if err := database.Conn(ctx, r.db).Where("token = ?", token).Delete(&models.Session{}).Error; err != nil {
 		return errors.Wrap(err, "[AuthRepository.DeleteSession]: Error deleting session")
 	}
 	return nil
 }
 
-func (r *authRepository) CleanupExpiredSessions() error {
-	if err := r.db.Where("expires_at < NOW()").Delete(&models.Session{}).Error; err != nil {
-		return errors.Wrap(err, "[AuthRepository.CleanupExpiredSessions]: Error cleaning up sessions")
+func (r *authRepository) DeleteSessionsByUser(ctx context.Context, userID uuid.UUID) error {
+	if err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&models.Session{}).Error; err != nil {
+		return errors.Wrap(err, "[AuthRepository.DeleteSessionsByUser]: Error deleting sessions")
 	}
 	return nil
 }
+
+// CleanupExpiredSessions compares expiry with the application clock, which set
+// it, rather than the database's own
+func (r *authRepository) CleanupExpiredSessions(ctx context.Context) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("expires_at < ?", time.Now()).Delete(&models.Session{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "[AuthRepository.CleanupExpiredSessions]: Error cleaning up sessions")
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *authRepository) CreateLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	if err := database.Conn(ctx, r.db).Create(attempt).Error; err != nil {
+		return errors.Wrap(err, "[AuthRepository.CreateLoginAttempt]: Error creating login attempt")
+	}
+	return nil
+}
+
+// GetFailedLoginsByUsername returns failures since the given time that happened after
+// the last successful login or unlock, newest first.
+func (r *authRepository) GetFailedLoginsByUsername(ctx context.Context, username string, since time.Time) ([]*models.LoginAttempt, error) {
+	lastReset := database.Conn(ctx, r.db).Model(&models.LoginAttempt{}).
+		Select("COALESCE(MAX(created_at), ?)", since).
+		Where("username = ? AND result IN ?", username, []string{constant.LoginResultSuccess, constant.LoginResultUnlocked})
+
+	var attempts []*models.LoginAttempt
+	if err := database.Conn(ctx, r.db).Where("username = ? AND result = ? AND created_at > ? AND created_at > (?)", username, constant.LoginResultFailure, since, lastReset).
+		Order("created_at DESC").
+		Find(&attempts).Error; err != nil {
+		return nil, errors.Wrap(err, "[AuthRepository.GetFailedLoginsByUsername]: Error querying database")
+	}
+	return attempts, nil
+}
+
+func (r *authRepository) GetFailedLoginsByIP(ctx context.Context, clientIP string, since time.Time) ([]*models.LoginAttempt, error) {
+	var attempts []*models.LoginAttempt
+	if err := database.Conn(ctx, r.db).Where("client_ip = ? AND result = ? AND created_at > ?", clientIP, constant.LoginResultFailure, since).
+		Order("created_at DESC").
+		Find(&attempts).Error; err != nil {
+		return nil, errors.Wrap(err, "[AuthRepository.GetFailedLoginsByIP]: Error querying database")
+	}
+	return attempts, nil
+}
+
+func (r *authRepository) GetLoginAttemptsByUser(ctx context.Context, userID uuid.UUID, page domain.PageParams) (domain.Page[models.LoginAttempt], error) {
+	attempts, err := pagination.Find(database.Conn(ctx, r.db).Where("user_id = ?", userID), page, loginAttemptKeys)
+	if err != nil {
+		return domain.Page[models.LoginAttempt]{}, errors.Wrap(err, "[AuthRepository.GetLoginAttemptsByUser]")
+	}
+	return attempts, nil
+}
@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database/dbtest"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/feature/auth/repository"
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+)
+
+func createUser(t *testing.T, db *gorm.DB, username string) *models.User {
+	t.Helper()
+
+	user := &models.User{Username: username, PasswordHash: "hash"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestCleanupExpiredSessions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := repository.NewAuthRepository(db)
+		user := createUser(t, db, "cashier")
+
+		now := time.Now()
+		for token, expiresAt := range map[string]time.Time{"expired": now.Add(-time.Minute), "live": now.Add(time.Hour)} {
+			if err := repo.CreateSession(ctx, &models.Session{UserID: user.ID, Token: token, ExpiresAt: expiresAt}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		purged, err := repo.CleanupExpiredSessions(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if purged != 1 {
+			t.Errorf("purged %d sessions, want 1", purged)
+		}
+		if _, err := repo.GetSessionByToken(ctx, "expired"); domain.ErrorKindOf(err) != domain.ErrorKindNotFound {
+			t.Errorf("expired session: %v, want not found", err)
+		}
+		if _, err := repo.GetSessionByToken(ctx, "live"); err != nil {
+			t.Errorf("live session: %v", err)
+		}
+	})
+}
+
+func TestFailedLoginsResetOnSuccess(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := repository.NewAuthRepository(db)
+		user := createUser(t, db, "cashier")
+
+		// Attempts a second apart, so their order does not depend on clock resolution
+		start := time.Now().Add(-time.Minute)
+		results := []string{constant.LoginResultFailure, constant.LoginResultSuccess, constant.LoginResultFailure, constant.LoginResultFailure}
+		for i, result := range results {
+			attempt := &models.LoginAttempt{Username: user.Username, UserID: &user.ID, ClientIP: "192.0.2.1", Result: result, CreatedAt: start.Add(time.Duration(i) * time.Second)}
+			if err := repo.CreateLoginAttempt(ctx, attempt); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		since := start.Add(-constant.LoginFailureWindow)
+		failures, err := repo.GetFailedLoginsByUsername(ctx, user.Username, since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(failures) != 2 {
+			t.Fatalf("%d failures after the last success, want 2", len(failures))
+		}
+		if !failures[0].CreatedAt.After(failures[1].CreatedAt) {
+			t.Error("failures are not newest first")
+		}
+
+		byIP, err := repo.GetFailedLoginsByIP(ctx, "192.0.2.1", since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(byIP) != 3 {
+			t.Errorf("%d failures from the client, want 3", len(byIP))
+		}
+	})
+}
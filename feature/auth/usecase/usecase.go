@@ -1,64 +1,71 @@
 package usecase
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// dummyPasswordHash is verified against when the username is unknown, so a
+// missing account takes as long to reject as a wrong password
+var dummyPasswordHash = "$2a$10$4o4o8Mfpw614aGuqkUdskOCIbrkXa.7mPBj.apLHnHkRQCW9ulW/u"
+
 type authUsecase struct {
 	authRepository domain.AuthRepository
+	transactor     domain.Transactor
+	auditUsecase   domain.AuditUsecase
+	metrics        domain.Metrics
+	authConfig     config.AuthConfig
 }
 
-func NewAuthUsecase(authRepository domain.AuthRepository) domain.AuthUsecase {
-	return &authUsecase{authRepository: authRepository}
+func NewAuthUsecase(authRepository domain.AuthRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase, metrics domain.Metrics, authConfig config.AuthConfig) domain.AuthUsecase {
+	return &authUsecase{authRepository: authRepository, transactor: transactor, auditUsecase: auditUsecase, metrics: metrics, authConfig: authConfig}
 }
 
-func (u *authUsecase) Login(req *request.LoginRequest) (*response.AuthResponse, error) {
-	// Get user by username
-	user, err := u.authRepository.GetUserByUsername(req.Username)
-	if err != nil {
-		return nil, errors.Wrap(err, "[AuthUsecase.Login]: Invalid username or password")
-	}
-
-	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, errors.Wrap(err, "[AuthUsecase.Login]: Invalid username or password")
-	}
+func (u *authUsecase) Login(ctx context.Context, req *request.LoginRequest, clientIP string) (*response.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.Login")
+	defer span.End()
 
-	// Check user status
-	if user.Status != nil && *user.Status == "locked" {
-		return nil, errors.New("[AuthUsecase.Login]: User account is locked")
+	user, err := u.authenticate(ctx, req.Username, clientIP, func(user *models.User) bool {
+		return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) == nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuthUsecase.Login]: Authentication failed")
 	}
 
 	// Generate session token
-	token, err := generateToken()
+	token, err := utils.GenerateToken(32)
 	if err != nil {
 		return nil, errors.Wrap(err, "[AuthUsecase.Login]: Error generating token")
 	}
 
-	// Create session (24 hours expiry)
-	expiresAt := time.Now().Add(24 * time.Hour)
+	// Create session
+	expiresAt := time.Now().Add(u.authConfig.SessionTTL)
 	session := &models.Session{
 		UserID:    user.ID,
 		Token:     token,
 		ExpiresAt: expiresAt,
 	}
 
-	if err := u.authRepository.CreateSession(session); err != nil {
+	if err := u.authRepository.CreateSession(ctx, session); err != nil {
 		return nil, errors.Wrap(err, "[AuthUsecase.Login]: Error creating session")
 	}
 
 	// Get user permissions
-	permissions, err := u.authRepository.GetUserPermissions(user.ID)
+	permissions, err := u.authRepository.GetUserPermissions(ctx, user.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "[AuthUsecase.Login]: Error getting permissions")
 	}
@@ -81,23 +88,29 @@ func (u *authUsecase) Login(req *request.LoginRequest) (*response.AuthResponse,
 	return authResponse, nil
 }
 
-func (u *authUsecase) Logout(token string) error {
-	if err := u.authRepository.DeleteSession(token); err != nil {
+func (u *authUsecase) Logout(ctx context.Context, token string) error {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.Logout")
+	defer span.End()
+
+	if err := u.authRepository.DeleteSession(ctx, token); err != nil {
 		return errors.Wrap(err, "[AuthUsecase.Logout]: Error deleting session")
 	}
 	return nil
 }
 
-func (u *authUsecase) ChangePassword(userID uuid.UUID, req *request.ChangePasswordRequest) error {
+func (u *authUsecase) ChangePassword(ctx context.Context, userID uuid.UUID, req *request.ChangePasswordRequest) error {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.ChangePassword")
+	defer span.End()
+
 	// Get user
-	user, err := u.authRepository.GetUserWithRolesAndPermissions(userID)
+	user, err := u.authRepository.GetUserWithRolesAndPermissions(ctx, userID)
 	if err != nil {
 		return errors.Wrap(err, "[AuthUsecase.ChangePassword]: Error getting user")
 	}
 
 	// Verify old password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
-		return errors.New("[AuthUsecase.ChangePassword]: Old password is incorrect")
+		return errors.Wrap(domain.ValidationError("Old password is incorrect", map[string]string{"old_password": "incorrect"}), "[AuthUsecase.ChangePassword]")
 	}
 
 	// Hash new password
@@ -106,16 +119,71 @@ func (u *authUsecase) ChangePassword(userID uuid.UUID, req *request.ChangePasswo
 		return errors.Wrap(err, "[AuthUsecase.ChangePassword]: Error hashing password")
 	}
 
-	// Update password
-	if err := u.authRepository.UpdatePassword(userID, string(hashedPassword)); err != nil {
-		return errors.Wrap(err, "[AuthUsecase.ChangePassword]: Error updating password")
+	// Update password; the hashes themselves are never written to the audit log
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.authRepository.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.ChangePassword]: Error updating password")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionChangePassword, constant.AuditEntityUser, userID.String(), nil, nil); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.ChangePassword]: Error recording audit log")
+		}
+		return nil
+	})
+}
+
+func (u *authUsecase) SetPin(ctx context.Context, userID uuid.UUID, req *request.SetPinRequest) error {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.SetPin")
+	defer span.End()
+
+	user, err := u.authRepository.GetUserWithRolesAndPermissions(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "[AuthUsecase.SetPin]: Error getting user")
+	}
+
+	// Verify password
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return errors.Wrap(domain.ValidationError("Password is incorrect", map[string]string{"password": "incorrect"}), "[AuthUsecase.SetPin]")
 	}
 
-	return nil
+	hashedPin, err := bcrypt.GenerateFromPassword([]byte(req.Pin), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.Wrap(err, "[AuthUsecase.SetPin]: Error hashing PIN")
+	}
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.authRepository.UpdatePin(ctx, userID, string(hashedPin)); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.SetPin]: Error updating PIN")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionSetPin, constant.AuditEntityUser, userID.String(), nil, nil); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.SetPin]: Error recording audit log")
+		}
+		return nil
+	})
+}
+
+// VerifyCredentials checks a PIN or password for approvals at the terminal. It goes
+// through the same throttling and lockout as Login but does not create a session.
+func (u *authUsecase) VerifyCredentials(ctx context.Context, username string, secret string, clientIP string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.VerifyCredentials")
+	defer span.End()
+
+	user, err := u.authenticate(ctx, username, clientIP, func(user *models.User) bool {
+		if user.PinHash != nil && bcrypt.CompareHashAndPassword([]byte(*user.PinHash), []byte(secret)) == nil {
+			return true
+		}
+		return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(secret)) == nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuthUsecase.VerifyCredentials]: Authentication failed")
+	}
+	return user, nil
 }
 
-func (u *authUsecase) VerifyPermission(userID uuid.UUID, permissionCode string) (bool, error) {
-	permissions, err := u.authRepository.GetUserPermissions(userID)
+func (u *authUsecase) VerifyPermission(ctx context.Context, userID uuid.UUID, permissionCode string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.VerifyPermission")
+	defer span.End()
+
+	permissions, err := u.authRepository.GetUserPermissions(ctx, userID)
 	if err != nil {
 		return false, errors.Wrap(err, "[AuthUsecase.VerifyPermission]: Error getting permissions")
 	}
@@ -129,39 +197,274 @@ func (u *authUsecase) VerifyPermission(userID uuid.UUID, permissionCode string)
 	return false, nil
 }
 
-func (u *authUsecase) GetUserPermissions(userID uuid.UUID) ([]string, error) {
-	permissions, err := u.authRepository.GetUserPermissions(userID)
+func (u *authUsecase) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.GetUserPermissions")
+	defer span.End()
+
+	permissions, err := u.authRepository.GetUserPermissions(ctx, userID)
 	if err != nil {
 		return nil, errors.Wrap(err, "[AuthUsecase.GetUserPermissions]: Error getting permissions")
 	}
 	return permissions, nil
 }
 
-func (u *authUsecase) GetUserByToken(token string) (*models.User, error) {
-	session, err := u.authRepository.GetSessionByToken(token)
+func (u *authUsecase) GetUserByToken(ctx context.Context, token string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.GetUserByToken")
+	defer span.End()
+
+	session, err := u.authRepository.GetSessionByToken(ctx, token)
 	if err != nil {
+		if domain.ErrorKindOf(err) == domain.ErrorKindNotFound {
+			u.metrics.AuthFailed(constant.AuthFailureSessionInvalid)
+		}
 		return nil, errors.Wrap(err, "[AuthUsecase.GetUserByToken]: Invalid or expired token")
 	}
 
 	// Check if session is expired
 	if session.ExpiresAt.Before(time.Now()) {
-		return nil, errors.New("[AuthUsecase.GetUserByToken]: Session expired")
+		u.metrics.AuthFailed(constant.AuthFailureSessionExpired)
+		return nil, errors.Wrap(domain.UnauthorizedError("Session expired"), "[AuthUsecase.GetUserByToken]")
 	}
 
 	// Get user with full details
-	user, err := u.authRepository.GetUserWithRolesAndPermissions(session.UserID)
+	user, err := u.authRepository.GetUserWithRolesAndPermissions(ctx, session.UserID)
 	if err != nil {
 		return nil, errors.Wrap(err, "[AuthUsecase.GetUserByToken]: Error getting user")
 	}
 
+	// Locked accounts lose their existing sessions too
+	if user.Status != nil && *user.Status == constant.UserStatusLocked {
+		u.metrics.AuthFailed(constant.AuthFailureSessionLocked)
+		return nil, errors.Wrap(domain.UnauthorizedError("User account is locked"), "[AuthUsecase.GetUserByToken]")
+	}
+
+	return user, nil
+}
+
+func (u *authUsecase) UnlockUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.UnlockUser")
+	defer span.End()
+
+	user, err := u.authRepository.GetUserWithRolesAndPermissions(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "[AuthUsecase.UnlockUser]: Error getting user")
+	}
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.authRepository.UpdateUserStatus(ctx, user.ID, constant.UserStatusActive); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.UnlockUser]: Error unlocking user")
+		}
+
+		// Recorded so earlier failures stop counting towards the lock threshold
+		if err := u.recordLoginAttempt(ctx, user.Username, &user.ID, "", constant.LoginResultUnlocked); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.UnlockUser]: Error recording unlock")
+		}
+
+		before := map[string]string{"status": utils.DerefString(user.Status)}
+		after := map[string]string{"status": constant.UserStatusActive}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUnlock, constant.AuditEntityUser, user.ID.String(), before, after); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.UnlockUser]: Error recording audit log")
+		}
+		return nil
+	})
+}
+
+// ResetPassword sets a new password without knowing the old one and signs the
+// user out everywhere. It is meant for operators, not for the HTTP API.
+func (u *authUsecase) ResetPassword(ctx context.Context, req *request.ResetPasswordRequest) error {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.ResetPassword")
+	defer span.End()
+
+	user, err := u.authRepository.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		return errors.Wrap(err, "[AuthUsecase.ResetPassword]: Error getting user")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.Wrap(err, "[AuthUsecase.ResetPassword]: Error hashing password")
+	}
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.authRepository.UpdatePassword(ctx, user.ID, string(hashedPassword)); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.ResetPassword]: Error updating password")
+		}
+		if err := u.authRepository.DeleteSessionsByUser(ctx, user.ID); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.ResetPassword]: Error revoking sessions")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionResetPassword, constant.AuditEntityUser, user.ID.String(), nil, nil); err != nil {
+			return errors.Wrap(err, "[AuthUsecase.ResetPassword]: Error recording audit log")
+		}
+		return nil
+	})
+}
+
+func (u *authUsecase) PurgeExpiredSessions(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.PurgeExpiredSessions")
+	defer span.End()
+
+	purged, err := u.authRepository.CleanupExpiredSessions(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "[AuthUsecase.PurgeExpiredSessions]: Error deleting expired sessions")
+	}
+	return purged, nil
+}
+
+func (u *authUsecase) GetLoginHistory(ctx context.Context, userID uuid.UUID, query *request.LoginHistoryQuery) (*response.Page[*response.LoginAttemptResponse], error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.GetLoginHistory")
+	defer span.End()
+
+	if query.Limit == 0 {
+		query.Limit = constant.LoginHistoryDefaultLimit
+	}
+	page, err := pagination.Parse(query.PageQuery, "", "-created_at")
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuthUsecase.GetLoginHistory]: Invalid page")
+	}
+
+	attempts, err := u.authRepository.GetLoginAttemptsByUser(ctx, userID, page)
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuthUsecase.GetLoginHistory]: Error getting login history")
+	}
+
+	attemptResponses := make([]*response.LoginAttemptResponse, len(attempts.Items))
+	for i, attempt := range attempts.Items {
+		attemptResponses[i] = &response.LoginAttemptResponse{
+			ID:        attempt.ID,
+			Username:  attempt.Username,
+			ClientIP:  attempt.ClientIP,
+			Result:    attempt.Result,
+			CreatedAt: attempt.CreatedAt,
+		}
+	}
+
+	return &response.Page[*response.LoginAttemptResponse]{Items: attemptResponses, NextCursor: attempts.NextCursor}, nil
+}
+
+// authenticate applies the client IP throttle, account lock and per-user backoff around
+// verify, recording every attempt in the login history.
+func (u *authUsecase) authenticate(ctx context.Context, username string, clientIP string, verify func(user *models.User) bool) (*models.User, error) {
+	now := time.Now()
+	since := now.Add(-constant.LoginFailureWindow)
+
+	// Throttle by client IP before looking at the account
+	ipFailures, err := u.authRepository.GetFailedLoginsByIP(ctx, clientIP, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error checking login attempts")
+	}
+	if wait := loginBackoff(ipFailures, constant.LoginIPBackoffThreshold, now); wait > 0 {
+		if err := u.recordLoginAttempt(ctx, username, nil, clientIP, constant.LoginResultBlocked); err != nil {
+			return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error recording login attempt")
+		}
+		return nil, errors.Wrap(domain.TooManyRequestsError(fmt.Sprintf("Too many failed login attempts, try again in %s", wait)), "[AuthUsecase.authenticate]")
+	}
+
+	// Get user by username
+	user, err := u.authRepository.GetUserByUsername(ctx, username)
+	if err != nil {
+		if domain.ErrorKindOf(err) != domain.ErrorKindNotFound {
+			return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error getting user")
+		}
+		verify(&models.User{PasswordHash: dummyPasswordHash})
+		if err := u.recordLoginAttempt(ctx, username, nil, clientIP, constant.LoginResultFailure); err != nil {
+			return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error recording login attempt")
+		}
+		return nil, errors.Wrap(domain.UnauthorizedError("Invalid username or password"), "[AuthUsecase.authenticate]")
+	}
+
+	// Check user status before the password so a locked account never confirms a guess
+	if user.Status != nil && *user.Status == constant.UserStatusLocked {
+		if err := u.recordLoginAttempt(ctx, user.Username, &user.ID, clientIP, constant.LoginResultLocked); err != nil {
+			return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error recording login attempt")
+		}
+		return nil, errors.Wrap(domain.ForbiddenError("User account is locked"), "[AuthUsecase.authenticate]")
+	}
+
+	userFailures, err := u.authRepository.GetFailedLoginsByUsername(ctx, user.Username, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error checking login attempts")
+	}
+	if wait := loginBackoff(userFailures, constant.LoginBackoffThreshold, now); wait > 0 {
+		if err := u.recordLoginAttempt(ctx, user.Username, &user.ID, clientIP, constant.LoginResultBlocked); err != nil {
+			return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error recording login attempt")
+		}
+		return nil, errors.Wrap(domain.TooManyRequestsError(fmt.Sprintf("Too many failed login attempts, try again in %s", wait)), "[AuthUsecase.authenticate]")
+	}
+
+	// Verify credentials
+	if !verify(user) {
+		if err := u.recordLoginAttempt(ctx, user.Username, &user.ID, clientIP, constant.LoginResultFailure); err != nil {
+			return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error recording login attempt")
+		}
+
+		// Lock the account once the threshold is reached
+		if len(userFailures)+1 >= constant.LoginMaxFailedAttempts {
+			if err := u.lockUser(ctx, user, clientIP); err != nil {
+				return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error locking user")
+			}
+		}
+
+		return nil, errors.Wrap(domain.UnauthorizedError("Invalid username or password"), "[AuthUsecase.authenticate]")
+	}
+
+	if err := u.recordLoginAttempt(ctx, user.Username, &user.ID, clientIP, constant.LoginResultSuccess); err != nil {
+		return nil, errors.Wrap(err, "[AuthUsecase.authenticate]: Error recording login attempt")
+	}
+
 	return user, nil
 }
 
-// generateToken creates a random token for session
-func generateToken() (string, error) {
-	bytes := make([]byte, 32)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
+// lockUser locks an account that reached the failed attempt threshold, recorded
+// in the audit log like a manual lock. Nobody is signed in, so the entry names
+// the client the failed attempts came from.
+func (u *authUsecase) lockUser(ctx context.Context, user *models.User, clientIP string) error {
+	ctx = domain.WithActor(ctx, &domain.Actor{ClientIP: clientIP})
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.authRepository.UpdateUserStatus(ctx, user.ID, constant.UserStatusLocked); err != nil {
+			return err
+		}
+
+		before := map[string]string{"status": utils.DerefString(user.Status)}
+		after := map[string]string{"status": constant.UserStatusLocked}
+		return u.auditUsecase.Record(ctx, constant.AuditActionLock, constant.AuditEntityUser, user.ID.String(), before, after)
+	})
+}
+
+// recordLoginAttempt adds an attempt to the login history, and counts it as an
+// auth failure when it was rejected
+func (u *authUsecase) recordLoginAttempt(ctx context.Context, username string, userID *uuid.UUID, clientIP string, result string) error {
+	err := u.authRepository.CreateLoginAttempt(ctx, &models.LoginAttempt{
+		Username: username,
+		UserID:   userID,
+		ClientIP: clientIP,
+		Result:   result,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case constant.LoginResultFailure, constant.LoginResultBlocked, constant.LoginResultLocked:
+		u.metrics.AuthFailed(result)
+	}
+	return nil
+}
+
+// loginBackoff returns how long the caller still has to wait given recent failures
+// (newest first). The delay doubles with every failure past the threshold.
+func loginBackoff(failures []*models.LoginAttempt, threshold int, now time.Time) time.Duration {
+	if len(failures) < threshold {
+		return 0
+	}
+
+	delay := constant.LoginBackoffBase << (len(failures) - threshold)
+	if delay <= 0 || delay > constant.LoginBackoffMax {
+		delay = constant.LoginBackoffMax
+	}
+
+	wait := failures[0].CreatedAt.Add(delay).Sub(now)
+	if wait <= 0 {
+		return 0
 	}
-	return hex.EncodeToString(bytes), nil
+	return wait.Truncate(time.Second) + time.Second
 }
@@ -8,8 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type categoryHandler struct {
@@ -21,11 +21,9 @@ func NewCategoryHandler(categoryUsecase domain.CategoryUsecase) *categoryHandler
 }
 
 func (h *categoryHandler) GetAllCategories(c *gin.Context) {
-	categories, err := h.categoryUsecase.GetAllCategories()
+	categories, err := h.categoryUsecase.GetAllCategories(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[CategoryHandler.GetAllCategories]: Error getting categories")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[CategoryHandler.GetAllCategories]: Error getting categories"))
 		return
 	}
 	c.JSON(http.StatusOK, categories)
@@ -34,15 +32,13 @@ func (h *categoryHandler) GetAllCategories(c *gin.Context) {
 func (h *categoryHandler) GetCategoryByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid category ID", nil))
 		return
 	}
 
-	category, err := h.categoryUsecase.GetCategoryByID(id)
+	category, err := h.categoryUsecase.GetCategoryByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[CategoryHandler.GetCategoryByID]: Error getting category")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[CategoryHandler.GetCategoryByID]: Error getting category"))
 		return
 	}
 	c.JSON(http.StatusOK, category)
@@ -51,15 +47,13 @@ func (h *categoryHandler) GetCategoryByID(c *gin.Context) {
 func (h *categoryHandler) CreateCategory(c *gin.Context) {
 	var req request.CategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	category, err := h.categoryUsecase.CreateCategory(&req)
+	category, err := h.categoryUsecase.CreateCategory(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[CategoryHandler.CreateCategory]: Error creating category")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[CategoryHandler.CreateCategory]: Error creating category"))
 		return
 	}
 	c.JSON(http.StatusCreated, category)
@@ -68,21 +62,19 @@ func (h *categoryHandler) CreateCategory(c *gin.Context) {
 func (h *categoryHandler) UpdateCategory(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid category ID", nil))
 		return
 	}
 
 	var req request.CategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	category, err := h.categoryUsecase.UpdateCategory(id, &req)
+	category, err := h.categoryUsecase.UpdateCategory(c.Request.Context(), id, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[CategoryHandler.UpdateCategory]: Error updating category")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[CategoryHandler.UpdateCategory]: Error updating category"))
 		return
 	}
 	c.JSON(http.StatusOK, category)
@@ -91,15 +83,13 @@ func (h *categoryHandler) UpdateCategory(c *gin.Context) {
 func (h *categoryHandler) DeleteCategory(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid category ID", nil))
 		return
 	}
 
-	if err := h.categoryUsecase.DeleteCategory(id); err != nil {
-		err = errors.Wrap(err, "[CategoryHandler.DeleteCategory]: Error deleting category")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+	if err := h.categoryUsecase.DeleteCategory(c.Request.Context(), id); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[CategoryHandler.DeleteCategory]: Error deleting category"))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Category deleted successfully"})
 }
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type categoryMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewCategoryMemoryRepository(store *memory.Store) domain.CategoryRepository {
+	return &categoryMemoryRepository{store: store}
+}
+
+func (r *categoryMemoryRepository) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	var categories []*models.Category
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		categories = memory.Select(t.Categories, nil)
+		memory.Sort(categories,
+			func(a, b *models.Category) int { return memory.CompareInt(a.DisplayOrder, b.DisplayOrder) },
+			func(a, b *models.Category) int { return memory.CompareString(a.Name, b.Name) },
+		)
+		return nil
+	})
+	return categories, err
+}
+
+func (r *categoryMemoryRepository) GetCategoryByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Categories[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Category not found"), "[CategoryMemoryRepository.GetCategoryByID]")
+		}
+		category = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryMemoryRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if category.ID == uuid.Nil {
+			category.ID = uuid.New()
+		}
+		return r.save(t, category, "[CategoryMemoryRepository.CreateCategory]")
+	})
+}
+
+func (r *categoryMemoryRepository) UpdateCategory(ctx context.Context, category *models.Category) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, category, "[CategoryMemoryRepository.UpdateCategory]")
+	})
+}
+
+func (r *categoryMemoryRepository) save(t *memory.Tables, category *models.Category, op string) error {
+	if memory.Any(t.Categories, func(other *models.Category) bool {
+		return other.ID != category.ID && memory.EqualString(other.Name, category.Name)
+	}) {
+		return errors.Wrap(domain.ConflictError("A category with this name already exists"), op)
+	}
+
+	row := *category
+	row.MenuItems = nil
+	row.Schedules = nil
+	row.ModifierGroups = nil
+	t.Categories[row.ID] = row
+	return nil
+}
+
+// DeleteCategory leaves the category's menu items uncategorised, like ON DELETE
+// SET NULL, and drops its sales account, schedules and modifier group links,
+// like ON DELETE CASCADE
+func (r *categoryMemoryRepository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		delete(t.Categories, id)
+		for menuItemID, menuItem := range t.MenuItems {
+			if menuItem.CategoryID != nil && *menuItem.CategoryID == id {
+				menuItem.CategoryID = nil
+				t.MenuItems[menuItemID] = menuItem
+			}
+		}
+		for accountID, account := range t.LedgerAccounts {
+			if account.CategoryID != nil && *account.CategoryID == id {
+				delete(t.LedgerAccounts, accountID)
+			}
+		}
+		t.DeleteMenuSchedules(func(s *models.MenuSchedule) bool { return s.CategoryID != nil && *s.CategoryID == id })
+		for key := range t.CategoryModifierGroups {
+			if key.CategoryID == id {
+				delete(t.CategoryModifierGroups, key)
+			}
+		}
+		return nil
+	})
+}
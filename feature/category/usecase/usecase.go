@@ -1,55 +1,62 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
 type categoryUsecase struct {
 	categoryRepository domain.CategoryRepository
+	transactor         domain.Transactor
+	auditUsecase       domain.AuditUsecase
 }
 
-func NewCategoryUsecase(categoryRepository domain.CategoryRepository) domain.CategoryUsecase {
-	return &categoryUsecase{categoryRepository: categoryRepository}
+func NewCategoryUsecase(categoryRepository domain.CategoryRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.CategoryUsecase {
+	return &categoryUsecase{categoryRepository: categoryRepository, transactor: transactor, auditUsecase: auditUsecase}
 }
 
-func (u *categoryUsecase) GetAllCategories() ([]*response.CategoryResponse, error) {
-	categories, err := u.categoryRepository.GetAllCategories()
+func (u *categoryUsecase) GetAllCategories(ctx context.Context) (*response.Page[*response.CategoryResponse], error) {
+	ctx, span := tracing.Start(ctx, "CategoryUsecase.GetAllCategories")
+	defer span.End()
+
+	categories, err := u.categoryRepository.GetAllCategories(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "[CategoryUsecase.GetAllCategories]: Error getting categories")
 	}
 
 	categoryResponses := make([]*response.CategoryResponse, len(categories))
 	for i, category := range categories {
-		categoryResponses[i] = &response.CategoryResponse{
-			ID:           category.ID,
-			Name:         utils.DerefString(category.Name),
-			DisplayOrder: utils.DerefInt(category.DisplayOrder),
-		}
+		categoryResponses[i] = u.buildCategoryResponse(category)
 	}
 
-	return categoryResponses, nil
+	return response.SinglePage(categoryResponses), nil
 }
 
-func (u *categoryUsecase) GetCategoryByID(id uuid.UUID) (*response.CategoryResponse, error) {
-	category, err := u.categoryRepository.GetCategoryByID(id)
+func (u *categoryUsecase) GetCategoryByID(ctx context.Context, id uuid.UUID) (*response.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryUsecase.GetCategoryByID")
+	defer span.End()
+
+	category, err := u.categoryRepository.GetCategoryByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[CategoryUsecase.GetCategoryByID]: Error getting category")
 	}
 
-	return &response.CategoryResponse{
-		ID:           category.ID,
-		Name:         utils.DerefString(category.Name),
-		DisplayOrder: utils.DerefInt(category.DisplayOrder),
-	}, nil
+	return u.buildCategoryResponse(category), nil
 }
 
-func (u *categoryUsecase) CreateCategory(req *request.CategoryRequest) (*response.CategoryResponse, error) {
+func (u *categoryUsecase) CreateCategory(ctx context.Context, req *request.CategoryRequest) (*response.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryUsecase.CreateCategory")
+	defer span.End()
+
 	// Set default display order if not provided
 	displayOrder := 0
 	if req.DisplayOrder != nil {
@@ -61,23 +68,32 @@ func (u *categoryUsecase) CreateCategory(req *request.CategoryRequest) (*respons
 		DisplayOrder: &displayOrder,
 	}
 
-	if err := u.categoryRepository.CreateCategory(category); err != nil {
-		return nil, errors.Wrap(err, "[CategoryUsecase.CreateCategory]: Error creating category")
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.categoryRepository.CreateCategory(ctx, category); err != nil {
+			return errors.Wrap(err, "[CategoryUsecase.CreateCategory]: Error creating category")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityCategory, category.ID.String(), nil, u.buildCategoryResponse(category)); err != nil {
+			return errors.Wrap(err, "[CategoryUsecase.CreateCategory]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.CategoryResponse{
-		ID:           category.ID,
-		Name:         req.Name,
-		DisplayOrder: displayOrder,
-	}, nil
+	return u.buildCategoryResponse(category), nil
 }
 
-func (u *categoryUsecase) UpdateCategory(id uuid.UUID, req *request.CategoryRequest) (*response.CategoryResponse, error) {
+func (u *categoryUsecase) UpdateCategory(ctx context.Context, id uuid.UUID, req *request.CategoryRequest) (*response.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryUsecase.UpdateCategory")
+	defer span.End()
+
 	// Get existing category
-	category, err := u.categoryRepository.GetCategoryByID(id)
+	category, err := u.categoryRepository.GetCategoryByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[CategoryUsecase.UpdateCategory]: Category not found")
 	}
+	before := u.buildCategoryResponse(category)
 
 	// Update fields
 	category.Name = &req.Name
@@ -85,27 +101,48 @@ func (u *categoryUsecase) UpdateCategory(id uuid.UUID, req *request.CategoryRequ
 		category.DisplayOrder = req.DisplayOrder
 	}
 
-	if err := u.categoryRepository.UpdateCategory(category); err != nil {
-		return nil, errors.Wrap(err, "[CategoryUsecase.UpdateCategory]: Error updating category")
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.categoryRepository.UpdateCategory(ctx, category); err != nil {
+			return errors.Wrap(err, "[CategoryUsecase.UpdateCategory]: Error updating category")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityCategory, category.ID.String(), before, u.buildCategoryResponse(category)); err != nil {
+			return errors.Wrap(err, "[CategoryUsecase.UpdateCategory]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.CategoryResponse{
-		ID:           category.ID,
-		Name:         utils.DerefString(category.Name),
-		DisplayOrder: utils.DerefInt(category.DisplayOrder),
-	}, nil
+	return u.buildCategoryResponse(category), nil
 }
 
-func (u *categoryUsecase) DeleteCategory(id uuid.UUID) error {
+func (u *categoryUsecase) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "CategoryUsecase.DeleteCategory")
+	defer span.End()
+
 	// Check if category exists
-	_, err := u.categoryRepository.GetCategoryByID(id)
+	category, err := u.categoryRepository.GetCategoryByID(ctx, id)
 	if err != nil {
 		return errors.Wrap(err, "[CategoryUsecase.DeleteCategory]: Category not found")
 	}
 
-	if err := u.categoryRepository.DeleteCategory(id); err != nil {
-		return errors.Wrap(err, "[CategoryUsecase.DeleteCategory]: Error deleting category")
-	}
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.categoryRepository.DeleteCategory(ctx, id); err != nil {
+			return errors.Wrap(err, "[CategoryUsecase.DeleteCategory]: Error deleting category")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionDelete, constant.AuditEntityCategory, id.String(), u.buildCategoryResponse(category), nil); err != nil {
+			return errors.Wrap(err, "[CategoryUsecase.DeleteCategory]: Error recording audit log")
+		}
+		return nil
+	})
+}
 
-	return nil
+// Helper function to build category response
+func (u *categoryUsecase) buildCategoryResponse(category *models.Category) *response.CategoryResponse {
+	return &response.CategoryResponse{
+		ID:           category.ID,
+		Name:         utils.DerefString(category.Name),
+		DisplayOrder: utils.DerefInt(category.DisplayOrder),
+	}
 }
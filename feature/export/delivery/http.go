@@ -0,0 +1,117 @@
+package delivery
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/logging"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/spreadsheet"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type exportHandler struct {
+	exportUsecase domain.ExportUsecase
+}
+
+func NewExportHandler(exportUsecase domain.ExportUsecase) *exportHandler {
+	return &exportHandler{exportUsecase: exportUsecase}
+}
+
+func (h *exportHandler) ExportOrders(c *gin.Context) {
+	var req request.OrderExportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	w, ok := download(c, req.Format, "orders")
+	if !ok {
+		return
+	}
+	c.Status(http.StatusOK)
+	err := h.exportUsecase.ExportOrders(c.Request.Context(), &req, w)
+	finish(c, errors.Wrap(err, "[ExportHandler.ExportOrders]: Error exporting orders"))
+}
+
+func (h *exportHandler) ExportPayments(c *gin.Context) {
+	var req request.PaymentExportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	w, ok := download(c, req.Format, "payments")
+	if !ok {
+		return
+	}
+	c.Status(http.StatusOK)
+	err := h.exportUsecase.ExportPayments(c.Request.Context(), &req, w)
+	finish(c, errors.Wrap(err, "[ExportHandler.ExportPayments]: Error exporting payments"))
+}
+
+func (h *exportHandler) ExportVoids(c *gin.Context) {
+	var req request.VoidExportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	w, ok := download(c, req.Format, "voids")
+	if !ok {
+		return
+	}
+	c.Status(http.StatusOK)
+	err := h.exportUsecase.ExportVoids(c.Request.Context(), &req, w)
+	finish(c, errors.Wrap(err, "[ExportHandler.ExportVoids]: Error exporting voids"))
+}
+
+func (h *exportHandler) ExportSalesReport(c *gin.Context) {
+	var req request.SalesReportExportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	w, ok := download(c, req.Format, "sales-by-"+req.By)
+	if !ok {
+		return
+	}
+	c.Status(http.StatusOK)
+	err := h.exportUsecase.ExportSalesReport(c.Request.Context(), &req, w)
+	finish(c, errors.Wrap(err, "[ExportHandler.ExportSalesReport]: Error exporting sales report"))
+}
+
+// download sets the headers of a file named name in format and returns a
+// writer of the response body
+func download(c *gin.Context, format string, name string) (spreadsheet.Writer, bool) {
+	w, err := spreadsheet.New(format, c.Writer, name)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(domain.ValidationError("Unknown format", map[string]string{"format": "oneof"}), err.Error()))
+		return nil, false
+	}
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	return w, true
+}
+
+// finish reports an export that failed. Before the first byte the file
+// headers are dropped for an error response. After it the status has been
+// sent, so the connection is aborted instead: the client sees a broken
+// download rather than a file that ends early.
+func finish(c *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		utils.RenderError(c, err)
+		return
+	}
+	logging.FromContext(c.Request.Context()).Error(err)
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/spreadsheet"
+	"github.com/pubestpubest/pos-backend/tracing"
+)
+
+// timeLayout is how exported times read, in the restaurant's time zone
+const timeLayout = "2006-01-02 15:04:05"
+
+type exportUsecase struct {
+	orderUsecase   domain.OrderUsecase
+	paymentUsecase domain.PaymentUsecase
+	reportUsecase  domain.ReportUsecase
+	calendar       businessday.Calendar
+}
+
+// NewExportUsecase exports through the list and report usecases, so an export
+// holds the same rows, filtered and sorted the same way, as the API shows
+func NewExportUsecase(orderUsecase domain.OrderUsecase, paymentUsecase domain.PaymentUsecase, reportUsecase domain.ReportUsecase, calendar businessday.Calendar) domain.ExportUsecase {
+	return &exportUsecase{orderUsecase: orderUsecase, paymentUsecase: paymentUsecase, reportUsecase: reportUsecase, calendar: calendar}
+}
+
+// ExportOrders writes a line per item, with the order's figures repeated on
+// each, and a single line for an order without items
+func (u *exportUsecase) ExportOrders(ctx context.Context, query *request.OrderExportQuery, w spreadsheet.Writer) error {
+	ctx, span := tracing.Start(ctx, "ExportUsecase.ExportOrders")
+	defer span.End()
+
+	header := []any{
+		"order_id", "business_date", "created_at", "closed_at", "status", "source", "table", "opened_by",
+		"order_total_baht", "paid_baht", "item_id", "menu_item", "quantity", "unit_price_baht",
+		"line_total_baht", "modifiers", "item_note", "cancelled_at", "cancel_reason",
+	}
+	load := func(cursor string) (*response.Page[*response.OrderSummaryResponse], error) {
+		return u.orderUsecase.GetAllOrders(ctx, &request.OrderListQuery{
+			PageQuery:   request.PageQuery{Cursor: cursor, Limit: pagination.MaxLimit},
+			OrderFilter: query.OrderFilter,
+			Sort:        query.Sort,
+			Expand:      constant.OrderExpandItems,
+		})
+	}
+	err := walk(w, header, load, func(order *response.OrderSummaryResponse) error {
+		cells := []any{
+			order.ID.String(), order.BusinessDate, u.formatTime(&order.CreatedAt), u.formatTime(order.ClosedAt),
+			order.Status, order.Source, order.TableName, order.OpenerName, order.TotalBaht, order.PaidBaht,
+		}
+		if len(order.Items) == 0 {
+			return w.Row(cells...)
+		}
+		for _, item := range order.Items {
+			modifiers := make([]string, len(item.Modifiers))
+			for i, modifier := range item.Modifiers {
+				modifiers[i] = fmt.Sprintf("%s (%+d)", modifier.ModifierName, modifier.PriceDeltaBaht)
+			}
+			row := append(cells[:len(cells):len(cells)],
+				item.ID.String(), item.MenuItemName, item.Quantity, item.UnitPriceBaht, item.LineTotalBaht,
+				strings.Join(modifiers, "; "), item.Note, u.formatTime(item.CancelledAt), item.CancelReason,
+			)
+			if err := w.Row(row...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return errors.Wrap(err, "[ExportUsecase.ExportOrders]")
+}
+
+func (u *exportUsecase) ExportPayments(ctx context.Context, query *request.PaymentExportQuery, w spreadsheet.Writer) error {
+	ctx, span := tracing.Start(ctx, "ExportUsecase.ExportPayments")
+	defer span.End()
+
+	header := []any{
+		"payment_id", "order_id", "business_date", "created_at", "method", "status",
+		"amount_baht", "currency", "provider", "provider_ref",
+	}
+	load := func(cursor string) (*response.Page[*response.PaymentResponse], error) {
+		return u.paymentUsecase.GetAllPayments(ctx, &request.PaymentListQuery{
+			PageQuery:     request.PageQuery{Cursor: cursor, Limit: pagination.MaxLimit},
+			PaymentFilter: query.PaymentFilter,
+			Sort:          query.Sort,
+		})
+	}
+	err := walk(w, header, load, func(payment *response.PaymentResponse) error {
+		return w.Row(
+			payment.ID.String(), payment.OrderID.String(), payment.BusinessDate, u.formatTime(&payment.CreatedAt),
+			payment.Method, payment.Status, payment.AmountBaht, payment.Currency, payment.Provider, payment.ProviderRef,
+		)
+	})
+	return errors.Wrap(err, "[ExportUsecase.ExportPayments]")
+}
+
+func (u *exportUsecase) ExportVoids(ctx context.Context, query *request.VoidExportQuery, w spreadsheet.Writer) error {
+	ctx, span := tracing.Start(ctx, "ExportUsecase.ExportVoids")
+	defer span.End()
+
+	header := []any{
+		"id", "kind", "order_id", "business_date", "voided_at", "table", "menu_item", "quantity",
+		"value_baht", "reason_code", "reason_label", "voided_by",
+	}
+	load := func(cursor string) (*response.Page[*response.VoidResponse], error) {
+		return u.orderUsecase.GetVoids(ctx, &request.VoidListQuery{
+			PageQuery:  request.PageQuery{Cursor: cursor, Limit: pagination.MaxLimit},
+			VoidFilter: query.VoidFilter,
+			Sort:       query.Sort,
+		})
+	}
+	err := walk(w, header, load, func(v *response.VoidResponse) error {
+		return w.Row(
+			v.ID.String(), v.Kind, v.OrderID.String(), v.BusinessDate, u.formatTime(&v.VoidedAt), v.TableName,
+			v.MenuItemName, v.Quantity, v.ValueBaht, v.ReasonCode, v.ReasonLabel, v.VoiderName,
+		)
+	})
+	return errors.Wrap(err, "[ExportUsecase.ExportVoids]")
+}
+
+// ExportSalesReport writes the rows of the report followed by its totals. A
+// report has a row per group at most, so it is loaded whole.
+func (u *exportUsecase) ExportSalesReport(ctx context.Context, query *request.SalesReportExportQuery, w spreadsheet.Writer) error {
+	ctx, span := tracing.Start(ctx, "ExportUsecase.ExportSalesReport")
+	defer span.End()
+
+	report, err := u.reportUsecase.GetSalesReport(ctx, &query.SalesReportQuery)
+	if err != nil {
+		return errors.Wrap(err, "[ExportUsecase.ExportSalesReport]: Error getting sales report")
+	}
+
+	write := func(key *string, label string, figures response.SalesFiguresResponse) error {
+		return w.Row(
+			report.From, report.To, report.By, key, label, figures.OrderCount, figures.GrossBaht,
+			figures.DiscountBaht, figures.VATBaht, figures.NetBaht, figures.AverageTicketBaht,
+		)
+	}
+	if err := w.Row("from", "to", "by", "key", "label", "order_count", "gross_baht", "discount_baht", "vat_baht", "net_baht", "average_ticket_baht"); err != nil {
+		return errors.Wrap(err, "[ExportUsecase.ExportSalesReport]")
+	}
+	for _, row := range report.Rows {
+		if err := write(row.Key, row.Label, row.SalesFiguresResponse); err != nil {
+			return errors.Wrap(err, "[ExportUsecase.ExportSalesReport]")
+		}
+	}
+	if err := write(nil, "Total", report.Totals); err != nil {
+		return errors.Wrap(err, "[ExportUsecase.ExportSalesReport]")
+	}
+	return errors.Wrap(w.Close(), "[ExportUsecase.ExportSalesReport]")
+}
+
+// walk writes header and then every item of a list, a page at a time. The
+// header waits for the first page, so a list that cannot be loaded, say for
+// a bad filter, writes nothing.
+func walk[T any](w spreadsheet.Writer, header []any, load func(cursor string) (*response.Page[T], error), write func(item T) error) error {
+	page, err := load("")
+	if err != nil {
+		return err
+	}
+	if err := w.Row(header...); err != nil {
+		return err
+	}
+	for {
+		for _, item := range page.Items {
+			if err := write(item); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == nil {
+			return w.Close()
+		}
+		if page, err = load(*page.NextCursor); err != nil {
+			return err
+		}
+	}
+}
+
+func (u *exportUsecase) formatTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.In(u.calendar.Location()).Format(timeLayout)
+}
@@ -0,0 +1,144 @@
+package delivery
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/logging"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/spreadsheet"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type journalHandler struct {
+	journalUsecase domain.JournalUsecase
+}
+
+func NewJournalHandler(journalUsecase domain.JournalUsecase) *journalHandler {
+	return &journalHandler{journalUsecase: journalUsecase}
+}
+
+func (h *journalHandler) GetLedgerAccounts(c *gin.Context) {
+	accounts, err := h.journalUsecase.GetLedgerAccounts(c.Request.Context())
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[JournalHandler.GetLedgerAccounts]: Error getting ledger accounts"))
+		return
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+func (h *journalHandler) ReplaceLedgerAccounts(c *gin.Context) {
+	var req request.LedgerAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	accounts, err := h.journalUsecase.ReplaceLedgerAccounts(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[JournalHandler.ReplaceLedgerAccounts]: Error replacing ledger accounts"))
+		return
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+func (h *journalHandler) GetJournalEntry(c *gin.Context) {
+	date, err := time.Parse(businessday.Layout, c.Param("date"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid business date", nil))
+		return
+	}
+	var req request.JournalQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	entry, err := h.journalUsecase.GetJournalEntry(c.Request.Context(), date)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[JournalHandler.GetJournalEntry]: Error getting journal entry"))
+		return
+	}
+	if req.Format == constant.JournalFormatCSV {
+		attach(c, entry)
+		c.Status(http.StatusOK)
+		writeCSV(c, entry)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *journalHandler) ExportJournalEntry(c *gin.Context) {
+	date, err := time.Parse(businessday.Layout, c.Param("date"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid business date", nil))
+		return
+	}
+	var req request.JournalQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	entry, err := h.journalUsecase.ExportJournalEntry(c.Request.Context(), date)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[JournalHandler.ExportJournalEntry]: Error exporting journal entry"))
+		return
+	}
+	if req.Format == constant.JournalFormatCSV {
+		attach(c, entry)
+		c.Status(http.StatusCreated)
+		writeCSV(c, entry)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *journalHandler) GetJournalExports(c *gin.Context) {
+	var req request.JournalExportListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	exports, err := h.journalUsecase.GetJournalExports(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[JournalHandler.GetJournalExports]: Error getting journal exports"))
+		return
+	}
+	c.JSON(http.StatusOK, exports)
+}
+
+// attach sets the headers of the CSV file of entry
+func attach(c *gin.Context, entry *response.JournalEntryResponse) {
+	c.Header("Content-Type", spreadsheet.ContentType(spreadsheet.CSV))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="journal-%s.csv"`, entry.BusinessDate))
+}
+
+// writeCSV writes entry a line per row. The entry is complete by now, so the
+// only failure left is the client going away, which is logged.
+func writeCSV(c *gin.Context, entry *response.JournalEntryResponse) {
+	w, err := spreadsheet.New(spreadsheet.CSV, c.Writer, "")
+	if err == nil {
+		err = w.Row("business_date", "line_no", "account_code", "account_name", "description", "debit_baht", "credit_baht")
+	}
+	for _, line := range entry.Lines {
+		if err != nil {
+			break
+		}
+		err = w.Row(entry.BusinessDate, line.LineNo, line.AccountCode, line.AccountName, line.Description, line.DebitBaht, line.CreditBaht)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error(errors.Wrap(err, "[JournalHandler.writeCSV]: Error writing journal entry"))
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+)
+
+// journalExportKeys are the fields journal exports can be sorted by
+var journalExportKeys = pagination.Keys[models.JournalExport]{
+	IDColumn: "id",
+	ID:       func(e *models.JournalExport) uuid.UUID { return e.ID },
+	Sorts: map[string]pagination.Key[models.JournalExport]{
+		"business_date": {Expr: "business_date", Value: func(e *models.JournalExport) any { return e.BusinessDate }},
+	},
+}
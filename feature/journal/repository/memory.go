@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type journalMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewJournalMemoryRepository(store *memory.Store) domain.JournalRepository {
+	return &journalMemoryRepository{store: store}
+}
+
+func (r *journalMemoryRepository) GetLedgerAccounts(ctx context.Context) ([]*models.LedgerAccount, error) {
+	var accounts []*models.LedgerAccount
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		accounts = memory.Select(t.LedgerAccounts, nil)
+		memory.Sort(accounts,
+			func(a, b *models.LedgerAccount) int { return strings.Compare(a.Purpose, b.Purpose) },
+			func(a, b *models.LedgerAccount) int { return strings.Compare(a.Code, b.Code) },
+			func(a, b *models.LedgerAccount) int { return strings.Compare(a.ID.String(), b.ID.String()) },
+		)
+		return nil
+	})
+	return accounts, err
+}
+
+func (r *journalMemoryRepository) ReplaceLedgerAccounts(ctx context.Context, accounts []*models.LedgerAccount) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		clear(t.LedgerAccounts)
+		for _, account := range accounts {
+			if account.CategoryID != nil {
+				if _, ok := t.Categories[*account.CategoryID]; !ok {
+					return errors.Wrap(domain.NotFoundError("Category not found"), "[JournalMemoryRepository.ReplaceLedgerAccounts]")
+				}
+			}
+			if account.ID == uuid.Nil {
+				account.ID = uuid.New()
+			}
+			t.LedgerAccounts[account.ID] = *account
+		}
+		return nil
+	})
+}
+
+func (r *journalMemoryRepository) GetJournalTotals(ctx context.Context, date time.Time) ([]*models.JournalTotal, error) {
+	var totals []*models.JournalTotal
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		open := &models.JournalTotal{Kind: constant.JournalTotalOpen}
+		subtotal := &models.JournalTotal{Kind: constant.JournalTotalSubtotal}
+		total := &models.JournalTotal{Kind: constant.JournalTotalTotal}
+		discount := &models.JournalTotal{Kind: constant.JournalTotalDiscount}
+		totals = []*models.JournalTotal{open, subtotal, total, discount}
+
+		paid := make(map[uuid.UUID]bool)
+		for _, o := range t.Orders {
+			if o.BusinessDate == nil || !o.BusinessDate.Equal(date) {
+				continue
+			}
+			switch utils.DerefString(o.Status) {
+			case constant.OrderStatusOpen:
+				open.Amount++
+			case constant.OrderStatusPaid:
+				paid[o.ID] = true
+				subtotal.Amount += utils.DerefInt64(o.SubtotalBaht)
+				total.Amount += utils.DerefInt64(o.TotalBaht)
+				discount.Amount += min(utils.DerefInt64(o.DiscountBaht), utils.DerefInt64(o.SubtotalBaht))
+			}
+		}
+
+		// Groups are keyed like the SQL query's, with "" for a NULL key
+		groups := make(map[string]*models.JournalTotal)
+		add := func(kind string, key *string, label *string, amount int64) {
+			g, ok := groups[kind+":"+utils.DerefString(key)]
+			if !ok {
+				g = &models.JournalTotal{Kind: kind, Key: key, Label: label}
+				groups[kind+":"+utils.DerefString(key)] = g
+				totals = append(totals, g)
+			}
+			g.Amount += amount
+		}
+		for _, item := range t.OrderItems {
+			if !paid[item.OrderID] || item.CancelledAt != nil {
+				continue
+			}
+			var key, label *string
+			if menuItem, ok := t.MenuItems[item.MenuItemID]; ok && menuItem.CategoryID != nil {
+				if category, ok := t.Categories[*menuItem.CategoryID]; ok {
+					key, label = utils.Ptr(category.ID.String()), category.Name
+				}
+			}
+			add(constant.JournalTotalCategory, key, label, item.LineTotalBaht)
+		}
+		for _, p := range t.Payments {
+			if !paid[p.OrderID] || utils.DerefString(p.Status) != constant.PaymentStatusSucceeded {
+				continue
+			}
+			switch {
+			case p.AmountBaht > 0:
+				add(constant.JournalTotalPayment, p.Method, p.Method, p.AmountBaht)
+			case p.AmountBaht < 0:
+				add(constant.JournalTotalRefund, p.Method, p.Method, -p.AmountBaht)
+			}
+		}
+		return nil
+	})
+	return totals, err
+}
+
+func (r *journalMemoryRepository) GetJournalExport(ctx context.Context, date time.Time) (*models.JournalExport, error) {
+	var export *models.JournalExport
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		exports := memory.Select(t.JournalExports, func(e *models.JournalExport) bool { return e.BusinessDate.Equal(date) })
+		if len(exports) == 0 {
+			return errors.Wrap(domain.NotFoundError("Business day has not been exported"), "[JournalMemoryRepository.GetJournalExport]")
+		}
+		export = exports[0]
+		lines := memory.Select(t.JournalLines, func(l *models.JournalLine) bool { return l.ExportID == export.ID })
+		memory.Sort(lines, func(a, b *models.JournalLine) int { return memory.CompareInt(&a.LineNo, &b.LineNo) })
+		for _, line := range lines {
+			export.Lines = append(export.Lines, *line)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *journalMemoryRepository) CreateJournalExport(ctx context.Context, export *models.JournalExport) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.JournalExports, func(other *models.JournalExport) bool { return other.BusinessDate.Equal(export.BusinessDate) }) {
+			return errors.Wrap(domain.ConflictError("Business day has already been exported"), "[JournalMemoryRepository.CreateJournalExport]")
+		}
+		if export.ID == uuid.Nil {
+			export.ID = uuid.New()
+		}
+		if export.ExportedAt.IsZero() {
+			export.ExportedAt = time.Now()
+		}
+		for i := range export.Lines {
+			line := &export.Lines[i]
+			if line.ID == uuid.Nil {
+				line.ID = uuid.New()
+			}
+			line.ExportID = export.ID
+			t.JournalLines[line.ID] = *line
+		}
+		row := *export
+		row.Lines = nil
+		t.JournalExports[export.ID] = row
+		return nil
+	})
+}
+
+func (r *journalMemoryRepository) GetJournalExports(ctx context.Context, page domain.PageParams) (domain.Page[models.JournalExport], error) {
+	var exports domain.Page[models.JournalExport]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		var err error
+		exports, err = pagination.Slice(memory.Select(t.JournalExports, nil), page, journalExportKeys)
+		return errors.Wrap(err, "[JournalMemoryRepository.GetJournalExports]")
+	})
+	return exports, err
+}
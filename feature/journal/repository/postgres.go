@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"gorm.io/gorm"
+)
+
+type journalRepository struct {
+	db *gorm.DB
+}
+
+func NewJournalRepository(db *gorm.DB) domain.JournalRepository {
+	return &journalRepository{db: db}
+}
+
+func (r *journalRepository) GetLedgerAccounts(ctx context.Context) ([]*models.LedgerAccount, error) {
+	var accounts []*models.LedgerAccount
+	if err := database.Conn(ctx, r.db).Order("purpose ASC, code ASC, id ASC").Find(&accounts).Error; err != nil {
+		return nil, errors.Wrap(err, "[JournalRepository.GetLedgerAccounts]: Error querying database")
+	}
+	return accounts, nil
+}
+
+func (r *journalRepository) ReplaceLedgerAccounts(ctx context.Context, accounts []*models.LedgerAccount) error {
+	db := database.Conn(ctx, r.db)
+	if err := db.Where("1 = 1").Delete(&models.LedgerAccount{}).Error; err != nil {
+		return errors.Wrap(err, "[JournalRepository.ReplaceLedgerAccounts]: Error deleting ledger accounts")
+	}
+	if len(accounts) == 0 {
+		return nil
+	}
+	if err := db.Create(accounts).Error; err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return errors.Wrap(domain.NotFoundError("Category not found"), "[JournalRepository.ReplaceLedgerAccounts]")
+		}
+		return errors.Wrap(err, "[JournalRepository.ReplaceLedgerAccounts]: Error creating ledger accounts")
+	}
+	return nil
+}
+
+// journalTotalsQuery sums a business date in one round trip, a row per total.
+// A discount counts as far as the order's subtotal, which is all it takes off.
+// Refunds are succeeded payments of a negative amount, summed by method as
+// positive amounts.
+const journalTotalsQuery = `
+	SELECT CAST(@open AS varchar) AS kind, NULL AS total_key, NULL AS total_label, COUNT(*) AS amount
+	FROM orders o
+	WHERE o.status = @opened AND o.business_date = @date
+	UNION ALL
+	SELECT CAST(@subtotal AS varchar), NULL, NULL, COALESCE(SUM(o.subtotal_baht), 0)
+	FROM orders o
+	WHERE o.status = @paid AND o.business_date = @date
+	UNION ALL
+	SELECT CAST(@total AS varchar), NULL, NULL, COALESCE(SUM(o.total_baht), 0)
+	FROM orders o
+	WHERE o.status = @paid AND o.business_date = @date
+	UNION ALL
+	SELECT CAST(@discount AS varchar), NULL, NULL,
+		COALESCE(SUM(CASE WHEN o.discount_baht > o.subtotal_baht THEN o.subtotal_baht ELSE o.discount_baht END), 0)
+	FROM orders o
+	WHERE o.status = @paid AND o.business_date = @date
+	UNION ALL
+	SELECT CAST(@category AS varchar), CAST(c.id AS varchar), c.name, SUM(oi.line_total_baht)
+	FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		JOIN menu_items mi ON mi.id = oi.menu_item_id
+		LEFT JOIN categories c ON c.id = mi.category_id
+	WHERE o.status = @paid AND o.business_date = @date AND oi.cancelled_at IS NULL
+	GROUP BY c.id, c.name
+	UNION ALL
+	SELECT CAST(@payment AS varchar), p.method, p.method, SUM(p.amount_baht)
+	FROM payments p
+		JOIN orders o ON o.id = p.order_id
+	WHERE o.status = @paid AND o.business_date = @date AND p.status = @succeeded AND p.amount_baht > 0
+	GROUP BY p.method
+	UNION ALL
+	SELECT CAST(@refund AS varchar), p.method, p.method, -SUM(p.amount_baht)
+	FROM payments p
+		JOIN orders o ON o.id = p.order_id
+	WHERE o.status = @paid AND o.business_date = @date AND p.status = @succeeded AND p.amount_baht < 0
+	GROUP BY p.method`
+
+func (r *journalRepository) GetJournalTotals(ctx context.Context, date time.Time) ([]*models.JournalTotal, error) {
+	args := map[string]any{
+		"open":      constant.JournalTotalOpen,
+		"subtotal":  constant.JournalTotalSubtotal,
+		"total":     constant.JournalTotalTotal,
+		"discount":  constant.JournalTotalDiscount,
+		"category":  constant.JournalTotalCategory,
+		"payment":   constant.JournalTotalPayment,
+		"refund":    constant.JournalTotalRefund,
+		"opened":    constant.OrderStatusOpen,
+		"paid":      constant.OrderStatusPaid,
+		"succeeded": constant.PaymentStatusSucceeded,
+		"date":      date,
+	}
+	var totals []*models.JournalTotal
+	if err := database.Conn(ctx, r.db).Raw(journalTotalsQuery, args).Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, "[JournalRepository.GetJournalTotals]: Error querying database")
+	}
+	return totals, nil
+}
+
+func (r *journalRepository) GetJournalExport(ctx context.Context, date time.Time) (*models.JournalExport, error) {
+	var export models.JournalExport
+	err := database.Conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no ASC") }).
+		Where("business_date = ?", date).
+		First(&export).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Business day has not been exported"), "[JournalRepository.GetJournalExport]")
+		}
+		return nil, errors.Wrap(err, "[JournalRepository.GetJournalExport]: Error querying database")
+	}
+	return &export, nil
+}
+
+func (r *journalRepository) CreateJournalExport(ctx context.Context, export *models.JournalExport) error {
+	if err := database.Conn(ctx, r.db).Create(export).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("Business day has already been exported"), "[JournalRepository.CreateJournalExport]")
+		}
+		return errors.Wrap(err, "[JournalRepository.CreateJournalExport]: Error creating journal export")
+	}
+	return nil
+}
+
+func (r *journalRepository) GetJournalExports(ctx context.Context, page domain.PageParams) (domain.Page[models.JournalExport], error) {
+	exports, err := pagination.Find(database.Conn(ctx, r.db), page, journalExportKeys)
+	if err != nil {
+		return domain.Page[models.JournalExport]{}, errors.Wrap(err, "[JournalRepository.GetJournalExports]")
+	}
+	return exports, nil
+}
@@ -0,0 +1,471 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+// paymentOrder is the order payment lines are posted in; methods outside it
+// follow in alphabetical order
+var paymentOrder = []string{constant.PaymentMethodCash, constant.PaymentMethodCard, constant.PaymentMethodPromptpay}
+
+var paymentLabels = map[string]string{
+	constant.PaymentMethodCash:      "Cash",
+	constant.PaymentMethodCard:      "Card",
+	constant.PaymentMethodPromptpay: "PromptPay",
+}
+
+type journalUsecase struct {
+	journalRepository domain.JournalRepository
+	transactor        domain.Transactor
+	auditUsecase      domain.AuditUsecase
+	calendar          businessday.Calendar
+	sales             config.SalesConfig
+}
+
+func NewJournalUsecase(journalRepository domain.JournalRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase, calendar businessday.Calendar, sales config.SalesConfig) domain.JournalUsecase {
+	return &journalUsecase{journalRepository: journalRepository, transactor: transactor, auditUsecase: auditUsecase, calendar: calendar, sales: sales}
+}
+
+func (u *journalUsecase) GetLedgerAccounts(ctx context.Context) (*response.Page[*response.LedgerAccountResponse], error) {
+	ctx, span := tracing.Start(ctx, "JournalUsecase.GetLedgerAccounts")
+	defer span.End()
+
+	accounts, err := u.journalRepository.GetLedgerAccounts(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[JournalUsecase.GetLedgerAccounts]: Error getting ledger accounts")
+	}
+	return response.SinglePage(u.buildLedgerAccountResponses(accounts)), nil
+}
+
+// ReplaceLedgerAccounts swaps the whole mapping at once, so the accounts never
+// stand half changed. Each purpose takes one account, except sales, which may
+// also take one per category.
+func (u *journalUsecase) ReplaceLedgerAccounts(ctx context.Context, req *request.LedgerAccountsRequest) (*response.Page[*response.LedgerAccountResponse], error) {
+	ctx, span := tracing.Start(ctx, "JournalUsecase.ReplaceLedgerAccounts")
+	defer span.End()
+
+	fields := make(map[string]string)
+	mapped := make(map[string]bool)
+	accounts := make([]*models.LedgerAccount, len(req.Accounts))
+	for i, account := range req.Accounts {
+		field := fmt.Sprintf("accounts[%d]", i)
+		if account.CategoryID != nil && account.Purpose != constant.LedgerPurposeSales {
+			fields[field+".category_id"] = "only sales accounts take a category"
+		}
+		key := ledgerKey(account.Purpose, account.CategoryID)
+		if mapped[key] {
+			fields[field+".purpose"] = "is mapped more than once"
+		}
+		mapped[key] = true
+
+		accounts[i] = &models.LedgerAccount{
+			Purpose:    account.Purpose,
+			CategoryID: account.CategoryID,
+			Code:       account.Code,
+		}
+		if account.Name != "" {
+			accounts[i].Name = utils.Ptr(account.Name)
+		}
+	}
+	if len(fields) > 0 {
+		return nil, errors.Wrap(domain.ValidationError("Invalid ledger accounts", fields), "[JournalUsecase.ReplaceLedgerAccounts]")
+	}
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := u.journalRepository.GetLedgerAccounts(ctx)
+		if err != nil {
+			return errors.Wrap(err, "[JournalUsecase.ReplaceLedgerAccounts]: Error getting ledger accounts")
+		}
+		if err := u.journalRepository.ReplaceLedgerAccounts(ctx, accounts); err != nil {
+			return errors.Wrap(err, "[JournalUsecase.ReplaceLedgerAccounts]: Error replacing ledger accounts")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityJournal, "accounts", u.buildLedgerAccountResponses(before), u.buildLedgerAccountResponses(accounts)); err != nil {
+			return errors.Wrap(err, "[JournalUsecase.ReplaceLedgerAccounts]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetLedgerAccounts(ctx)
+}
+
+// GetJournalEntry returns the lines a business date was exported with, or
+// builds them from its orders as they stand when it has not been exported
+func (u *journalUsecase) GetJournalEntry(ctx context.Context, date time.Time) (*response.JournalEntryResponse, error) {
+	ctx, span := tracing.Start(ctx, "JournalUsecase.GetJournalEntry")
+	defer span.End()
+
+	export, err := u.journalRepository.GetJournalExport(ctx, date)
+	if err == nil {
+		return u.buildJournalEntryResponse(date, export.Lines, export), nil
+	}
+	if domain.ErrorKindOf(err) != domain.ErrorKindNotFound {
+		return nil, errors.Wrap(err, "[JournalUsecase.GetJournalEntry]: Error getting journal export")
+	}
+
+	lines, _, err := u.buildLines(ctx, date)
+	if err != nil {
+		return nil, errors.Wrap(err, "[JournalUsecase.GetJournalEntry]")
+	}
+	return u.buildJournalEntryResponse(date, lines, nil), nil
+}
+
+// ExportJournalEntry records the entry of a business date once the day is over
+// and its orders are settled. The lines are stored as exported, so orders
+// changed afterwards do not alter what the accounts were given.
+func (u *journalUsecase) ExportJournalEntry(ctx context.Context, date time.Time) (*response.JournalEntryResponse, error) {
+	ctx, span := tracing.Start(ctx, "JournalUsecase.ExportJournalEntry")
+	defer span.End()
+
+	if !date.Before(u.calendar.Today()) {
+		return nil, errors.Wrap(domain.PreconditionFailedError("Business day is not over"), "[JournalUsecase.ExportJournalEntry]")
+	}
+
+	var export *models.JournalExport
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := u.journalRepository.GetJournalExport(ctx, date)
+		if err == nil {
+			return errors.Wrap(domain.ConflictError("Business day has already been exported"), "[JournalUsecase.ExportJournalEntry]")
+		}
+		if domain.ErrorKindOf(err) != domain.ErrorKindNotFound {
+			return errors.Wrap(err, "[JournalUsecase.ExportJournalEntry]: Error getting journal export")
+		}
+
+		lines, open, err := u.buildLines(ctx, date)
+		if err != nil {
+			return errors.Wrap(err, "[JournalUsecase.ExportJournalEntry]")
+		}
+		if open > 0 {
+			return errors.Wrap(domain.PreconditionFailedError("Business day still has open orders"), "[JournalUsecase.ExportJournalEntry]")
+		}
+
+		export = &models.JournalExport{
+			BusinessDate: date,
+			ExportedBy:   domain.ActorFromContext(ctx).UserID,
+			Lines:        lines,
+		}
+		export.DebitBaht, export.CreditBaht = sumLines(lines)
+		if err := u.journalRepository.CreateJournalExport(ctx, export); err != nil {
+			return errors.Wrap(err, "[JournalUsecase.ExportJournalEntry]: Error recording journal export")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionExport, constant.AuditEntityJournal, date.Format(businessday.Layout), nil, u.buildJournalExportResponse(export)); err != nil {
+			return errors.Wrap(err, "[JournalUsecase.ExportJournalEntry]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.buildJournalEntryResponse(date, export.Lines, export), nil
+}
+
+// GetJournalExports pages the exported business days, latest first unless the
+// query sorts otherwise
+func (u *journalUsecase) GetJournalExports(ctx context.Context, query *request.JournalExportListQuery) (*response.Page[*response.JournalExportResponse], error) {
+	ctx, span := tracing.Start(ctx, "JournalUsecase.GetJournalExports")
+	defer span.End()
+
+	page, err := pagination.Parse(query.PageQuery, query.Sort, "-business_date")
+	if err != nil {
+		return nil, err
+	}
+	exports, err := u.journalRepository.GetJournalExports(ctx, page)
+	if err != nil {
+		return nil, errors.Wrap(err, "[JournalUsecase.GetJournalExports]: Error getting journal exports")
+	}
+
+	exportResponses := make([]*response.JournalExportResponse, len(exports.Items))
+	for i, export := range exports.Items {
+		exportResponses[i] = u.buildJournalExportResponse(export)
+	}
+	return &response.Page[*response.JournalExportResponse]{Items: exportResponses, NextCursor: exports.NextCursor}, nil
+}
+
+// buildLines posts the paid orders of a business date to the mapped accounts,
+// and counts the orders of the date still open. Payments are debited by method
+// and discounts net of VAT; sales are credited net of VAT by category, before
+// discounts, as is whatever the orders charged beyond their items less
+// discounts, to service charge, and the VAT charged to output VAT. Payments
+// over or short of what the orders charged go to over/short. Each net figure is
+// rounded on its own, so the baht the roundings leave over is taken up by the
+// largest sales line. Refunds are debited to refunds as they were paid back,
+// VAT included, and credited to the method they were paid back by.
+func (u *journalUsecase) buildLines(ctx context.Context, date time.Time) ([]models.JournalLine, int64, error) {
+	totals, err := u.journalRepository.GetJournalTotals(ctx, date)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "[JournalUsecase.buildLines]: Error getting journal totals")
+	}
+	ledger, err := u.journalRepository.GetLedgerAccounts(ctx)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "[JournalUsecase.buildLines]: Error getting ledger accounts")
+	}
+	accounts := make(map[string]*models.LedgerAccount, len(ledger))
+	for _, account := range ledger {
+		accounts[ledgerKey(account.Purpose, account.CategoryID)] = account
+	}
+
+	var open, subtotal, total, discounted int64
+	var categories, payments, refunds []*models.JournalTotal
+	for _, row := range totals {
+		switch row.Kind {
+		case constant.JournalTotalOpen:
+			open += row.Amount
+		case constant.JournalTotalSubtotal:
+			subtotal += row.Amount
+		case constant.JournalTotalTotal:
+			total += row.Amount
+		case constant.JournalTotalDiscount:
+			discounted += row.Amount
+		case constant.JournalTotalRefund:
+			refunds = append(refunds, row)
+		case constant.JournalTotalCategory:
+			categories = append(categories, row)
+		case constant.JournalTotalPayment:
+			payments = append(payments, row)
+		}
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		if (a.Label == nil) != (b.Label == nil) {
+			return b.Label == nil
+		}
+		return utils.DerefString(a.Label) < utils.DerefString(b.Label)
+	})
+	sortByMethod(payments)
+	sortByMethod(refunds)
+
+	serviceCharge := total - (subtotal - discounted)
+	if serviceCharge < 0 {
+		message := fmt.Sprintf("Orders of %s charged %d baht less than their items less discounts", date.Format(businessday.Layout), -serviceCharge)
+		return nil, 0, errors.Wrap(domain.PreconditionFailedError(message), "[JournalUsecase.buildLines]")
+	}
+
+	vat := int64(math.Round(float64(total) * u.sales.VATPercent / (100 + u.sales.VATPercent)))
+	discount := u.net(discounted)
+	service := u.net(serviceCharge)
+
+	var debits, credits []line
+	paid := int64(0)
+	for _, p := range payments {
+		method := utils.DerefString(p.Key)
+		debits = append(debits, line{purpose: method, description: methodLabel(method) + " received", debit: p.Amount})
+		paid += p.Amount
+	}
+	debits = append(debits, line{purpose: constant.LedgerPurposeDiscounts, description: "Discounts given", debit: discount})
+	refunded := int64(0)
+	for _, r := range refunds {
+		refunded += r.Amount
+	}
+	debits = append(debits, line{purpose: constant.LedgerPurposeRefunds, description: "Refunds given", debit: refunded})
+
+	sales := int64(0)
+	largest := -1
+	for _, c := range categories {
+		name := utils.DerefString(c.Label)
+		if c.Key == nil {
+			name = "Uncategorised"
+		}
+		credits = append(credits, line{purpose: constant.LedgerPurposeSales, category: c.Key, description: "Sales: " + name, credit: u.net(c.Amount)})
+		sales += u.net(c.Amount)
+		if largest < 0 || credits[len(credits)-1].credit > credits[largest].credit {
+			largest = len(credits) - 1
+		}
+	}
+	credits = append(credits, line{purpose: constant.LedgerPurposeServiceCharge, description: "Service charge", credit: service})
+
+	// Each rounded figure is off by half a baht at most, so anything more is an
+	// amount the entry leaves out, which must not pass for rounding
+	remainder := total - vat + discount - sales - service
+	if rounded := int64(len(categories) + 3); 2*remainder > rounded || -2*remainder > rounded {
+		message := fmt.Sprintf("Entry of %s leaves %d baht unposted", date.Format(businessday.Layout), remainder)
+		return nil, 0, errors.Wrap(domain.PreconditionFailedError(message), "[JournalUsecase.buildLines]")
+	}
+	if largest >= 0 {
+		credits[largest].credit += remainder
+	} else {
+		vat += remainder
+	}
+	credits = append(credits, line{
+		purpose:     constant.LedgerPurposeVATOutput,
+		description: "Output VAT " + strconv.FormatFloat(u.sales.VATPercent, 'g', -1, 64) + "%",
+		credit:      vat,
+	})
+
+	for _, r := range refunds {
+		method := utils.DerefString(r.Key)
+		credits = append(credits, line{purpose: method, description: methodLabel(method) + " refunded", credit: r.Amount})
+	}
+
+	switch over := paid - total; {
+	case over > 0:
+		credits = append(credits, line{purpose: constant.LedgerPurposeOverShort, description: "Payments over sales", credit: over})
+	case over < 0:
+		debits = append(debits, line{purpose: constant.LedgerPurposeOverShort, description: "Payments short of sales", debit: -over})
+	}
+
+	var lines []models.JournalLine
+	for _, l := range append(debits, credits...) {
+		if l.debit == 0 && l.credit == 0 {
+			continue
+		}
+		account, err := u.account(accounts, l)
+		if err != nil {
+			return nil, 0, err
+		}
+		lines = append(lines, models.JournalLine{
+			LineNo:      len(lines) + 1,
+			Purpose:     l.purpose,
+			AccountCode: account.Code,
+			AccountName: account.Name,
+			Description: l.description,
+			DebitBaht:   l.debit,
+			CreditBaht:  l.credit,
+		})
+	}
+	if debit, credit := sumLines(lines); debit != credit {
+		return nil, 0, errors.Errorf("[JournalUsecase.buildLines]: Entry of %s does not balance: %d debit, %d credit", date.Format(businessday.Layout), debit, credit)
+	}
+	return lines, open, nil
+}
+
+// sortByMethod puts payment or refund totals in paymentOrder, then other
+// methods by name
+func sortByMethod(totals []*models.JournalTotal) {
+	sort.SliceStable(totals, func(i, j int) bool {
+		a, b := rank(utils.DerefString(totals[i].Key)), rank(utils.DerefString(totals[j].Key))
+		if a != b {
+			return a < b
+		}
+		return utils.DerefString(totals[i].Key) < utils.DerefString(totals[j].Key)
+	})
+}
+
+// line is a journal line before it is posted to an account
+type line struct {
+	purpose     string
+	category    *string
+	description string
+	debit       int64
+	credit      int64
+}
+
+// account is the account l posts to. Sales of a category without an account of
+// its own go to the sales account without a category.
+func (u *journalUsecase) account(accounts map[string]*models.LedgerAccount, l line) (*models.LedgerAccount, error) {
+	if l.category != nil {
+		if account, ok := accounts[l.purpose+":"+*l.category]; ok {
+			return account, nil
+		}
+	}
+	if account, ok := accounts[l.purpose+":"]; ok {
+		return account, nil
+	}
+	return nil, errors.Wrap(domain.PreconditionFailedError("No ledger account is mapped for "+l.purpose), "[JournalUsecase.account]")
+}
+
+// ledgerKey identifies what an account is mapped for
+func ledgerKey(purpose string, categoryID *uuid.UUID) string {
+	if categoryID == nil {
+		return purpose + ":"
+	}
+	return purpose + ":" + categoryID.String()
+}
+
+// net takes the VAT back out of an amount that includes it
+func (u *journalUsecase) net(amount int64) int64 {
+	return int64(math.Round(float64(amount) * 100 / (100 + u.sales.VATPercent)))
+}
+
+// methodLabel names a payment method in line descriptions
+func methodLabel(method string) string {
+	if label, ok := paymentLabels[method]; ok {
+		return label
+	}
+	return method
+}
+
+func rank(method string) int {
+	for i, m := range paymentOrder {
+		if m == method {
+			return i
+		}
+	}
+	return len(paymentOrder)
+}
+
+func sumLines(lines []models.JournalLine) (int64, int64) {
+	var debit, credit int64
+	for _, l := range lines {
+		debit += l.DebitBaht
+		credit += l.CreditBaht
+	}
+	return debit, credit
+}
+
+func (u *journalUsecase) buildLedgerAccountResponses(accounts []*models.LedgerAccount) []*response.LedgerAccountResponse {
+	accountResponses := make([]*response.LedgerAccountResponse, len(accounts))
+	for i, account := range accounts {
+		accountResponses[i] = &response.LedgerAccountResponse{
+			ID:         account.ID,
+			Purpose:    account.Purpose,
+			CategoryID: account.CategoryID,
+			Code:       account.Code,
+			Name:       utils.DerefString(account.Name),
+		}
+	}
+	return accountResponses
+}
+
+func (u *journalUsecase) buildJournalEntryResponse(date time.Time, lines []models.JournalLine, export *models.JournalExport) *response.JournalEntryResponse {
+	entry := &response.JournalEntryResponse{
+		BusinessDate: date.Format(businessday.Layout),
+		Lines:        make([]response.JournalLineResponse, len(lines)),
+	}
+	entry.DebitBaht, entry.CreditBaht = sumLines(lines)
+	for i, l := range lines {
+		entry.Lines[i] = response.JournalLineResponse{
+			LineNo:      l.LineNo,
+			Purpose:     l.Purpose,
+			AccountCode: l.AccountCode,
+			AccountName: utils.DerefString(l.AccountName),
+			Description: l.Description,
+			DebitBaht:   l.DebitBaht,
+			CreditBaht:  l.CreditBaht,
+		}
+	}
+	if export != nil {
+		entry.Export = u.buildJournalExportResponse(export)
+	}
+	return entry
+}
+
+func (u *journalUsecase) buildJournalExportResponse(export *models.JournalExport) *response.JournalExportResponse {
+	return &response.JournalExportResponse{
+		BusinessDate: export.BusinessDate.Format(businessday.Layout),
+		ExportedAt:   export.ExportedAt,
+		ExportedBy:   export.ExportedBy,
+		DebitBaht:    export.DebitBaht,
+		CreditBaht:   export.CreditBaht,
+	}
+}
@@ -1,15 +1,17 @@
 package delivery
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type menuItemHandler struct {
@@ -21,11 +23,15 @@ func NewMenuItemHandler(menuItemUsecase domain.MenuItemUsecase) *menuItemHandler
 }
 
 func (h *menuItemHandler) GetAllMenuItems(c *gin.Context) {
-	menuItems, err := h.menuItemUsecase.GetAllMenuItems()
+	var req request.MenuItemListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	menuItems, err := h.menuItemUsecase.GetAllMenuItems(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[MenuItemHandler.GetAllMenuItems]: Error getting menu items")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.GetAllMenuItems]: Error getting menu items"))
 		return
 	}
 	c.JSON(http.StatusOK, menuItems)
@@ -34,15 +40,13 @@ func (h *menuItemHandler) GetAllMenuItems(c *gin.Context) {
 func (h *menuItemHandler) GetMenuItemByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid menu item ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid menu item ID", nil))
 		return
 	}
 
-	menuItem, err := h.menuItemUsecase.GetMenuItemByID(id)
+	menuItem, err := h.menuItemUsecase.GetMenuItemByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[MenuItemHandler.GetMenuItemByID]: Error getting menu item")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.GetMenuItemByID]: Error getting menu item"))
 		return
 	}
 	c.JSON(http.StatusOK, menuItem)
@@ -51,15 +55,13 @@ func (h *menuItemHandler) GetMenuItemByID(c *gin.Context) {
 func (h *menuItemHandler) CreateMenuItem(c *gin.Context) {
 	var req request.MenuItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	menuItem, err := h.menuItemUsecase.CreateMenuItem(&req)
+	menuItem, err := h.menuItemUsecase.CreateMenuItem(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[MenuItemHandler.CreateMenuItem]: Error creating menu item")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.CreateMenuItem]: Error creating menu item"))
 		return
 	}
 	c.JSON(http.StatusCreated, menuItem)
@@ -68,21 +70,19 @@ func (h *menuItemHandler) CreateMenuItem(c *gin.Context) {
 func (h *menuItemHandler) UpdateMenuItem(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid menu item ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid menu item ID", nil))
 		return
 	}
 
 	var req request.MenuItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	menuItem, err := h.menuItemUsecase.UpdateMenuItem(id, &req)
+	menuItem, err := h.menuItemUsecase.UpdateMenuItem(c.Request.Context(), id, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[MenuItemHandler.UpdateMenuItem]: Error updating menu item")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.UpdateMenuItem]: Error updating menu item"))
 		return
 	}
 	c.JSON(http.StatusOK, menuItem)
@@ -91,26 +91,96 @@ func (h *menuItemHandler) UpdateMenuItem(c *gin.Context) {
 func (h *menuItemHandler) DeleteMenuItem(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid menu item ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid menu item ID", nil))
 		return
 	}
 
-	if err := h.menuItemUsecase.DeleteMenuItem(id); err != nil {
-		err = errors.Wrap(err, "[MenuItemHandler.DeleteMenuItem]: Error deleting menu item")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+	if err := h.menuItemUsecase.DeleteMenuItem(c.Request.Context(), id); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.DeleteMenuItem]: Error deleting menu item"))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Menu item deleted successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Menu item deleted successfully"})
 }
 
 func (h *menuItemHandler) GetAvailableModifiers(c *gin.Context) {
-	modifiers, err := h.menuItemUsecase.GetAvailableModifiers()
+	modifiers, err := h.menuItemUsecase.GetAvailableModifiers(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[MenuItemHandler.GetAvailableModifiers]: Error getting modifiers")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.GetAvailableModifiers]: Error getting modifiers"))
 		return
 	}
 	c.JSON(http.StatusOK, modifiers)
 }
+
+func (h *menuItemHandler) GetMenu(c *gin.Context) {
+	var req request.MenuQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	menuItems, err := h.menuItemUsecase.GetMenu(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.GetMenu]: Error getting menu"))
+		return
+	}
+	c.JSON(http.StatusOK, menuItems)
+}
+
+func (h *menuItemHandler) GetAvailability(c *gin.Context) {
+	availability, err := h.menuItemUsecase.GetAvailability(c.Request.Context())
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.GetAvailability]: Error getting availability"))
+		return
+	}
+	c.JSON(http.StatusOK, availability)
+}
+
+func (h *menuItemHandler) SetAvailability(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid menu item ID", nil))
+		return
+	}
+
+	var req request.MenuItemAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	availability, err := h.menuItemUsecase.SetAvailability(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.SetAvailability]: Error setting availability"))
+		return
+	}
+	c.JSON(http.StatusOK, availability)
+}
+
+// StreamAvailability sends server-sent events until the client goes away or
+// the server shuts down; an EventSource then reconnects and gets the full
+// snapshot again. A comment goes out while nothing changes, so proxies keep
+// the connection open.
+func (h *menuItemHandler) StreamAvailability(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	// Tells nginx not to buffer the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	err := h.menuItemUsecase.WatchAvailability(c.Request.Context(), func(availability *response.MenuItemAvailabilityResponse) error {
+		c.SSEvent(constant.MenuAvailabilityEvent, availability)
+		c.Writer.Flush()
+		return nil
+	}, func() error {
+		if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	// Once an event is out, an error can no longer be rendered; the client
+	// sees the stream end and reconnects
+	if err != nil && !c.Writer.Written() {
+		utils.RenderError(c, errors.Wrap(err, "[MenuItemHandler.StreamAvailability]: Error watching availability"))
+	}
+}
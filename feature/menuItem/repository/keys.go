@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+// menuItemKeys are the fields menu items can be sorted by
+var menuItemKeys = pagination.Keys[models.MenuItem]{
+	IDColumn: "id",
+	ID:       func(m *models.MenuItem) uuid.UUID { return m.ID },
+	Sorts: map[string]pagination.Key[models.MenuItem]{
+		"name":       {Expr: "COALESCE(name, '')", Value: func(m *models.MenuItem) any { return utils.DerefString(m.Name) }},
+		"price_baht": {Expr: "COALESCE(price_baht, 0)", Value: func(m *models.MenuItem) any { return utils.DerefInt64(m.PriceBaht) }},
+	},
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+type menuItemMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewMenuItemMemoryRepository(store *memory.Store) domain.MenuItemRepository {
+	return &menuItemMemoryRepository{store: store}
+}
+
+func (r *menuItemMemoryRepository) GetMenuItems(ctx context.Context, query *request.MenuItemListQuery, page domain.PageParams) (domain.Page[models.MenuItem], error) {
+	var menuItems domain.Page[models.MenuItem]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		rows := memory.Select(t.MenuItems, func(m *models.MenuItem) bool {
+			switch {
+			case query.CategoryID != "" && (m.CategoryID == nil || m.CategoryID.String() != query.CategoryID):
+				return false
+			case query.Active != nil && utils.DerefBool(m.Active) != *query.Active:
+				return false
+			}
+			return true
+		})
+
+		var err error
+		if menuItems, err = pagination.Slice(rows, page, menuItemKeys); err != nil {
+			return errors.Wrap(err, "[MenuItemMemoryRepository.GetMenuItems]: Error getting menu items")
+		}
+		for _, menuItem := range menuItems.Items {
+			preloadMenu(t, menuItem)
+		}
+		return nil
+	})
+	return menuItems, err
+}
+
+func (r *menuItemMemoryRepository) GetMenuItemByID(ctx context.Context, id uuid.UUID) (*models.MenuItem, error) {
+	var menuItem models.MenuItem
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.MenuItems[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Menu item not found"), "[MenuItemMemoryRepository.GetMenuItemByID]")
+		}
+		menuItem = found
+		preloadMenu(t, &menuItem)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &menuItem, nil
+}
+
+func (r *menuItemMemoryRepository) CreateMenuItem(ctx context.Context, menuItem *models.MenuItem) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if menuItem.ID == uuid.Nil {
+			menuItem.ID = uuid.New()
+		}
+		if menuItem.Active == nil {
+			active := true
+			menuItem.Active = &active
+		}
+		return r.save(t, menuItem, "[MenuItemMemoryRepository.CreateMenuItem]")
+	})
+}
+
+func (r *menuItemMemoryRepository) UpdateMenuItem(ctx context.Context, menuItem *models.MenuItem) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, menuItem, "[MenuItemMemoryRepository.UpdateMenuItem]")
+	})
+}
+
+func (r *menuItemMemoryRepository) save(t *memory.Tables, menuItem *models.MenuItem, op string) error {
+	if memory.Any(t.MenuItems, func(other *models.MenuItem) bool {
+		return other.ID != menuItem.ID && memory.EqualString(other.SKU, menuItem.SKU)
+	}) {
+		return errors.Wrap(domain.ConflictError("A menu item with this SKU already exists"), op)
+	}
+	if menuItem.CategoryID != nil {
+		if _, ok := t.Categories[*menuItem.CategoryID]; !ok {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, op+": Category does not exist")
+		}
+	}
+
+	row := *menuItem
+	row.Category = nil
+	row.Availability = nil
+	row.Schedules = nil
+	row.ModifierGroups = nil
+	t.MenuItems[row.ID] = row
+	return nil
+}
+
+// DeleteMenuItem refuses to delete an item that has been ordered, like ON DELETE
+// RESTRICT, and drops its availability, schedules and modifier group links,
+// like ON DELETE CASCADE
+func (r *menuItemMemoryRepository) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.OrderItems, func(item *models.OrderItem) bool { return item.MenuItemID == id }) {
+			return errors.Wrap(domain.ConflictError("Menu item is used by existing orders"), "[MenuItemMemoryRepository.DeleteMenuItem]")
+		}
+		delete(t.MenuItems, id)
+		delete(t.MenuItemAvailability, id)
+		t.DeleteMenuSchedules(func(s *models.MenuSchedule) bool { return s.MenuItemID != nil && *s.MenuItemID == id })
+		for key := range t.MenuItemModifierGroups {
+			if key.MenuItemID == id {
+				delete(t.MenuItemModifierGroups, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *menuItemMemoryRepository) GetAllModifiers(ctx context.Context) ([]*models.Modifier, error) {
+	var modifiers []*models.Modifier
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		modifiers = memory.Select(t.Modifiers, nil)
+		memory.Sort(modifiers, func(a, b *models.Modifier) int { return memory.CompareString(a.Name, b.Name) })
+		return nil
+	})
+	return modifiers, err
+}
+
+func (r *menuItemMemoryRepository) GetAvailability(ctx context.Context, ids []uuid.UUID) ([]*models.MenuItem, error) {
+	var menuItems []*models.MenuItem
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		menuItems = memory.Select(t.MenuItems, func(m *models.MenuItem) bool { return ids == nil || slices.Contains(ids, m.ID) })
+		memory.Sort(menuItems,
+			func(a, b *models.MenuItem) int { return memory.CompareString(a.Name, b.Name) },
+			func(a, b *models.MenuItem) int { return strings.Compare(a.ID.String(), b.ID.String()) },
+		)
+		for _, menuItem := range menuItems {
+			preloadAvailability(t, menuItem)
+		}
+		return nil
+	})
+	return menuItems, err
+}
+
+func (r *menuItemMemoryRepository) GetMenu(ctx context.Context) ([]*models.MenuItem, error) {
+	var menuItems []*models.MenuItem
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		menuItems = memory.Select(t.MenuItems, func(m *models.MenuItem) bool { return utils.DerefBool(m.Active) })
+		memory.Sort(menuItems,
+			func(a, b *models.MenuItem) int { return memory.CompareString(a.Name, b.Name) },
+			func(a, b *models.MenuItem) int { return strings.Compare(a.ID.String(), b.ID.String()) },
+		)
+		for _, menuItem := range menuItems {
+			preloadMenu(t, menuItem)
+		}
+		return nil
+	})
+	return menuItems, err
+}
+
+func (r *menuItemMemoryRepository) GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error) {
+	var table models.DiningTable
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.DiningTables[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Table not found"), "[MenuItemMemoryRepository.GetTableByID]")
+		}
+		table = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *menuItemMemoryRepository) SaveAvailability(ctx context.Context, availability *models.MenuItemAvailability) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if _, ok := t.MenuItems[availability.MenuItemID]; !ok {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, "[MenuItemMemoryRepository.SaveAvailability]: Menu item does not exist")
+		}
+		row := *availability
+		row.MenuItem = nil
+		row.Updater = nil
+		t.MenuItemAvailability[row.MenuItemID] = row
+		return nil
+	})
+}
+
+// preloadMenu fills in what tells whether a menu item is on the menu: its
+// category with the category's schedules, its own schedules and its availability
+func preloadMenu(t *memory.Tables, menuItem *models.MenuItem) {
+	menuItem.Category = nil
+	if menuItem.CategoryID != nil {
+		if category, ok := t.Categories[*menuItem.CategoryID]; ok {
+			category.Schedules = t.SchedulesOfCategory(category.ID)
+			category.ModifierGroups = t.ModifierGroupsOfCategory(category.ID)
+			menuItem.Category = &category
+		}
+	}
+	menuItem.Schedules = t.SchedulesOfMenuItem(menuItem.ID)
+	menuItem.ModifierGroups = t.ModifierGroupsOfMenuItem(menuItem.ID)
+	preloadAvailability(t, menuItem)
+}
+
+func preloadAvailability(t *memory.Tables, menuItem *models.MenuItem) {
+	menuItem.Availability = nil
+	if availability, ok := t.MenuItemAvailability[menuItem.ID]; ok {
+		menuItem.Availability = &availability
+	}
+}
@@ -1,11 +1,17 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type menuItemRepository struct {
@@ -16,50 +22,120 @@ func NewMenuItemRepository(db *gorm.DB) domain.MenuItemRepository {
 	return &menuItemRepository{db: db}
 }
 
-func (r *menuItemRepository) GetAllMenuItems() ([]*models.MenuItem, error) {
-	var menuItemsList []*models.MenuItem
-	if err := r.db.Preload("Category").Order("name ASC").Find(&menuItemsList).Error; err != nil {
-		return nil, errors.Wrap(err, "[MenuItemRepository.GetAllMenuItems]: Error getting menu items")
+func (r *menuItemRepository) GetMenuItems(ctx context.Context, query *request.MenuItemListQuery, page domain.PageParams) (domain.Page[models.MenuItem], error) {
+	db := withMenu(database.Conn(ctx, r.db))
+	if query.CategoryID != "" {
+		db = db.Where("category_id = ?", query.CategoryID)
+	}
+	if query.Active != nil {
+		db = db.Where("active = ?", *query.Active)
+	}
+
+	menuItems, err := pagination.Find(db, page, menuItemKeys)
+	if err != nil {
+		return domain.Page[models.MenuItem]{}, errors.Wrap(err, "[MenuItemRepository.GetMenuItems]: Error getting menu items")
 	}
-	return menuItemsList, nil
+	return menuItems, nil
 }
 
-func (r *menuItemRepository) GetMenuItemByID(id uuid.UUID) (*models.MenuItem, error) {
+func (r *menuItemRepository) GetMenuItemByID(ctx context.Context, id uuid.UUID) (*models.MenuItem, error) {
 	var menuItem models.MenuItem
-	if err := r.db.Preload("Category").Where("id = ?", id).First(&menuItem).Error; err != nil {
+	if err := withMenu(database.Conn(ctx, r.db)).Where("id = ?", id).First(&menuItem).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[MenuItemRepository.GetMenuItemByID]: Menu item not found")
+			return nil, errors.Wrap(domain.NotFoundError("Menu item not found"), "[MenuItemRepository.GetMenuItemByID]")
 		}
 		return nil, errors.Wrap(err, "[MenuItemRepository.GetMenuItemByID]: Error querying database")
 	}
 	return &menuItem, nil
 }
 
-func (r *menuItemRepository) CreateMenuItem(menuItem *models.MenuItem) error {
-	if err := r.db.Create(menuItem).Error; err != nil {
+func (r *menuItemRepository) CreateMenuItem(ctx context.Context, menuItem *models.MenuItem) error {
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(menuItem).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A menu item with this SKU already exists"), "[MenuItemRepository.CreateMenuItem]")
+		}
 		return errors.Wrap(err, "[MenuItemRepository.CreateMenuItem]: Error creating menu item")
 	}
 	return nil
 }
 
-func (r *menuItemRepository) UpdateMenuItem(menuItem *models.MenuItem) error {
-	if err := r.db.Save(menuItem).Error; err != nil {
+func (r *menuItemRepository) UpdateMenuItem(ctx context.Context, menuItem *models.MenuItem) error {
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Save(menuItem).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A menu item with this SKU already exists"), "[MenuItemRepository.UpdateMenuItem]")
+		}
 		return errors.Wrap(err, "[MenuItemRepository.UpdateMenuItem]: Error updating menu item")
 	}
 	return nil
 }
 
-func (r *menuItemRepository) DeleteMenuItem(id uuid.UUID) error {
-	if err := r.db.Where("id = ?", id).Delete(&models.MenuItem{}).Error; err != nil {
+func (r *menuItemRepository) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).Delete(&models.MenuItem{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return errors.Wrap(domain.ConflictError("Menu item is used by existing orders"), "[MenuItemRepository.DeleteMenuItem]")
+		}
 		return errors.Wrap(err, "[MenuItemRepository.DeleteMenuItem]: Error deleting menu item")
 	}
 	return nil
 }
 
-func (r *menuItemRepository) GetAllModifiers() ([]*models.Modifier, error) {
+func (r *menuItemRepository) GetAllModifiers(ctx context.Context) ([]*models.Modifier, error) {
 	var modifiers []*models.Modifier
-	if err := r.db.Order("name ASC").Find(&modifiers).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Order("name ASC").Find(&modifiers).Error; err != nil {
 		return nil, errors.Wrap(err, "[MenuItemRepository.GetAllModifiers]: Error getting modifiers")
 	}
 	return modifiers, nil
 }
+
+func (r *menuItemRepository) GetAvailability(ctx context.Context, ids []uuid.UUID) ([]*models.MenuItem, error) {
+	db := database.Conn(ctx, r.db).Preload("Availability")
+	if ids != nil {
+		db = db.Where("id IN ?", ids)
+	}
+	var menuItems []*models.MenuItem
+	if err := db.Order("name ASC").Order("id ASC").Find(&menuItems).Error; err != nil {
+		return nil, errors.Wrap(err, "[MenuItemRepository.GetAvailability]: Error getting menu items")
+	}
+	return menuItems, nil
+}
+
+func (r *menuItemRepository) GetMenu(ctx context.Context) ([]*models.MenuItem, error) {
+	var menuItems []*models.MenuItem
+	if err := withMenu(database.Conn(ctx, r.db)).Where("active = ?", true).Order("name ASC").Order("id ASC").Find(&menuItems).Error; err != nil {
+		return nil, errors.Wrap(err, "[MenuItemRepository.GetMenu]: Error getting menu items")
+	}
+	return menuItems, nil
+}
+
+func (r *menuItemRepository) GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error) {
+	var table models.DiningTable
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&table).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Table not found"), "[MenuItemRepository.GetTableByID]")
+		}
+		return nil, errors.Wrap(err, "[MenuItemRepository.GetTableByID]: Error querying database")
+	}
+	return &table, nil
+}
+
+func (r *menuItemRepository) SaveAvailability(ctx context.Context, availability *models.MenuItemAvailability) error {
+	if err := database.Conn(ctx, r.db).Save(availability).Error; err != nil {
+		return errors.Wrap(err, "[MenuItemRepository.SaveAvailability]: Error saving availability")
+	}
+	return nil
+}
+
+// withMenu preloads what tells whether a menu item is on the menu: its
+// category's schedules and its own, and its availability; and the modifier
+// groups it offers
+func withMenu(db *gorm.DB) *gorm.DB {
+	db = db.Preload("Category").Preload("Category.Schedules.Exceptions").Preload("Schedules.Exceptions").Preload("Availability")
+	return withModifierGroups(withModifierGroups(db, "Category.ModifierGroups"), "ModifierGroups")
+}
+
+// withModifierGroups preloads the groups behind links, with their options in
+// order
+func withModifierGroups(db *gorm.DB, links string) *gorm.DB {
+	return db.Preload(links+".ModifierGroup.Options", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		Preload(links + ".ModifierGroup.Options.Modifier")
+}
@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database/dbtest"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/feature/menuItem/repository"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+func TestMenuItemConstraints(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := repository.NewMenuItemRepository(db)
+
+		menuItem := &models.MenuItem{Name: utils.Ptr("ผัดไทย"), SKU: utils.Ptr("PT-01"), PriceBaht: utils.Ptr(int64(70))}
+		if err := repo.CreateMenuItem(ctx, menuItem); err != nil {
+			t.Fatal(err)
+		}
+		if !utils.DerefBool(menuItem.Active) {
+			t.Error("active default was not applied")
+		}
+
+		duplicate := &models.MenuItem{Name: utils.Ptr("ผัดไทยกุ้งสด"), SKU: utils.Ptr("PT-01")}
+		if err := repo.CreateMenuItem(ctx, duplicate); domain.ErrorKindOf(err) != domain.ErrorKindConflict {
+			t.Errorf("duplicate SKU: %v, want conflict", err)
+		}
+		if _, err := repo.GetMenuItemByID(ctx, uuid.New()); domain.ErrorKindOf(err) != domain.ErrorKindNotFound {
+			t.Errorf("missing menu item: %v, want not found", err)
+		}
+
+		// Ordered items keep their menu item
+		order := &models.Order{Status: utils.Ptr(constant.OrderStatusOpen)}
+		if err := db.Create(order).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Create(&models.OrderItem{OrderID: order.ID, MenuItemID: menuItem.ID, Quantity: 1}).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.DeleteMenuItem(ctx, menuItem.ID); domain.ErrorKindOf(err) != domain.ErrorKindConflict {
+			t.Errorf("ordered menu item: %v, want conflict", err)
+		}
+	})
+}
+
+func TestDeleteMenuItemCascades(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := repository.NewMenuItemRepository(db)
+
+		menuItem := &models.MenuItem{Name: utils.Ptr("ต้มยำกุ้ง")}
+		if err := repo.CreateMenuItem(ctx, menuItem); err != nil {
+			t.Fatal(err)
+		}
+		availability := &models.MenuItemAvailability{MenuItemID: menuItem.ID, Status: constant.MenuItemSoldOut}
+		if err := db.Create(availability).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.DeleteMenuItem(ctx, menuItem.ID); err != nil {
+			t.Fatal(err)
+		}
+		var left int64
+		if err := db.Model(&models.MenuItemAvailability{}).Where("menu_item_id = ?", menuItem.ID).Count(&left).Error; err != nil {
+			t.Fatal(err)
+		}
+		if left != 0 {
+			t.Error("availability outlived its menu item")
+		}
+	})
+}
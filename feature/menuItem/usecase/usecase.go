@@ -1,61 +1,74 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
 type menuItemUsecase struct {
 	menuItemRepository domain.MenuItemRepository
+	transactor         domain.Transactor
+	auditUsecase       domain.AuditUsecase
+	availabilityFeed   domain.AvailabilityFeed
+	calendar           businessday.Calendar
 }
 
-func NewMenuItemUsecase(menuItemRepository domain.MenuItemRepository) domain.MenuItemUsecase {
-	return &menuItemUsecase{menuItemRepository: menuItemRepository}
+// NewMenuItemUsecase publishes availability changes, its own and those the
+// order usecase makes, to availabilityFeed. Schedules are read on the clock of
+// calendar's time zone.
+func NewMenuItemUsecase(menuItemRepository domain.MenuItemRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase, availabilityFeed domain.AvailabilityFeed, calendar businessday.Calendar) domain.MenuItemUsecase {
+	return &menuItemUsecase{menuItemRepository: menuItemRepository, transactor: transactor, auditUsecase: auditUsecase, availabilityFeed: availabilityFeed, calendar: calendar}
 }
 
-func (u *menuItemUsecase) GetAllMenuItems() ([]*response.MenuItemResponse, error) {
-	menuItems, err := u.menuItemRepository.GetAllMenuItems()
+func (u *menuItemUsecase) GetAllMenuItems(ctx context.Context, query *request.MenuItemListQuery) (*response.Page[*response.MenuItemResponse], error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.GetAllMenuItems")
+	defer span.End()
+
+	page, err := pagination.Parse(query.PageQuery, query.Sort, "name")
+	if err != nil {
+		return nil, errors.Wrap(err, "[MenuItemUsecase.GetAllMenuItems]: Invalid page")
+	}
+	menuItems, err := u.menuItemRepository.GetMenuItems(ctx, query, page)
 	if err != nil {
 		return nil, errors.Wrap(err, "[MenuItemUsecase.GetAllMenuItems]: Error getting menu items")
 	}
 
-	menuItemResponses := make([]*response.MenuItemResponse, len(menuItems))
-	for i, menuItem := range menuItems {
-		menuItemResponses[i] = &response.MenuItemResponse{
-			ID:         menuItem.ID,
-			Name:       utils.DerefString(menuItem.Name),
-			PriceBaht:  utils.DerefInt64(menuItem.PriceBaht),
-			Active:     utils.DerefBool(menuItem.Active),
-			ImageURL:   utils.DerefString(menuItem.ImageURL),
-			CategoryID: utils.DerefUUID(menuItem.CategoryID),
-		}
+	menuItemResponses := make([]*response.MenuItemResponse, len(menuItems.Items))
+	for i, menuItem := range menuItems.Items {
+		menuItemResponses[i] = u.buildMenuItemResponse(menuItem)
 	}
 
-	return menuItemResponses, nil
+	return &response.Page[*response.MenuItemResponse]{Items: menuItemResponses, NextCursor: menuItems.NextCursor}, nil
 }
 
-func (u *menuItemUsecase) GetMenuItemByID(id uuid.UUID) (*response.MenuItemResponse, error) {
-	menuItem, err := u.menuItemRepository.GetMenuItemByID(id)
+func (u *menuItemUsecase) GetMenuItemByID(ctx context.Context, id uuid.UUID) (*response.MenuItemResponse, error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.GetMenuItemByID")
+	defer span.End()
+
+	menuItem, err := u.menuItemRepository.GetMenuItemByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[MenuItemUsecase.GetMenuItemByID]: Error getting menu item")
 	}
 
-	return &response.MenuItemResponse{
-		ID:         menuItem.ID,
-		Name:       utils.DerefString(menuItem.Name),
-		PriceBaht:  utils.DerefInt64(menuItem.PriceBaht),
-		Active:     utils.DerefBool(menuItem.Active),
-		ImageURL:   utils.DerefString(menuItem.ImageURL),
-		CategoryID: utils.DerefUUID(menuItem.CategoryID),
-	}, nil
+	return u.buildMenuItemResponse(menuItem), nil
 }
 
-func (u *menuItemUsecase) CreateMenuItem(req *request.MenuItemRequest) (*response.MenuItemResponse, error) {
+func (u *menuItemUsecase) CreateMenuItem(ctx context.Context, req *request.MenuItemRequest) (*response.MenuItemResponse, error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.CreateMenuItem")
+	defer span.End()
+
 	active := true
 	if req.Active != nil {
 		active = *req.Active
@@ -70,26 +83,40 @@ func (u *menuItemUsecase) CreateMenuItem(req *request.MenuItemRequest) (*respons
 		ImageURL:   req.ImageURL,
 	}
 
-	if err := u.menuItemRepository.CreateMenuItem(menuItem); err != nil {
-		return nil, errors.Wrap(err, "[MenuItemUsecase.CreateMenuItem]: Error creating menu item")
+	var menuItemResponse *response.MenuItemResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.menuItemRepository.CreateMenuItem(ctx, menuItem); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.CreateMenuItem]: Error creating menu item")
+		}
+		// Reloaded for its category's schedules
+		created, err := u.menuItemRepository.GetMenuItemByID(ctx, menuItem.ID)
+		if err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.CreateMenuItem]: Error getting menu item")
+		}
+		menuItemResponse = u.buildMenuItemResponse(created)
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityMenuItem, menuItem.ID.String(), nil, menuItemResponse); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.CreateMenuItem]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.MenuItemResponse{
-		ID:         menuItem.ID,
-		Name:       req.Name,
-		PriceBaht:  req.PriceBaht,
-		Active:     active,
-		ImageURL:   utils.DerefString(req.ImageURL),
-		CategoryID: utils.DerefUUID(req.CategoryID),
-	}, nil
+	return menuItemResponse, nil
 }
 
-func (u *menuItemUsecase) UpdateMenuItem(id uuid.UUID, req *request.MenuItemRequest) (*response.MenuItemResponse, error) {
+func (u *menuItemUsecase) UpdateMenuItem(ctx context.Context, id uuid.UUID, req *request.MenuItemRequest) (*response.MenuItemResponse, error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.UpdateMenuItem")
+	defer span.End()
+
 	// Get existing menu item
-	menuItem, err := u.menuItemRepository.GetMenuItemByID(id)
+	menuItem, err := u.menuItemRepository.GetMenuItemByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[MenuItemUsecase.UpdateMenuItem]: Menu item not found")
 	}
+	before := u.buildMenuItemResponse(menuItem)
+	wasActive := utils.DerefBool(menuItem.Active)
 
 	// Update fields
 	menuItem.CategoryID = req.CategoryID
@@ -101,36 +128,59 @@ func (u *menuItemUsecase) UpdateMenuItem(id uuid.UUID, req *request.MenuItemRequ
 		menuItem.Active = req.Active
 	}
 
-	if err := u.menuItemRepository.UpdateMenuItem(menuItem); err != nil {
-		return nil, errors.Wrap(err, "[MenuItemUsecase.UpdateMenuItem]: Error updating menu item")
+	var menuItemResponse *response.MenuItemResponse
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.menuItemRepository.UpdateMenuItem(ctx, menuItem); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.UpdateMenuItem]: Error updating menu item")
+		}
+		// Reloaded for the schedules of its category, which may have changed
+		updated, err := u.menuItemRepository.GetMenuItemByID(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.UpdateMenuItem]: Error getting menu item")
+		}
+		menuItemResponse = u.buildMenuItemResponse(updated)
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityMenuItem, menuItem.ID.String(), before, menuItemResponse); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.UpdateMenuItem]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Terminals and menus stop offering an item taken off the menu
+	if utils.DerefBool(menuItem.Active) != wasActive {
+		u.availabilityFeed.Publish(menuItem.ID)
 	}
 
-	return &response.MenuItemResponse{
-		ID:         menuItem.ID,
-		Name:       utils.DerefString(menuItem.Name),
-		PriceBaht:  utils.DerefInt64(menuItem.PriceBaht),
-		Active:     utils.DerefBool(menuItem.Active),
-		ImageURL:   utils.DerefString(menuItem.ImageURL),
-		CategoryID: utils.DerefUUID(menuItem.CategoryID),
-	}, nil
+	return menuItemResponse, nil
 }
 
-func (u *menuItemUsecase) DeleteMenuItem(id uuid.UUID) error {
+func (u *menuItemUsecase) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.DeleteMenuItem")
+	defer span.End()
+
 	// Check if menu item exists
-	_, err := u.menuItemRepository.GetMenuItemByID(id)
+	menuItem, err := u.menuItemRepository.GetMenuItemByID(ctx, id)
 	if err != nil {
 		return errors.Wrap(err, "[MenuItemUsecase.DeleteMenuItem]: Menu item not found")
 	}
 
-	if err := u.menuItemRepository.DeleteMenuItem(id); err != nil {
-		return errors.Wrap(err, "[MenuItemUsecase.DeleteMenuItem]: Error deleting menu item")
-	}
-
-	return nil
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.menuItemRepository.DeleteMenuItem(ctx, id); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.DeleteMenuItem]: Error deleting menu item")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionDelete, constant.AuditEntityMenuItem, id.String(), u.buildMenuItemResponse(menuItem), nil); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.DeleteMenuItem]: Error recording audit log")
+		}
+		return nil
+	})
 }
 
-func (u *menuItemUsecase) GetAvailableModifiers() ([]*response.ModifierResponse, error) {
-	modifiers, err := u.menuItemRepository.GetAllModifiers()
+func (u *menuItemUsecase) GetAvailableModifiers(ctx context.Context) (*response.Page[*response.ModifierResponse], error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.GetAvailableModifiers")
+	defer span.End()
+
+	modifiers, err := u.menuItemRepository.GetAllModifiers(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "[MenuItemUsecase.GetAvailableModifiers]: Error getting modifiers")
 	}
@@ -145,5 +195,205 @@ func (u *menuItemUsecase) GetAvailableModifiers() ([]*response.ModifierResponse,
 		}
 	}
 
-	return modifierResponses, nil
+	return response.SinglePage(modifierResponses), nil
+}
+
+func (u *menuItemUsecase) GetMenu(ctx context.Context, query *request.MenuQuery) (*response.Page[*response.MenuItemResponse], error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.GetMenu")
+	defer span.End()
+
+	at := time.Now()
+	if query.At != nil {
+		at = *query.At
+	}
+	at = at.In(u.calendar.Location())
+
+	var areaID *uuid.UUID
+	switch {
+	case query.TableID != "":
+		table, err := u.menuItemRepository.GetTableByID(ctx, uuid.MustParse(query.TableID))
+		if err != nil {
+			return nil, errors.Wrap(err, "[MenuItemUsecase.GetMenu]: Table not found")
+		}
+		// A table outside any area only gets the items offered in every area
+		areaID = table.AreaID
+	case query.AreaID != "":
+		areaID = utils.Ptr(uuid.MustParse(query.AreaID))
+	}
+
+	menuItems, err := u.menuItemRepository.GetMenu(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[MenuItemUsecase.GetMenu]: Error getting menu items")
+	}
+
+	menuItemResponses := make([]*response.MenuItemResponse, 0, len(menuItems))
+	for _, menuItem := range menuItems {
+		if domain.MenuItemOffered(menuItem, at, areaID) {
+			menuItemResponse := u.buildMenuItemResponse(menuItem)
+			menuItemResponse.Offered = true
+			menuItemResponses = append(menuItemResponses, menuItemResponse)
+		}
+	}
+
+	return response.SinglePage(menuItemResponses), nil
+}
+
+// GetAvailability lists the availability of every menu item, by name
+func (u *menuItemUsecase) GetAvailability(ctx context.Context) (*response.Page[*response.MenuItemAvailabilityResponse], error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.GetAvailability")
+	defer span.End()
+
+	availability, err := u.getAvailability(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "[MenuItemUsecase.GetAvailability]: Error getting availability")
+	}
+	return response.SinglePage(availability), nil
+}
+
+// SetAvailability marks an item available, low on stock or sold out, and sets
+// or stops counting its portions
+func (u *menuItemUsecase) SetAvailability(ctx context.Context, id uuid.UUID, req *request.MenuItemAvailabilityRequest) (*response.MenuItemAvailabilityResponse, error) {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.SetAvailability")
+	defer span.End()
+
+	menuItem, err := u.menuItemRepository.GetMenuItemByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[MenuItemUsecase.SetAvailability]: Menu item not found")
+	}
+	before := u.buildAvailabilityResponse(menuItem)
+
+	menuItem.Availability = &models.MenuItemAvailability{
+		MenuItemID:        id,
+		Status:            req.Status,
+		RemainingPortions: req.RemainingPortions,
+		UpdatedAt:         time.Now(),
+		UpdatedBy:         domain.ActorFromContext(ctx).UserID,
+	}
+	after := u.buildAvailabilityResponse(menuItem)
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.menuItemRepository.SaveAvailability(ctx, menuItem.Availability); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.SetAvailability]: Error saving availability")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionSetAvailability, constant.AuditEntityMenuItem, id.String(), before, after); err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.SetAvailability]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.availabilityFeed.Publish(id)
+
+	return after, nil
+}
+
+// WatchAvailability subscribes before it reads the snapshot, so a change made
+// in between is sent again rather than missed. A watcher too slow to keep up is
+// dropped; it ends without error and is expected to start over. keepAlive is
+// called whenever nothing has been sent for MenuAvailabilityKeepAlive.
+func (u *menuItemUsecase) WatchAvailability(ctx context.Context, send func(*response.MenuItemAvailabilityResponse) error, keepAlive func() error) error {
+	ctx, span := tracing.Start(ctx, "MenuItemUsecase.WatchAvailability")
+	defer span.End()
+
+	changes, unsubscribe := u.availabilityFeed.Subscribe()
+	defer unsubscribe()
+	ticker := time.NewTicker(constant.MenuAvailabilityKeepAlive)
+	defer ticker.Stop()
+
+	var ids []uuid.UUID
+	for {
+		availability, err := u.getAvailability(ctx, ids)
+		if err != nil {
+			return errors.Wrap(err, "[MenuItemUsecase.WatchAvailability]: Error getting availability")
+		}
+		for _, a := range availability {
+			if err := send(a); err != nil {
+				return errors.Wrap(err, "[MenuItemUsecase.WatchAvailability]: Error sending availability")
+			}
+		}
+
+		ticker.Reset(constant.MenuAvailabilityKeepAlive)
+
+		ids = nil
+		for ids == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := keepAlive(); err != nil {
+					return errors.Wrap(err, "[MenuItemUsecase.WatchAvailability]: Error keeping stream alive")
+				}
+			case id, ok := <-changes:
+				if !ok {
+					return nil
+				}
+				ids = []uuid.UUID{id}
+			}
+		}
+	}
+}
+
+func (u *menuItemUsecase) getAvailability(ctx context.Context, ids []uuid.UUID) ([]*response.MenuItemAvailabilityResponse, error) {
+	menuItems, err := u.menuItemRepository.GetAvailability(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	availability := make([]*response.MenuItemAvailabilityResponse, len(menuItems))
+	for i, menuItem := range menuItems {
+		availability[i] = u.buildAvailabilityResponse(menuItem)
+	}
+	return availability, nil
+}
+
+func (u *menuItemUsecase) buildAvailabilityResponse(menuItem *models.MenuItem) *response.MenuItemAvailabilityResponse {
+	availability := &response.MenuItemAvailabilityResponse{
+		MenuItemID: menuItem.ID,
+		Active:     utils.DerefBool(menuItem.Active),
+		Status:     domain.MenuItemStatus(menuItem.Availability),
+	}
+	if a := menuItem.Availability; a != nil {
+		availability.RemainingPortions = a.RemainingPortions
+		availability.UpdatedAt = &a.UpdatedAt
+	}
+	return availability
+}
+
+// Helper function to build menu item response
+func (u *menuItemUsecase) buildMenuItemResponse(menuItem *models.MenuItem) *response.MenuItemResponse {
+	menuItemResponse := &response.MenuItemResponse{
+		ID:         menuItem.ID,
+		Name:       utils.DerefString(menuItem.Name),
+		PriceBaht:  utils.DerefInt64(menuItem.PriceBaht),
+		Active:     utils.DerefBool(menuItem.Active),
+		ImageURL:   utils.DerefString(menuItem.ImageURL),
+		CategoryID: utils.DerefUUID(menuItem.CategoryID),
+
+		Availability: domain.MenuItemStatus(menuItem.Availability),
+		Offered:      domain.MenuItemOfferedInAnyArea(menuItem, time.Now().In(u.calendar.Location())),
+	}
+	if menuItem.Availability != nil {
+		menuItemResponse.RemainingPortions = menuItem.Availability.RemainingPortions
+	}
+
+	groups := domain.MenuItemModifierGroups(menuItem)
+	menuItemResponse.ModifierGroups = make([]response.MenuItemModifierGroupResponse, len(groups))
+	for i, group := range groups {
+		options := make([]response.ModifierGroupOptionResponse, len(group.Options))
+		for j, option := range group.Options {
+			options[j] = response.ModifierGroupOptionResponse{ModifierID: option.ModifierID, Default: option.IsDefault}
+			if option.Modifier != nil {
+				options[j].Name = utils.DerefString(option.Modifier.Name)
+				options[j].PriceDeltaBaht = utils.DerefInt64(option.Modifier.PriceDeltaBaht)
+			}
+		}
+		menuItemResponse.ModifierGroups[i] = response.MenuItemModifierGroupResponse{
+			ID:            group.ID,
+			Name:          utils.DerefString(group.Name),
+			MinSelections: group.MinSelections,
+			MaxSelections: group.MaxSelections,
+			Options:       options,
+		}
+	}
+	return menuItemResponse
 }
@@ -0,0 +1,101 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type menuScheduleHandler struct {
+	menuScheduleUsecase domain.MenuScheduleUsecase
+}
+
+func NewMenuScheduleHandler(menuScheduleUsecase domain.MenuScheduleUsecase) *menuScheduleHandler {
+	return &menuScheduleHandler{menuScheduleUsecase: menuScheduleUsecase}
+}
+
+func (h *menuScheduleHandler) GetMenuSchedules(c *gin.Context) {
+	var req request.MenuScheduleListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	schedules, err := h.menuScheduleUsecase.GetMenuSchedules(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuScheduleHandler.GetMenuSchedules]: Error getting menu schedules"))
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+func (h *menuScheduleHandler) GetMenuScheduleByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid menu schedule ID", nil))
+		return
+	}
+
+	schedule, err := h.menuScheduleUsecase.GetMenuScheduleByID(c.Request.Context(), id)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuScheduleHandler.GetMenuScheduleByID]: Error getting menu schedule"))
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *menuScheduleHandler) CreateMenuSchedule(c *gin.Context) {
+	var req request.MenuScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	schedule, err := h.menuScheduleUsecase.CreateMenuSchedule(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuScheduleHandler.CreateMenuSchedule]: Error creating menu schedule"))
+		return
+	}
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *menuScheduleHandler) UpdateMenuSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid menu schedule ID", nil))
+		return
+	}
+
+	var req request.MenuScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	schedule, err := h.menuScheduleUsecase.UpdateMenuSchedule(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuScheduleHandler.UpdateMenuSchedule]: Error updating menu schedule"))
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *menuScheduleHandler) DeleteMenuSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid menu schedule ID", nil))
+		return
+	}
+
+	if err := h.menuScheduleUsecase.DeleteMenuSchedule(c.Request.Context(), id); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[MenuScheduleHandler.DeleteMenuSchedule]: Error deleting menu schedule"))
+		return
+	}
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Menu schedule deleted successfully"})
+}
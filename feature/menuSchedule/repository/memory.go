@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+)
+
+type menuScheduleMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewMenuScheduleMemoryRepository(store *memory.Store) domain.MenuScheduleRepository {
+	return &menuScheduleMemoryRepository{store: store}
+}
+
+func (r *menuScheduleMemoryRepository) GetMenuSchedules(ctx context.Context, query *request.MenuScheduleListQuery) ([]*models.MenuSchedule, error) {
+	var schedules []*models.MenuSchedule
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		schedules = memory.Select(t.MenuSchedules, func(s *models.MenuSchedule) bool {
+			if query.CategoryID != "" && (s.CategoryID == nil || s.CategoryID.String() != query.CategoryID) {
+				return false
+			}
+			return query.MenuItemID == "" || (s.MenuItemID != nil && s.MenuItemID.String() == query.MenuItemID)
+		})
+		memory.Sort(schedules,
+			func(a, b *models.MenuSchedule) int { return memory.CompareString(a.Name, b.Name) },
+			func(a, b *models.MenuSchedule) int { return cmp.Compare(a.ID.String(), b.ID.String()) },
+		)
+		for _, schedule := range schedules {
+			schedule.Exceptions = t.ExceptionsOfMenuSchedule(schedule.ID)
+		}
+		return nil
+	})
+	return schedules, err
+}
+
+func (r *menuScheduleMemoryRepository) GetMenuScheduleByID(ctx context.Context, id uuid.UUID) (*models.MenuSchedule, error) {
+	var schedule models.MenuSchedule
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.MenuSchedules[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Menu schedule not found"), "[MenuScheduleMemoryRepository.GetMenuScheduleByID]")
+		}
+		schedule = found
+		schedule.Exceptions = t.ExceptionsOfMenuSchedule(id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *menuScheduleMemoryRepository) CreateMenuSchedule(ctx context.Context, schedule *models.MenuSchedule) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if schedule.ID == uuid.Nil {
+			schedule.ID = uuid.New()
+		}
+		if err := r.save(t, schedule, "[MenuScheduleMemoryRepository.CreateMenuSchedule]"); err != nil {
+			return err
+		}
+		t.Inserted(schedule.ID)
+		return nil
+	})
+}
+
+func (r *menuScheduleMemoryRepository) UpdateMenuSchedule(ctx context.Context, schedule *models.MenuSchedule) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, schedule, "[MenuScheduleMemoryRepository.UpdateMenuSchedule]")
+	})
+}
+
+// save checks what the schedule references, like its foreign keys, then
+// stores it and replaces its exceptions
+func (r *menuScheduleMemoryRepository) save(t *memory.Tables, schedule *models.MenuSchedule, op string) error {
+	if schedule.CategoryID != nil {
+		if _, ok := t.Categories[*schedule.CategoryID]; !ok {
+			return errors.Wrap(domain.NotFoundError("Category, menu item or area not found"), op)
+		}
+	}
+	if schedule.MenuItemID != nil {
+		if _, ok := t.MenuItems[*schedule.MenuItemID]; !ok {
+			return errors.Wrap(domain.NotFoundError("Category, menu item or area not found"), op)
+		}
+	}
+	if schedule.AreaID != nil {
+		if _, ok := t.Areas[*schedule.AreaID]; !ok {
+			return errors.Wrap(domain.NotFoundError("Category, menu item or area not found"), op)
+		}
+	}
+
+	for id, exception := range t.MenuScheduleExceptions {
+		if exception.ScheduleID == schedule.ID {
+			delete(t.MenuScheduleExceptions, id)
+		}
+	}
+	for i := range schedule.Exceptions {
+		exception := &schedule.Exceptions[i]
+		exception.ID = uuid.New()
+		exception.ScheduleID = schedule.ID
+		t.MenuScheduleExceptions[exception.ID] = *exception
+	}
+
+	row := *schedule
+	row.Category, row.MenuItem, row.Area, row.Exceptions = nil, nil, nil, nil
+	t.MenuSchedules[row.ID] = row
+	return nil
+}
+
+// DeleteMenuSchedule deletes the schedule and its exceptions, like ON DELETE
+// CASCADE
+func (r *menuScheduleMemoryRepository) DeleteMenuSchedule(ctx context.Context, id uuid.UUID) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		t.DeleteMenuSchedules(func(s *models.MenuSchedule) bool { return s.ID == id })
+		return nil
+	})
+}
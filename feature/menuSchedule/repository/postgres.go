@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type menuScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewMenuScheduleRepository(db *gorm.DB) domain.MenuScheduleRepository {
+	return &menuScheduleRepository{db: db}
+}
+
+func (r *menuScheduleRepository) GetMenuSchedules(ctx context.Context, query *request.MenuScheduleListQuery) ([]*models.MenuSchedule, error) {
+	db := withExceptions(database.Conn(ctx, r.db))
+	if query.CategoryID != "" {
+		db = db.Where("category_id = ?", query.CategoryID)
+	}
+	if query.MenuItemID != "" {
+		db = db.Where("menu_item_id = ?", query.MenuItemID)
+	}
+
+	var schedules []*models.MenuSchedule
+	if err := db.Order("name ASC").Order("id ASC").Find(&schedules).Error; err != nil {
+		return nil, errors.Wrap(err, "[MenuScheduleRepository.GetMenuSchedules]: Error querying database")
+	}
+	return schedules, nil
+}
+
+func (r *menuScheduleRepository) GetMenuScheduleByID(ctx context.Context, id uuid.UUID) (*models.MenuSchedule, error) {
+	var schedule models.MenuSchedule
+	if err := withExceptions(database.Conn(ctx, r.db)).Where("id = ?", id).First(&schedule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Menu schedule not found"), "[MenuScheduleRepository.GetMenuScheduleByID]")
+		}
+		return nil, errors.Wrap(err, "[MenuScheduleRepository.GetMenuScheduleByID]: Error querying database")
+	}
+	return &schedule, nil
+}
+
+func (r *menuScheduleRepository) CreateMenuSchedule(ctx context.Context, schedule *models.MenuSchedule) error {
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(schedule).Error; err != nil {
+		return errors.Wrap(scheduleError(err), "[MenuScheduleRepository.CreateMenuSchedule]: Error creating menu schedule")
+	}
+	return r.saveExceptions(ctx, schedule, "[MenuScheduleRepository.CreateMenuSchedule]")
+}
+
+func (r *menuScheduleRepository) UpdateMenuSchedule(ctx context.Context, schedule *models.MenuSchedule) error {
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Save(schedule).Error; err != nil {
+		return errors.Wrap(scheduleError(err), "[MenuScheduleRepository.UpdateMenuSchedule]: Error updating menu schedule")
+	}
+	if err := database.Conn(ctx, r.db).Where("schedule_id = ?", schedule.ID).Delete(&models.MenuScheduleException{}).Error; err != nil {
+		return errors.Wrap(err, "[MenuScheduleRepository.UpdateMenuSchedule]: Error deleting exceptions")
+	}
+	return r.saveExceptions(ctx, schedule, "[MenuScheduleRepository.UpdateMenuSchedule]")
+}
+
+func (r *menuScheduleRepository) saveExceptions(ctx context.Context, schedule *models.MenuSchedule, op string) error {
+	for i := range schedule.Exceptions {
+		schedule.Exceptions[i].ID = uuid.Nil
+		schedule.Exceptions[i].ScheduleID = schedule.ID
+	}
+	if len(schedule.Exceptions) == 0 {
+		return nil
+	}
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(&schedule.Exceptions).Error; err != nil {
+		return errors.Wrap(err, op+": Error creating exceptions")
+	}
+	return nil
+}
+
+func (r *menuScheduleRepository) DeleteMenuSchedule(ctx context.Context, id uuid.UUID) error {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).Delete(&models.MenuSchedule{}).Error; err != nil {
+		return errors.Wrap(err, "[MenuScheduleRepository.DeleteMenuSchedule]: Error deleting menu schedule")
+	}
+	return nil
+}
+
+func withExceptions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Exceptions", func(db *gorm.DB) *gorm.DB { return db.Order("date ASC") })
+}
+
+// scheduleError reports a missing category, menu item or area as not found
+func scheduleError(err error) error {
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return domain.NotFoundError("Category, menu item or area not found")
+	}
+	return err
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+// weekdays names the days of the week in requests and responses, in
+// time.Weekday order
+var weekdays = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+type menuScheduleUsecase struct {
+	menuScheduleRepository domain.MenuScheduleRepository
+	transactor             domain.Transactor
+	auditUsecase           domain.AuditUsecase
+}
+
+func NewMenuScheduleUsecase(menuScheduleRepository domain.MenuScheduleRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.MenuScheduleUsecase {
+	return &menuScheduleUsecase{menuScheduleRepository: menuScheduleRepository, transactor: transactor, auditUsecase: auditUsecase}
+}
+
+func (u *menuScheduleUsecase) GetMenuSchedules(ctx context.Context, query *request.MenuScheduleListQuery) (*response.Page[*response.MenuScheduleResponse], error) {
+	ctx, span := tracing.Start(ctx, "MenuScheduleUsecase.GetMenuSchedules")
+	defer span.End()
+
+	schedules, err := u.menuScheduleRepository.GetMenuSchedules(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "[MenuScheduleUsecase.GetMenuSchedules]: Error getting menu schedules")
+	}
+
+	scheduleResponses := make([]*response.MenuScheduleResponse, len(schedules))
+	for i, schedule := range schedules {
+		scheduleResponses[i] = buildMenuScheduleResponse(schedule)
+	}
+
+	return response.SinglePage(scheduleResponses), nil
+}
+
+func (u *menuScheduleUsecase) GetMenuScheduleByID(ctx context.Context, id uuid.UUID) (*response.MenuScheduleResponse, error) {
+	ctx, span := tracing.Start(ctx, "MenuScheduleUsecase.GetMenuScheduleByID")
+	defer span.End()
+
+	schedule, err := u.menuScheduleRepository.GetMenuScheduleByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[MenuScheduleUsecase.GetMenuScheduleByID]: Error getting menu schedule")
+	}
+
+	return buildMenuScheduleResponse(schedule), nil
+}
+
+func (u *menuScheduleUsecase) CreateMenuSchedule(ctx context.Context, req *request.MenuScheduleRequest) (*response.MenuScheduleResponse, error) {
+	ctx, span := tracing.Start(ctx, "MenuScheduleUsecase.CreateMenuSchedule")
+	defer span.End()
+
+	schedule := &models.MenuSchedule{}
+	if err := applyMenuScheduleRequest(schedule, req); err != nil {
+		return nil, errors.Wrap(err, "[MenuScheduleUsecase.CreateMenuSchedule]")
+	}
+
+	var scheduleResponse *response.MenuScheduleResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.menuScheduleRepository.CreateMenuSchedule(ctx, schedule); err != nil {
+			return errors.Wrap(err, "[MenuScheduleUsecase.CreateMenuSchedule]: Error creating menu schedule")
+		}
+		scheduleResponse = buildMenuScheduleResponse(schedule)
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityMenuSchedule, schedule.ID.String(), nil, scheduleResponse); err != nil {
+			return errors.Wrap(err, "[MenuScheduleUsecase.CreateMenuSchedule]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduleResponse, nil
+}
+
+func (u *menuScheduleUsecase) UpdateMenuSchedule(ctx context.Context, id uuid.UUID, req *request.MenuScheduleRequest) (*response.MenuScheduleResponse, error) {
+	ctx, span := tracing.Start(ctx, "MenuScheduleUsecase.UpdateMenuSchedule")
+	defer span.End()
+
+	// Get existing menu schedule
+	schedule, err := u.menuScheduleRepository.GetMenuScheduleByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[MenuScheduleUsecase.UpdateMenuSchedule]: Menu schedule not found")
+	}
+	before := buildMenuScheduleResponse(schedule)
+
+	if err := applyMenuScheduleRequest(schedule, req); err != nil {
+		return nil, errors.Wrap(err, "[MenuScheduleUsecase.UpdateMenuSchedule]")
+	}
+
+	var scheduleResponse *response.MenuScheduleResponse
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.menuScheduleRepository.UpdateMenuSchedule(ctx, schedule); err != nil {
+			return errors.Wrap(err, "[MenuScheduleUsecase.UpdateMenuSchedule]: Error updating menu schedule")
+		}
+		scheduleResponse = buildMenuScheduleResponse(schedule)
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityMenuSchedule, schedule.ID.String(), before, scheduleResponse); err != nil {
+			return errors.Wrap(err, "[MenuScheduleUsecase.UpdateMenuSchedule]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduleResponse, nil
+}
+
+func (u *menuScheduleUsecase) DeleteMenuSchedule(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "MenuScheduleUsecase.DeleteMenuSchedule")
+	defer span.End()
+
+	// Check if menu schedule exists
+	schedule, err := u.menuScheduleRepository.GetMenuScheduleByID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "[MenuScheduleUsecase.DeleteMenuSchedule]: Menu schedule not found")
+	}
+	before := buildMenuScheduleResponse(schedule)
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.menuScheduleRepository.DeleteMenuSchedule(ctx, id); err != nil {
+			return errors.Wrap(err, "[MenuScheduleUsecase.DeleteMenuSchedule]: Error deleting menu schedule")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionDelete, constant.AuditEntityMenuSchedule, id.String(), before, nil); err != nil {
+			return errors.Wrap(err, "[MenuScheduleUsecase.DeleteMenuSchedule]: Error recording audit log")
+		}
+		return nil
+	})
+}
+
+// applyMenuScheduleRequest validates req and copies it onto schedule,
+// replacing its exceptions
+func applyMenuScheduleRequest(schedule *models.MenuSchedule, req *request.MenuScheduleRequest) error {
+	if (req.CategoryID == nil) == (req.MenuItemID == nil) {
+		return domain.ValidationError("Schedule either a category or a menu item", map[string]string{"category_id": "exactly one of category_id and menu_item_id is required"})
+	}
+	if (req.Start == nil) != (req.End == nil) {
+		return domain.ValidationError("Start and end go together", map[string]string{"end": "required with start, and only with it"})
+	}
+
+	weekdayBits := 0
+	for _, day := range req.Days {
+		for i, name := range weekdays {
+			if day == name {
+				weekdayBits |= 1 << i
+			}
+		}
+	}
+
+	exceptions := make([]models.MenuScheduleException, len(req.Exceptions))
+	for i, exception := range req.Exceptions {
+		date, err := time.Parse(businessday.Layout, exception.Date)
+		if err != nil {
+			return domain.ValidationError("Invalid exception date", map[string]string{"exceptions": "dates are YYYY-MM-DD"})
+		}
+		for _, other := range exceptions[:i] {
+			if other.Date.Equal(date) {
+				return domain.ValidationError("Exception dates must be unique", map[string]string{"exceptions": fmt.Sprintf("%s is given twice", exception.Date)})
+			}
+		}
+		exceptions[i] = models.MenuScheduleException{Date: date, Open: *exception.Open}
+	}
+	slices.SortFunc(exceptions, func(a, b models.MenuScheduleException) int { return a.Date.Compare(b.Date) })
+
+	schedule.Name = req.Name
+	schedule.CategoryID = req.CategoryID
+	schedule.MenuItemID = req.MenuItemID
+	schedule.AreaID = req.AreaID
+	schedule.Weekdays = weekdayBits
+	schedule.StartMinute = clockMinute(req.Start)
+	schedule.EndMinute = clockMinute(req.End)
+	schedule.Exceptions = exceptions
+	return nil
+}
+
+// clockMinute turns a validated "15:04" clock time into minutes after midnight
+func clockMinute(clock *string) *int {
+	if clock == nil {
+		return nil
+	}
+	t, err := time.Parse("15:04", *clock)
+	if err != nil {
+		return nil
+	}
+	return utils.Ptr(t.Hour()*60 + t.Minute())
+}
+
+func formatClock(minute *int) *string {
+	if minute == nil {
+		return nil
+	}
+	return utils.Ptr(fmt.Sprintf("%02d:%02d", *minute/60, *minute%60))
+}
+
+func buildMenuScheduleResponse(schedule *models.MenuSchedule) *response.MenuScheduleResponse {
+	days := []string{}
+	for i, name := range weekdays {
+		if schedule.Weekdays&(1<<i) != 0 {
+			days = append(days, name)
+		}
+	}
+
+	exceptions := make([]response.MenuScheduleExceptionResponse, len(schedule.Exceptions))
+	for i, exception := range schedule.Exceptions {
+		exceptions[i] = response.MenuScheduleExceptionResponse{
+			Date: exception.Date.Format(businessday.Layout),
+			Open: exception.Open,
+		}
+	}
+
+	return &response.MenuScheduleResponse{
+		ID:         schedule.ID,
+		Name:       utils.DerefString(schedule.Name),
+		CategoryID: schedule.CategoryID,
+		MenuItemID: schedule.MenuItemID,
+		AreaID:     schedule.AreaID,
+		Days:       days,
+		Start:      formatClock(schedule.StartMinute),
+		End:        formatClock(schedule.EndMinute),
+		Exceptions: exceptions,
+	}
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type modifierHandler struct {
@@ -21,11 +21,9 @@ func NewModifierHandler(modifierUsecase domain.ModifierUsecase) *modifierHandler
 }
 
 func (h *modifierHandler) GetAllModifiers(c *gin.Context) {
-	modifiers, err := h.modifierUsecase.GetAllModifiers()
+	modifiers, err := h.modifierUsecase.GetAllModifiers(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[ModifierHandler.GetAllModifiers]: Error getting modifiers")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[ModifierHandler.GetAllModifiers]: Error getting modifiers"))
 		return
 	}
 	c.JSON(http.StatusOK, modifiers)
@@ -34,15 +32,13 @@ func (h *modifierHandler) GetAllModifiers(c *gin.Context) {
 func (h *modifierHandler) GetModifierByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid modifier ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid modifier ID", nil))
 		return
 	}
 
-	modifier, err := h.modifierUsecase.GetModifierByID(id)
+	modifier, err := h.modifierUsecase.GetModifierByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[ModifierHandler.GetModifierByID]: Error getting modifier")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[ModifierHandler.GetModifierByID]: Error getting modifier"))
 		return
 	}
 	c.JSON(http.StatusOK, modifier)
@@ -51,15 +47,13 @@ func (h *modifierHandler) GetModifierByID(c *gin.Context) {
 func (h *modifierHandler) CreateModifier(c *gin.Context) {
 	var req request.ModifierRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	modifier, err := h.modifierUsecase.CreateModifier(&req)
+	modifier, err := h.modifierUsecase.CreateModifier(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[ModifierHandler.CreateModifier]: Error creating modifier")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[ModifierHandler.CreateModifier]: Error creating modifier"))
 		return
 	}
 	c.JSON(http.StatusCreated, modifier)
@@ -68,21 +62,19 @@ func (h *modifierHandler) CreateModifier(c *gin.Context) {
 func (h *modifierHandler) UpdateModifier(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid modifier ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid modifier ID", nil))
 		return
 	}
 
 	var req request.ModifierRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	modifier, err := h.modifierUsecase.UpdateModifier(id, &req)
+	modifier, err := h.modifierUsecase.UpdateModifier(c.Request.Context(), id, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[ModifierHandler.UpdateModifier]: Error updating modifier")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[ModifierHandler.UpdateModifier]: Error updating modifier"))
 		return
 	}
 	c.JSON(http.StatusOK, modifier)
@@ -91,15 +83,13 @@ func (h *modifierHandler) UpdateModifier(c *gin.Context) {
 func (h *modifierHandler) DeleteModifier(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid modifier ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid modifier ID", nil))
 		return
 	}
 
-	if err := h.modifierUsecase.DeleteModifier(id); err != nil {
-		err = errors.Wrap(err, "[ModifierHandler.DeleteModifier]: Error deleting modifier")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+	if err := h.modifierUsecase.DeleteModifier(c.Request.Context(), id); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[ModifierHandler.DeleteModifier]: Error deleting modifier"))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Modifier deleted successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Modifier deleted successfully"})
 }
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type modifierMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewModifierMemoryRepository(store *memory.Store) domain.ModifierRepository {
+	return &modifierMemoryRepository{store: store}
+}
+
+func (r *modifierMemoryRepository) GetAllModifiers(ctx context.Context) ([]*models.Modifier, error) {
+	var modifiers []*models.Modifier
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		modifiers = memory.Select(t.Modifiers, nil)
+		memory.Sort(modifiers, func(a, b *models.Modifier) int { return memory.CompareString(a.Name, b.Name) })
+		return nil
+	})
+	return modifiers, err
+}
+
+func (r *modifierMemoryRepository) GetModifierByID(ctx context.Context, id uuid.UUID) (*models.Modifier, error) {
+	var modifier models.Modifier
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Modifiers[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Modifier not found"), "[ModifierMemoryRepository.GetModifierByID]")
+		}
+		modifier = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &modifier, nil
+}
+
+func (r *modifierMemoryRepository) CreateModifier(ctx context.Context, modifier *models.Modifier) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if modifier.ID == uuid.Nil {
+			modifier.ID = uuid.New()
+		}
+		if modifier.PriceDeltaBaht == nil {
+			zero := int64(0)
+			modifier.PriceDeltaBaht = &zero
+		}
+		return r.save(t, modifier, "[ModifierMemoryRepository.CreateModifier]")
+	})
+}
+
+func (r *modifierMemoryRepository) UpdateModifier(ctx context.Context, modifier *models.Modifier) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, modifier, "[ModifierMemoryRepository.UpdateModifier]")
+	})
+}
+
+func (r *modifierMemoryRepository) save(t *memory.Tables, modifier *models.Modifier, op string) error {
+	if memory.Any(t.Modifiers, func(other *models.Modifier) bool {
+		return other.ID != modifier.ID && memory.EqualString(other.Name, modifier.Name)
+	}) {
+		return errors.Wrap(domain.ConflictError("A modifier with this name already exists"), op)
+	}
+
+	t.Modifiers[modifier.ID] = *modifier
+	return nil
+}
+
+// DeleteModifier refuses to delete a modifier that an order item references,
+// like ON DELETE RESTRICT, and takes it out of modifier groups, like ON DELETE
+// CASCADE
+func (r *modifierMemoryRepository) DeleteModifier(ctx context.Context, id uuid.UUID) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.OrderItemModifiers, func(m *models.OrderItemModifier) bool { return m.ModifierID == id }) {
+			return errors.Wrap(domain.ConflictError("Modifier is used by existing orders"), "[ModifierMemoryRepository.DeleteModifier]")
+		}
+		delete(t.Modifiers, id)
+		for key := range t.ModifierGroupOptions {
+			if key.ModifierID == id {
+				delete(t.ModifierGroupOptions, key)
+			}
+		}
+		return nil
+	})
+}
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"gorm.io/gorm"
@@ -16,41 +19,50 @@ func NewModifierRepository(db *gorm.DB) domain.ModifierRepository {
 	return &modifierRepository{db: db}
 }
 
-func (r *modifierRepository) GetAllModifiers() ([]*models.Modifier, error) {
+func (r *modifierRepository) GetAllModifiers(ctx context.Context) ([]*models.Modifier, error) {
 	var modifiers []*models.Modifier
-	if err := r.db.Order("name ASC").Find(&modifiers).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Order("name ASC").Find(&modifiers).Error; err != nil {
 		return nil, errors.Wrap(err, "[ModifierRepository.GetAllModifiers]: Error querying database")
 	}
 	return modifiers, nil
 }
 
-func (r *modifierRepository) GetModifierByID(id uuid.UUID) (*models.Modifier, error) {
+func (r *modifierRepository) GetModifierByID(ctx context.Context, id uuid.UUID) (*models.Modifier, error) {
 	var modifier models.Modifier
-	if err := r.db.Where("id = ?", id).First(&modifier).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&modifier).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[ModifierRepository.GetModifierByID]: Modifier not found")
+			return nil, errors.Wrap(domain.NotFoundError("Modifier not found"), "[ModifierRepository.GetModifierByID]")
 		}
 		return nil, errors.Wrap(err, "[ModifierRepository.GetModifierByID]: Error querying database")
 	}
 	return &modifier, nil
 }
 
-func (r *modifierRepository) CreateModifier(modifier *models.Modifier) error {
-	if err := r.db.Create(modifier).Error; err != nil {
+func (r *modifierRepository) CreateModifier(ctx context.Context, modifier *models.Modifier) error {
+	if err := database.Conn(ctx, r.db).Create(modifier).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A modifier with this name already exists"), "[ModifierRepository.CreateModifier]")
+		}
 		return errors.Wrap(err, "[ModifierRepository.CreateModifier]: Error creating modifier")
 	}
 	return nil
 }
 
-func (r *modifierRepository) UpdateModifier(modifier *models.Modifier) error {
-	if err := r.db.Save(modifier).Error; err != nil {
+func (r *modifierRepository) UpdateModifier(ctx context.Context, modifier *models.Modifier) error {
+	if err := database.Conn(ctx, r.db).Save(modifier).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A modifier with this name already exists"), "[ModifierRepository.UpdateModifier]")
+		}
 		return errors.Wrap(err, "[ModifierRepository.UpdateModifier]: Error updating modifier")
 	}
 	return nil
 }
 
-func (r *modifierRepository) DeleteModifier(id uuid.UUID) error {
-	if err := r.db.Where("id = ?", id).Delete(&models.Modifier{}).Error; err != nil {
+func (r *modifierRepository) DeleteModifier(ctx context.Context, id uuid.UUID) error {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).Delete(&models.Modifier{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return errors.Wrap(domain.ConflictError("Modifier is used by existing orders"), "[ModifierRepository.DeleteModifier]")
+		}
 		return errors.Wrap(err, "[ModifierRepository.DeleteModifier]: Error deleting modifier")
 	}
 	return nil
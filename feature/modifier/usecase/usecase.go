@@ -1,57 +1,62 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
 type modifierUsecase struct {
 	modifierRepository domain.ModifierRepository
+	transactor         domain.Transactor
+	auditUsecase       domain.AuditUsecase
 }
 
-func NewModifierUsecase(modifierRepository domain.ModifierRepository) domain.ModifierUsecase {
-	return &modifierUsecase{modifierRepository: modifierRepository}
+func NewModifierUsecase(modifierRepository domain.ModifierRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.ModifierUsecase {
+	return &modifierUsecase{modifierRepository: modifierRepository, transactor: transactor, auditUsecase: auditUsecase}
 }
 
-func (u *modifierUsecase) GetAllModifiers() ([]*response.ModifierResponse, error) {
-	modifiers, err := u.modifierRepository.GetAllModifiers()
+func (u *modifierUsecase) GetAllModifiers(ctx context.Context) (*response.Page[*response.ModifierResponse], error) {
+	ctx, span := tracing.Start(ctx, "ModifierUsecase.GetAllModifiers")
+	defer span.End()
+
+	modifiers, err := u.modifierRepository.GetAllModifiers(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "[ModifierUsecase.GetAllModifiers]: Error getting modifiers")
 	}
 
 	modifierResponses := make([]*response.ModifierResponse, len(modifiers))
 	for i, modifier := range modifiers {
-		modifierResponses[i] = &response.ModifierResponse{
-			ID:             modifier.ID,
-			Name:           utils.DerefString(modifier.Name),
-			PriceDeltaBaht: utils.DerefInt64(modifier.PriceDeltaBaht),
-			Note:           utils.DerefString(modifier.Note),
-		}
+		modifierResponses[i] = u.buildModifierResponse(modifier)
 	}
 
-	return modifierResponses, nil
+	return response.SinglePage(modifierResponses), nil
 }
 
-func (u *modifierUsecase) GetModifierByID(id uuid.UUID) (*response.ModifierResponse, error) {
-	modifier, err := u.modifierRepository.GetModifierByID(id)
+func (u *modifierUsecase) GetModifierByID(ctx context.Context, id uuid.UUID) (*response.ModifierResponse, error) {
+	ctx, span := tracing.Start(ctx, "ModifierUsecase.GetModifierByID")
+	defer span.End()
+
+	modifier, err := u.modifierRepository.GetModifierByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[ModifierUsecase.GetModifierByID]: Error getting modifier")
 	}
 
-	return &response.ModifierResponse{
-		ID:             modifier.ID,
-		Name:           utils.DerefString(modifier.Name),
-		PriceDeltaBaht: utils.DerefInt64(modifier.PriceDeltaBaht),
-		Note:           utils.DerefString(modifier.Note),
-	}, nil
+	return u.buildModifierResponse(modifier), nil
 }
 
-func (u *modifierUsecase) CreateModifier(req *request.ModifierRequest) (*response.ModifierResponse, error) {
+func (u *modifierUsecase) CreateModifier(ctx context.Context, req *request.ModifierRequest) (*response.ModifierResponse, error) {
+	ctx, span := tracing.Start(ctx, "ModifierUsecase.CreateModifier")
+	defer span.End()
+
 	// Set default price delta to 0 if not provided
 	priceDelta := int64(0)
 	if req.PriceDeltaBaht != nil {
@@ -64,24 +69,32 @@ func (u *modifierUsecase) CreateModifier(req *request.ModifierRequest) (*respons
 		Note:           req.Note,
 	}
 
-	if err := u.modifierRepository.CreateModifier(modifier); err != nil {
-		return nil, errors.Wrap(err, "[ModifierUsecase.CreateModifier]: Error creating modifier")
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.modifierRepository.CreateModifier(ctx, modifier); err != nil {
+			return errors.Wrap(err, "[ModifierUsecase.CreateModifier]: Error creating modifier")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityModifier, modifier.ID.String(), nil, u.buildModifierResponse(modifier)); err != nil {
+			return errors.Wrap(err, "[ModifierUsecase.CreateModifier]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.ModifierResponse{
-		ID:             modifier.ID,
-		Name:           req.Name,
-		PriceDeltaBaht: priceDelta,
-		Note:           utils.DerefString(req.Note),
-	}, nil
+	return u.buildModifierResponse(modifier), nil
 }
 
-func (u *modifierUsecase) UpdateModifier(id uuid.UUID, req *request.ModifierRequest) (*response.ModifierResponse, error) {
+func (u *modifierUsecase) UpdateModifier(ctx context.Context, id uuid.UUID, req *request.ModifierRequest) (*response.ModifierResponse, error) {
+	ctx, span := tracing.Start(ctx, "ModifierUsecase.UpdateModifier")
+	defer span.End()
+
 	// Get existing modifier
-	modifier, err := u.modifierRepository.GetModifierByID(id)
+	modifier, err := u.modifierRepository.GetModifierByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[ModifierUsecase.UpdateModifier]: Modifier not found")
 	}
+	before := u.buildModifierResponse(modifier)
 
 	// Update fields
 	modifier.Name = &req.Name
@@ -90,28 +103,49 @@ func (u *modifierUsecase) UpdateModifier(id uuid.UUID, req *request.ModifierRequ
 	}
 	modifier.Note = req.Note
 
-	if err := u.modifierRepository.UpdateModifier(modifier); err != nil {
-		return nil, errors.Wrap(err, "[ModifierUsecase.UpdateModifier]: Error updating modifier")
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.modifierRepository.UpdateModifier(ctx, modifier); err != nil {
+			return errors.Wrap(err, "[ModifierUsecase.UpdateModifier]: Error updating modifier")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityModifier, modifier.ID.String(), before, u.buildModifierResponse(modifier)); err != nil {
+			return errors.Wrap(err, "[ModifierUsecase.UpdateModifier]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.ModifierResponse{
-		ID:             modifier.ID,
-		Name:           utils.DerefString(modifier.Name),
-		PriceDeltaBaht: utils.DerefInt64(modifier.PriceDeltaBaht),
-		Note:           utils.DerefString(modifier.Note),
-	}, nil
+	return u.buildModifierResponse(modifier), nil
 }
 
-func (u *modifierUsecase) DeleteModifier(id uuid.UUID) error {
+func (u *modifierUsecase) DeleteModifier(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "ModifierUsecase.DeleteModifier")
+	defer span.End()
+
 	// Check if modifier exists
-	_, err := u.modifierRepository.GetModifierByID(id)
+	modifier, err := u.modifierRepository.GetModifierByID(ctx, id)
 	if err != nil {
 		return errors.Wrap(err, "[ModifierUsecase.DeleteModifier]: Modifier not found")
 	}
 
-	if err := u.modifierRepository.DeleteModifier(id); err != nil {
-		return errors.Wrap(err, "[ModifierUsecase.DeleteModifier]: Error deleting modifier")
-	}
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.modifierRepository.DeleteModifier(ctx, id); err != nil {
+			return errors.Wrap(err, "[ModifierUsecase.DeleteModifier]: Error deleting modifier")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionDelete, constant.AuditEntityModifier, id.String(), u.buildModifierResponse(modifier), nil); err != nil {
+			return errors.Wrap(err, "[ModifierUsecase.DeleteModifier]: Error recording audit log")
+		}
+		return nil
+	})
+}
 
-	return nil
+// Helper function to build modifier response
+func (u *modifierUsecase) buildModifierResponse(modifier *models.Modifier) *response.ModifierResponse {
+	return &response.ModifierResponse{
+		ID:             modifier.ID,
+		Name:           utils.DerefString(modifier.Name),
+		PriceDeltaBaht: utils.DerefInt64(modifier.PriceDeltaBaht),
+		Note:           utils.DerefString(modifier.Note),
+	}
 }
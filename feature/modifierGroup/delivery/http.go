@@ -0,0 +1,95 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type modifierGroupHandler struct {
+	modifierGroupUsecase domain.ModifierGroupUsecase
+}
+
+func NewModifierGroupHandler(modifierGroupUsecase domain.ModifierGroupUsecase) *modifierGroupHandler {
+	return &modifierGroupHandler{modifierGroupUsecase: modifierGroupUsecase}
+}
+
+func (h *modifierGroupHandler) GetAllModifierGroups(c *gin.Context) {
+	groups, err := h.modifierGroupUsecase.GetAllModifierGroups(c.Request.Context())
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[ModifierGroupHandler.GetAllModifierGroups]: Error getting modifier groups"))
+		return
+	}
+	c.JSON(http.StatusOK, groups)
+}
+
+func (h *modifierGroupHandler) GetModifierGroupByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid modifier group ID", nil))
+		return
+	}
+
+	group, err := h.modifierGroupUsecase.GetModifierGroupByID(c.Request.Context(), id)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[ModifierGroupHandler.GetModifierGroupByID]: Error getting modifier group"))
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+func (h *modifierGroupHandler) CreateModifierGroup(c *gin.Context) {
+	var req request.ModifierGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	group, err := h.modifierGroupUsecase.CreateModifierGroup(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[ModifierGroupHandler.CreateModifierGroup]: Error creating modifier group"))
+		return
+	}
+	c.JSON(http.StatusCreated, group)
+}
+
+func (h *modifierGroupHandler) UpdateModifierGroup(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid modifier group ID", nil))
+		return
+	}
+
+	var req request.ModifierGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	group, err := h.modifierGroupUsecase.UpdateModifierGroup(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[ModifierGroupHandler.UpdateModifierGroup]: Error updating modifier group"))
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+func (h *modifierGroupHandler) DeleteModifierGroup(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid modifier group ID", nil))
+		return
+	}
+
+	if err := h.modifierGroupUsecase.DeleteModifierGroup(c.Request.Context(), id); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[ModifierGroupHandler.DeleteModifierGroup]: Error deleting modifier group"))
+		return
+	}
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Modifier group deleted successfully"})
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type modifierGroupMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewModifierGroupMemoryRepository(store *memory.Store) domain.ModifierGroupRepository {
+	return &modifierGroupMemoryRepository{store: store}
+}
+
+func (r *modifierGroupMemoryRepository) GetAllModifierGroups(ctx context.Context) ([]*models.ModifierGroup, error) {
+	var groups []*models.ModifierGroup
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		groups = memory.Select(t.ModifierGroups, nil)
+		memory.Sort(groups,
+			func(a, b *models.ModifierGroup) int { return cmp.Compare(a.SortOrder, b.SortOrder) },
+			func(a, b *models.ModifierGroup) int { return memory.CompareString(a.Name, b.Name) },
+		)
+		for _, group := range groups {
+			preloadOptions(t, group)
+		}
+		return nil
+	})
+	return groups, err
+}
+
+func (r *modifierGroupMemoryRepository) GetModifierGroupByID(ctx context.Context, id uuid.UUID) (*models.ModifierGroup, error) {
+	var group models.ModifierGroup
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.ModifierGroups[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Modifier group not found"), "[ModifierGroupMemoryRepository.GetModifierGroupByID]")
+		}
+		group = found
+		preloadOptions(t, &group)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *modifierGroupMemoryRepository) CreateModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if group.ID == uuid.Nil {
+			group.ID = uuid.New()
+		}
+		return r.save(t, group, "[ModifierGroupMemoryRepository.CreateModifierGroup]")
+	})
+}
+
+func (r *modifierGroupMemoryRepository) UpdateModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, group, "[ModifierGroupMemoryRepository.UpdateModifierGroup]")
+	})
+}
+
+// save checks the group's name is unique and what its options and links
+// reference exists, like its constraints, then stores it and replaces its
+// options and links
+func (r *modifierGroupMemoryRepository) save(t *memory.Tables, group *models.ModifierGroup, op string) error {
+	if memory.Any(t.ModifierGroups, func(other *models.ModifierGroup) bool {
+		return other.ID != group.ID && memory.EqualString(other.Name, group.Name)
+	}) {
+		return errors.Wrap(domain.ConflictError("A modifier group with this name already exists"), op)
+	}
+	for _, option := range group.Options {
+		if _, ok := t.Modifiers[option.ModifierID]; !ok {
+			return errors.Wrap(domain.NotFoundError("Modifier, menu item or category not found"), op)
+		}
+	}
+	for _, link := range group.MenuItems {
+		if _, ok := t.MenuItems[link.MenuItemID]; !ok {
+			return errors.Wrap(domain.NotFoundError("Modifier, menu item or category not found"), op)
+		}
+	}
+	for _, link := range group.Categories {
+		if _, ok := t.Categories[link.CategoryID]; !ok {
+			return errors.Wrap(domain.NotFoundError("Modifier, menu item or category not found"), op)
+		}
+	}
+
+	deleteOptionsAndLinks(t, group.ID)
+	for i := range group.Options {
+		option := &group.Options[i]
+		option.ModifierGroupID = group.ID
+		row := *option
+		row.ModifierGroup, row.Modifier = nil, nil
+		t.ModifierGroupOptions[memory.ModifierGroupOptionKey{ModifierGroupID: group.ID, ModifierID: row.ModifierID}] = row
+	}
+	for i := range group.MenuItems {
+		link := &group.MenuItems[i]
+		link.ModifierGroupID = group.ID
+		row := *link
+		row.MenuItem, row.ModifierGroup = nil, nil
+		t.MenuItemModifierGroups[memory.MenuItemModifierGroupKey{MenuItemID: row.MenuItemID, ModifierGroupID: group.ID}] = row
+	}
+	for i := range group.Categories {
+		link := &group.Categories[i]
+		link.ModifierGroupID = group.ID
+		row := *link
+		row.Category, row.ModifierGroup = nil, nil
+		t.CategoryModifierGroups[memory.CategoryModifierGroupKey{CategoryID: row.CategoryID, ModifierGroupID: group.ID}] = row
+	}
+
+	row := *group
+	row.Options, row.MenuItems, row.Categories = nil, nil, nil
+	t.ModifierGroups[row.ID] = row
+	return nil
+}
+
+// DeleteModifierGroup deletes the group with its options and links, like ON
+// DELETE CASCADE
+func (r *modifierGroupMemoryRepository) DeleteModifierGroup(ctx context.Context, id uuid.UUID) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		delete(t.ModifierGroups, id)
+		deleteOptionsAndLinks(t, id)
+		return nil
+	})
+}
+
+func deleteOptionsAndLinks(t *memory.Tables, groupID uuid.UUID) {
+	for key := range t.ModifierGroupOptions {
+		if key.ModifierGroupID == groupID {
+			delete(t.ModifierGroupOptions, key)
+		}
+	}
+	for key := range t.MenuItemModifierGroups {
+		if key.ModifierGroupID == groupID {
+			delete(t.MenuItemModifierGroups, key)
+		}
+	}
+	for key := range t.CategoryModifierGroups {
+		if key.ModifierGroupID == groupID {
+			delete(t.CategoryModifierGroups, key)
+		}
+	}
+}
+
+func preloadOptions(t *memory.Tables, group *models.ModifierGroup) {
+	group.Options = t.OptionsOfModifierGroup(group.ID)
+	group.MenuItems = nil
+	for _, link := range t.MenuItemModifierGroups {
+		if link.ModifierGroupID == group.ID {
+			group.MenuItems = append(group.MenuItems, link)
+		}
+	}
+	slices.SortFunc(group.MenuItems, func(a, b models.MenuItemModifierGroup) int {
+		return cmp.Compare(a.MenuItemID.String(), b.MenuItemID.String())
+	})
+	group.Categories = nil
+	for _, link := range t.CategoryModifierGroups {
+		if link.ModifierGroupID == group.ID {
+			group.Categories = append(group.Categories, link)
+		}
+	}
+	slices.SortFunc(group.Categories, func(a, b models.CategoryModifierGroup) int {
+		return cmp.Compare(a.CategoryID.String(), b.CategoryID.String())
+	})
+}
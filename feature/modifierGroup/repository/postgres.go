@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type modifierGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewModifierGroupRepository(db *gorm.DB) domain.ModifierGroupRepository {
+	return &modifierGroupRepository{db: db}
+}
+
+func (r *modifierGroupRepository) GetAllModifierGroups(ctx context.Context) ([]*models.ModifierGroup, error) {
+	var groups []*models.ModifierGroup
+	if err := withOptions(database.Conn(ctx, r.db)).Order("sort_order ASC").Order("name ASC").Find(&groups).Error; err != nil {
+		return nil, errors.Wrap(err, "[ModifierGroupRepository.GetAllModifierGroups]: Error querying database")
+	}
+	return groups, nil
+}
+
+func (r *modifierGroupRepository) GetModifierGroupByID(ctx context.Context, id uuid.UUID) (*models.ModifierGroup, error) {
+	var group models.ModifierGroup
+	if err := withOptions(database.Conn(ctx, r.db)).Where("id = ?", id).First(&group).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Modifier group not found"), "[ModifierGroupRepository.GetModifierGroupByID]")
+		}
+		return nil, errors.Wrap(err, "[ModifierGroupRepository.GetModifierGroupByID]: Error querying database")
+	}
+	return &group, nil
+}
+
+func (r *modifierGroupRepository) CreateModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(group).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A modifier group with this name already exists"), "[ModifierGroupRepository.CreateModifierGroup]")
+		}
+		return errors.Wrap(err, "[ModifierGroupRepository.CreateModifierGroup]: Error creating modifier group")
+	}
+	return r.saveOptions(ctx, group, "[ModifierGroupRepository.CreateModifierGroup]")
+}
+
+func (r *modifierGroupRepository) UpdateModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Save(group).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A modifier group with this name already exists"), "[ModifierGroupRepository.UpdateModifierGroup]")
+		}
+		return errors.Wrap(err, "[ModifierGroupRepository.UpdateModifierGroup]: Error updating modifier group")
+	}
+	for _, model := range []any{&models.ModifierGroupOption{}, &models.MenuItemModifierGroup{}, &models.CategoryModifierGroup{}} {
+		if err := database.Conn(ctx, r.db).Where("modifier_group_id = ?", group.ID).Delete(model).Error; err != nil {
+			return errors.Wrap(err, "[ModifierGroupRepository.UpdateModifierGroup]: Error deleting options and links")
+		}
+	}
+	return r.saveOptions(ctx, group, "[ModifierGroupRepository.UpdateModifierGroup]")
+}
+
+// saveOptions creates the group's options and its links to menu items and
+// categories
+func (r *modifierGroupRepository) saveOptions(ctx context.Context, group *models.ModifierGroup, op string) error {
+	for i := range group.Options {
+		group.Options[i].ModifierGroupID = group.ID
+	}
+	for i := range group.MenuItems {
+		group.MenuItems[i].ModifierGroupID = group.ID
+	}
+	for i := range group.Categories {
+		group.Categories[i].ModifierGroupID = group.ID
+	}
+
+	if err := r.create(ctx, &group.Options, len(group.Options), op); err != nil {
+		return err
+	}
+	if err := r.create(ctx, &group.MenuItems, len(group.MenuItems), op); err != nil {
+		return err
+	}
+	return r.create(ctx, &group.Categories, len(group.Categories), op)
+}
+
+func (r *modifierGroupRepository) create(ctx context.Context, rows any, count int, op string) error {
+	if count == 0 {
+		return nil
+	}
+	if err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(rows).Error; err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return errors.Wrap(domain.NotFoundError("Modifier, menu item or category not found"), op)
+		}
+		return errors.Wrap(err, op+": Error creating options and links")
+	}
+	return nil
+}
+
+func (r *modifierGroupRepository) DeleteModifierGroup(ctx context.Context, id uuid.UUID) error {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).Delete(&models.ModifierGroup{}).Error; err != nil {
+		return errors.Wrap(err, "[ModifierGroupRepository.DeleteModifierGroup]: Error deleting modifier group")
+	}
+	return nil
+}
+
+func withOptions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		Preload("Options.Modifier").
+		Preload("MenuItems", func(db *gorm.DB) *gorm.DB { return db.Order("menu_item_id ASC") }).
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("category_id ASC") })
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type modifierGroupUsecase struct {
+	modifierGroupRepository domain.ModifierGroupRepository
+	transactor              domain.Transactor
+	auditUsecase            domain.AuditUsecase
+}
+
+func NewModifierGroupUsecase(modifierGroupRepository domain.ModifierGroupRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.ModifierGroupUsecase {
+	return &modifierGroupUsecase{modifierGroupRepository: modifierGroupRepository, transactor: transactor, auditUsecase: auditUsecase}
+}
+
+func (u *modifierGroupUsecase) GetAllModifierGroups(ctx context.Context) (*response.Page[*response.ModifierGroupResponse], error) {
+	ctx, span := tracing.Start(ctx, "ModifierGroupUsecase.GetAllModifierGroups")
+	defer span.End()
+
+	groups, err := u.modifierGroupRepository.GetAllModifierGroups(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ModifierGroupUsecase.GetAllModifierGroups]: Error getting modifier groups")
+	}
+
+	groupResponses := make([]*response.ModifierGroupResponse, len(groups))
+	for i, group := range groups {
+		groupResponses[i] = buildModifierGroupResponse(group)
+	}
+
+	return response.SinglePage(groupResponses), nil
+}
+
+func (u *modifierGroupUsecase) GetModifierGroupByID(ctx context.Context, id uuid.UUID) (*response.ModifierGroupResponse, error) {
+	ctx, span := tracing.Start(ctx, "ModifierGroupUsecase.GetModifierGroupByID")
+	defer span.End()
+
+	group, err := u.modifierGroupRepository.GetModifierGroupByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ModifierGroupUsecase.GetModifierGroupByID]: Error getting modifier group")
+	}
+
+	return buildModifierGroupResponse(group), nil
+}
+
+func (u *modifierGroupUsecase) CreateModifierGroup(ctx context.Context, req *request.ModifierGroupRequest) (*response.ModifierGroupResponse, error) {
+	ctx, span := tracing.Start(ctx, "ModifierGroupUsecase.CreateModifierGroup")
+	defer span.End()
+
+	group := &models.ModifierGroup{}
+	if err := applyModifierGroupRequest(group, req); err != nil {
+		return nil, errors.Wrap(err, "[ModifierGroupUsecase.CreateModifierGroup]")
+	}
+
+	var groupResponse *response.ModifierGroupResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.modifierGroupRepository.CreateModifierGroup(ctx, group); err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.CreateModifierGroup]: Error creating modifier group")
+		}
+		// Reloaded for the names and prices of its modifiers
+		created, err := u.modifierGroupRepository.GetModifierGroupByID(ctx, group.ID)
+		if err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.CreateModifierGroup]: Error getting modifier group")
+		}
+		groupResponse = buildModifierGroupResponse(created)
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityModifierGroup, group.ID.String(), nil, groupResponse); err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.CreateModifierGroup]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groupResponse, nil
+}
+
+func (u *modifierGroupUsecase) UpdateModifierGroup(ctx context.Context, id uuid.UUID, req *request.ModifierGroupRequest) (*response.ModifierGroupResponse, error) {
+	ctx, span := tracing.Start(ctx, "ModifierGroupUsecase.UpdateModifierGroup")
+	defer span.End()
+
+	// Get existing modifier group
+	group, err := u.modifierGroupRepository.GetModifierGroupByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ModifierGroupUsecase.UpdateModifierGroup]: Modifier group not found")
+	}
+	before := buildModifierGroupResponse(group)
+
+	if err := applyModifierGroupRequest(group, req); err != nil {
+		return nil, errors.Wrap(err, "[ModifierGroupUsecase.UpdateModifierGroup]")
+	}
+
+	var groupResponse *response.ModifierGroupResponse
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.modifierGroupRepository.UpdateModifierGroup(ctx, group); err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.UpdateModifierGroup]: Error updating modifier group")
+		}
+		// Reloaded for the names and prices of its modifiers
+		updated, err := u.modifierGroupRepository.GetModifierGroupByID(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.UpdateModifierGroup]: Error getting modifier group")
+		}
+		groupResponse = buildModifierGroupResponse(updated)
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityModifierGroup, id.String(), before, groupResponse); err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.UpdateModifierGroup]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groupResponse, nil
+}
+
+func (u *modifierGroupUsecase) DeleteModifierGroup(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "ModifierGroupUsecase.DeleteModifierGroup")
+	defer span.End()
+
+	// Check if modifier group exists
+	group, err := u.modifierGroupRepository.GetModifierGroupByID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "[ModifierGroupUsecase.DeleteModifierGroup]: Modifier group not found")
+	}
+	before := buildModifierGroupResponse(group)
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.modifierGroupRepository.DeleteModifierGroup(ctx, id); err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.DeleteModifierGroup]: Error deleting modifier group")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionDelete, constant.AuditEntityModifierGroup, id.String(), before, nil); err != nil {
+			return errors.Wrap(err, "[ModifierGroupUsecase.DeleteModifierGroup]: Error recording audit log")
+		}
+		return nil
+	})
+}
+
+// applyModifierGroupRequest validates req and copies it onto group, replacing
+// its options and links. Options are offered in the order given.
+func applyModifierGroupRequest(group *models.ModifierGroup, req *request.ModifierGroupRequest) error {
+	if req.MaxSelections != nil && req.MinSelections > *req.MaxSelections {
+		return domain.ValidationError("Min selections must not be above max selections", map[string]string{"min_selections": "must not be above max_selections"})
+	}
+	if req.MinSelections > len(req.Options) {
+		return domain.ValidationError("Min selections must not be above the number of options", map[string]string{"min_selections": "must not be above the number of options"})
+	}
+
+	options := make([]models.ModifierGroupOption, len(req.Options))
+	defaults := 0
+	for i, option := range req.Options {
+		for _, other := range options[:i] {
+			if other.ModifierID == option.ModifierID {
+				return domain.ValidationError("Options must be unique", map[string]string{"options": fmt.Sprintf("%s is given twice", option.ModifierID)})
+			}
+		}
+		options[i] = models.ModifierGroupOption{ModifierID: option.ModifierID, SortOrder: i, IsDefault: option.Default}
+		if option.Default {
+			defaults++
+		}
+	}
+	if req.MaxSelections != nil && defaults > *req.MaxSelections {
+		return domain.ValidationError("More defaults than max selections", map[string]string{"options": "more defaults than max_selections"})
+	}
+
+	menuItems := make([]models.MenuItemModifierGroup, len(req.MenuItemIDs))
+	for i, menuItemID := range req.MenuItemIDs {
+		for _, other := range menuItems[:i] {
+			if other.MenuItemID == menuItemID {
+				return domain.ValidationError("Menu items must be unique", map[string]string{"menu_item_ids": fmt.Sprintf("%s is given twice", menuItemID)})
+			}
+		}
+		menuItems[i] = models.MenuItemModifierGroup{MenuItemID: menuItemID}
+	}
+
+	categories := make([]models.CategoryModifierGroup, len(req.CategoryIDs))
+	for i, categoryID := range req.CategoryIDs {
+		for _, other := range categories[:i] {
+			if other.CategoryID == categoryID {
+				return domain.ValidationError("Categories must be unique", map[string]string{"category_ids": fmt.Sprintf("%s is given twice", categoryID)})
+			}
+		}
+		categories[i] = models.CategoryModifierGroup{CategoryID: categoryID}
+	}
+
+	group.Name = &req.Name
+	group.MinSelections = req.MinSelections
+	group.MaxSelections = req.MaxSelections
+	group.SortOrder = req.SortOrder
+	group.Options = options
+	group.MenuItems = menuItems
+	group.Categories = categories
+	return nil
+}
+
+func buildModifierGroupResponse(group *models.ModifierGroup) *response.ModifierGroupResponse {
+	menuItemIDs := make([]uuid.UUID, len(group.MenuItems))
+	for i, link := range group.MenuItems {
+		menuItemIDs[i] = link.MenuItemID
+	}
+	categoryIDs := make([]uuid.UUID, len(group.Categories))
+	for i, link := range group.Categories {
+		categoryIDs[i] = link.CategoryID
+	}
+
+	options := make([]response.ModifierGroupOptionResponse, len(group.Options))
+	for i, option := range group.Options {
+		options[i] = response.ModifierGroupOptionResponse{ModifierID: option.ModifierID, Default: option.IsDefault}
+		if option.Modifier != nil {
+			options[i].Name = utils.DerefString(option.Modifier.Name)
+			options[i].PriceDeltaBaht = utils.DerefInt64(option.Modifier.PriceDeltaBaht)
+		}
+	}
+
+	return &response.ModifierGroupResponse{
+		ID:            group.ID,
+		Name:          utils.DerefString(group.Name),
+		MinSelections: group.MinSelections,
+		MaxSelections: group.MaxSelections,
+		SortOrder:     group.SortOrder,
+		Options:       options,
+		MenuItemIDs:   menuItemIDs,
+		CategoryIDs:   categoryIDs,
+	}
+}
@@ -6,10 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type orderHandler struct {
@@ -21,11 +22,15 @@ func NewOrderHandler(orderUsecase domain.OrderUsecase) *orderHandler {
 }
 
 func (h *orderHandler) GetAllOrders(c *gin.Context) {
-	orders, err := h.orderUsecase.GetAllOrders()
+	var req request.OrderListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	orders, err := h.orderUsecase.GetAllOrders(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.GetAllOrders]: Error getting orders")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.GetAllOrders]: Error getting orders"))
 		return
 	}
 	c.JSON(http.StatusOK, orders)
@@ -34,15 +39,13 @@ func (h *orderHandler) GetAllOrders(c *gin.Context) {
 func (h *orderHandler) GetOrderByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
 		return
 	}
 
-	order, err := h.orderUsecase.GetOrderByID(id)
+	order, err := h.orderUsecase.GetOrderByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.GetOrderByID]: Error getting order")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.GetOrderByID]: Error getting order"))
 		return
 	}
 	c.JSON(http.StatusOK, order)
@@ -51,26 +54,34 @@ func (h *orderHandler) GetOrderByID(c *gin.Context) {
 func (h *orderHandler) GetOrdersByTable(c *gin.Context) {
 	tableID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid table ID", nil))
+		return
+	}
+
+	var req request.OrderListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
 		return
 	}
 
-	orders, err := h.orderUsecase.GetOrdersByTable(tableID)
+	orders, err := h.orderUsecase.GetOrdersByTable(c.Request.Context(), tableID, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.GetOrdersByTable]: Error getting orders")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.GetOrdersByTable]: Error getting orders"))
 		return
 	}
 	c.JSON(http.StatusOK, orders)
 }
 
 func (h *orderHandler) GetOpenOrders(c *gin.Context) {
-	orders, err := h.orderUsecase.GetOpenOrders()
+	var req request.OrderListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	orders, err := h.orderUsecase.GetOpenOrders(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.GetOpenOrders]: Error getting open orders")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.GetOpenOrders]: Error getting open orders"))
 		return
 	}
 	c.JSON(http.StatusOK, orders)
@@ -79,15 +90,13 @@ func (h *orderHandler) GetOpenOrders(c *gin.Context) {
 func (h *orderHandler) CreateOrder(c *gin.Context) {
 	var req request.OrderCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	order, err := h.orderUsecase.CreateOrder(&req)
+	order, err := h.orderUsecase.CreateOrder(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.CreateOrder]: Error creating order")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.CreateOrder]: Error creating order"))
 		return
 	}
 	c.JSON(http.StatusCreated, order)
@@ -96,44 +105,52 @@ func (h *orderHandler) CreateOrder(c *gin.Context) {
 func (h *orderHandler) AddItemToOrder(c *gin.Context) {
 	orderID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
 		return
 	}
 
 	var req request.AddOrderItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	order, err := h.orderUsecase.AddItemToOrder(orderID, &req)
+	order, err := h.orderUsecase.AddItemToOrder(c.Request.Context(), orderID, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.AddItemToOrder]: Error adding item to order")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.AddItemToOrder]: Error adding item to order"))
 		return
 	}
 	c.JSON(http.StatusOK, order)
 }
 
-func (h *orderHandler) RemoveItemFromOrder(c *gin.Context) {
+func (h *orderHandler) CancelOrderItem(c *gin.Context) {
 	orderID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
 		return
 	}
 
 	itemID, err := uuid.Parse(c.Param("item_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid item ID", nil))
+		return
+	}
+
+	var req request.CancelOrderItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
 		return
 	}
 
-	order, err := h.orderUsecase.RemoveItemFromOrder(orderID, itemID)
+	order, err := h.orderUsecase.CancelOrderItem(c.Request.Context(), orderID, itemID, &req, userID.(uuid.UUID), c.GetHeader(constant.OverrideTokenHeader))
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.RemoveItemFromOrder]: Error removing item from order")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.CancelOrderItem]: Error cancelling order item"))
 		return
 	}
 	c.JSON(http.StatusOK, order)
@@ -142,27 +159,73 @@ func (h *orderHandler) RemoveItemFromOrder(c *gin.Context) {
 func (h *orderHandler) UpdateOrderItemQuantity(c *gin.Context) {
 	orderID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
 		return
 	}
 
 	itemID, err := uuid.Parse(c.Param("item_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid item ID", nil))
 		return
 	}
 
 	var req request.UpdateOrderItemQuantityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	order, err := h.orderUsecase.UpdateOrderItemQuantity(orderID, itemID, req.Quantity)
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
+		return
+	}
+
+	order, err := h.orderUsecase.UpdateOrderItemQuantity(c.Request.Context(), orderID, itemID, req.Quantity, userID.(uuid.UUID), c.GetHeader(constant.OverrideTokenHeader))
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.UpdateOrderItemQuantity]: Error updating item quantity")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.UpdateOrderItemQuantity]: Error updating item quantity"))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *orderHandler) SendOrderToKitchen(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
+		return
+	}
+
+	order, err := h.orderUsecase.SendOrderToKitchen(c.Request.Context(), id)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.SendOrderToKitchen]: Error sending order to kitchen"))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *orderHandler) ApplyDiscount(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
+		return
+	}
+
+	var req request.ApplyDiscountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
+		return
+	}
+
+	order, err := h.orderUsecase.ApplyDiscount(c.Request.Context(), id, &req, userID.(uuid.UUID), c.GetHeader(constant.OverrideTokenHeader))
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.ApplyDiscount]: Error applying discount"))
 		return
 	}
 	c.JSON(http.StatusOK, order)
@@ -171,15 +234,34 @@ func (h *orderHandler) UpdateOrderItemQuantity(c *gin.Context) {
 func (h *orderHandler) CloseOrder(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
 		return
 	}
 
-	order, err := h.orderUsecase.CloseOrder(id)
+	order, err := h.orderUsecase.CloseOrder(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[OrderHandler.CloseOrder]: Error closing order")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.CloseOrder]: Error closing order"))
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *orderHandler) ReopenOrder(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
+		return
+	}
+
+	order, err := h.orderUsecase.ReopenOrder(c.Request.Context(), id, userID.(uuid.UUID), c.GetHeader(constant.OverrideTokenHeader))
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.ReopenOrder]: Error reopening order"))
 		return
 	}
 	c.JSON(http.StatusOK, order)
@@ -188,15 +270,55 @@ func (h *orderHandler) CloseOrder(c *gin.Context) {
 func (h *orderHandler) VoidOrder(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
+		return
+	}
+
+	var req request.VoidOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
+		return
+	}
+
+	if err := h.orderUsecase.VoidOrder(c.Request.Context(), id, &req, userID.(uuid.UUID), c.GetHeader(constant.OverrideTokenHeader)); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.VoidOrder]: Error voiding order"))
+		return
+	}
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Order voided successfully"})
+}
+
+func (h *orderHandler) GetVoidReport(c *gin.Context) {
+	var req request.VoidReportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	report, err := h.orderUsecase.GetVoidReport(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.GetVoidReport]: Error getting void report"))
 		return
 	}
+	c.JSON(http.StatusOK, report)
+}
 
-	if err := h.orderUsecase.VoidOrder(id); err != nil {
-		err = errors.Wrap(err, "[OrderHandler.VoidOrder]: Error voiding order")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+func (h *orderHandler) GetVoids(c *gin.Context) {
+	var req request.VoidListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	voids, err := h.orderUsecase.GetVoids(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OrderHandler.GetVoids]: Error getting voids"))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Order voided successfully"})
+	c.JSON(http.StatusOK, voids)
 }
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+)
+
+// orderSummaryKeys are the fields order lists can be sorted by. The columns
+// are qualified as the summary query joins tables that share their names.
+var orderSummaryKeys = pagination.Keys[models.OrderSummary]{
+	IDColumn: "orders.id",
+	ID:       func(o *models.OrderSummary) uuid.UUID { return o.ID },
+	Sorts: map[string]pagination.Key[models.OrderSummary]{
+		"created_at": {Expr: "orders.created_at", Value: func(o *models.OrderSummary) any { return o.CreatedAt }},
+		"total_baht": {Expr: "COALESCE(orders.total_baht, 0)", Value: func(o *models.OrderSummary) any { return o.TotalBaht }},
+	},
+}
+
+// voidKeys are the fields void lists can be sorted by
+var voidKeys = pagination.Keys[models.Void]{
+	IDColumn: "voids.id",
+	ID:       func(v *models.Void) uuid.UUID { return v.ID },
+	Sorts: map[string]pagination.Key[models.Void]{
+		"voided_at":  {Expr: "voids.voided_at", Value: func(v *models.Void) any { return v.VoidedAt }},
+		"value_baht": {Expr: "voids.value_baht", Value: func(v *models.Void) any { return v.ValueBaht }},
+	},
+}
@@ -0,0 +1,594 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+type orderMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewOrderMemoryRepository(store *memory.Store) domain.OrderRepository {
+	return &orderMemoryRepository{store: store}
+}
+
+func (r *orderMemoryRepository) GetOrderSummaries(ctx context.Context, query *request.OrderListQuery, page domain.PageParams) (domain.Page[models.OrderSummary], error) {
+	var summaries domain.Page[models.OrderSummary]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		orders := memory.Select(t.Orders, func(o *models.Order) bool {
+			switch {
+			case query.Status != "" && utils.DerefString(o.Status) != query.Status:
+				return false
+			case query.TableID != "" && (o.TableID == nil || o.TableID.String() != query.TableID):
+				return false
+			case query.AreaID != "" && !inArea(t, o.TableID, query.AreaID):
+				return false
+			case query.Source != "" && utils.DerefString(o.Source) != query.Source:
+				return false
+			case query.OpenedBy != "" && (o.OpenedBy == nil || o.OpenedBy.String() != query.OpenedBy):
+				return false
+			case query.From != nil && o.CreatedAt.Before(*query.From):
+				return false
+			case query.To != nil && !o.CreatedAt.Before(*query.To):
+				return false
+			}
+			return true
+		})
+
+		rows := make([]*models.OrderSummary, len(orders))
+		for i, order := range orders {
+			rows[i] = summarizeOrder(t, order)
+		}
+		var err error
+		summaries, err = pagination.Slice(rows, page, orderSummaryKeys)
+		return errors.Wrap(err, "[OrderMemoryRepository.GetOrderSummaries]")
+	})
+	return summaries, err
+}
+
+// summarizeOrder computes the row the summary query returns for order
+func summarizeOrder(t *memory.Tables, order *models.Order) *models.OrderSummary {
+	summary := &models.OrderSummary{
+		ID:           order.ID,
+		TableID:      order.TableID,
+		Status:       order.Status,
+		Source:       order.Source,
+		OpenedBy:     order.OpenedBy,
+		TotalBaht:    utils.DerefInt64(order.TotalBaht),
+		CreatedAt:    order.CreatedAt,
+		BusinessDate: order.BusinessDate,
+		ClosedAt:     order.ClosedAt,
+		VoidedAt:     order.VoidedAt,
+	}
+	if order.TableID != nil {
+		if table, ok := t.DiningTables[*order.TableID]; ok {
+			summary.TableName = table.Name
+		}
+	}
+	if order.OpenedBy != nil {
+		if opener, ok := t.Users[*order.OpenedBy]; ok {
+			summary.OpenerName = opener.FullName
+		}
+	}
+	for _, item := range t.OrderItems {
+		if item.OrderID == order.ID && item.CancelledAt == nil {
+			summary.ItemCount += int64(item.Quantity)
+		}
+	}
+	for _, payment := range t.Payments {
+		if payment.OrderID == order.ID && utils.DerefString(payment.Status) == constant.PaymentStatusSucceeded {
+			summary.PaidBaht += payment.AmountBaht
+		}
+	}
+	return summary
+}
+
+func (r *orderMemoryRepository) GetItemsOfOrders(ctx context.Context, orderIDs []uuid.UUID) ([]*models.OrderItem, error) {
+	var items []*models.OrderItem
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		items = memory.Select(t.OrderItems, func(item *models.OrderItem) bool { return slices.Contains(orderIDs, item.OrderID) })
+		memory.Sort(items, func(a, b *models.OrderItem) int { return t.CompareInserted(a.ID, b.ID) })
+		for _, item := range items {
+			preloadOrderItem(t, item)
+		}
+		return nil
+	})
+	return items, err
+}
+
+// inArea reports whether the table is in the area
+func inArea(t *memory.Tables, tableID *uuid.UUID, areaID string) bool {
+	if tableID == nil {
+		return false
+	}
+	table, ok := t.DiningTables[*tableID]
+	return ok && table.AreaID != nil && table.AreaID.String() == areaID
+}
+
+func (r *orderMemoryRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Orders[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Order not found"), "[OrderMemoryRepository.GetOrderByID]")
+		}
+		order = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetOrderForUpdate needs no lock of its own: a transaction holds the store's
+// write lock until it ends
+func (r *orderMemoryRepository) GetOrderForUpdate(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	order, err := r.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OrderMemoryRepository.GetOrderForUpdate]")
+	}
+	return order, nil
+}
+
+func (r *orderMemoryRepository) GetOrderWithItems(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Orders[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Order not found"), "[OrderMemoryRepository.GetOrderWithItems]")
+		}
+		order = found
+		preloadOrder(t, &order)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *orderMemoryRepository) CreateOrder(ctx context.Context, order *models.Order) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if order.ID == uuid.Nil {
+			order.ID = uuid.New()
+		}
+		if order.CreatedAt.IsZero() {
+			order.CreatedAt = time.Now()
+		}
+		return r.saveOrder(t, order, "[OrderMemoryRepository.CreateOrder]: Error creating order")
+	})
+}
+
+func (r *orderMemoryRepository) UpdateOrder(ctx context.Context, order *models.Order) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.saveOrder(t, order, "[OrderMemoryRepository.UpdateOrder]: Error updating order")
+	})
+}
+
+func (r *orderMemoryRepository) saveOrder(t *memory.Tables, order *models.Order, op string) error {
+	if order.TableID != nil {
+		if _, ok := t.DiningTables[*order.TableID]; !ok {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, op)
+		}
+	}
+	for _, userID := range []*uuid.UUID{order.OpenedBy, order.VoidedBy} {
+		if userID == nil {
+			continue
+		}
+		if _, ok := t.Users[*userID]; !ok {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, op)
+		}
+	}
+
+	row := *order
+	row.Table, row.Opener, row.Voider, row.Items, row.Payments = nil, nil, nil, nil, nil
+	t.Orders[row.ID] = row
+	return nil
+}
+
+func (r *orderMemoryRepository) CreateOrderItem(ctx context.Context, item *models.OrderItem) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		if err := r.saveOrderItem(t, item, "[OrderMemoryRepository.CreateOrderItem]: Error creating order item"); err != nil {
+			return err
+		}
+		t.Inserted(item.ID)
+		return nil
+	})
+}
+
+func (r *orderMemoryRepository) UpdateOrderItem(ctx context.Context, item *models.OrderItem) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if err := r.saveOrderItem(t, item, "[OrderMemoryRepository.UpdateOrderItem]: Error updating order item"); err != nil {
+			return err
+		}
+		t.Inserted(item.ID)
+		return nil
+	})
+}
+
+func (r *orderMemoryRepository) saveOrderItem(t *memory.Tables, item *models.OrderItem, op string) error {
+	_, orderExists := t.Orders[item.OrderID]
+	_, menuItemExists := t.MenuItems[item.MenuItemID]
+	if !orderExists || !menuItemExists {
+		return errors.Wrap(gorm.ErrForeignKeyViolated, op)
+	}
+	if item.CancelledBy != nil {
+		if _, ok := t.Users[*item.CancelledBy]; !ok {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, op)
+		}
+	}
+
+	row := *item
+	row.Order, row.MenuItem, row.Canceller, row.Modifiers = nil, nil, nil, nil
+	t.OrderItems[row.ID] = row
+	return nil
+}
+
+func (r *orderMemoryRepository) MarkOrderItemsSent(ctx context.Context, orderID uuid.UUID, sentAt time.Time) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		for id, item := range t.OrderItems {
+			if item.OrderID == orderID && item.SentAt == nil && item.CancelledAt == nil {
+				item.SentAt = &sentAt
+				t.OrderItems[id] = item
+			}
+		}
+		return nil
+	})
+}
+
+func (r *orderMemoryRepository) GetOrderItemByID(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
+	var orderItem models.OrderItem
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.OrderItems[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Order item not found"), "[OrderMemoryRepository.GetOrderItemByID]")
+		}
+		orderItem = found
+		preloadOrderItem(t, &orderItem)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &orderItem, nil
+}
+
+// GetOrderItemForUpdate needs no lock of its own: a transaction holds the
+// store's write lock until it ends
+func (r *orderMemoryRepository) GetOrderItemForUpdate(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
+	orderItem, err := r.GetOrderItemByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OrderMemoryRepository.GetOrderItemForUpdate]")
+	}
+	return orderItem, nil
+}
+
+func (r *orderMemoryRepository) GetMenuItemByID(ctx context.Context, id uuid.UUID) (*models.MenuItem, error) {
+	var menuItem models.MenuItem
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.MenuItems[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Menu item not found"), "[OrderMemoryRepository.GetMenuItemByID]")
+		}
+		menuItem = found
+		if menuItem.CategoryID != nil {
+			if category, ok := t.Categories[*menuItem.CategoryID]; ok {
+				category.Schedules = t.SchedulesOfCategory(category.ID)
+				category.ModifierGroups = t.ModifierGroupsOfCategory(category.ID)
+				menuItem.Category = &category
+			}
+		}
+		menuItem.Schedules = t.SchedulesOfMenuItem(id)
+		menuItem.ModifierGroups = t.ModifierGroupsOfMenuItem(id)
+		if availability, ok := t.MenuItemAvailability[id]; ok {
+			menuItem.Availability = &availability
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &menuItem, nil
+}
+
+func (r *orderMemoryRepository) AdjustPortions(ctx context.Context, menuItemID uuid.UUID, delta int) (bool, error) {
+	var counted bool
+	err := r.store.Write(ctx, func(t *memory.Tables) error {
+		availability, ok := t.MenuItemAvailability[menuItemID]
+		if !ok || availability.RemainingPortions == nil {
+			return nil
+		}
+		counted = true
+		remaining := *availability.RemainingPortions + delta
+		if remaining < 0 {
+			return errors.Wrap(notEnoughPortions(*availability.RemainingPortions), "[OrderMemoryRepository.AdjustPortions]")
+		}
+		availability.RemainingPortions = &remaining
+		availability.UpdatedAt = time.Now()
+		t.MenuItemAvailability[menuItemID] = availability
+		return nil
+	})
+	return counted, err
+}
+
+func (r *orderMemoryRepository) CreateOrderItemModifier(ctx context.Context, modifier *models.OrderItemModifier) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		key := memory.OrderItemModifierKey{OrderItemID: modifier.OrderItemID, ModifierID: modifier.ModifierID}
+		if _, ok := t.OrderItemModifiers[key]; ok {
+			return errors.Wrap(gorm.ErrDuplicatedKey, "[OrderMemoryRepository.CreateOrderItemModifier]: Error creating order item modifier")
+		}
+		_, itemExists := t.OrderItems[modifier.OrderItemID]
+		_, modifierExists := t.Modifiers[modifier.ModifierID]
+		if !itemExists || !modifierExists {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, "[OrderMemoryRepository.CreateOrderItemModifier]: Error creating order item modifier")
+		}
+
+		row := *modifier
+		row.OrderItem, row.Modifier = nil, nil
+		t.OrderItemModifiers[key] = row
+		return nil
+	})
+}
+
+func (r *orderMemoryRepository) GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error) {
+	var table models.DiningTable
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.DiningTables[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Table not found"), "[OrderMemoryRepository.GetTableByID]")
+		}
+		table = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *orderMemoryRepository) GetVoidReasonByCode(ctx context.Context, code string) (*models.VoidReason, error) {
+	var reason *models.VoidReason
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		reasons := memory.Select(t.VoidReasons, func(v *models.VoidReason) bool { return v.Code == code })
+		if len(reasons) == 0 {
+			return errors.Wrap(domain.NotFoundError("Void reason not found"), "[OrderMemoryRepository.GetVoidReasonByCode]")
+		}
+		reason = reasons[0]
+		return nil
+	})
+	return reason, err
+}
+
+// GetVoidTotals sums voided orders and cancelled items in [from, to) by reason code
+// and acting user. Items cancelled before their order was voided are counted once,
+// as the voided total no longer includes them.
+func (r *orderMemoryRepository) GetVoidTotals(ctx context.Context, from time.Time, to time.Time) ([]*models.VoidTotal, error) {
+	type group struct {
+		reasonCode string
+		actorID    uuid.UUID
+	}
+	var totals []*models.VoidTotal
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		byGroup := make(map[group]*models.VoidTotal)
+		add := func(reasonCode *string, actorID *uuid.UUID, valueBaht int64) {
+			key := group{}
+			if reasonCode != nil {
+				key.reasonCode = *reasonCode
+			}
+			if actorID != nil {
+				key.actorID = *actorID
+			}
+			total, ok := byGroup[key]
+			if !ok {
+				total = &models.VoidTotal{ReasonCode: key.reasonCode, ActorID: actorID}
+				for _, reason := range t.VoidReasons {
+					if reason.Code == key.reasonCode {
+						total.ReasonLabel = reason.Label
+					}
+				}
+				if actorID != nil {
+					if actor, ok := t.Users[*actorID]; ok {
+						total.ActorName = actor.FullName
+					}
+				}
+				byGroup[key] = total
+				totals = append(totals, total)
+			}
+			total.Count++
+			total.ValueBaht += valueBaht
+		}
+
+		inRange := func(at *time.Time) bool {
+			return at != nil && !at.Before(from) && at.Before(to)
+		}
+		for _, order := range t.Orders {
+			if order.Status != nil && *order.Status == constant.OrderStatusVoid && inRange(order.VoidedAt) {
+				var value int64
+				if order.TotalBaht != nil {
+					value = *order.TotalBaht
+				}
+				add(order.VoidReason, order.VoidedBy, value)
+			}
+		}
+		for _, item := range t.OrderItems {
+			if inRange(item.CancelledAt) {
+				add(item.CancelReason, item.CancelledBy, item.LineTotalBaht)
+			}
+		}
+
+		memory.Sort(totals, func(a, b *models.VoidTotal) int { return cmp.Compare(b.ValueBaht, a.ValueBaht) })
+		return nil
+	})
+	return totals, err
+}
+
+// GetVoids lists voided orders and cancelled items together, each at its own
+// void time
+func (r *orderMemoryRepository) GetVoids(ctx context.Context, filter *request.VoidFilter, page domain.PageParams) (domain.Page[models.Void], error) {
+	var voids domain.Page[models.Void]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		var rows []*models.Void
+		add := func(v *models.Void, order models.Order) {
+			switch {
+			case filter.Kind != "" && v.Kind != filter.Kind:
+				return
+			case filter.ReasonCode != "" && utils.DerefString(v.ReasonCode) != filter.ReasonCode:
+				return
+			case filter.VoidedBy != "" && (v.VoidedBy == nil || v.VoidedBy.String() != filter.VoidedBy):
+				return
+			case filter.From != nil && v.VoidedAt.Before(*filter.From):
+				return
+			case filter.To != nil && !v.VoidedAt.Before(*filter.To):
+				return
+			}
+			v.BusinessDate = order.BusinessDate
+			if order.TableID != nil {
+				if table, ok := t.DiningTables[*order.TableID]; ok {
+					v.TableName = table.Name
+				}
+			}
+			if v.ReasonCode != nil {
+				for _, reason := range t.VoidReasons {
+					if reason.Code == *v.ReasonCode {
+						v.ReasonLabel = reason.Label
+					}
+				}
+			}
+			if v.VoidedBy != nil {
+				if voider, ok := t.Users[*v.VoidedBy]; ok {
+					v.VoiderName = voider.FullName
+				}
+			}
+			rows = append(rows, v)
+		}
+
+		for _, order := range t.Orders {
+			if utils.DerefString(order.Status) == constant.OrderStatusVoid && order.VoidedAt != nil {
+				add(&models.Void{
+					ID:         order.ID,
+					Kind:       constant.VoidKindOrder,
+					OrderID:    order.ID,
+					ValueBaht:  utils.DerefInt64(order.TotalBaht),
+					ReasonCode: order.VoidReason,
+					VoidedBy:   order.VoidedBy,
+					VoidedAt:   *order.VoidedAt,
+				}, order)
+			}
+		}
+		for _, item := range t.OrderItems {
+			order, ok := t.Orders[item.OrderID]
+			if item.CancelledAt == nil || !ok {
+				continue
+			}
+			v := &models.Void{
+				ID:         item.ID,
+				Kind:       constant.VoidKindItem,
+				OrderID:    item.OrderID,
+				Quantity:   utils.PtrI64(int64(item.Quantity)),
+				ValueBaht:  item.LineTotalBaht,
+				ReasonCode: item.CancelReason,
+				VoidedBy:   item.CancelledBy,
+				VoidedAt:   *item.CancelledAt,
+			}
+			if menuItem, ok := t.MenuItems[item.MenuItemID]; ok {
+				v.MenuItemName = menuItem.Name
+			}
+			add(v, order)
+		}
+
+		var err error
+		voids, err = pagination.Slice(rows, page, voidKeys)
+		return errors.Wrap(err, "[OrderMemoryRepository.GetVoids]")
+	})
+	return voids, err
+}
+
+// CountOpenOrdersByArea groups open orders by the name of their table's area,
+// with a nil name for orders outside any area
+func (r *orderMemoryRepository) CountOpenOrdersByArea(ctx context.Context) ([]*models.AreaOrderCount, error) {
+	var counts []*models.AreaOrderCount
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		byArea := make(map[uuid.UUID]*models.AreaOrderCount)
+		for _, order := range t.Orders {
+			if order.Status == nil || *order.Status != constant.OrderStatusOpen {
+				continue
+			}
+			var areaID uuid.UUID
+			var areaName *string
+			if order.TableID != nil {
+				if table, ok := t.DiningTables[*order.TableID]; ok && table.AreaID != nil {
+					if area, ok := t.Areas[*table.AreaID]; ok {
+						areaID, areaName = area.ID, area.Name
+					}
+				}
+			}
+			count, ok := byArea[areaID]
+			if !ok {
+				count = &models.AreaOrderCount{AreaName: areaName}
+				byArea[areaID] = count
+				counts = append(counts, count)
+			}
+			count.Count++
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// preloadOrder fills in the table and the items with their menu items and modifiers
+func preloadOrder(t *memory.Tables, order *models.Order) {
+	order.Table = nil
+	if order.TableID != nil {
+		if table, ok := t.DiningTables[*order.TableID]; ok {
+			order.Table = &table
+		}
+	}
+
+	items := memory.Select(t.OrderItems, func(item *models.OrderItem) bool { return item.OrderID == order.ID })
+	memory.Sort(items, func(a, b *models.OrderItem) int { return t.CompareInserted(a.ID, b.ID) })
+	order.Items = make([]models.OrderItem, len(items))
+	for i, item := range items {
+		preloadOrderItem(t, item)
+		order.Items[i] = *item
+	}
+}
+
+// preloadOrderItem fills in the menu item and the modifiers with their modifier
+func preloadOrderItem(t *memory.Tables, item *models.OrderItem) {
+	item.MenuItem = nil
+	if menuItem, ok := t.MenuItems[item.MenuItemID]; ok {
+		item.MenuItem = &menuItem
+	}
+
+	modifiers := memory.Select(t.OrderItemModifiers, func(m *models.OrderItemModifier) bool { return m.OrderItemID == item.ID })
+	for _, modifier := range modifiers {
+		if found, ok := t.Modifiers[modifier.ModifierID]; ok {
+			modifier.Modifier = &found
+		}
+	}
+	memory.Sort(modifiers, func(a, b *models.OrderItemModifier) int {
+		return memory.CompareString(a.Modifier.Name, b.Modifier.Name)
+	})
+	item.Modifiers = make([]models.OrderItemModifier, len(modifiers))
+	for i, modifier := range modifiers {
+		item.Modifiers[i] = *modifier
+	}
+}
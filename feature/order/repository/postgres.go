@@ -1,11 +1,20 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type orderRepository struct {
@@ -16,141 +25,319 @@ func NewOrderRepository(db *gorm.DB) domain.OrderRepository {
 	return &orderRepository{db: db}
 }
 
-func (r *orderRepository) GetAllOrders() ([]*models.Order, error) {
-	var orders []*models.Order
-	if err := r.db.Preload("Table").Preload("Items.MenuItem").Preload("Items.Modifiers.Modifier").Order("created_at DESC").Find(&orders).Error; err != nil {
-		return nil, errors.Wrap(err, "[OrderRepository.GetAllOrders]: Error querying database")
+// GetOrderSummaries sums each order's items and payments in subqueries, so a
+// page of summaries is one statement however many items the orders hold
+func (r *orderRepository) GetOrderSummaries(ctx context.Context, query *request.OrderListQuery, page domain.PageParams) (domain.Page[models.OrderSummary], error) {
+	db := database.Conn(ctx, r.db).Table("orders").
+		Select(`orders.id, orders.table_id, t.name AS table_name, orders.status, orders.source,
+			orders.opened_by, u.full_name AS opener_name, COALESCE(orders.total_baht, 0) AS total_baht,
+			orders.created_at, orders.business_date, orders.closed_at, orders.voided_at,
+			(SELECT COALESCE(SUM(oi.quantity), 0) FROM order_items oi
+				WHERE oi.order_id = orders.id AND oi.cancelled_at IS NULL) AS item_count,
+			(SELECT COALESCE(SUM(p.amount_baht), 0) FROM payments p
+				WHERE p.order_id = orders.id AND p.status = ?) AS paid_baht`, constant.PaymentStatusSucceeded).
+		Joins("LEFT JOIN dining_tables t ON t.id = orders.table_id").
+		Joins("LEFT JOIN users u ON u.id = orders.opened_by")
+	if query.Status != "" {
+		db = db.Where("orders.status = ?", query.Status)
+	}
+	if query.TableID != "" {
+		db = db.Where("orders.table_id = ?", query.TableID)
+	}
+	if query.AreaID != "" {
+		db = db.Where("t.area_id = ?", query.AreaID)
+	}
+	if query.Source != "" {
+		db = db.Where("orders.source = ?", query.Source)
 	}
-	return orders, nil
+	if query.OpenedBy != "" {
+		db = db.Where("orders.opened_by = ?", query.OpenedBy)
+	}
+	if query.From != nil {
+		db = db.Where("orders.created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("orders.created_at < ?", *query.To)
+	}
+
+	summaries, err := pagination.Find(db, page, orderSummaryKeys)
+	if err != nil {
+		return domain.Page[models.OrderSummary]{}, errors.Wrap(err, "[OrderRepository.GetOrderSummaries]")
+	}
+	return summaries, nil
 }
 
-func (r *orderRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
+func (r *orderRepository) GetItemsOfOrders(ctx context.Context, orderIDs []uuid.UUID) ([]*models.OrderItem, error) {
+	var items []*models.OrderItem
+	if len(orderIDs) == 0 {
+		return items, nil
+	}
+	if err := database.Conn(ctx, r.db).Preload("MenuItem").Preload("Modifiers.Modifier").Where("order_id IN ?", orderIDs).Find(&items).Error; err != nil {
+		return nil, errors.Wrap(err, "[OrderRepository.GetItemsOfOrders]: Error querying database")
+	}
+	return items, nil
+}
+
+func (r *orderRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	var order models.Order
-	if err := r.db.Where("id = ?", id).First(&order).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[OrderRepository.GetOrderByID]: Order not found")
+			return nil, errors.Wrap(domain.NotFoundError("Order not found"), "[OrderRepository.GetOrderByID]")
 		}
 		return nil, errors.Wrap(err, "[OrderRepository.GetOrderByID]: Error querying database")
 	}
 	return &order, nil
 }
 
-func (r *orderRepository) GetOrderWithItems(id uuid.UUID) (*models.Order, error) {
+func (r *orderRepository) GetOrderForUpdate(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	var order models.Order
-	if err := r.db.Preload("Table").Preload("Items.MenuItem").Preload("Items.Modifiers.Modifier").Where("id = ?", id).First(&order).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[OrderRepository.GetOrderWithItems]: Order not found")
+			return nil, errors.Wrap(domain.NotFoundError("Order not found"), "[OrderRepository.GetOrderForUpdate]")
 		}
-		return nil, errors.Wrap(err, "[OrderRepository.GetOrderWithItems]: Error querying database")
+		return nil, errors.Wrap(err, "[OrderRepository.GetOrderForUpdate]: Error querying database")
 	}
 	return &order, nil
 }
 
-func (r *orderRepository) GetOrdersByTable(tableID uuid.UUID) ([]*models.Order, error) {
-	var orders []*models.Order
-	if err := r.db.Preload("Table").Preload("Items.MenuItem").Preload("Items.Modifiers.Modifier").Where("table_id = ?", tableID).Order("created_at DESC").Find(&orders).Error; err != nil {
-		return nil, errors.Wrap(err, "[OrderRepository.GetOrdersByTable]: Error querying database")
-	}
-	return orders, nil
-}
-
-func (r *orderRepository) GetOrdersByStatus(status string) ([]*models.Order, error) {
-	var orders []*models.Order
-	if err := r.db.Preload("Table").Preload("Items.MenuItem").Preload("Items.Modifiers.Modifier").Where("status = ?", status).Order("created_at DESC").Find(&orders).Error; err != nil {
-		return nil, errors.Wrap(err, "[OrderRepository.GetOrdersByStatus]: Error querying database")
+func (r *orderRepository) GetOrderWithItems(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	if err := database.Conn(ctx, r.db).Preload("Table").Preload("Items.MenuItem").Preload("Items.Modifiers.Modifier").Where("id = ?", id).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Order not found"), "[OrderRepository.GetOrderWithItems]")
+		}
+		return nil, errors.Wrap(err, "[OrderRepository.GetOrderWithItems]: Error querying database")
 	}
-	return orders, nil
+	return &order, nil
 }
 
-func (r *orderRepository) CreateOrder(order *models.Order) error {
-	if err := r.db.Create(order).Error; err != nil {
+func (r *orderRepository) CreateOrder(ctx context.Context, order *models.Order) error {
+	if err := database.Conn(ctx, r.db).Create(order).Error; err != nil {
 		return errors.Wrap(err, "[OrderRepository.CreateOrder]: Error creating order")
 	}
 	return nil
 }
 
-func (r *orderRepository) UpdateOrder(order *models.Order) error {
-	if err := r.db.Save(order).Error; err != nil {
+func (r *orderRepository) UpdateOrder(ctx context.Context, order *models.Order) error {
+	if err := database.Conn(ctx, r.db).Save(order).Error; err != nil {
 		return errors.Wrap(err, "[OrderRepository.UpdateOrder]: Error updating order")
 	}
 	return nil
 }
 
-func (r *orderRepository) CreateOrderItem(item *models.OrderItem) error {
-	if err := r.db.Create(item).Error; err != nil {
+func (r *orderRepository) CreateOrderItem(ctx context.Context, item *models.OrderItem) error {
+	if err := database.Conn(ctx, r.db).Create(item).Error; err != nil {
 		return errors.Wrap(err, "[OrderRepository.CreateOrderItem]: Error creating order item")
 	}
 	return nil
 }
 
-func (r *orderRepository) UpdateOrderItem(item *models.OrderItem) error {
-	if err := r.db.Save(item).Error; err != nil {
+func (r *orderRepository) UpdateOrderItem(ctx context.Context, item *models.OrderItem) error {
+	if err := database.Conn(ctx, r.db).Save(item).Error; err != nil {
 		return errors.Wrap(err, "[OrderRepository.UpdateOrderItem]: Error updating order item")
 	}
 	return nil
 }
 
-func (r *orderRepository) DeleteOrderItem(id uuid.UUID) error {
-	if err := r.db.Where("id = ?", id).Delete(&models.OrderItem{}).Error; err != nil {
-		return errors.Wrap(err, "[OrderRepository.DeleteOrderItem]: Error deleting order item")
+func (r *orderRepository) MarkOrderItemsSent(ctx context.Context, orderID uuid.UUID, sentAt time.Time) error {
+	if err := database.Conn(ctx, r.db).Model(&models.OrderItem{}).Where("order_id = ? AND sent_at IS NULL AND cancelled_at IS NULL", orderID).Update("sent_at", sentAt).Error; err != nil {
+		return errors.Wrap(err, "[OrderRepository.MarkOrderItemsSent]: Error updating order items")
 	}
 	return nil
 }
 
-func (r *orderRepository) GetOrderItemByID(id uuid.UUID) (*models.OrderItem, error) {
+func (r *orderRepository) GetOrderItemByID(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
 	var orderItem models.OrderItem
-	if err := r.db.Preload("MenuItem").Preload("Modifiers.Modifier").Where("id = ?", id).First(&orderItem).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("MenuItem").Preload("Modifiers.Modifier").Where("id = ?", id).First(&orderItem).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[OrderRepository.GetOrderItemByID]: Order item not found")
+			return nil, errors.Wrap(domain.NotFoundError("Order item not found"), "[OrderRepository.GetOrderItemByID]")
 		}
 		return nil, errors.Wrap(err, "[OrderRepository.GetOrderItemByID]: Error querying database")
 	}
 	return &orderItem, nil
 }
 
-func (r *orderRepository) GetMenuItemByID(id uuid.UUID) (*models.MenuItem, error) {
+func (r *orderRepository) GetOrderItemForUpdate(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
+	var orderItem models.OrderItem
+	if err := database.Conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("MenuItem").Preload("Modifiers.Modifier").Where("id = ?", id).First(&orderItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Order item not found"), "[OrderRepository.GetOrderItemForUpdate]")
+		}
+		return nil, errors.Wrap(err, "[OrderRepository.GetOrderItemForUpdate]: Error querying database")
+	}
+	return &orderItem, nil
+}
+
+func (r *orderRepository) GetMenuItemByID(ctx context.Context, id uuid.UUID) (*models.MenuItem, error) {
 	var menuItem models.MenuItem
-	if err := r.db.Where("id = ?", id).First(&menuItem).Error; err != nil {
+	db := database.Conn(ctx, r.db).Preload("Category.Schedules.Exceptions").Preload("Schedules.Exceptions").Preload("Availability")
+	db = withModifierGroups(withModifierGroups(db, "Category.ModifierGroups"), "ModifierGroups")
+	if err := db.Where("id = ?", id).First(&menuItem).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[OrderRepository.GetMenuItemByID]: Menu item not found")
+			return nil, errors.Wrap(domain.NotFoundError("Menu item not found"), "[OrderRepository.GetMenuItemByID]")
 		}
 		return nil, errors.Wrap(err, "[OrderRepository.GetMenuItemByID]: Error querying database")
 	}
 	return &menuItem, nil
 }
 
-func (r *orderRepository) GetModifierByID(id uuid.UUID) (*models.Modifier, error) {
-	var modifier models.Modifier
-	if err := r.db.Where("id = ?", id).First(&modifier).Error; err != nil {
+// AdjustPortions changes the count in one conditional UPDATE, so two terminals
+// ordering the last portion cannot both get it
+func (r *orderRepository) AdjustPortions(ctx context.Context, menuItemID uuid.UUID, delta int) (bool, error) {
+	db := database.Conn(ctx, r.db)
+	result := db.Model(&models.MenuItemAvailability{}).
+		Where("menu_item_id = ? AND remaining_portions IS NOT NULL AND remaining_portions + ? >= 0", menuItemID, delta).
+		Updates(map[string]any{"remaining_portions": gorm.Expr("remaining_portions + ?", delta), "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "[OrderRepository.AdjustPortions]: Error updating portions")
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	var availability models.MenuItemAvailability
+	if err := db.Where("menu_item_id = ?", menuItemID).First(&availability).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[OrderRepository.GetModifierByID]: Modifier not found")
+			return false, nil
 		}
-		return nil, errors.Wrap(err, "[OrderRepository.GetModifierByID]: Error querying database")
+		return false, errors.Wrap(err, "[OrderRepository.AdjustPortions]: Error querying database")
+	}
+	if availability.RemainingPortions == nil {
+		return false, nil
 	}
-	return &modifier, nil
+	return true, errors.Wrap(notEnoughPortions(*availability.RemainingPortions), "[OrderRepository.AdjustPortions]")
 }
 
-func (r *orderRepository) CreateOrderItemModifier(modifier *models.OrderItemModifier) error {
-	if err := r.db.Create(modifier).Error; err != nil {
-		return errors.Wrap(err, "[OrderRepository.CreateOrderItemModifier]: Error creating order item modifier")
+func notEnoughPortions(remaining int) error {
+	if remaining == 0 {
+		return domain.PreconditionFailedError("Menu item is sold out")
 	}
-	return nil
+	return domain.PreconditionFailedError(fmt.Sprintf("Only %d portions left", remaining))
 }
 
-func (r *orderRepository) DeleteOrderItemModifiers(orderItemID uuid.UUID) error {
-	if err := r.db.Where("order_item_id = ?", orderItemID).Delete(&models.OrderItemModifier{}).Error; err != nil {
-		return errors.Wrap(err, "[OrderRepository.DeleteOrderItemModifiers]: Error deleting order item modifiers")
+func (r *orderRepository) CreateOrderItemModifier(ctx context.Context, modifier *models.OrderItemModifier) error {
+	if err := database.Conn(ctx, r.db).Create(modifier).Error; err != nil {
+		return errors.Wrap(err, "[OrderRepository.CreateOrderItemModifier]: Error creating order item modifier")
 	}
 	return nil
 }
 
-func (r *orderRepository) GetTableByID(id uuid.UUID) (*models.DiningTable, error) {
+func (r *orderRepository) GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error) {
 	var table models.DiningTable
-	if err := r.db.Where("id = ?", id).First(&table).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&table).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[OrderRepository.GetTableByID]: Table not found")
+			return nil, errors.Wrap(domain.NotFoundError("Table not found"), "[OrderRepository.GetTableByID]")
 		}
 		return nil, errors.Wrap(err, "[OrderRepository.GetTableByID]: Error querying database")
 	}
 	return &table, nil
 }
+
+func (r *orderRepository) GetVoidReasonByCode(ctx context.Context, code string) (*models.VoidReason, error) {
+	var reason models.VoidReason
+	if err := database.Conn(ctx, r.db).Where("code = ?", code).First(&reason).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Void reason not found"), "[OrderRepository.GetVoidReasonByCode]")
+		}
+		return nil, errors.Wrap(err, "[OrderRepository.GetVoidReasonByCode]: Error querying database")
+	}
+	return &reason, nil
+}
+
+// GetVoidTotals sums voided orders and cancelled items in [from, to) by reason code
+// and acting user. Items cancelled before their order was voided are counted once,
+// as the voided total no longer includes them.
+func (r *orderRepository) GetVoidTotals(ctx context.Context, from time.Time, to time.Time) ([]*models.VoidTotal, error) {
+	var totals []*models.VoidTotal
+	query := `
+		SELECT v.reason_code, vr.label AS reason_label, v.actor_id, u.full_name AS actor_name,
+			COUNT(*) AS count, COALESCE(SUM(v.value_baht), 0) AS value_baht
+		FROM (
+			SELECT void_reason AS reason_code, voided_by AS actor_id, COALESCE(total_baht, 0) AS value_baht
+			FROM orders
+			WHERE status = ? AND voided_at >= ? AND voided_at < ?
+			UNION ALL
+			SELECT oi.cancel_reason, oi.cancelled_by, oi.line_total_baht
+			FROM order_items oi
+			WHERE oi.cancelled_at >= ? AND oi.cancelled_at < ?
+		) v
+		LEFT JOIN void_reasons vr ON vr.code = v.reason_code
+		LEFT JOIN users u ON u.id = v.actor_id
+		GROUP BY v.reason_code, vr.label, v.actor_id, u.full_name
+		ORDER BY value_baht DESC`
+	if err := database.Conn(ctx, r.db).Raw(query, constant.OrderStatusVoid, from, to, from, to).Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, "[OrderRepository.GetVoidTotals]: Error querying database")
+	}
+	return totals, nil
+}
+
+// GetVoids lists voided orders and cancelled items together. Like the void
+// report it takes each at its own void time, so an item cancelled before its
+// order was voided is listed on its own.
+func (r *orderRepository) GetVoids(ctx context.Context, filter *request.VoidFilter, page domain.PageParams) (domain.Page[models.Void], error) {
+	voids := `(
+		SELECT CAST(? AS varchar) AS kind, o.id, o.id AS order_id, o.table_id, NULL AS menu_item_id, NULL AS quantity,
+			COALESCE(o.total_baht, 0) AS value_baht, o.void_reason AS reason_code, o.voided_by,
+			o.voided_at, o.business_date
+		FROM orders o
+		WHERE o.status = ? AND o.voided_at IS NOT NULL
+		UNION ALL
+		SELECT CAST(? AS varchar), oi.id, oi.order_id, o.table_id, oi.menu_item_id, oi.quantity,
+			oi.line_total_baht, oi.cancel_reason, oi.cancelled_by,
+			oi.cancelled_at, o.business_date
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		WHERE oi.cancelled_at IS NOT NULL
+	) AS voids`
+	db := database.Conn(ctx, r.db).Table(voids, constant.VoidKindOrder, constant.OrderStatusVoid, constant.VoidKindItem).
+		Select(`voids.id, voids.kind, voids.order_id, t.name AS table_name, mi.name AS menu_item_name,
+			voids.quantity, voids.value_baht, voids.reason_code, vr.label AS reason_label,
+			voids.voided_by, u.full_name AS voider_name, voids.voided_at, voids.business_date`).
+		Joins("LEFT JOIN dining_tables t ON t.id = voids.table_id").
+		Joins("LEFT JOIN menu_items mi ON mi.id = voids.menu_item_id").
+		Joins("LEFT JOIN void_reasons vr ON vr.code = voids.reason_code").
+		Joins("LEFT JOIN users u ON u.id = voids.voided_by")
+	if filter.Kind != "" {
+		db = db.Where("voids.kind = ?", filter.Kind)
+	}
+	if filter.ReasonCode != "" {
+		db = db.Where("voids.reason_code = ?", filter.ReasonCode)
+	}
+	if filter.VoidedBy != "" {
+		db = db.Where("voids.voided_by = ?", filter.VoidedBy)
+	}
+	if filter.From != nil {
+		db = db.Where("voids.voided_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("voids.voided_at < ?", *filter.To)
+	}
+
+	rows, err := pagination.Find(db, page, voidKeys)
+	if err != nil {
+		return domain.Page[models.Void]{}, errors.Wrap(err, "[OrderRepository.GetVoids]")
+	}
+	return rows, nil
+}
+
+func (r *orderRepository) CountOpenOrdersByArea(ctx context.Context) ([]*models.AreaOrderCount, error) {
+	var counts []*models.AreaOrderCount
+	query := `
+		SELECT a.name AS area_name, COUNT(*) AS count
+		FROM orders o
+		LEFT JOIN dining_tables t ON t.id = o.table_id
+		LEFT JOIN areas a ON a.id = t.area_id
+		WHERE o.status = ?
+		GROUP BY a.name`
+	if err := database.Conn(ctx, r.db).Raw(query, constant.OrderStatusOpen).Scan(&counts).Error; err != nil {
+		return nil, errors.Wrap(err, "[OrderRepository.CountOpenOrdersByArea]: Error querying database")
+	}
+	return counts, nil
+}
+
+// withModifierGroups preloads the groups behind links, with their options in
+// order
+func withModifierGroups(db *gorm.DB, links string) *gorm.DB {
+	return db.Preload(links+".ModifierGroup.Options", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		Preload(links + ".ModifierGroup.Options.Modifier")
+}
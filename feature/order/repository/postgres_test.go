@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/database/dbtest"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/feature/order/repository"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+func TestGetOrderForUpdate(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := repository.NewOrderRepository(db)
+		order := &models.Order{Status: utils.Ptr(constant.OrderStatusOpen), DiscountBaht: utils.Ptr(int64(0))}
+		if err := repo.CreateOrder(ctx, order); err != nil {
+			t.Fatal(err)
+		}
+
+		// SQLite has no FOR UPDATE; the clause must be dropped, not rejected
+		err := database.NewTransactor(db).WithinTransaction(ctx, func(ctx context.Context) error {
+			locked, err := repo.GetOrderForUpdate(ctx, order.ID)
+			if err != nil {
+				return err
+			}
+			locked.DiscountBaht = utils.Ptr(int64(10))
+			return repo.UpdateOrder(ctx, locked)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stored, err := repo.GetOrderByID(ctx, order.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if utils.DerefInt64(stored.DiscountBaht) != 10 {
+			t.Errorf("discount = %d, want 10", utils.DerefInt64(stored.DiscountBaht))
+		}
+
+		if _, err := repo.GetOrderForUpdate(ctx, uuid.New()); domain.ErrorKindOf(err) != domain.ErrorKindNotFound {
+			t.Errorf("missing order: %v, want not found", err)
+		}
+	})
+}
+
+func TestAdjustPortions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := repository.NewOrderRepository(db)
+
+		counted := &models.MenuItem{Name: utils.Ptr("ข้าวมันไก่")}
+		uncounted := &models.MenuItem{Name: utils.Ptr("ชาเย็น")}
+		for _, menuItem := range []*models.MenuItem{counted, uncounted} {
+			if err := db.Create(menuItem).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		availability := &models.MenuItemAvailability{MenuItemID: counted.ID, Status: constant.MenuItemAvailable, RemainingPortions: utils.Ptr(2)}
+		if err := db.Create(availability).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if ok, err := repo.AdjustPortions(ctx, uncounted.ID, -5); ok || err != nil {
+			t.Errorf("uncounted item: %t, %v, want false, nil", ok, err)
+		}
+		if ok, err := repo.AdjustPortions(ctx, counted.ID, -2); !ok || err != nil {
+			t.Fatalf("taking the last portions: %t, %v", ok, err)
+		}
+		if _, err := repo.AdjustPortions(ctx, counted.ID, -1); domain.ErrorKindOf(err) != domain.ErrorKindPreconditionFailed {
+			t.Errorf("sold out item: %v, want precondition failed", err)
+		}
+		if _, err := repo.AdjustPortions(ctx, counted.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		var stored models.MenuItemAvailability
+		if err := db.First(&stored, "menu_item_id = ?", counted.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if utils.DerefInt(stored.RemainingPortions) != 1 {
+			t.Errorf("remaining = %d, want 1", utils.DerefInt(stored.RemainingPortions))
+		}
+	})
+}
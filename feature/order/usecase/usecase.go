@@ -1,37 +1,55 @@
 package usecase
 
 import (
+	"context"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
 	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
 type orderUsecase struct {
-	orderRepository domain.OrderRepository
+	orderRepository  domain.OrderRepository
+	overrideUsecase  domain.OverrideUsecase
+	transactor       domain.Transactor
+	auditUsecase     domain.AuditUsecase
+	metrics          domain.Metrics
+	calendar         businessday.Calendar
+	availabilityFeed domain.AvailabilityFeed
 }
 
-func NewOrderUsecase(orderRepository domain.OrderRepository) domain.OrderUsecase {
-	return &orderUsecase{orderRepository: orderRepository}
+// NewOrderUsecase publishes to availabilityFeed the menu items whose counted
+// portions an order takes or gives back
+func NewOrderUsecase(orderRepository domain.OrderRepository, overrideUsecase domain.OverrideUsecase, transactor domain.Transactor, auditUsecase domain.AuditUsecase, metrics domain.Metrics, calendar businessday.Calendar, availabilityFeed domain.AvailabilityFeed) domain.OrderUsecase {
+	return &orderUsecase{orderRepository: orderRepository, overrideUsecase: overrideUsecase, transactor: transactor, auditUsecase: auditUsecase, metrics: metrics, calendar: calendar, availabilityFeed: availabilityFeed}
 }
 
-func (u *orderUsecase) GetAllOrders() ([]*response.OrderResponse, error) {
-	orders, err := u.orderRepository.GetAllOrders()
+func (u *orderUsecase) GetAllOrders(ctx context.Context, query *request.OrderListQuery) (*response.Page[*response.OrderSummaryResponse], error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.GetAllOrders")
+	defer span.End()
+
+	orders, err := u.getOrders(ctx, query)
 	if err != nil {
 		return nil, errors.Wrap(err, "[OrderUsecase.GetAllOrders]: Error getting orders")
 	}
-
-	return u.buildOrderResponses(orders), nil
+	return orders, nil
 }
 
-func (u *orderUsecase) GetOrderByID(id uuid.UUID) (*response.OrderResponse, error) {
-	order, err := u.orderRepository.GetOrderWithItems(id)
+func (u *orderUsecase) GetOrderByID(ctx context.Context, id uuid.UUID) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.GetOrderByID", tracing.OrderID(id))
+	defer span.End()
+
+	order, err := u.orderRepository.GetOrderWithItems(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[OrderUsecase.GetOrderByID]: Error getting order")
 	}
@@ -39,32 +57,87 @@ func (u *orderUsecase) GetOrderByID(id uuid.UUID) (*response.OrderResponse, erro
 	return u.buildOrderResponse(order), nil
 }
 
-func (u *orderUsecase) GetOrdersByTable(tableID uuid.UUID) ([]*response.OrderResponse, error) {
-	orders, err := u.orderRepository.GetOrdersByTable(tableID)
+// GetOrdersByTable lists the table's orders; a table_id in the query is ignored
+func (u *orderUsecase) GetOrdersByTable(ctx context.Context, tableID uuid.UUID, query *request.OrderListQuery) (*response.Page[*response.OrderSummaryResponse], error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.GetOrdersByTable", tracing.TableID(tableID))
+	defer span.End()
+
+	byTable := *query
+	byTable.TableID = tableID.String()
+	orders, err := u.getOrders(ctx, &byTable)
 	if err != nil {
 		return nil, errors.Wrap(err, "[OrderUsecase.GetOrdersByTable]: Error getting orders")
 	}
-
-	return u.buildOrderResponses(orders), nil
+	return orders, nil
 }
 
-func (u *orderUsecase) GetOpenOrders() ([]*response.OrderResponse, error) {
-	orders, err := u.orderRepository.GetOrdersByStatus(constant.OrderStatusOpen)
+// GetOpenOrders lists open orders; a status in the query is ignored
+func (u *orderUsecase) GetOpenOrders(ctx context.Context, query *request.OrderListQuery) (*response.Page[*response.OrderSummaryResponse], error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.GetOpenOrders")
+	defer span.End()
+
+	open := *query
+	open.Status = constant.OrderStatusOpen
+	orders, err := u.getOrders(ctx, &open)
 	if err != nil {
 		return nil, errors.Wrap(err, "[OrderUsecase.GetOpenOrders]: Error getting open orders")
 	}
+	return orders, nil
+}
+
+// getOrders loads one page of order summaries, newest first unless the query
+// sorts otherwise, and the items of those orders when the query expands them
+func (u *orderUsecase) getOrders(ctx context.Context, query *request.OrderListQuery) (*response.Page[*response.OrderSummaryResponse], error) {
+	page, err := pagination.Parse(query.PageQuery, query.Sort, "-created_at")
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := u.orderRepository.GetOrderSummaries(ctx, query, page)
+	if err != nil {
+		return nil, err
+	}
 
-	return u.buildOrderResponses(orders), nil
+	now := time.Now()
+	responses := make([]*response.OrderSummaryResponse, len(summaries.Items))
+	for i, summary := range summaries.Items {
+		responses[i] = u.buildOrderSummaryResponse(summary, now)
+	}
+
+	if query.Expand == constant.OrderExpandItems {
+		orderIDs := make([]uuid.UUID, len(summaries.Items))
+		for i, summary := range summaries.Items {
+			orderIDs[i] = summary.ID
+		}
+		items, err := u.orderRepository.GetItemsOfOrders(ctx, orderIDs)
+		if err != nil {
+			return nil, err
+		}
+		itemsByOrder := map[uuid.UUID][]response.OrderItemResponse{}
+		for _, item := range items {
+			itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], u.buildOrderItemResponse(item))
+		}
+		for _, summary := range responses {
+			summary.Items = itemsByOrder[summary.ID]
+		}
+	}
+
+	return &response.Page[*response.OrderSummaryResponse]{Items: responses, NextCursor: summaries.NextCursor}, nil
 }
 
-func (u *orderUsecase) CreateOrder(req *request.OrderCreateRequest) (*response.OrderResponse, error) {
+func (u *orderUsecase) CreateOrder(ctx context.Context, req *request.OrderCreateRequest) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.CreateOrder", tracing.TableID(req.TableID))
+	defer span.End()
+
 	// Validate table exists
-	_, err := u.orderRepository.GetTableByID(req.TableID)
+	_, err := u.orderRepository.GetTableByID(ctx, req.TableID)
 	if err != nil {
 		return nil, errors.Wrap(err, "[OrderUsecase.CreateOrder]: Invalid table ID")
 	}
 
+	now := time.Now()
 	order := &models.Order{
+		CreatedAt:    now,
+		BusinessDate: utils.Ptr(u.calendar.Date(now)),
 		TableID:      &req.TableID,
 		OpenedBy:     &req.OpenedBy,
 		Source:       &req.Source,
@@ -75,33 +148,32 @@ func (u *orderUsecase) CreateOrder(req *request.OrderCreateRequest) (*response.O
 		Note:         req.Note,
 	}
 
-	if err := u.orderRepository.CreateOrder(order); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.CreateOrder]: Error creating order")
-	}
+	var orderResponse *response.OrderResponse
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.orderRepository.CreateOrder(ctx, order); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CreateOrder]: Error creating order")
+		}
 
-	// Get order with items
-	orderWithItems, err := u.orderRepository.GetOrderWithItems(order.ID)
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionCreate, order.ID, nil)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CreateOrder]: Error recording audit log")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.CreateOrder]: Error retrieving created order")
+		return nil, err
 	}
+	u.metrics.OrderOpened(req.Source)
 
-	return u.buildOrderResponse(orderWithItems), nil
+	return orderResponse, nil
 }
 
-func (u *orderUsecase) AddItemToOrder(orderID uuid.UUID, req *request.AddOrderItemRequest) (*response.OrderResponse, error) {
-	// Get order
-	order, err := u.orderRepository.GetOrderByID(orderID)
-	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Order not found")
-	}
-
-	// Check if order is open
-	if *order.Status != constant.OrderStatusOpen {
-		return nil, errors.New("[OrderUsecase.AddItemToOrder]: Cannot add items to closed order")
-	}
+func (u *orderUsecase) AddItemToOrder(ctx context.Context, orderID uuid.UUID, req *request.AddOrderItemRequest) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.AddItemToOrder", tracing.OrderID(orderID))
+	defer span.End()
 
 	// Get menu item
-	menuItem, err := u.orderRepository.GetMenuItemByID(req.MenuItemID)
+	menuItem, err := u.orderRepository.GetMenuItemByID(ctx, req.MenuItemID)
 	if err != nil {
 		return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Menu item not found")
 	}
@@ -109,13 +181,13 @@ func (u *orderUsecase) AddItemToOrder(orderID uuid.UUID, req *request.AddOrderIt
 	// Calculate unit price (base price)
 	unitPrice := utils.DerefInt64(menuItem.PriceBaht)
 
-	// Calculate modifier total
+	// Check the modifiers against the item's groups, then total them
+	modifiers, err := domain.SelectModifiers(domain.MenuItemModifierGroups(menuItem), req.ModifierIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]")
+	}
 	modifierTotal := int64(0)
-	for _, modifierID := range req.ModifierIDs {
-		modifier, err := u.orderRepository.GetModifierByID(modifierID)
-		if err != nil {
-			return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Modifier not found")
-		}
+	for _, modifier := range modifiers {
 		modifierTotal += utils.DerefInt64(modifier.PriceDeltaBaht)
 	}
 
@@ -132,218 +204,713 @@ func (u *orderUsecase) AddItemToOrder(orderID uuid.UUID, req *request.AddOrderIt
 		Note:          req.Note,
 	}
 
-	if err := u.orderRepository.CreateOrderItem(orderItem); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error creating order item")
-	}
+	var orderResponse *response.OrderResponse
+	var counted bool
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Read under the lock so the order cannot be closed or voided meanwhile
+		order, err := u.lockOrder(ctx, orderID)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Order not found")
+		}
+
+		// Check if order is open
+		if *order.Status != constant.OrderStatusOpen {
+			return errors.Wrap(domain.PreconditionFailedError("Cannot add items to closed order"), "[OrderUsecase.AddItemToOrder]")
+		}
+		before := u.buildOrderResponse(order)
+		if err := u.orderable(menuItem, order); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.AddItemToOrder]")
+		}
 
-	// Add modifiers
-	for _, modifierID := range req.ModifierIDs {
-		modifier, _ := u.orderRepository.GetModifierByID(modifierID)
-		orderItemModifier := &models.OrderItemModifier{
-			OrderItemID:    orderItem.ID,
-			ModifierID:     modifierID,
-			PriceDeltaBaht: modifier.PriceDeltaBaht,
+		if counted, err = u.orderRepository.AdjustPortions(ctx, req.MenuItemID, -req.Quantity); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Not enough portions")
 		}
-		if err := u.orderRepository.CreateOrderItemModifier(orderItemModifier); err != nil {
-			return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error adding modifier")
+
+		if err := u.orderRepository.CreateOrderItem(ctx, orderItem); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error creating order item")
 		}
+
+		// Add modifiers
+		for _, modifier := range modifiers {
+			orderItemModifier := &models.OrderItemModifier{
+				OrderItemID:    orderItem.ID,
+				ModifierID:     modifier.ID,
+				PriceDeltaBaht: modifier.PriceDeltaBaht,
+			}
+			if err := u.orderRepository.CreateOrderItemModifier(ctx, orderItemModifier); err != nil {
+				return errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error adding modifier")
+			}
+		}
+
+		// Recalculate order total
+		if err := u.recalculateOrderTotal(ctx, orderID); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error recalculating total")
+		}
+
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionAddItem, orderID, before)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	if counted {
+		u.availabilityFeed.Publish(req.MenuItemID)
+	}
+
+	return orderResponse, nil
+}
+
+// CancelOrderItem marks an item cancelled with a reason code instead of deleting it,
+// so cancelled food stays visible in the void report.
+func (u *orderUsecase) CancelOrderItem(ctx context.Context, orderID uuid.UUID, itemID uuid.UUID, req *request.CancelOrderItemRequest, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.CancelOrderItem", tracing.OrderID(orderID))
+	defer span.End()
+
+	if err := u.validateVoidReason(ctx, req.ReasonCode); err != nil {
+		return nil, errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Invalid reason")
+	}
+
+	var orderItem *models.OrderItem
+	var orderResponse *response.OrderResponse
+	var counted bool
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Read under the lock so the order cannot be closed or voided meanwhile
+		order, err := u.lockOrder(ctx, orderID)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Order not found")
+		}
+
+		// Check if order is open
+		if *order.Status != constant.OrderStatusOpen {
+			return errors.Wrap(domain.PreconditionFailedError("Cannot cancel items of closed order"), "[OrderUsecase.CancelOrderItem]")
+		}
+		before := u.buildOrderResponse(order)
+
+		// Get order item, locked so that a concurrent request works from the
+		// quantity and state this one leaves
+		orderItem, err = u.orderRepository.GetOrderItemForUpdate(ctx, itemID)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Order item not found")
+		}
+
+		// Verify item belongs to order
+		if orderItem.OrderID != orderID {
+			return errors.Wrap(domain.NotFoundError("Order item does not belong to this order"), "[OrderUsecase.CancelOrderItem]")
+		}
+		if orderItem.CancelledAt != nil {
+			return errors.Wrap(domain.PreconditionFailedError("Order item is already cancelled"), "[OrderUsecase.CancelOrderItem]")
+		}
+
+		// Items already sent to the kitchen need a manager's approval, and
+		// their portions were used; those of unsent items are given back
+		if orderItem.SentAt != nil {
+			if _, err := u.overrideUsecase.ConsumeOverride(ctx, overrideToken, constant.OverrideActionRemoveItem, orderID, &itemID, actorID); err != nil {
+				return errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Override required")
+			}
+		} else if counted, err = u.orderRepository.AdjustPortions(ctx, orderItem.MenuItemID, orderItem.Quantity); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Error returning portions")
+		}
+
+		now := time.Now()
+		orderItem.CancelledAt = &now
+		orderItem.CancelledBy = &actorID
+		orderItem.CancelReason = &req.ReasonCode
+		if err := u.orderRepository.UpdateOrderItem(ctx, orderItem); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Error cancelling order item")
+		}
 
-	// Recalculate order total
-	if err := u.recalculateOrderTotal(orderID); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error recalculating total")
+		// Recalculate order total
+		if err := u.recalculateOrderTotal(ctx, orderID); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Error recalculating total")
+		}
+
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionCancelItem, orderID, before)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CancelOrderItem]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	if counted {
+		u.availabilityFeed.Publish(orderItem.MenuItemID)
+	}
+	u.metrics.Voided(constant.VoidKindItem, req.ReasonCode)
+
+	return orderResponse, nil
+}
+
+func (u *orderUsecase) UpdateOrderItemQuantity(ctx context.Context, orderID uuid.UUID, itemID uuid.UUID, quantity int, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.UpdateOrderItemQuantity", tracing.OrderID(orderID))
+	defer span.End()
+
+	var orderItem *models.OrderItem
+	var orderResponse *response.OrderResponse
+	var counted bool
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Read under the lock so the order cannot be closed or voided meanwhile
+		order, err := u.lockOrder(ctx, orderID)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Order not found")
+		}
+
+		// Check if order is open
+		if *order.Status != constant.OrderStatusOpen {
+			return errors.Wrap(domain.PreconditionFailedError("Cannot update items in closed order"), "[OrderUsecase.UpdateOrderItemQuantity]")
+		}
+		before := u.buildOrderResponse(order)
+
+		// Get order item with modifiers, locked so that a concurrent request works from the
+		// quantity and state this one leaves
+		orderItem, err = u.orderRepository.GetOrderItemForUpdate(ctx, itemID)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Order item not found")
+		}
+
+		// Verify item belongs to order
+		if orderItem.OrderID != orderID {
+			return errors.Wrap(domain.NotFoundError("Order item does not belong to this order"), "[OrderUsecase.UpdateOrderItemQuantity]")
+		}
+		if orderItem.CancelledAt != nil {
+			return errors.Wrap(domain.PreconditionFailedError("Cannot update cancelled item"), "[OrderUsecase.UpdateOrderItemQuantity]")
+		}
+
+		// Raising the quantity orders more of the item, which must still be on offer
+		if quantity > orderItem.Quantity {
+			menuItem, err := u.orderRepository.GetMenuItemByID(ctx, orderItem.MenuItemID)
+			if err != nil {
+				return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Menu item not found")
+			}
+			if err := u.orderable(menuItem, order); err != nil {
+				return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]")
+			}
+		}
 
-	// Get updated order
-	updatedOrder, err := u.orderRepository.GetOrderWithItems(orderID)
+		// Lowering the quantity of a sent item removes food the kitchen already
+		// made, so its portions are not given back
+		if orderItem.SentAt != nil && quantity < orderItem.Quantity {
+			if _, err := u.overrideUsecase.ConsumeOverride(ctx, overrideToken, constant.OverrideActionRemoveItem, orderID, &itemID, actorID); err != nil {
+				return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Override required")
+			}
+		} else if quantity != orderItem.Quantity {
+			if counted, err = u.orderRepository.AdjustPortions(ctx, orderItem.MenuItemID, orderItem.Quantity-quantity); err != nil {
+				return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Not enough portions")
+			}
+		}
+
+		// Calculate modifier total
+		modifierTotal := int64(0)
+		for _, mod := range orderItem.Modifiers {
+			modifierTotal += utils.DerefInt64(mod.PriceDeltaBaht)
+		}
+
+		// Update quantity and line total
+		orderItem.Quantity = quantity
+		orderItem.LineTotalBaht = (orderItem.UnitPriceBaht + modifierTotal) * int64(quantity)
+
+		if err := u.orderRepository.UpdateOrderItem(ctx, orderItem); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Error updating order item")
+		}
+
+		// Recalculate order total
+		if err := u.recalculateOrderTotal(ctx, orderID); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Error recalculating total")
+		}
+
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionUpdateQuantity, orderID, before)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Error recording audit log")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.AddItemToOrder]: Error retrieving updated order")
+		return nil, err
+	}
+	if counted {
+		u.availabilityFeed.Publish(orderItem.MenuItemID)
 	}
 
-	return u.buildOrderResponse(updatedOrder), nil
+	return orderResponse, nil
 }
 
-func (u *orderUsecase) RemoveItemFromOrder(orderID uuid.UUID, itemID uuid.UUID) (*response.OrderResponse, error) {
-	// Get order
-	order, err := u.orderRepository.GetOrderByID(orderID)
+func (u *orderUsecase) SendOrderToKitchen(ctx context.Context, id uuid.UUID) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.SendOrderToKitchen", tracing.OrderID(id))
+	defer span.End()
+
+	order, err := u.orderRepository.GetOrderWithItems(ctx, id)
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.RemoveItemFromOrder]: Order not found")
+		return nil, errors.Wrap(err, "[OrderUsecase.SendOrderToKitchen]: Order not found")
 	}
 
 	// Check if order is open
 	if *order.Status != constant.OrderStatusOpen {
-		return nil, errors.New("[OrderUsecase.RemoveItemFromOrder]: Cannot remove items from closed order")
+		return nil, errors.Wrap(domain.PreconditionFailedError("Cannot send items of closed order"), "[OrderUsecase.SendOrderToKitchen]")
 	}
+	before := u.buildOrderResponse(order)
 
-	// Get order item
-	orderItem, err := u.orderRepository.GetOrderItemByID(itemID)
+	var orderResponse *response.OrderResponse
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.orderRepository.MarkOrderItemsSent(ctx, id, time.Now()); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.SendOrderToKitchen]: Error sending items")
+		}
+
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionSend, id, before)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.SendOrderToKitchen]: Error recording audit log")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.RemoveItemFromOrder]: Order item not found")
+		return nil, err
 	}
 
-	// Verify item belongs to order
-	if orderItem.OrderID != orderID {
-		return nil, errors.New("[OrderUsecase.RemoveItemFromOrder]: Order item does not belong to this order")
-	}
+	return orderResponse, nil
+}
 
-	// Delete modifiers first
-	if err := u.orderRepository.DeleteOrderItemModifiers(itemID); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.RemoveItemFromOrder]: Error deleting modifiers")
-	}
+func (u *orderUsecase) ApplyDiscount(ctx context.Context, id uuid.UUID, req *request.ApplyDiscountRequest, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.ApplyDiscount", tracing.OrderID(id))
+	defer span.End()
 
-	// Delete order item
-	if err := u.orderRepository.DeleteOrderItem(itemID); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.RemoveItemFromOrder]: Error deleting order item")
-	}
+	var orderResponse *response.OrderResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Read under the lock so an item added meanwhile is not overwritten
+		order, err := u.orderRepository.GetOrderForUpdate(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ApplyDiscount]: Order not found")
+		}
 
-	// Recalculate order total
-	if err := u.recalculateOrderTotal(orderID); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.RemoveItemFromOrder]: Error recalculating total")
-	}
+		// Check if order is open
+		if *order.Status != constant.OrderStatusOpen {
+			return errors.Wrap(domain.PreconditionFailedError("Cannot discount closed order"), "[OrderUsecase.ApplyDiscount]")
+		}
+
+		if *req.DiscountBaht > utils.DerefInt64(order.SubtotalBaht) {
+			return errors.Wrap(domain.ValidationError("Discount exceeds order subtotal", map[string]string{"discount_baht": "exceeds subtotal"}), "[OrderUsecase.ApplyDiscount]")
+		}
+
+		orderWithItems, err := u.orderRepository.GetOrderWithItems(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ApplyDiscount]: Order not found")
+		}
+		before := u.buildOrderResponse(orderWithItems)
+
+		if _, err := u.overrideUsecase.ConsumeOverride(ctx, overrideToken, constant.OverrideActionDiscount, id, nil, actorID); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ApplyDiscount]: Override required")
+		}
+
+		order.DiscountBaht = req.DiscountBaht
+		if err := u.orderRepository.UpdateOrder(ctx, order); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ApplyDiscount]: Error updating order")
+		}
+
+		// Recalculate order total
+		if err := u.recalculateOrderTotal(ctx, id); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ApplyDiscount]: Error recalculating total")
+		}
 
-	// Get updated order
-	updatedOrder, err := u.orderRepository.GetOrderWithItems(orderID)
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionDiscount, id, before)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ApplyDiscount]: Error recording audit log")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.RemoveItemFromOrder]: Error retrieving updated order")
+		return nil, err
 	}
 
-	return u.buildOrderResponse(updatedOrder), nil
+	return orderResponse, nil
 }
 
-func (u *orderUsecase) UpdateOrderItemQuantity(orderID uuid.UUID, itemID uuid.UUID, quantity int) (*response.OrderResponse, error) {
-	// Get order
-	order, err := u.orderRepository.GetOrderByID(orderID)
+func (u *orderUsecase) CloseOrder(ctx context.Context, id uuid.UUID) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.CloseOrder", tracing.OrderID(id))
+	defer span.End()
+
+	var orderResponse *response.OrderResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Read under the lock so an item added meanwhile is not overwritten
+		order, err := u.orderRepository.GetOrderForUpdate(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CloseOrder]: Order not found")
+		}
+
+		// Check if order is already closed
+		if *order.Status != constant.OrderStatusOpen {
+			return errors.Wrap(domain.PreconditionFailedError("Order is already closed"), "[OrderUsecase.CloseOrder]")
+		}
+
+		orderWithItems, err := u.orderRepository.GetOrderWithItems(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CloseOrder]: Order not found")
+		}
+		before := u.buildOrderResponse(orderWithItems)
+
+		// Update order status
+		now := time.Now()
+		order.Status = utils.Ptr(constant.OrderStatusPaid)
+		order.ClosedAt = &now
+		if err := u.orderRepository.UpdateOrder(ctx, order); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CloseOrder]: Error closing order")
+		}
+
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionClose, id, before)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.CloseOrder]: Error recording audit log")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Order not found")
+		return nil, err
 	}
+	u.metrics.OrderClosed(constant.OrderStatusPaid)
 
-	// Check if order is open
-	if *order.Status != constant.OrderStatusOpen {
-		return nil, errors.New("[OrderUsecase.UpdateOrderItemQuantity]: Cannot update items in closed order")
-	}
+	return orderResponse, nil
+}
+
+func (u *orderUsecase) ReopenOrder(ctx context.Context, id uuid.UUID, actorID uuid.UUID, overrideToken string) (*response.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.ReopenOrder", tracing.OrderID(id))
+	defer span.End()
+
+	var orderResponse *response.OrderResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Read under the lock so the order cannot be reopened twice
+		order, err := u.orderRepository.GetOrderForUpdate(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ReopenOrder]: Order not found")
+		}
+
+		// Only closed checks can be reopened
+		if *order.Status != constant.OrderStatusPaid {
+			return errors.Wrap(domain.PreconditionFailedError("Only closed orders can be reopened"), "[OrderUsecase.ReopenOrder]")
+		}
 
-	// Get order item with modifiers
-	orderItem, err := u.orderRepository.GetOrderItemByID(itemID)
+		orderWithItems, err := u.orderRepository.GetOrderWithItems(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ReopenOrder]: Order not found")
+		}
+		before := u.buildOrderResponse(orderWithItems)
+
+		if _, err := u.overrideUsecase.ConsumeOverride(ctx, overrideToken, constant.OverrideActionReopen, id, nil, actorID); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ReopenOrder]: Override required")
+		}
+
+		order.Status = utils.Ptr(constant.OrderStatusOpen)
+		order.ClosedAt = nil
+		if err := u.orderRepository.UpdateOrder(ctx, order); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ReopenOrder]: Error reopening order")
+		}
+
+		orderResponse, err = u.auditOrderChange(ctx, constant.AuditActionReopen, id, before)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.ReopenOrder]: Error recording audit log")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Order item not found")
+		return nil, err
 	}
+	u.metrics.OrderReopened()
 
-	// Verify item belongs to order
-	if orderItem.OrderID != orderID {
-		return nil, errors.New("[OrderUsecase.UpdateOrderItemQuantity]: Order item does not belong to this order")
-	}
+	return orderResponse, nil
+}
 
-	// Calculate modifier total
-	modifierTotal := int64(0)
-	for _, mod := range orderItem.Modifiers {
-		modifierTotal += utils.DerefInt64(mod.PriceDeltaBaht)
+func (u *orderUsecase) VoidOrder(ctx context.Context, id uuid.UUID, req *request.VoidOrderRequest, actorID uuid.UUID, overrideToken string) error {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.VoidOrder", tracing.OrderID(id))
+	defer span.End()
+
+	if err := u.validateVoidReason(ctx, req.ReasonCode); err != nil {
+		return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Invalid reason")
 	}
 
-	// Update quantity and line total
-	orderItem.Quantity = quantity
-	orderItem.LineTotalBaht = (orderItem.UnitPriceBaht + modifierTotal) * int64(quantity)
+	var returned []uuid.UUID
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Read under the lock so the portions given back are those of the
+		// items on the order when it is voided
+		order, err := u.orderRepository.GetOrderForUpdate(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Order not found")
+		}
 
-	if err := u.orderRepository.UpdateOrderItem(orderItem); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Error updating order item")
+		// Paid orders are reopened first; a void never erases a settled check
+		if *order.Status != constant.OrderStatusOpen {
+			return errors.Wrap(domain.PreconditionFailedError("Only open orders can be voided"), "[OrderUsecase.VoidOrder]")
+		}
+
+		orderWithItems, err := u.orderRepository.GetOrderWithItems(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Order not found")
+		}
+		before := u.buildOrderResponse(orderWithItems)
+
+		if _, err := u.overrideUsecase.ConsumeOverride(ctx, overrideToken, constant.OverrideActionVoidOrder, id, nil, actorID); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Override required")
+		}
+
+		// Portions of items the kitchen never got are given back
+		for _, item := range orderWithItems.Items {
+			if item.CancelledAt != nil || item.SentAt != nil {
+				continue
+			}
+			counted, err := u.orderRepository.AdjustPortions(ctx, item.MenuItemID, item.Quantity)
+			if err != nil {
+				return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Error returning portions")
+			}
+			if counted {
+				returned = append(returned, item.MenuItemID)
+			}
+		}
+
+		// Update order status
+		now := time.Now()
+		order.Status = utils.Ptr(constant.OrderStatusVoid)
+		order.VoidReason = &req.ReasonCode
+		order.VoidedBy = &actorID
+		order.VoidedAt = &now
+
+		if err := u.orderRepository.UpdateOrder(ctx, order); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Error voiding order")
+		}
+
+		if _, err := u.auditOrderChange(ctx, constant.AuditActionVoid, id, before); err != nil {
+			return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	for _, menuItemID := range returned {
+		u.availabilityFeed.Publish(menuItemID)
+	}
+	u.metrics.Voided(constant.VoidKindOrder, req.ReasonCode)
+	u.metrics.OrderClosed(constant.OrderStatusVoid)
+
+	return nil
+}
 
-	// Recalculate order total
-	if err := u.recalculateOrderTotal(orderID); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Error recalculating total")
+// GetVoidReport totals voided orders and cancelled items by reason and by staff
+// member. It covers the current business day unless a range is given.
+func (u *orderUsecase) GetVoidReport(ctx context.Context, req *request.VoidReportQuery) (*response.VoidReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.GetVoidReport")
+	defer span.End()
+
+	today := u.calendar.Today()
+	from, to := u.calendar.Start(today), u.calendar.Start(today.AddDate(0, 0, 1))
+	if req.From != nil {
+		from = *req.From
+	}
+	if req.To != nil {
+		to = *req.To
+	}
+	if !from.Before(to) {
+		return nil, errors.Wrap(domain.ValidationError("From must be before to", map[string]string{"from": "must be before to"}), "[OrderUsecase.GetVoidReport]")
 	}
 
-	// Get updated order
-	updatedOrder, err := u.orderRepository.GetOrderWithItems(orderID)
+	totals, err := u.orderRepository.GetVoidTotals(ctx, from, to)
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.UpdateOrderItemQuantity]: Error retrieving updated order")
+		return nil, errors.Wrap(err, "[OrderUsecase.GetVoidReport]: Error getting void totals")
+	}
+
+	report := &response.VoidReportResponse{
+		From:     from,
+		To:       to,
+		ByReason: []response.VoidReportReasonResponse{},
+		ByStaff:  []response.VoidReportStaffResponse{},
+	}
+	reasonIndex := make(map[string]int)
+	staffIndex := make(map[uuid.UUID]int)
+	for _, total := range totals {
+		report.TotalCount += total.Count
+		report.TotalValueBaht += total.ValueBaht
+
+		i, ok := reasonIndex[total.ReasonCode]
+		if !ok {
+			label := utils.DerefString(total.ReasonLabel)
+			if label == "" {
+				label = total.ReasonCode
+			}
+			report.ByReason = append(report.ByReason, response.VoidReportReasonResponse{ReasonCode: total.ReasonCode, ReasonLabel: label})
+			i = len(report.ByReason) - 1
+			reasonIndex[total.ReasonCode] = i
+		}
+		report.ByReason[i].Count += total.Count
+		report.ByReason[i].ValueBaht += total.ValueBaht
+
+		actorID := utils.DerefUUID(total.ActorID)
+		j, ok := staffIndex[actorID]
+		if !ok {
+			report.ByStaff = append(report.ByStaff, response.VoidReportStaffResponse{UserID: total.ActorID, FullName: utils.DerefString(total.ActorName)})
+			j = len(report.ByStaff) - 1
+			staffIndex[actorID] = j
+		}
+		report.ByStaff[j].Count += total.Count
+		report.ByStaff[j].ValueBaht += total.ValueBaht
 	}
 
-	return u.buildOrderResponse(updatedOrder), nil
+	sort.Slice(report.ByReason, func(a, b int) bool { return report.ByReason[a].ValueBaht > report.ByReason[b].ValueBaht })
+	sort.Slice(report.ByStaff, func(a, b int) bool { return report.ByStaff[a].ValueBaht > report.ByStaff[b].ValueBaht })
+
+	return report, nil
 }
 
-func (u *orderUsecase) CloseOrder(id uuid.UUID) (*response.OrderResponse, error) {
-	order, err := u.orderRepository.GetOrderWithItems(id)
+// GetVoids lists voided orders and cancelled items, newest first unless the
+// query sorts otherwise
+func (u *orderUsecase) GetVoids(ctx context.Context, query *request.VoidListQuery) (*response.Page[*response.VoidResponse], error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.GetVoids")
+	defer span.End()
+
+	page, err := pagination.Parse(query.PageQuery, query.Sort, "-voided_at")
 	if err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.CloseOrder]: Order not found")
+		return nil, errors.Wrap(err, "[OrderUsecase.GetVoids]")
 	}
+	voids, err := u.orderRepository.GetVoids(ctx, &query.VoidFilter, page)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OrderUsecase.GetVoids]: Error getting voids")
+	}
+
+	responses := make([]*response.VoidResponse, len(voids.Items))
+	for i, v := range voids.Items {
+		responses[i] = &response.VoidResponse{
+			ID:           v.ID,
+			Kind:         v.Kind,
+			OrderID:      v.OrderID,
+			TableName:    utils.DerefString(v.TableName),
+			MenuItemName: utils.DerefString(v.MenuItemName),
+			Quantity:     v.Quantity,
+			ValueBaht:    v.ValueBaht,
+			ReasonCode:   utils.DerefString(v.ReasonCode),
+			ReasonLabel:  utils.DerefString(v.ReasonLabel),
+			VoidedBy:     v.VoidedBy,
+			VoiderName:   utils.DerefString(v.VoiderName),
+			VoidedAt:     v.VoidedAt,
+			BusinessDate: businessday.Format(v.BusinessDate),
+		}
+	}
+	return &response.Page[*response.VoidResponse]{Items: responses, NextCursor: voids.NextCursor}, nil
+}
 
-	// Check if order is already closed
-	if *order.Status != constant.OrderStatusOpen {
-		return nil, errors.New("[OrderUsecase.CloseOrder]: Order is already closed")
+// CountOpenOrdersByArea returns the number of open orders keyed by area name.
+// Orders whose table has no area are counted under constant.OrderAreaNone.
+func (u *orderUsecase) CountOpenOrdersByArea(ctx context.Context) (map[string]int64, error) {
+	ctx, span := tracing.Start(ctx, "OrderUsecase.CountOpenOrdersByArea")
+	defer span.End()
+
+	counts, err := u.orderRepository.CountOpenOrdersByArea(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OrderUsecase.CountOpenOrdersByArea]: Error counting open orders")
 	}
 
-	// Update order status
-	now := time.Now()
-	order.Status = utils.Ptr(constant.OrderStatusPaid)
-	order.ClosedAt = &now
+	byArea := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		area := utils.DerefString(count.AreaName)
+		if area == "" {
+			area = constant.OrderAreaNone
+		}
+		byArea[area] += count.Count
+	}
+	return byArea, nil
+}
 
-	if err := u.orderRepository.UpdateOrder(order); err != nil {
-		return nil, errors.Wrap(err, "[OrderUsecase.CloseOrder]: Error closing order")
+// orderable refuses a menu item taken off the menu, sold out, or not offered
+// now at the order's table by its day-part schedules. Counted portions are
+// checked again as they are taken.
+func (u *orderUsecase) orderable(menuItem *models.MenuItem, order *models.Order) error {
+	if !utils.DerefBool(menuItem.Active) {
+		return domain.PreconditionFailedError("Menu item is not active")
+	}
+	if domain.MenuItemStatus(menuItem.Availability) == constant.MenuItemSoldOut {
+		return domain.PreconditionFailedError("Menu item is sold out")
 	}
 
-	return u.buildOrderResponse(order), nil
+	now := time.Now().In(u.calendar.Location())
+	var areaID *uuid.UUID
+	if order.Table != nil {
+		areaID = order.Table.AreaID
+	}
+	if !domain.MenuItemOffered(menuItem, now, areaID) {
+		if domain.MenuItemOfferedInAnyArea(menuItem, now) {
+			return domain.PreconditionFailedError("Menu item is not offered in this area")
+		}
+		return domain.PreconditionFailedError("Menu item is not offered at this time")
+	}
+	return nil
 }
 
-func (u *orderUsecase) VoidOrder(id uuid.UUID) error {
-	order, err := u.orderRepository.GetOrderByID(id)
+// Helper function to check a reason code is on the active list
+func (u *orderUsecase) validateVoidReason(ctx context.Context, code string) error {
+	reason, err := u.orderRepository.GetVoidReasonByCode(ctx, code)
 	if err != nil {
-		return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Order not found")
+		if domain.ErrorKindOf(err) == domain.ErrorKindNotFound {
+			return errors.Wrap(domain.ValidationError("Unknown reason code", map[string]string{"reason_code": "unknown"}), "[OrderUsecase.validateVoidReason]")
+		}
+		return err
 	}
+	if !utils.DerefBool(reason.Active) {
+		return errors.Wrap(domain.ValidationError("Void reason is no longer in use", map[string]string{"reason_code": "inactive"}), "[OrderUsecase.validateVoidReason]")
+	}
+	return nil
+}
 
-	// Update order status
-	order.Status = utils.Ptr(constant.OrderStatusVoid)
+// Helper function to record an order change in the audit log and return the updated order
+func (u *orderUsecase) auditOrderChange(ctx context.Context, action string, orderID uuid.UUID, before *response.OrderResponse) (*response.OrderResponse, error) {
+	updatedOrder, err := u.orderRepository.GetOrderWithItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := u.orderRepository.UpdateOrder(order); err != nil {
-		return errors.Wrap(err, "[OrderUsecase.VoidOrder]: Error voiding order")
+	after := u.buildOrderResponse(updatedOrder)
+	if err := u.auditUsecase.Record(ctx, action, constant.AuditEntityOrder, orderID.String(), before, after); err != nil {
+		return nil, err
 	}
+	return after, nil
+}
 
-	return nil
+// lockOrder locks the order until the transaction ends, so its status cannot
+// change under the caller, and loads it with its items
+func (u *orderUsecase) lockOrder(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	if _, err := u.orderRepository.GetOrderForUpdate(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.orderRepository.GetOrderWithItems(ctx, id)
 }
 
-// Helper function to recalculate order total
-func (u *orderUsecase) recalculateOrderTotal(orderID uuid.UUID) error {
-	order, err := u.orderRepository.GetOrderWithItems(orderID)
+// Helper function to recalculate order total. The order is locked first so the
+// totals are computed from the latest items and discount.
+func (u *orderUsecase) recalculateOrderTotal(ctx context.Context, orderID uuid.UUID) error {
+	order, err := u.lockOrder(ctx, orderID)
 	if err != nil {
 		return err
 	}
 
 	subtotal := int64(0)
 	for _, item := range order.Items {
+		// Cancelled items stay on the order but are not charged
+		if item.CancelledAt != nil {
+			continue
+		}
 		subtotal += item.LineTotalBaht
 	}
 
 	discount := utils.DerefInt64(order.DiscountBaht)
 	total := subtotal - discount
+	if total < 0 {
+		total = 0
+	}
 
 	order.SubtotalBaht = &subtotal
 	order.TotalBaht = &total
 
-	return u.orderRepository.UpdateOrder(order)
+	return u.orderRepository.UpdateOrder(ctx, order)
 }
 
 // Helper function to build order response
 func (u *orderUsecase) buildOrderResponse(order *models.Order) *response.OrderResponse {
 	items := make([]response.OrderItemResponse, len(order.Items))
-	for i, item := range order.Items {
-		modifiers := make([]response.OrderItemModifierResponse, len(item.Modifiers))
-		for j, mod := range item.Modifiers {
-			modifiers[j] = response.OrderItemModifierResponse{
-				ModifierID:     mod.ModifierID,
-				ModifierName:   utils.DerefString(mod.Modifier.Name),
-				PriceDeltaBaht: utils.DerefInt64(mod.PriceDeltaBaht),
-			}
-		}
-
-		items[i] = response.OrderItemResponse{
-			ID:            item.ID,
-			MenuItemID:    item.MenuItemID,
-			MenuItemName:  utils.DerefString(item.MenuItem.Name),
-			Quantity:      item.Quantity,
-			UnitPriceBaht: item.UnitPriceBaht,
-			LineTotalBaht: item.LineTotalBaht,
-			Note:          utils.DerefString(item.Note),
-			Modifiers:     modifiers,
-		}
+	for i := range order.Items {
+		items[i] = u.buildOrderItemResponse(&order.Items[i])
 	}
 
 	return &response.OrderResponse{
@@ -358,16 +925,66 @@ func (u *orderUsecase) buildOrderResponse(order *models.Order) *response.OrderRe
 		TotalBaht:    utils.DerefInt64(order.TotalBaht),
 		Note:         utils.DerefString(order.Note),
 		CreatedAt:    order.CreatedAt,
+		BusinessDate: businessday.Format(order.BusinessDate),
 		ClosedAt:     order.ClosedAt,
+		VoidReason:   utils.DerefString(order.VoidReason),
+		VoidedBy:     order.VoidedBy,
+		VoidedAt:     order.VoidedAt,
 		Items:        items,
 	}
 }
 
-// Helper function to build multiple order responses
-func (u *orderUsecase) buildOrderResponses(orders []*models.Order) []*response.OrderResponse {
-	responses := make([]*response.OrderResponse, len(orders))
-	for i, order := range orders {
-		responses[i] = u.buildOrderResponse(order)
+// Helper function to build an order item response from an item loaded with its menu item and modifiers
+func (u *orderUsecase) buildOrderItemResponse(item *models.OrderItem) response.OrderItemResponse {
+	modifiers := make([]response.OrderItemModifierResponse, len(item.Modifiers))
+	for j, mod := range item.Modifiers {
+		modifiers[j] = response.OrderItemModifierResponse{
+			ModifierID:     mod.ModifierID,
+			ModifierName:   utils.DerefString(mod.Modifier.Name),
+			PriceDeltaBaht: utils.DerefInt64(mod.PriceDeltaBaht),
+		}
+	}
+
+	return response.OrderItemResponse{
+		ID:            item.ID,
+		MenuItemID:    item.MenuItemID,
+		MenuItemName:  utils.DerefString(item.MenuItem.Name),
+		Quantity:      item.Quantity,
+		UnitPriceBaht: item.UnitPriceBaht,
+		LineTotalBaht: item.LineTotalBaht,
+		Note:          utils.DerefString(item.Note),
+		SentAt:        item.SentAt,
+		CancelledAt:   item.CancelledAt,
+		CancelledBy:   item.CancelledBy,
+		CancelReason:  utils.DerefString(item.CancelReason),
+		Modifiers:     modifiers,
+	}
+}
+
+// Helper function to build order summary response; now ends the age of orders still open
+func (u *orderUsecase) buildOrderSummaryResponse(summary *models.OrderSummary, now time.Time) *response.OrderSummaryResponse {
+	end := now
+	if summary.ClosedAt != nil {
+		end = *summary.ClosedAt
+	} else if summary.VoidedAt != nil {
+		end = *summary.VoidedAt
+	}
+
+	return &response.OrderSummaryResponse{
+		ID:           summary.ID,
+		TableID:      utils.DerefUUID(summary.TableID),
+		TableName:    utils.DerefString(summary.TableName),
+		Status:       utils.DerefString(summary.Status),
+		Source:       utils.DerefString(summary.Source),
+		OpenedBy:     utils.DerefUUID(summary.OpenedBy),
+		OpenerName:   utils.DerefString(summary.OpenerName),
+		ItemCount:    summary.ItemCount,
+		TotalBaht:    summary.TotalBaht,
+		PaidBaht:     summary.PaidBaht,
+		BalanceBaht:  summary.TotalBaht - summary.PaidBaht,
+		AgeSeconds:   int64(max(end.Sub(summary.CreatedAt), 0) / time.Second),
+		CreatedAt:    summary.CreatedAt,
+		BusinessDate: businessday.Format(summary.BusinessDate),
+		ClosedAt:     summary.ClosedAt,
 	}
-	return responses
 }
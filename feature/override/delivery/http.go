@@ -0,0 +1,56 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type overrideHandler struct {
+	overrideUsecase domain.OverrideUsecase
+}
+
+func NewOverrideHandler(overrideUsecase domain.OverrideUsecase) *overrideHandler {
+	return &overrideHandler{overrideUsecase: overrideUsecase}
+}
+
+func (h *overrideHandler) IssueOverride(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
+		return
+	}
+
+	var req request.OverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	override, err := h.overrideUsecase.IssueOverride(c.Request.Context(), userID.(uuid.UUID), c.ClientIP(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OverrideHandler.IssueOverride]: Error issuing override"))
+		return
+	}
+	c.JSON(http.StatusCreated, override)
+}
+
+func (h *overrideHandler) GetOverridesByOrder(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
+		return
+	}
+
+	overrides, err := h.overrideUsecase.GetOverridesByOrder(c.Request.Context(), orderID)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[OverrideHandler.GetOverridesByOrder]: Error getting overrides"))
+		return
+	}
+	c.JSON(http.StatusOK, overrides)
+}
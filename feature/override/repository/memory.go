@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+)
+
+type overrideMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewOverrideMemoryRepository(store *memory.Store) domain.OverrideRepository {
+	return &overrideMemoryRepository{store: store}
+}
+
+func (r *overrideMemoryRepository) CreateOverride(ctx context.Context, override *models.ManagerOverride) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.ManagerOverrides, func(other *models.ManagerOverride) bool { return other.Token == override.Token }) {
+			return errors.Wrap(gorm.ErrDuplicatedKey, "[OverrideMemoryRepository.CreateOverride]: Error creating override")
+		}
+		_, orderExists := t.Orders[override.OrderID]
+		_, requesterExists := t.Users[override.RequestedBy]
+		_, approverExists := t.Users[override.ApprovedBy]
+		if !orderExists || !requesterExists || !approverExists {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, "[OverrideMemoryRepository.CreateOverride]: Error creating override")
+		}
+
+		if override.ID == uuid.Nil {
+			override.ID = uuid.New()
+		}
+		if override.CreatedAt.IsZero() {
+			override.CreatedAt = time.Now()
+		}
+		row := *override
+		row.Order, row.Requester, row.Approver = nil, nil, nil
+		t.ManagerOverrides[row.ID] = row
+		return nil
+	})
+}
+
+func (r *overrideMemoryRepository) GetOverrideByToken(ctx context.Context, token string) (*models.ManagerOverride, error) {
+	var override *models.ManagerOverride
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		overrides := memory.Select(t.ManagerOverrides, func(o *models.ManagerOverride) bool { return o.Token == token })
+		if len(overrides) == 0 {
+			return errors.Wrap(domain.NotFoundError("Override not found"), "[OverrideMemoryRepository.GetOverrideByToken]")
+		}
+		override = overrides[0]
+		return nil
+	})
+	return override, err
+}
+
+// MarkOverrideUsed only succeeds once per override, so a token cannot be replayed by
+// two concurrent requests.
+func (r *overrideMemoryRepository) MarkOverrideUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		override, ok := t.ManagerOverrides[id]
+		if !ok || override.UsedAt != nil {
+			return errors.Wrap(domain.ConflictError("Override has already been used"), "[OverrideMemoryRepository.MarkOverrideUsed]")
+		}
+		override.UsedAt = &usedAt
+		t.ManagerOverrides[id] = override
+		return nil
+	})
+}
+
+func (r *overrideMemoryRepository) GetOverridesByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.ManagerOverride, error) {
+	var overrides []*models.ManagerOverride
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		overrides = memory.Select(t.ManagerOverrides, func(o *models.ManagerOverride) bool { return o.OrderID == orderID })
+		memory.Sort(overrides, func(a, b *models.ManagerOverride) int { return b.CreatedAt.Compare(a.CreatedAt) })
+		for _, override := range overrides {
+			if requester, ok := t.Users[override.RequestedBy]; ok {
+				override.Requester = &requester
+			}
+			if approver, ok := t.Users[override.ApprovedBy]; ok {
+				override.Approver = &approver
+			}
+		}
+		return nil
+	})
+	return overrides, err
+}
+
+func (r *overrideMemoryRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Orders[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Order not found"), "[OverrideMemoryRepository.GetOrderByID]")
+		}
+		order = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *overrideMemoryRepository) GetOrderItemByID(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
+	var orderItem models.OrderItem
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.OrderItems[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Order item not found"), "[OverrideMemoryRepository.GetOrderItemByID]")
+		}
+		orderItem = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &orderItem, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+)
+
+type overrideRepository struct {
+	db *gorm.DB
+}
+
+func NewOverrideRepository(db *gorm.DB) domain.OverrideRepository {
+	return &overrideRepository{db: db}
+}
+
+func (r *overrideRepository) CreateOverride(ctx context.Context, override *models.ManagerOverride) error {
+	if err := database.Conn(ctx, r.db).Create(override).Error; err != nil {
+		return errors.Wrap(err, "[OverrideRepository.CreateOverride]: Error creating override")
+	}
+	return nil
+}
+
+func (r *overrideRepository) GetOverrideByToken(ctx context.Context, token string) (*models.ManagerOverride, error) {
+	var override models.ManagerOverride
+	if err := database.Conn(ctx, r.db).Where("token = ?", token).First(&override).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Override not found"), "[OverrideRepository.GetOverrideByToken]")
+		}
+		return nil, errors.Wrap(err, "[OverrideRepository.GetOverrideByToken]: Error querying database")
+	}
+	return &override, nil
+}
+
+// MarkOverrideUsed only succeeds once per override, so a token cannot be replayed by
+// two concurrent requests.
+func (r *overrideRepository) MarkOverrideUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	result := database.Conn(ctx, r.db).Model(&models.ManagerOverride{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", usedAt)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[OverrideRepository.MarkOverrideUsed]: Error updating override")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(domain.ConflictError("Override has already been used"), "[OverrideRepository.MarkOverrideUsed]")
+	}
+	return nil
+}
+
+func (r *overrideRepository) GetOverridesByOrder(ctx context.Context, orderID uuid.UUID) ([]*models.ManagerOverride, error) {
+	var overrides []*models.ManagerOverride
+	if err := database.Conn(ctx, r.db).Preload("Requester").Preload("Approver").Where("order_id = ?", orderID).Order("created_at DESC").Find(&overrides).Error; err != nil {
+		return nil, errors.Wrap(err, "[OverrideRepository.GetOverridesByOrder]: Error querying database")
+	}
+	return overrides, nil
+}
+
+func (r *overrideRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Order not found"), "[OverrideRepository.GetOrderByID]")
+		}
+		return nil, errors.Wrap(err, "[OverrideRepository.GetOrderByID]: Error querying database")
+	}
+	return &order, nil
+}
+
+func (r *overrideRepository) GetOrderItemByID(ctx context.Context, id uuid.UUID) (*models.OrderItem, error) {
+	var orderItem models.OrderItem
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&orderItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Order item not found"), "[OverrideRepository.GetOrderItemByID]")
+		}
+		return nil, errors.Wrap(err, "[OverrideRepository.GetOrderItemByID]: Error querying database")
+	}
+	return &orderItem, nil
+}
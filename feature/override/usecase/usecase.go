@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/logging"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type overrideUsecase struct {
+	overrideRepository domain.OverrideRepository
+	authUsecase        domain.AuthUsecase
+	transactor         domain.Transactor
+	auditUsecase       domain.AuditUsecase
+}
+
+func NewOverrideUsecase(overrideRepository domain.OverrideRepository, authUsecase domain.AuthUsecase, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.OverrideUsecase {
+	return &overrideUsecase{overrideRepository: overrideRepository, authUsecase: authUsecase, transactor: transactor, auditUsecase: auditUsecase}
+}
+
+func (u *overrideUsecase) IssueOverride(ctx context.Context, requestedBy uuid.UUID, clientIP string, req *request.OverrideRequest) (*response.OverrideResponse, error) {
+	ctx, span := tracing.Start(ctx, "OverrideUsecase.IssueOverride")
+	defer span.End()
+
+	// Validate order exists
+	if _, err := u.overrideRepository.GetOrderByID(ctx, req.OrderID); err != nil {
+		return nil, errors.Wrap(err, "[OverrideUsecase.IssueOverride]: Invalid order ID")
+	}
+
+	// Item removals are approved for one specific item
+	if req.Action == constant.OverrideActionRemoveItem {
+		if req.OrderItemID == nil {
+			return nil, errors.Wrap(domain.ValidationError("Order item ID is required to remove an item", map[string]string{"order_item_id": "required"}), "[OverrideUsecase.IssueOverride]")
+		}
+		orderItem, err := u.overrideRepository.GetOrderItemByID(ctx, *req.OrderItemID)
+		if err != nil {
+			return nil, errors.Wrap(err, "[OverrideUsecase.IssueOverride]: Invalid order item ID")
+		}
+		if orderItem.OrderID != req.OrderID {
+			return nil, errors.Wrap(domain.ValidationError("Order item does not belong to this order", map[string]string{"order_item_id": "not on this order"}), "[OverrideUsecase.IssueOverride]")
+		}
+	}
+
+	// Verify the approver's PIN or password
+	approver, err := u.authUsecase.VerifyCredentials(ctx, req.ApproverUsername, req.ApproverSecret, clientIP)
+	if err != nil {
+		// A bad approver secret must not look like the requester's session expired
+		if domain.ErrorKindOf(err) == domain.ErrorKindUnauthorized {
+			logging.FromContext(ctx).WithError(err).Warn("[OverrideUsecase.IssueOverride]: Approver authentication failed")
+			return nil, errors.Wrap(domain.ForbiddenError("Approver authentication failed"), "[OverrideUsecase.IssueOverride]")
+		}
+		return nil, errors.Wrap(err, "[OverrideUsecase.IssueOverride]: Approver authentication failed")
+	}
+
+	allowed, err := u.authUsecase.VerifyPermission(ctx, approver.ID, constant.OverridePermission)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OverrideUsecase.IssueOverride]: Error checking approver permissions")
+	}
+	if !allowed {
+		return nil, errors.Wrap(domain.ForbiddenError("Approver is not allowed to authorize overrides"), "[OverrideUsecase.IssueOverride]")
+	}
+
+	token, err := utils.GenerateToken(16)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OverrideUsecase.IssueOverride]: Error generating token")
+	}
+
+	override := &models.ManagerOverride{
+		Token:       token,
+		Action:      req.Action,
+		OrderID:     req.OrderID,
+		OrderItemID: req.OrderItemID,
+		RequestedBy: requestedBy,
+		ApprovedBy:  approver.ID,
+		Reason:      req.Reason,
+		ExpiresAt:   time.Now().Add(constant.OverrideTokenTTL),
+		Approver:    approver,
+	}
+
+	// Credentials are verified outside the transaction so failed attempts are kept
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.overrideRepository.CreateOverride(ctx, override); err != nil {
+			return errors.Wrap(err, "[OverrideUsecase.IssueOverride]: Error creating override")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionIssueOverride, constant.AuditEntityOverride, override.ID.String(), nil, u.buildOverrideResponse(override)); err != nil {
+			return errors.Wrap(err, "[OverrideUsecase.IssueOverride]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	overrideResponse := u.buildOverrideResponse(override)
+	overrideResponse.Token = token
+	return overrideResponse, nil
+}
+
+// ConsumeOverride validates a token against the action being performed and marks it
+// used. A token only works once, for the user who requested it, before it expires.
+func (u *overrideUsecase) ConsumeOverride(ctx context.Context, token string, action string, orderID uuid.UUID, orderItemID *uuid.UUID, actorID uuid.UUID) (*models.ManagerOverride, error) {
+	ctx, span := tracing.Start(ctx, "OverrideUsecase.ConsumeOverride", tracing.OrderID(orderID))
+	defer span.End()
+
+	if token == "" {
+		return nil, errors.Wrap(domain.ForbiddenError("Manager approval is required for this action"), "[OverrideUsecase.ConsumeOverride]")
+	}
+
+	override, err := u.overrideRepository.GetOverrideByToken(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OverrideUsecase.ConsumeOverride]: Invalid override token")
+	}
+
+	if override.UsedAt != nil {
+		return nil, errors.Wrap(domain.ForbiddenError("Override token has already been used"), "[OverrideUsecase.ConsumeOverride]")
+	}
+	if override.ExpiresAt.Before(time.Now()) {
+		return nil, errors.Wrap(domain.ForbiddenError("Override token has expired"), "[OverrideUsecase.ConsumeOverride]")
+	}
+	if override.Action != action || override.OrderID != orderID || override.RequestedBy != actorID {
+		return nil, errors.Wrap(domain.ForbiddenError("Override token does not match this action"), "[OverrideUsecase.ConsumeOverride]")
+	}
+	if orderItemID != nil && utils.DerefUUID(override.OrderItemID) != *orderItemID {
+		return nil, errors.Wrap(domain.ForbiddenError("Override token does not match this item"), "[OverrideUsecase.ConsumeOverride]")
+	}
+
+	now := time.Now()
+	if err := u.overrideRepository.MarkOverrideUsed(ctx, override.ID, now); err != nil {
+		return nil, errors.Wrap(err, "[OverrideUsecase.ConsumeOverride]: Error using override")
+	}
+	override.UsedAt = &now
+
+	return override, nil
+}
+
+func (u *overrideUsecase) GetOverridesByOrder(ctx context.Context, orderID uuid.UUID) (*response.Page[*response.OverrideResponse], error) {
+	ctx, span := tracing.Start(ctx, "OverrideUsecase.GetOverridesByOrder", tracing.OrderID(orderID))
+	defer span.End()
+
+	overrides, err := u.overrideRepository.GetOverridesByOrder(ctx, orderID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[OverrideUsecase.GetOverridesByOrder]: Error getting overrides")
+	}
+
+	overrideResponses := make([]*response.OverrideResponse, len(overrides))
+	for i, override := range overrides {
+		overrideResponses[i] = u.buildOverrideResponse(override)
+	}
+	return response.SinglePage(overrideResponses), nil
+}
+
+// Helper function to build override response
+func (u *overrideUsecase) buildOverrideResponse(override *models.ManagerOverride) *response.OverrideResponse {
+	overrideResponse := &response.OverrideResponse{
+		ID:          override.ID,
+		Action:      override.Action,
+		OrderID:     override.OrderID,
+		OrderItemID: override.OrderItemID,
+		RequestedBy: override.RequestedBy,
+		ApprovedBy:  override.ApprovedBy,
+		Reason:      override.Reason,
+		ExpiresAt:   override.ExpiresAt,
+		UsedAt:      override.UsedAt,
+		CreatedAt:   override.CreatedAt,
+	}
+	if override.Requester != nil {
+		overrideResponse.RequesterName = utils.DerefString(override.Requester.FullName)
+	}
+	if override.Approver != nil {
+		overrideResponse.ApproverName = utils.DerefString(override.Approver.FullName)
+	}
+	return overrideResponse
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type paymentHandler struct {
@@ -21,11 +20,15 @@ func NewPaymentHandler(paymentUsecase domain.PaymentUsecase) *paymentHandler {
 }
 
 func (h *paymentHandler) GetAllPayments(c *gin.Context) {
-	payments, err := h.paymentUsecase.GetAllPayments()
+	var req request.PaymentListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	payments, err := h.paymentUsecase.GetAllPayments(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[PaymentHandler.GetAllPayments]: Error getting payments")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[PaymentHandler.GetAllPayments]: Error getting payments"))
 		return
 	}
 	c.JSON(http.StatusOK, payments)
@@ -34,15 +37,13 @@ func (h *paymentHandler) GetAllPayments(c *gin.Context) {
 func (h *paymentHandler) GetPaymentByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid payment ID", nil))
 		return
 	}
 
-	payment, err := h.paymentUsecase.GetPaymentByID(id)
+	payment, err := h.paymentUsecase.GetPaymentByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[PaymentHandler.GetPaymentByID]: Error getting payment")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[PaymentHandler.GetPaymentByID]: Error getting payment"))
 		return
 	}
 	c.JSON(http.StatusOK, payment)
@@ -51,15 +52,19 @@ func (h *paymentHandler) GetPaymentByID(c *gin.Context) {
 func (h *paymentHandler) GetPaymentsByOrder(c *gin.Context) {
 	orderID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid order ID", nil))
+		return
+	}
+
+	var req request.PaymentListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
 		return
 	}
 
-	payments, err := h.paymentUsecase.GetPaymentsByOrder(orderID)
+	payments, err := h.paymentUsecase.GetPaymentsByOrder(c.Request.Context(), orderID, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[PaymentHandler.GetPaymentsByOrder]: Error getting payments")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[PaymentHandler.GetPaymentsByOrder]: Error getting payments"))
 		return
 	}
 	c.JSON(http.StatusOK, payments)
@@ -68,26 +73,22 @@ func (h *paymentHandler) GetPaymentsByOrder(c *gin.Context) {
 func (h *paymentHandler) ProcessPayment(c *gin.Context) {
 	var req request.PaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	payment, err := h.paymentUsecase.ProcessPayment(&req)
+	payment, err := h.paymentUsecase.ProcessPayment(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[PaymentHandler.ProcessPayment]: Error processing payment")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[PaymentHandler.ProcessPayment]: Error processing payment"))
 		return
 	}
 	c.JSON(http.StatusCreated, payment)
 }
 
 func (h *paymentHandler) GetPaymentMethods(c *gin.Context) {
-	methods, err := h.paymentUsecase.GetPaymentMethods()
+	methods, err := h.paymentUsecase.GetPaymentMethods(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[PaymentHandler.GetPaymentMethods]: Error getting payment methods")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[PaymentHandler.GetPaymentMethods]: Error getting payment methods"))
 		return
 	}
 	c.JSON(http.StatusOK, methods)
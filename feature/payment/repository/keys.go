@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+)
+
+// paymentKeys are the fields payments can be sorted by
+var paymentKeys = pagination.Keys[models.Payment]{
+	IDColumn: "id",
+	ID:       func(p *models.Payment) uuid.UUID { return p.ID },
+	Sorts: map[string]pagination.Key[models.Payment]{
+		"created_at":  {Expr: "created_at", Value: func(p *models.Payment) any { return p.CreatedAt }},
+		"amount_baht": {Expr: "amount_baht", Value: func(p *models.Payment) any { return p.AmountBaht }},
+	},
+}
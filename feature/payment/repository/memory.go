@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+type paymentMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewPaymentMemoryRepository(store *memory.Store) domain.PaymentRepository {
+	return &paymentMemoryRepository{store: store}
+}
+
+func (r *paymentMemoryRepository) GetPayments(ctx context.Context, query *request.PaymentListQuery, page domain.PageParams) (domain.Page[models.Payment], error) {
+	var payments domain.Page[models.Payment]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		rows := memory.Select(t.Payments, func(p *models.Payment) bool {
+			switch {
+			case query.OrderID != nil && p.OrderID != *query.OrderID:
+				return false
+			case query.Method != "" && utils.DerefString(p.Method) != query.Method:
+				return false
+			case query.Status != "" && utils.DerefString(p.Status) != query.Status:
+				return false
+			case query.From != nil && p.CreatedAt.Before(*query.From):
+				return false
+			case query.To != nil && !p.CreatedAt.Before(*query.To):
+				return false
+			}
+			return true
+		})
+
+		var err error
+		if payments, err = pagination.Slice(rows, page, paymentKeys); err != nil {
+			return errors.Wrap(err, "[PaymentMemoryRepository.GetPayments]")
+		}
+		for _, payment := range payments.Items {
+			preloadOrder(t, payment)
+		}
+		return nil
+	})
+	return payments, err
+}
+
+func (r *paymentMemoryRepository) GetPaymentByID(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
+	var payment models.Payment
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Payments[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Payment not found"), "[PaymentMemoryRepository.GetPaymentByID]")
+		}
+		payment = found
+		preloadOrder(t, &payment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentMemoryRepository) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if payment.ID == uuid.Nil {
+			payment.ID = uuid.New()
+		}
+		if payment.CreatedAt.IsZero() {
+			payment.CreatedAt = time.Now()
+		}
+		if payment.Currency == nil {
+			currency := "THB"
+			payment.Currency = &currency
+		}
+		return r.save(t, payment, "[PaymentMemoryRepository.CreatePayment]: Error creating payment")
+	})
+}
+
+func (r *paymentMemoryRepository) UpdatePayment(ctx context.Context, payment *models.Payment) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, payment, "[PaymentMemoryRepository.UpdatePayment]: Error updating payment")
+	})
+}
+
+func (r *paymentMemoryRepository) save(t *memory.Tables, payment *models.Payment, op string) error {
+	if _, ok := t.Orders[payment.OrderID]; !ok {
+		return errors.Wrap(gorm.ErrForeignKeyViolated, op)
+	}
+
+	row := *payment
+	row.Order = nil
+	t.Payments[row.ID] = row
+	return nil
+}
+
+func (r *paymentMemoryRepository) GetTotalPaidForOrder(ctx context.Context, orderID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		for _, payment := range t.Payments {
+			if payment.OrderID == orderID && payment.Status != nil && *payment.Status == "succeeded" {
+				total += payment.AmountBaht
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+func (r *paymentMemoryRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Orders[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Order not found"), "[PaymentMemoryRepository.GetOrderByID]")
+		}
+		order = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func preloadOrder(t *memory.Tables, payment *models.Payment) {
+	if order, ok := t.Orders[payment.OrderID]; ok {
+		payment.Order = &order
+	}
+}
@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
 	"gorm.io/gorm"
 )
 
@@ -16,50 +21,59 @@ func NewPaymentRepository(db *gorm.DB) domain.PaymentRepository {
 	return &paymentRepository{db: db}
 }
 
-func (r *paymentRepository) GetAllPayments() ([]*models.Payment, error) {
-	var payments []*models.Payment
-	if err := r.db.Preload("Order").Order("created_at DESC").Find(&payments).Error; err != nil {
-		return nil, errors.Wrap(err, "[PaymentRepository.GetAllPayments]: Error querying database")
+func (r *paymentRepository) GetPayments(ctx context.Context, query *request.PaymentListQuery, page domain.PageParams) (domain.Page[models.Payment], error) {
+	db := database.Conn(ctx, r.db).Preload("Order")
+	if query.OrderID != nil {
+		db = db.Where("order_id = ?", *query.OrderID)
+	}
+	if query.Method != "" {
+		db = db.Where("method = ?", query.Method)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.From != nil {
+		db = db.Where("created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("created_at < ?", *query.To)
+	}
+
+	payments, err := pagination.Find(db, page, paymentKeys)
+	if err != nil {
+		return domain.Page[models.Payment]{}, errors.Wrap(err, "[PaymentRepository.GetPayments]")
 	}
 	return payments, nil
 }
 
-func (r *paymentRepository) GetPaymentByID(id uuid.UUID) (*models.Payment, error) {
+func (r *paymentRepository) GetPaymentByID(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
 	var payment models.Payment
-	if err := r.db.Preload("Order").Where("id = ?", id).First(&payment).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("Order").Where("id = ?", id).First(&payment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[PaymentRepository.GetPaymentByID]: Payment not found")
+			return nil, errors.Wrap(domain.NotFoundError("Payment not found"), "[PaymentRepository.GetPaymentByID]")
 		}
 		return nil, errors.Wrap(err, "[PaymentRepository.GetPaymentByID]: Error querying database")
 	}
 	return &payment, nil
 }
 
-func (r *paymentRepository) GetPaymentsByOrder(orderID uuid.UUID) ([]*models.Payment, error) {
-	var payments []*models.Payment
-	if err := r.db.Where("order_id = ?", orderID).Order("created_at DESC").Find(&payments).Error; err != nil {
-		return nil, errors.Wrap(err, "[PaymentRepository.GetPaymentsByOrder]: Error querying database")
-	}
-	return payments, nil
-}
-
-func (r *paymentRepository) CreatePayment(payment *models.Payment) error {
-	if err := r.db.Create(payment).Error; err != nil {
+func (r *paymentRepository) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	if err := database.Conn(ctx, r.db).Create(payment).Error; err != nil {
 		return errors.Wrap(err, "[PaymentRepository.CreatePayment]: Error creating payment")
 	}
 	return nil
 }
 
-func (r *paymentRepository) UpdatePayment(payment *models.Payment) error {
-	if err := r.db.Save(payment).Error; err != nil {
+func (r *paymentRepository) UpdatePayment(ctx context.Context, payment *models.Payment) error {
+	if err := database.Conn(ctx, r.db).Save(payment).Error; err != nil {
 		return errors.Wrap(err, "[PaymentRepository.UpdatePayment]: Error updating payment")
 	}
 	return nil
 }
 
-func (r *paymentRepository) GetTotalPaidForOrder(orderID uuid.UUID) (int64, error) {
+func (r *paymentRepository) GetTotalPaidForOrder(ctx context.Context, orderID uuid.UUID) (int64, error) {
 	var total int64
-	if err := r.db.Model(&models.Payment{}).
+	if err := database.Conn(ctx, r.db).Model(&models.Payment{}).
 		Where("order_id = ? AND status = ?", orderID, "succeeded").
 		Select("COALESCE(SUM(amount_baht), 0)").
 		Scan(&total).Error; err != nil {
@@ -68,11 +82,11 @@ func (r *paymentRepository) GetTotalPaidForOrder(orderID uuid.UUID) (int64, erro
 	return total, nil
 }
 
-func (r *paymentRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
+func (r *paymentRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	var order models.Order
-	if err := r.db.Where("id = ?", id).First(&order).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[PaymentRepository.GetOrderByID]: Order not found")
+			return nil, errors.Wrap(domain.NotFoundError("Order not found"), "[PaymentRepository.GetOrderByID]")
 		}
 		return nil, errors.Wrap(err, "[PaymentRepository.GetOrderByID]: Error querying database")
 	}
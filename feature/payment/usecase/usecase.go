@@ -1,35 +1,50 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
 	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
 type paymentUsecase struct {
 	paymentRepository domain.PaymentRepository
+	transactor        domain.Transactor
+	auditUsecase      domain.AuditUsecase
+	metrics           domain.Metrics
+	calendar          businessday.Calendar
 }
 
-func NewPaymentUsecase(paymentRepository domain.PaymentRepository) domain.PaymentUsecase {
-	return &paymentUsecase{paymentRepository: paymentRepository}
+func NewPaymentUsecase(paymentRepository domain.PaymentRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase, metrics domain.Metrics, calendar businessday.Calendar) domain.PaymentUsecase {
+	return &paymentUsecase{paymentRepository: paymentRepository, transactor: transactor, auditUsecase: auditUsecase, metrics: metrics, calendar: calendar}
 }
 
-func (u *paymentUsecase) GetAllPayments() ([]*response.PaymentResponse, error) {
-	payments, err := u.paymentRepository.GetAllPayments()
+func (u *paymentUsecase) GetAllPayments(ctx context.Context, query *request.PaymentListQuery) (*response.Page[*response.PaymentResponse], error) {
+	ctx, span := tracing.Start(ctx, "PaymentUsecase.GetAllPayments")
+	defer span.End()
+
+	payments, err := u.getPayments(ctx, query)
 	if err != nil {
 		return nil, errors.Wrap(err, "[PaymentUsecase.GetAllPayments]: Error getting payments")
 	}
-
-	return u.buildPaymentResponses(payments), nil
+	return payments, nil
 }
 
-func (u *paymentUsecase) GetPaymentByID(id uuid.UUID) (*response.PaymentResponse, error) {
-	payment, err := u.paymentRepository.GetPaymentByID(id)
+func (u *paymentUsecase) GetPaymentByID(ctx context.Context, id uuid.UUID) (*response.PaymentResponse, error) {
+	ctx, span := tracing.Start(ctx, "PaymentUsecase.GetPaymentByID", tracing.PaymentID(id))
+	defer span.End()
+
+	payment, err := u.paymentRepository.GetPaymentByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[PaymentUsecase.GetPaymentByID]: Error getting payment")
 	}
@@ -37,29 +52,49 @@ func (u *paymentUsecase) GetPaymentByID(id uuid.UUID) (*response.PaymentResponse
 	return u.buildPaymentResponse(payment), nil
 }
 
-func (u *paymentUsecase) GetPaymentsByOrder(orderID uuid.UUID) ([]*response.PaymentResponse, error) {
-	payments, err := u.paymentRepository.GetPaymentsByOrder(orderID)
+func (u *paymentUsecase) GetPaymentsByOrder(ctx context.Context, orderID uuid.UUID, query *request.PaymentListQuery) (*response.Page[*response.PaymentResponse], error) {
+	ctx, span := tracing.Start(ctx, "PaymentUsecase.GetPaymentsByOrder", tracing.OrderID(orderID))
+	defer span.End()
+
+	byOrder := *query
+	byOrder.OrderID = &orderID
+	payments, err := u.getPayments(ctx, &byOrder)
 	if err != nil {
 		return nil, errors.Wrap(err, "[PaymentUsecase.GetPaymentsByOrder]: Error getting payments")
 	}
+	return payments, nil
+}
 
-	return u.buildPaymentResponses(payments), nil
+// getPayments loads one page of payments, newest first unless the query sorts otherwise
+func (u *paymentUsecase) getPayments(ctx context.Context, query *request.PaymentListQuery) (*response.Page[*response.PaymentResponse], error) {
+	page, err := pagination.Parse(query.PageQuery, query.Sort, "-created_at")
+	if err != nil {
+		return nil, err
+	}
+	payments, err := u.paymentRepository.GetPayments(ctx, query, page)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Page[*response.PaymentResponse]{Items: u.buildPaymentResponses(payments.Items), NextCursor: payments.NextCursor}, nil
 }
 
-func (u *paymentUsecase) ProcessPayment(req *request.PaymentRequest) (*response.PaymentResponse, error) {
+func (u *paymentUsecase) ProcessPayment(ctx context.Context, req *request.PaymentRequest) (*response.PaymentResponse, error) {
+	ctx, span := tracing.Start(ctx, "PaymentUsecase.ProcessPayment", tracing.OrderID(req.OrderID))
+	defer span.End()
+
 	// Validate order exists
-	order, err := u.paymentRepository.GetOrderByID(req.OrderID)
+	order, err := u.paymentRepository.GetOrderByID(ctx, req.OrderID)
 	if err != nil {
 		return nil, errors.Wrap(err, "[PaymentUsecase.ProcessPayment]: Order not found")
 	}
 
 	// Check if order is open
 	if *order.Status != constant.OrderStatusOpen {
-		return nil, errors.New("[PaymentUsecase.ProcessPayment]: Can only pay for open orders")
+		return nil, errors.Wrap(domain.PreconditionFailedError("Can only pay for open orders"), "[PaymentUsecase.ProcessPayment]")
 	}
 
 	// Get total already paid
-	totalPaid, err := u.paymentRepository.GetTotalPaidForOrder(req.OrderID)
+	totalPaid, err := u.paymentRepository.GetTotalPaidForOrder(ctx, req.OrderID)
 	if err != nil {
 		return nil, errors.Wrap(err, "[PaymentUsecase.ProcessPayment]: Error checking payment status")
 	}
@@ -67,34 +102,53 @@ func (u *paymentUsecase) ProcessPayment(req *request.PaymentRequest) (*response.
 	// Validate payment amount
 	orderTotal := utils.DerefInt64(order.TotalBaht)
 	if totalPaid+req.AmountBaht > orderTotal {
-		return nil, errors.New("[PaymentUsecase.ProcessPayment]: Payment amount exceeds order total")
+		return nil, errors.Wrap(domain.ValidationError("Payment amount exceeds order total", map[string]string{"amount_baht": "exceeds order total"}), "[PaymentUsecase.ProcessPayment]")
 	}
 
 	// Create payment
+	now := time.Now()
 	payment := &models.Payment{
-		OrderID:     req.OrderID,
-		Method:      &req.Method,
-		AmountBaht:  req.AmountBaht,
-		Currency:    utils.Ptr(constant.PaymentCurrencyTHB),
-		Provider:    req.Provider,
-		ProviderRef: req.ProviderRef,
-		Status:      utils.Ptr(constant.PaymentStatusSucceeded),
+		OrderID:      req.OrderID,
+		Method:       &req.Method,
+		AmountBaht:   req.AmountBaht,
+		Currency:     utils.Ptr(constant.PaymentCurrencyTHB),
+		Provider:     req.Provider,
+		ProviderRef:  req.ProviderRef,
+		Status:       utils.Ptr(constant.PaymentStatusSucceeded),
+		CreatedAt:    now,
+		BusinessDate: utils.Ptr(u.calendar.Date(now)),
 	}
 
-	if err := u.paymentRepository.CreatePayment(payment); err != nil {
-		return nil, errors.Wrap(err, "[PaymentUsecase.ProcessPayment]: Error processing payment")
-	}
-
-	// Reload payment with order
-	paymentWithOrder, err := u.paymentRepository.GetPaymentByID(payment.ID)
+	var paymentResponse *response.PaymentResponse
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.paymentRepository.CreatePayment(ctx, payment); err != nil {
+			return errors.Wrap(err, "[PaymentUsecase.ProcessPayment]: Error processing payment")
+		}
+
+		// Reload payment with order
+		paymentWithOrder, err := u.paymentRepository.GetPaymentByID(ctx, payment.ID)
+		if err != nil {
+			return errors.Wrap(err, "[PaymentUsecase.ProcessPayment]: Error retrieving payment")
+		}
+		paymentResponse = u.buildPaymentResponse(paymentWithOrder)
+
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionPay, constant.AuditEntityPayment, payment.ID.String(), nil, paymentResponse); err != nil {
+			return errors.Wrap(err, "[PaymentUsecase.ProcessPayment]: Error recording audit log")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "[PaymentUsecase.ProcessPayment]: Error retrieving payment")
+		return nil, err
 	}
+	u.metrics.PaymentRecorded(paymentResponse.Method, paymentResponse.Status, paymentResponse.AmountBaht)
 
-	return u.buildPaymentResponse(paymentWithOrder), nil
+	return paymentResponse, nil
 }
 
-func (u *paymentUsecase) GetPaymentMethods() ([]*response.PaymentMethodResponse, error) {
+func (u *paymentUsecase) GetPaymentMethods(ctx context.Context) (*response.Page[*response.PaymentMethodResponse], error) {
+	ctx, span := tracing.Start(ctx, "PaymentUsecase.GetPaymentMethods")
+	defer span.End()
+
 	// Return static list of payment methods
 	methods := []*response.PaymentMethodResponse{
 		{
@@ -111,21 +165,22 @@ func (u *paymentUsecase) GetPaymentMethods() ([]*response.PaymentMethodResponse,
 		},
 	}
 
-	return methods, nil
+	return response.SinglePage(methods), nil
 }
 
 // Helper function to build payment response
 func (u *paymentUsecase) buildPaymentResponse(payment *models.Payment) *response.PaymentResponse {
 	return &response.PaymentResponse{
-		ID:          payment.ID,
-		OrderID:     payment.OrderID,
-		Method:      utils.DerefString(payment.Method),
-		AmountBaht:  payment.AmountBaht,
-		Currency:    utils.DerefString(payment.Currency),
-		Provider:    utils.DerefString(payment.Provider),
-		ProviderRef: utils.DerefString(payment.ProviderRef),
-		Status:      utils.DerefString(payment.Status),
-		CreatedAt:   payment.CreatedAt,
+		ID:           payment.ID,
+		OrderID:      payment.OrderID,
+		Method:       utils.DerefString(payment.Method),
+		AmountBaht:   payment.AmountBaht,
+		Currency:     utils.DerefString(payment.Currency),
+		Provider:     utils.DerefString(payment.Provider),
+		ProviderRef:  utils.DerefString(payment.ProviderRef),
+		Status:       utils.DerefString(payment.Status),
+		CreatedAt:    payment.CreatedAt,
+		BusinessDate: businessday.Format(payment.BusinessDate),
 	}
 }
 
@@ -7,7 +7,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type permissionHandler struct {
@@ -19,11 +18,9 @@ func NewPermissionHandler(permissionUsecase domain.PermissionUsecase) *permissio
 }
 
 func (h *permissionHandler) GetAllPermissions(c *gin.Context) {
-	permissions, err := h.permissionUsecase.GetAllPermissions()
+	permissions, err := h.permissionUsecase.GetAllPermissions(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[PermissionHandler.GetAllPermissions]: Error getting permissions")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[PermissionHandler.GetAllPermissions]: Error getting permissions"))
 		return
 	}
 	c.JSON(http.StatusOK, permissions)
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type permissionMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewPermissionMemoryRepository(store *memory.Store) domain.PermissionRepository {
+	return &permissionMemoryRepository{store: store}
+}
+
+func (r *permissionMemoryRepository) GetAllPermissions(ctx context.Context) ([]*models.Permission, error) {
+	var permissions []*models.Permission
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		permissions = memory.Select(t.Permissions, nil)
+		memory.Sort(permissions, func(a, b *models.Permission) int { return strings.Compare(a.Code, b.Code) })
+		return nil
+	})
+	return permissions, err
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"gorm.io/gorm"
@@ -15,9 +18,9 @@ func NewPermissionRepository(db *gorm.DB) domain.PermissionRepository {
 	return &permissionRepository{db: db}
 }
 
-func (r *permissionRepository) GetAllPermissions() ([]*models.Permission, error) {
+func (r *permissionRepository) GetAllPermissions(ctx context.Context) ([]*models.Permission, error) {
 	var permissions []*models.Permission
-	if err := r.db.Order("code ASC").Find(&permissions).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Order("code ASC").Find(&permissions).Error; err != nil {
 		return nil, errors.Wrap(err, "[PermissionRepository.GetAllPermissions]: Error querying database")
 	}
 	return permissions, nil
@@ -1,9 +1,12 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
@@ -15,8 +18,11 @@ func NewPermissionUsecase(permissionRepository domain.PermissionRepository) doma
 	return &permissionUsecase{permissionRepository: permissionRepository}
 }
 
-func (u *permissionUsecase) GetAllPermissions() ([]*response.PermissionResponse, error) {
-	permissions, err := u.permissionRepository.GetAllPermissions()
+func (u *permissionUsecase) GetAllPermissions(ctx context.Context) (*response.Page[*response.PermissionResponse], error) {
+	ctx, span := tracing.Start(ctx, "PermissionUsecase.GetAllPermissions")
+	defer span.End()
+
+	permissions, err := u.permissionRepository.GetAllPermissions(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "[PermissionUsecase.GetAllPermissions]: Error getting permissions")
 	}
@@ -30,5 +36,5 @@ func (u *permissionUsecase) GetAllPermissions() ([]*response.PermissionResponse,
 		}
 	}
 
-	return permissionResponses, nil
+	return response.SinglePage(permissionResponses), nil
 }
@@ -0,0 +1,35 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type reportHandler struct {
+	reportUsecase domain.ReportUsecase
+}
+
+func NewReportHandler(reportUsecase domain.ReportUsecase) *reportHandler {
+	return &reportHandler{reportUsecase: reportUsecase}
+}
+
+func (h *reportHandler) GetSalesReport(c *gin.Context) {
+	var req request.SalesReportQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	report, err := h.reportUsecase.GetSalesReport(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[ReportHandler.GetSalesReport]: Error getting sales report"))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
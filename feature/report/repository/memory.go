@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type reportMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewReportMemoryRepository(store *memory.Store) domain.ReportRepository {
+	return &reportMemoryRepository{store: store}
+}
+
+// salesGroup accumulates one row of a sales report. Sales are kept unrounded
+// until the group is complete, as the SQL query rounds once per group.
+type salesGroup struct {
+	key    *string
+	label  *string
+	orders map[uuid.UUID]bool
+	gross  float64
+	sales  float64
+}
+
+func (r *reportMemoryRepository) GetSalesTotals(ctx context.Context, by string, from time.Time, to time.Time) ([]*models.SalesTotal, error) {
+	var totals []*models.SalesTotal
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		paid := memory.Select(t.Orders, func(o *models.Order) bool {
+			return utils.DerefString(o.Status) == constant.OrderStatusPaid && o.BusinessDate != nil &&
+				!o.BusinessDate.Before(from) && !o.BusinessDate.After(to)
+		})
+		orders := make(map[uuid.UUID]*models.Order, len(paid))
+		for _, order := range paid {
+			orders[order.ID] = order
+		}
+
+		groups := make(map[string]*salesGroup)
+		var order []*salesGroup
+		add := func(key *string, label *string, orderID uuid.UUID, gross float64, sales float64) {
+			g, ok := groups[utils.DerefString(key)]
+			if !ok {
+				g = &salesGroup{key: key, label: label, orders: make(map[uuid.UUID]bool)}
+				groups[utils.DerefString(key)] = g
+				order = append(order, g)
+			}
+			g.orders[orderID] = true
+			g.gross += gross
+			g.sales += sales
+		}
+
+		switch by {
+		case constant.SalesByCategory, constant.SalesByMenuItem:
+			for _, item := range t.OrderItems {
+				o, ok := orders[item.OrderID]
+				if !ok || item.CancelledAt != nil {
+					continue
+				}
+				key, label := menuItemGroup(t, by, item.MenuItemID)
+				add(key, label, o.ID, float64(item.LineTotalBaht), share(item.LineTotalBaht, o))
+			}
+		case constant.SalesByModifier:
+			for _, m := range t.OrderItemModifiers {
+				item, ok := t.OrderItems[m.OrderItemID]
+				if !ok || item.CancelledAt != nil {
+					continue
+				}
+				o, ok := orders[item.OrderID]
+				if !ok {
+					continue
+				}
+				value := utils.DerefInt64(m.PriceDeltaBaht) * int64(item.Quantity)
+				key := m.ModifierID.String()
+				var label *string
+				if modifier, ok := t.Modifiers[m.ModifierID]; ok {
+					label = modifier.Name
+				}
+				add(&key, label, o.ID, float64(value), share(value, o))
+			}
+		case constant.SalesByPaymentMethod:
+			paidBaht := make(map[uuid.UUID]int64)
+			for _, p := range t.Payments {
+				if utils.DerefString(p.Status) == constant.PaymentStatusSucceeded {
+					paidBaht[p.OrderID] += p.AmountBaht
+				}
+			}
+			for _, p := range t.Payments {
+				o, ok := orders[p.OrderID]
+				if !ok || utils.DerefString(p.Status) != constant.PaymentStatusSucceeded {
+					continue
+				}
+				var gross, sales float64
+				if whole := paidBaht[p.OrderID]; whole != 0 {
+					gross = float64(utils.DerefInt64(o.SubtotalBaht)) * float64(p.AmountBaht) / float64(whole)
+					sales = float64(utils.DerefInt64(o.TotalBaht)) * float64(p.AmountBaht) / float64(whole)
+				}
+				add(p.Method, p.Method, o.ID, gross, sales)
+			}
+		default:
+			for _, o := range paid {
+				key, label, err := orderGroup(t, by, o)
+				if err != nil {
+					return err
+				}
+				add(key, label, o.ID, float64(utils.DerefInt64(o.SubtotalBaht)), float64(utils.DerefInt64(o.TotalBaht)))
+			}
+			if by == "" && len(order) == 0 {
+				order = append(order, &salesGroup{})
+			}
+		}
+
+		if by == constant.SalesByDay || by == constant.SalesByHour {
+			sort.Slice(order, func(i, j int) bool { return utils.DerefString(order[i].key) < utils.DerefString(order[j].key) })
+		} else {
+			sort.Slice(order, func(i, j int) bool {
+				a, b := order[i], order[j]
+				if sa, sb := math.Round(a.sales), math.Round(b.sales); sa != sb {
+					return sa > sb
+				}
+				if utils.DerefString(a.label) != utils.DerefString(b.label) {
+					return utils.DerefString(a.label) < utils.DerefString(b.label)
+				}
+				return utils.DerefString(a.key) < utils.DerefString(b.key)
+			})
+		}
+
+		totals = make([]*models.SalesTotal, len(order))
+		for i, g := range order {
+			totals[i] = &models.SalesTotal{
+				Key:        g.key,
+				Label:      g.label,
+				OrderCount: int64(len(g.orders)),
+				GrossBaht:  int64(math.Round(g.gross)),
+				SalesBaht:  int64(math.Round(g.sales)),
+			}
+		}
+		return nil
+	})
+	return totals, err
+}
+
+// share is the part of o's discounted total that value of its subtotal carries;
+// an order with no subtotal has no sales to share
+func share(value int64, o *models.Order) float64 {
+	subtotal := utils.DerefInt64(o.SubtotalBaht)
+	if subtotal == 0 {
+		return 0
+	}
+	return float64(value) * float64(utils.DerefInt64(o.TotalBaht)) / float64(subtotal)
+}
+
+func menuItemGroup(t *memory.Tables, by string, menuItemID uuid.UUID) (*string, *string) {
+	menuItem, ok := t.MenuItems[menuItemID]
+	if !ok {
+		return nil, nil
+	}
+	if by == constant.SalesByMenuItem {
+		key := menuItem.ID.String()
+		return &key, menuItem.Name
+	}
+	if menuItem.CategoryID == nil {
+		return nil, nil
+	}
+	key := menuItem.CategoryID.String()
+	var label *string
+	if category, ok := t.Categories[*menuItem.CategoryID]; ok {
+		label = category.Name
+	}
+	return &key, label
+}
+
+// orderGroup is the group a whole order falls into, matching the keys and
+// labels the SQL query selects
+func orderGroup(t *memory.Tables, by string, o *models.Order) (*string, *string, error) {
+	var table *models.DiningTable
+	if o.TableID != nil {
+		if found, ok := t.DiningTables[*o.TableID]; ok {
+			table = &found
+		}
+	}
+
+	switch by {
+	case "":
+		return nil, nil, nil
+	case constant.SalesByDay:
+		key := o.BusinessDate.Format(businessday.Layout)
+		return &key, &key, nil
+	case constant.SalesByHour:
+		var key *string
+		if o.ClosedAt != nil {
+			key = utils.Ptr(o.ClosedAt.UTC().Format("2006-01-02 15"))
+		}
+		return key, key, nil
+	case constant.SalesByArea:
+		if table == nil || table.AreaID == nil {
+			return nil, nil, nil
+		}
+		key := table.AreaID.String()
+		var label *string
+		if area, ok := t.Areas[*table.AreaID]; ok {
+			label = area.Name
+		}
+		return &key, label, nil
+	case constant.SalesByTable:
+		if table == nil {
+			return nil, nil, nil
+		}
+		key := table.ID.String()
+		return &key, table.Name, nil
+	case constant.SalesByStaff:
+		if o.OpenedBy == nil {
+			return nil, nil, nil
+		}
+		key := o.OpenedBy.String()
+		var label *string
+		if user, ok := t.Users[*o.OpenedBy]; ok {
+			label = user.FullName
+		}
+		return &key, label, nil
+	case constant.SalesBySource:
+		return o.Source, o.Source, nil
+	}
+	return nil, nil, errors.Errorf("[ReportMemoryRepository.GetSalesTotals]: Unknown sales group %q", by)
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) domain.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// salesSource is the rows a group of sales reports reads, joined to their paid
+// order o, and how each row counts towards the order's gross and discounted sales
+type salesSource struct {
+	from       string
+	where      string
+	orderCount string
+	gross      string
+	sales      string
+}
+
+var (
+	// Whole orders, for the groups every order falls into once
+	orderSales = salesSource{
+		from: `orders o
+			LEFT JOIN dining_tables t ON t.id = o.table_id
+			LEFT JOIN areas a ON a.id = t.area_id
+			LEFT JOIN users u ON u.id = o.opened_by`,
+		orderCount: "COUNT(*)",
+		gross:      "COALESCE(SUM(o.subtotal_baht), 0)",
+		sales:      "COALESCE(SUM(o.total_baht), 0)",
+	}
+	// Charged items. The order's discount is spread over them by line total.
+	itemSales = salesSource{
+		from: `order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			JOIN menu_items mi ON mi.id = oi.menu_item_id
+			LEFT JOIN categories c ON c.id = mi.category_id`,
+		where:      "oi.cancelled_at IS NULL",
+		orderCount: "COUNT(DISTINCT o.id)",
+		gross:      "COALESCE(SUM(oi.line_total_baht), 0)",
+		sales:      sumShare("oi.line_total_baht", "o.total_baht", "o.subtotal_baht"),
+	}
+	// Modifiers of charged items, worth their price delta on every unit
+	modifierSales = salesSource{
+		from: `order_item_modifiers oim
+			JOIN order_items oi ON oi.id = oim.order_item_id
+			JOIN orders o ON o.id = oi.order_id
+			JOIN modifiers m ON m.id = oim.modifier_id`,
+		where:      "oi.cancelled_at IS NULL",
+		orderCount: "COUNT(DISTINCT o.id)",
+		gross:      "COALESCE(SUM(COALESCE(oim.price_delta_baht, 0) * oi.quantity), 0)",
+		sales:      sumShare("COALESCE(oim.price_delta_baht, 0) * oi.quantity", "o.total_baht", "o.subtotal_baht"),
+	}
+	// Succeeded payments. Orders paid by several methods are split by the
+	// share each payment took.
+	paymentSales = salesSource{
+		from: `payments p
+			JOIN orders o ON o.id = p.order_id
+			JOIN (
+				SELECT order_id, SUM(amount_baht) AS paid_baht
+				FROM payments
+				WHERE status = @succeeded
+				GROUP BY order_id
+			) paid ON paid.order_id = p.order_id`,
+		where:      "p.status = @succeeded",
+		orderCount: "COUNT(DISTINCT o.id)",
+		gross:      sumShare("o.subtotal_baht", "p.amount_baht", "paid.paid_baht"),
+		sales:      sumShare("o.total_baht", "p.amount_baht", "paid.paid_baht"),
+	}
+)
+
+// sumShare sums value * part / whole over the rows, rounded once per group so the
+// shares of one order are not each rounded down
+func sumShare(value string, part string, whole string) string {
+	return fmt.Sprintf("CAST(COALESCE(ROUND(SUM(%s * 1.0 * %s / NULLIF(%s, 0))), 0) AS BIGINT)", value, part, whole)
+}
+
+func (r *reportRepository) GetSalesTotals(ctx context.Context, by string, from time.Time, to time.Time) ([]*models.SalesTotal, error) {
+	db := database.Conn(ctx, r.db)
+
+	source, key, label := orderSales, "", ""
+	switch by {
+	case "":
+	case constant.SalesByDay:
+		key = formatTime(db, "o.business_date", "YYYY-MM-DD", "%Y-%m-%d")
+		label = key
+	case constant.SalesByHour:
+		key = formatTime(db, "o.closed_at", "YYYY-MM-DD HH24", "%Y-%m-%d %H")
+		label = key
+	case constant.SalesByCategory:
+		source, key, label = itemSales, "c.id", "c.name"
+	case constant.SalesByMenuItem:
+		source, key, label = itemSales, "mi.id", "mi.name"
+	case constant.SalesByModifier:
+		source, key, label = modifierSales, "m.id", "m.name"
+	case constant.SalesByPaymentMethod:
+		source, key, label = paymentSales, "p.method", "p.method"
+	case constant.SalesByArea:
+		key, label = "a.id", "a.name"
+	case constant.SalesByTable:
+		key, label = "t.id", "t.name"
+	case constant.SalesByStaff:
+		key, label = "u.id", "u.full_name"
+	case constant.SalesBySource:
+		key, label = "o.source", "o.source"
+	default:
+		return nil, errors.Errorf("[ReportRepository.GetSalesTotals]: Unknown sales group %q", by)
+	}
+
+	where := "o.status = @paid AND o.business_date >= @from AND o.business_date <= @to"
+	if source.where != "" {
+		where += " AND " + source.where
+	}
+	group, order := "NULL AS group_key, NULL AS group_label", ""
+	if key != "" {
+		group = key + " AS group_key, " + label + " AS group_label"
+		order = `
+		GROUP BY ` + key + ", " + label + `
+		ORDER BY sales_baht DESC, group_label, group_key`
+		if by == constant.SalesByDay || by == constant.SalesByHour {
+			order = `
+		GROUP BY ` + key + `
+		ORDER BY group_key`
+		}
+	}
+	query := fmt.Sprintf(`
+		SELECT %s, %s AS order_count, %s AS gross_baht, %s AS sales_baht
+		FROM %s
+		WHERE %s`, group, source.orderCount, source.gross, source.sales, source.from, where) + order
+
+	args := map[string]any{
+		"paid":      constant.OrderStatusPaid,
+		"succeeded": constant.PaymentStatusSucceeded,
+		"from":      from,
+		"to":        to,
+	}
+	var totals []*models.SalesTotal
+	if err := db.Raw(query, args).Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, "[ReportRepository.GetSalesTotals]: Error querying database")
+	}
+	return totals, nil
+}
+
+// formatTime formats a date or timestamp column as text; SQLite has strftime
+// where Postgres has to_char
+func formatTime(db *gorm.DB, column string, postgresFormat string, sqliteFormat string) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "strftime('" + sqliteFormat + "', " + column + ")"
+	}
+	return "to_char(" + column + ", '" + postgresFormat + "')"
+}
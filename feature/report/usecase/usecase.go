@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type reportUsecase struct {
+	reportRepository domain.ReportRepository
+	calendar         businessday.Calendar
+	sales            config.SalesConfig
+}
+
+func NewReportUsecase(reportRepository domain.ReportRepository, calendar businessday.Calendar, sales config.SalesConfig) domain.ReportUsecase {
+	return &reportUsecase{reportRepository: reportRepository, calendar: calendar, sales: sales}
+}
+
+// GetSalesReport sums the paid orders of a range of business dates, grouped by
+// req.By. Orders count towards the business date they were opened on. It covers
+// the current business date unless a range is given.
+func (u *reportUsecase) GetSalesReport(ctx context.Context, req *request.SalesReportQuery) (*response.SalesReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReportUsecase.GetSalesReport")
+	defer span.End()
+
+	from, to := u.calendar.Today(), u.calendar.Today()
+	if req.From != nil {
+		from = *req.From
+	}
+	if req.To != nil {
+		to = *req.To
+	}
+	if to.Before(from) {
+		return nil, errors.Wrap(domain.ValidationError("From must not be after to", map[string]string{"from": "must not be after to"}), "[ReportUsecase.GetSalesReport]")
+	}
+
+	totals, err := u.reportRepository.GetSalesTotals(ctx, "", from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ReportUsecase.GetSalesReport]: Error getting sales totals")
+	}
+	rows, err := u.reportRepository.GetSalesTotals(ctx, req.By, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ReportUsecase.GetSalesReport]: Error getting sales by "+req.By)
+	}
+	if req.By == constant.SalesByHour {
+		rows = u.byLocalHour(rows)
+	}
+
+	report := &response.SalesReportResponse{
+		From:       from.Format(businessday.Layout),
+		To:         to.Format(businessday.Layout),
+		By:         req.By,
+		VATPercent: u.sales.VATPercent,
+		Rows:       make([]response.SalesReportRowResponse, len(rows)),
+	}
+	if len(totals) > 0 {
+		report.Totals = u.buildSalesFigures(totals[0])
+	}
+	for i, row := range rows {
+		report.Rows[i] = response.SalesReportRowResponse{
+			Key:                  row.Key,
+			Label:                utils.DerefString(row.Label),
+			SalesFiguresResponse: u.buildSalesFigures(row),
+		}
+	}
+
+	return report, nil
+}
+
+// byLocalHour folds rows keyed by UTC hour into the hours of the day in the
+// restaurant's time zone, in order from the start of the business day. Zones
+// that are not a whole number of hours from UTC put each UTC hour in the local
+// hour it starts in.
+func (u *reportUsecase) byLocalHour(rows []*models.SalesTotal) []*models.SalesTotal {
+	hours := make(map[int]*models.SalesTotal)
+	for _, row := range rows {
+		at, err := time.Parse("2006-01-02 15", utils.DerefString(row.Key))
+		if err != nil {
+			continue
+		}
+		hour := at.In(u.calendar.Location()).Hour()
+		total, ok := hours[hour]
+		if !ok {
+			key := fmt.Sprintf("%02d", hour)
+			total = &models.SalesTotal{Key: &key, Label: &key}
+			hours[hour] = total
+		}
+		total.OrderCount += row.OrderCount
+		total.GrossBaht += row.GrossBaht
+		total.SalesBaht += row.SalesBaht
+	}
+
+	first := int(u.calendar.Cutoff() / time.Hour)
+	folded := make([]*models.SalesTotal, 0, len(hours))
+	for i := 0; i < 24; i++ {
+		if total, ok := hours[(first+i)%24]; ok {
+			folded = append(folded, total)
+		}
+	}
+	return folded
+}
+
+// buildSalesFigures splits what was charged into VAT and net sales. VAT is taken
+// from the group's total rather than from each order, so the VAT of the rows
+// may differ from the report's by a baht of rounding.
+func (u *reportUsecase) buildSalesFigures(total *models.SalesTotal) response.SalesFiguresResponse {
+	vat := int64(math.Round(float64(total.SalesBaht) * u.sales.VATPercent / (100 + u.sales.VATPercent)))
+	figures := response.SalesFiguresResponse{
+		OrderCount:   total.OrderCount,
+		GrossBaht:    total.GrossBaht,
+		DiscountBaht: total.GrossBaht - total.SalesBaht,
+		VATBaht:      vat,
+		NetBaht:      total.SalesBaht - vat,
+	}
+	if total.OrderCount > 0 {
+		figures.AverageTicketBaht = int64(math.Round(float64(total.SalesBaht) / float64(total.OrderCount)))
+	}
+	return figures
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type roleHandler struct {
@@ -20,11 +19,9 @@ func NewRoleHandler(roleUsecase domain.RoleUsecase) *roleHandler {
 }
 
 func (h *roleHandler) GetAllRoles(c *gin.Context) {
-	roles, err := h.roleUsecase.GetAllRoles()
+	roles, err := h.roleUsecase.GetAllRoles(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[RoleHandler.GetAllRoles]: Error getting roles")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[RoleHandler.GetAllRoles]: Error getting roles"))
 		return
 	}
 	c.JSON(http.StatusOK, roles)
@@ -33,15 +30,13 @@ func (h *roleHandler) GetAllRoles(c *gin.Context) {
 func (h *roleHandler) GetRoleWithPermissions(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid role ID", nil))
 		return
 	}
 
-	role, err := h.roleUsecase.GetRoleWithPermissions(id)
+	role, err := h.roleUsecase.GetRoleWithPermissions(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[RoleHandler.GetRoleWithPermissions]: Error getting role")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[RoleHandler.GetRoleWithPermissions]: Error getting role"))
 		return
 	}
 	c.JSON(http.StatusOK, role)
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type roleMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewRoleMemoryRepository(store *memory.Store) domain.RoleRepository {
+	return &roleMemoryRepository{store: store}
+}
+
+func (r *roleMemoryRepository) GetAllRoles(ctx context.Context) ([]*models.Role, error) {
+	var roles []*models.Role
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		roles = memory.Select(t.Roles, nil)
+		memory.Sort(roles, func(a, b *models.Role) int { return strings.Compare(a.Name, b.Name) })
+		return nil
+	})
+	return roles, err
+}
+
+func (r *roleMemoryRepository) GetRoleWithPermissions(ctx context.Context, id int) (*models.Role, error) {
+	var role models.Role
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Roles[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Role not found"), "[RoleMemoryRepository.GetRoleWithPermissions]")
+		}
+		role = found
+		role.Permissions = t.PermissionsOfRole(id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
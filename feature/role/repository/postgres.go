@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"gorm.io/gorm"
@@ -15,19 +18,19 @@ func NewRoleRepository(db *gorm.DB) domain.RoleRepository {
 	return &roleRepository{db: db}
 }
 
-func (r *roleRepository) GetAllRoles() ([]*models.Role, error) {
+func (r *roleRepository) GetAllRoles(ctx context.Context) ([]*models.Role, error) {
 	var roles []*models.Role
-	if err := r.db.Order("name ASC").Find(&roles).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Order("name ASC").Find(&roles).Error; err != nil {
 		return nil, errors.Wrap(err, "[RoleRepository.GetAllRoles]: Error querying database")
 	}
 	return roles, nil
 }
 
-func (r *roleRepository) GetRoleWithPermissions(id int) (*models.Role, error) {
+func (r *roleRepository) GetRoleWithPermissions(ctx context.Context, id int) (*models.Role, error) {
 	var role models.Role
-	if err := r.db.Preload("Permissions").Where("id = ?", id).First(&role).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("Permissions").Where("id = ?", id).First(&role).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[RoleRepository.GetRoleWithPermissions]: Role not found")
+			return nil, errors.Wrap(domain.NotFoundError("Role not found"), "[RoleRepository.GetRoleWithPermissions]")
 		}
 		return nil, errors.Wrap(err, "[RoleRepository.GetRoleWithPermissions]: Error querying database")
 	}
@@ -1,9 +1,12 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
@@ -15,8 +18,11 @@ func NewRoleUsecase(roleRepository domain.RoleRepository) domain.RoleUsecase {
 	return &roleUsecase{roleRepository: roleRepository}
 }
 
-func (u *roleUsecase) GetAllRoles() ([]*response.RoleResponse, error) {
-	roles, err := u.roleRepository.GetAllRoles()
+func (u *roleUsecase) GetAllRoles(ctx context.Context) (*response.Page[*response.RoleResponse], error) {
+	ctx, span := tracing.Start(ctx, "RoleUsecase.GetAllRoles")
+	defer span.End()
+
+	roles, err := u.roleRepository.GetAllRoles(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "[RoleUsecase.GetAllRoles]: Error getting roles")
 	}
@@ -29,11 +35,14 @@ func (u *roleUsecase) GetAllRoles() ([]*response.RoleResponse, error) {
 		}
 	}
 
-	return roleResponses, nil
+	return response.SinglePage(roleResponses), nil
 }
 
-func (u *roleUsecase) GetRoleWithPermissions(id int) (*response.RoleResponse, error) {
-	role, err := u.roleRepository.GetRoleWithPermissions(id)
+func (u *roleUsecase) GetRoleWithPermissions(ctx context.Context, id int) (*response.RoleResponse, error) {
+	ctx, span := tracing.Start(ctx, "RoleUsecase.GetRoleWithPermissions")
+	defer span.End()
+
+	role, err := u.roleRepository.GetRoleWithPermissions(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[RoleUsecase.GetRoleWithPermissions]: Error getting role")
 	}
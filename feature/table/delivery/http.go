@@ -8,8 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type tableHandler struct {
@@ -21,11 +21,9 @@ func NewTableHandler(tableUsecase domain.TableUsecase) *tableHandler {
 }
 
 func (h *tableHandler) GetAllTables(c *gin.Context) {
-	tables, err := h.tableUsecase.GetAllTables()
+	tables, err := h.tableUsecase.GetAllTables(c.Request.Context())
 	if err != nil {
-		err = errors.Wrap(err, "[TableHandler.GetAllTables]: Error getting tables")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[TableHandler.GetAllTables]: Error getting tables"))
 		return
 	}
 	c.JSON(http.StatusOK, tables)
@@ -34,15 +32,13 @@ func (h *tableHandler) GetAllTables(c *gin.Context) {
 func (h *tableHandler) GetTableByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid table ID", nil))
 		return
 	}
 
-	table, err := h.tableUsecase.GetTableByID(id)
+	table, err := h.tableUsecase.GetTableByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[TableHandler.GetTableByID]: Error getting table")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[TableHandler.GetTableByID]: Error getting table"))
 		return
 	}
 	c.JSON(http.StatusOK, table)
@@ -51,21 +47,19 @@ func (h *tableHandler) GetTableByID(c *gin.Context) {
 func (h *tableHandler) UpdateTableStatus(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid table ID", nil))
 		return
 	}
 
 	var req request.UpdateTableStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	if err := h.tableUsecase.UpdateTableStatus(id, req.Status); err != nil {
-		err = errors.Wrap(err, "[TableHandler.UpdateTableStatus]: Error updating table status")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+	if err := h.tableUsecase.UpdateTableStatus(c.Request.Context(), id, req.Status); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[TableHandler.UpdateTableStatus]: Error updating table status"))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Table status updated successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Table status updated successfully"})
 }
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+)
+
+type tableMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewTableMemoryRepository(store *memory.Store) domain.TableRepository {
+	return &tableMemoryRepository{store: store}
+}
+
+func (r *tableMemoryRepository) GetAllTables(ctx context.Context) ([]*models.DiningTable, error) {
+	var tablesList []*models.DiningTable
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		tablesList = memory.Select(t.DiningTables, nil)
+		for _, table := range tablesList {
+			preloadArea(t, table)
+		}
+		memory.Sort(tablesList, func(a, b *models.DiningTable) int { return memory.CompareString(a.Name, b.Name) })
+		return nil
+	})
+	return tablesList, err
+}
+
+func (r *tableMemoryRepository) GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error) {
+	var table models.DiningTable
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.DiningTables[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Table not found"), "[TableMemoryRepository.GetTableByID]")
+		}
+		table = found
+		preloadArea(t, &table)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *tableMemoryRepository) UpdateTable(ctx context.Context, table *models.DiningTable) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.DiningTables, func(other *models.DiningTable) bool {
+			return other.ID != table.ID && memory.EqualString(other.QRSlug, table.QRSlug)
+		}) {
+			return errors.Wrap(domain.ConflictError("A table with this QR slug already exists"), "[TableMemoryRepository.UpdateTable]")
+		}
+		if table.AreaID != nil {
+			if _, ok := t.Areas[*table.AreaID]; !ok {
+				return errors.Wrap(gorm.ErrForeignKeyViolated, "[TableMemoryRepository.UpdateTable]: Area does not exist")
+			}
+		}
+
+		row := *table
+		row.Area = nil
+		row.Orders = nil
+		t.DiningTables[row.ID] = row
+		return nil
+	})
+}
+
+func preloadArea(t *memory.Tables, table *models.DiningTable) {
+	table.Area = nil
+	table.Orders = nil
+	if table.AreaID == nil {
+		return
+	}
+	if area, ok := t.Areas[*table.AreaID]; ok {
+		table.Area = &area
+	}
+}
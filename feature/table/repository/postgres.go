@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
 	"gorm.io/gorm"
@@ -16,27 +19,30 @@ func NewTableRepository(db *gorm.DB) domain.TableRepository {
 	return &tableRepository{db: db}
 }
 
-func (r *tableRepository) GetAllTables() ([]*models.DiningTable, error) {
+func (r *tableRepository) GetAllTables(ctx context.Context) ([]*models.DiningTable, error) {
 	var tablesList []*models.DiningTable
-	if err := r.db.Preload("Area").Order("name ASC").Find(&tablesList).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("Area").Order("name ASC").Find(&tablesList).Error; err != nil {
 		return nil, errors.Wrap(err, "[TableRepository.GetAllTables]: Error getting tables")
 	}
 	return tablesList, nil
 }
 
-func (r *tableRepository) GetTableByID(id uuid.UUID) (*models.DiningTable, error) {
+func (r *tableRepository) GetTableByID(ctx context.Context, id uuid.UUID) (*models.DiningTable, error) {
 	var table models.DiningTable
-	if err := r.db.Preload("Area").Where("id = ?", id).First(&table).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("Area").Where("id = ?", id).First(&table).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[TableRepository.GetTableByID]: Table not found")
+			return nil, errors.Wrap(domain.NotFoundError("Table not found"), "[TableRepository.GetTableByID]")
 		}
 		return nil, errors.Wrap(err, "[TableRepository.GetTableByID]: Error querying database")
 	}
 	return &table, nil
 }
 
-func (r *tableRepository) UpdateTable(table *models.DiningTable) error {
-	if err := r.db.Save(table).Error; err != nil {
+func (r *tableRepository) UpdateTable(ctx context.Context, table *models.DiningTable) error {
+	if err := database.Conn(ctx, r.db).Save(table).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A table with this QR slug already exists"), "[TableRepository.UpdateTable]")
+		}
 		return errors.Wrap(err, "[TableRepository.UpdateTable]: Error updating table")
 	}
 	return nil
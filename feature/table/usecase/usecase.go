@@ -1,71 +1,90 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"github.com/pubestpubest/pos-backend/utils"
 )
 
 type tableUsecase struct {
 	tableRepository domain.TableRepository
+	transactor      domain.Transactor
+	auditUsecase    domain.AuditUsecase
 }
 
-func NewTableUsecase(tableRepository domain.TableRepository) domain.TableUsecase {
-	return &tableUsecase{tableRepository: tableRepository}
+func NewTableUsecase(tableRepository domain.TableRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.TableUsecase {
+	return &tableUsecase{tableRepository: tableRepository, transactor: transactor, auditUsecase: auditUsecase}
 }
 
-func (u *tableUsecase) GetAllTables() ([]*response.TableResponse, error) {
-	tables, err := u.tableRepository.GetAllTables()
+func (u *tableUsecase) GetAllTables(ctx context.Context) (*response.Page[*response.TableResponse], error) {
+	ctx, span := tracing.Start(ctx, "TableUsecase.GetAllTables")
+	defer span.End()
+
+	tables, err := u.tableRepository.GetAllTables(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "[TableUsecase.GetAllTables]: Error getting tables")
 	}
 
 	tableResponses := make([]*response.TableResponse, len(tables))
 	for i, table := range tables {
-		tableResponses[i] = &response.TableResponse{
-			ID:     table.ID,
-			Name:   utils.DerefString(table.Name),
-			Seats:  utils.DerefInt(table.Seats),
-			Status: utils.DerefString(table.Status),
-			QRCode: utils.DerefString(table.QRSlug),
-			AreaID: utils.DerefUUID(table.AreaID),
-		}
+		tableResponses[i] = u.buildTableResponse(table)
 	}
 
-	return tableResponses, nil
+	return response.SinglePage(tableResponses), nil
 }
 
-func (u *tableUsecase) GetTableByID(id uuid.UUID) (*response.TableResponse, error) {
-	table, err := u.tableRepository.GetTableByID(id)
+func (u *tableUsecase) GetTableByID(ctx context.Context, id uuid.UUID) (*response.TableResponse, error) {
+	ctx, span := tracing.Start(ctx, "TableUsecase.GetTableByID", tracing.TableID(id))
+	defer span.End()
+
+	table, err := u.tableRepository.GetTableByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[TableUsecase.GetTableByID]: Error getting table")
 	}
 
-	return &response.TableResponse{
-		ID:     table.ID,
-		Name:   utils.DerefString(table.Name),
-		Seats:  utils.DerefInt(table.Seats),
-		Status: utils.DerefString(table.Status),
-		QRCode: utils.DerefString(table.QRSlug),
-		AreaID: utils.DerefUUID(table.AreaID),
-	}, nil
+	return u.buildTableResponse(table), nil
 }
 
-func (u *tableUsecase) UpdateTableStatus(id uuid.UUID, status string) error {
+func (u *tableUsecase) UpdateTableStatus(ctx context.Context, id uuid.UUID, status string) error {
+	ctx, span := tracing.Start(ctx, "TableUsecase.UpdateTableStatus", tracing.TableID(id))
+	defer span.End()
+
 	// Get existing table
-	table, err := u.tableRepository.GetTableByID(id)
+	table, err := u.tableRepository.GetTableByID(ctx, id)
 	if err != nil {
 		return errors.Wrap(err, "[TableUsecase.UpdateTableStatus]: Table not found")
 	}
+	before := u.buildTableResponse(table)
 
 	// Update status
 	table.Status = &status
 
-	if err := u.tableRepository.UpdateTable(table); err != nil {
-		return errors.Wrap(err, "[TableUsecase.UpdateTableStatus]: Error updating table status")
-	}
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.tableRepository.UpdateTable(ctx, table); err != nil {
+			return errors.Wrap(err, "[TableUsecase.UpdateTableStatus]: Error updating table status")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdateStatus, constant.AuditEntityTable, table.ID.String(), before, u.buildTableResponse(table)); err != nil {
+			return errors.Wrap(err, "[TableUsecase.UpdateTableStatus]: Error recording audit log")
+		}
+		return nil
+	})
+}
 
-	return nil
+// Helper function to build table response
+func (u *tableUsecase) buildTableResponse(table *models.DiningTable) *response.TableResponse {
+	return &response.TableResponse{
+		ID:     table.ID,
+		Name:   utils.DerefString(table.Name),
+		Seats:  utils.DerefInt(table.Seats),
+		Status: utils.DerefString(table.Status),
+		QRCode: utils.DerefString(table.QRSlug),
+		AreaID: utils.DerefUUID(table.AreaID),
+	}
 }
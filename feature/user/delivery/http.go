@@ -8,8 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 	"github.com/pubestpubest/pos-backend/utils"
-	log "github.com/sirupsen/logrus"
 )
 
 type userHandler struct {
@@ -21,11 +21,15 @@ func NewUserHandler(userUsecase domain.UserUsecase) *userHandler {
 }
 
 func (h *userHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.userUsecase.GetAllUsers()
+	var req request.UserListQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid query parameters"))
+		return
+	}
+
+	users, err := h.userUsecase.GetAllUsers(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[UserHandler.GetAllUsers]: Error getting users")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[UserHandler.GetAllUsers]: Error getting users"))
 		return
 	}
 	c.JSON(http.StatusOK, users)
@@ -34,15 +38,13 @@ func (h *userHandler) GetAllUsers(c *gin.Context) {
 func (h *userHandler) GetUserByID(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid user ID", nil))
 		return
 	}
 
-	user, err := h.userUsecase.GetUserByID(id)
+	user, err := h.userUsecase.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		err = errors.Wrap(err, "[UserHandler.GetUserByID]: Error getting user")
-		log.Warn(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[UserHandler.GetUserByID]: Error getting user"))
 		return
 	}
 	c.JSON(http.StatusOK, user)
@@ -51,15 +53,13 @@ func (h *userHandler) GetUserByID(c *gin.Context) {
 func (h *userHandler) CreateUser(c *gin.Context) {
 	var req request.UserCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	user, err := h.userUsecase.CreateUser(&req)
+	user, err := h.userUsecase.CreateUser(c.Request.Context(), &req)
 	if err != nil {
-		err = errors.Wrap(err, "[UserHandler.CreateUser]: Error creating user")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[UserHandler.CreateUser]: Error creating user"))
 		return
 	}
 	c.JSON(http.StatusCreated, user)
@@ -68,21 +68,19 @@ func (h *userHandler) CreateUser(c *gin.Context) {
 func (h *userHandler) UpdateUser(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid user ID", nil))
 		return
 	}
 
 	var req request.UserUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	user, err := h.userUsecase.UpdateUser(id, &req)
+	user, err := h.userUsecase.UpdateUser(c.Request.Context(), id, &req)
 	if err != nil {
-		err = errors.Wrap(err, "[UserHandler.UpdateUser]: Error updating user")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+		utils.RenderError(c, errors.Wrap(err, "[UserHandler.UpdateUser]: Error updating user"))
 		return
 	}
 	c.JSON(http.StatusOK, user)
@@ -91,21 +89,19 @@ func (h *userHandler) UpdateUser(c *gin.Context) {
 func (h *userHandler) AssignRole(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		utils.RenderError(c, domain.ValidationError("Invalid user ID", nil))
 		return
 	}
 
 	var req request.AssignRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
 		return
 	}
 
-	if err := h.userUsecase.AssignRoleToUser(id, req.RoleID); err != nil {
-		err = errors.Wrap(err, "[UserHandler.AssignRole]: Error assigning role")
-		log.Warn(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.StandardError(err)})
+	if err := h.userUsecase.AssignRoleToUser(c.Request.Context(), id, req.RoleID); err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[UserHandler.AssignRole]: Error assigning role"))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Role assigned successfully"})
+	c.JSON(http.StatusOK, response.MessageResponse{Message: "Role assigned successfully"})
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+)
+
+// userKeys are the fields users can be sorted by
+var userKeys = pagination.Keys[models.User]{
+	IDColumn: "id",
+	ID:       func(u *models.User) uuid.UUID { return u.ID },
+	Sorts: map[string]pagination.Key[models.User]{
+		"username":   {Expr: "username", Value: func(u *models.User) any { return u.Username }},
+		"created_at": {Expr: "created_at", Value: func(u *models.User) any { return u.CreatedAt }},
+	},
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+	"gorm.io/gorm"
+)
+
+type userMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewUserMemoryRepository(store *memory.Store) domain.UserRepository {
+	return &userMemoryRepository{store: store}
+}
+
+func (r *userMemoryRepository) GetUsers(ctx context.Context, query *request.UserListQuery, page domain.PageParams) (domain.Page[models.User], error) {
+	var users domain.Page[models.User]
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		rows := memory.Select(t.Users, func(u *models.User) bool {
+			return query.Status == "" || utils.DerefString(u.Status) == query.Status
+		})
+
+		var err error
+		users, err = pagination.Slice(rows, page, userKeys)
+		return errors.Wrap(err, "[UserMemoryRepository.GetUsers]: Error getting users")
+	})
+	return users, err
+}
+
+func (r *userMemoryRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Users[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("User not found"), "[UserMemoryRepository.GetUserByID]")
+		}
+		user = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userMemoryRepository) CreateUser(ctx context.Context, user *models.User) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.Users, func(other *models.User) bool { return other.Username == user.Username }) {
+			return errors.Wrap(domain.ConflictError("A user with this username already exists"), "[UserMemoryRepository.CreateUser]")
+		}
+
+		if user.ID == uuid.Nil {
+			user.ID = uuid.New()
+		}
+		now := time.Now()
+		if user.CreatedAt.IsZero() {
+			user.CreatedAt = now
+		}
+		if user.UpdatedAt.IsZero() {
+			user.UpdatedAt = now
+		}
+		row := *user
+		row.Roles = nil
+		t.Users[row.ID] = row
+		return nil
+	})
+}
+
+func (r *userMemoryRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if memory.Any(t.Users, func(other *models.User) bool {
+			return other.ID != user.ID && other.Username == user.Username
+		}) {
+			return errors.Wrap(gorm.ErrDuplicatedKey, "[UserMemoryRepository.UpdateUser]: Error updating user")
+		}
+
+		user.UpdatedAt = time.Now()
+		row := *user
+		row.Roles = nil
+		t.Users[row.ID] = row
+		return nil
+	})
+}
+
+func (r *userMemoryRepository) GetUserWithRoles(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.Users[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("User not found"), "[UserMemoryRepository.GetUserWithRoles]")
+		}
+		user = found
+		user.Roles = t.RolesOfUser(id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userMemoryRepository) AssignRole(ctx context.Context, userRole *models.UserRole) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		key := memory.UserRoleKey{UserID: userRole.UserID, RoleID: userRole.RoleID}
+		if _, ok := t.UserRoles[key]; ok {
+			return errors.Wrap(domain.ConflictError("User already has this role"), "[UserMemoryRepository.AssignRole]")
+		}
+		_, userExists := t.Users[userRole.UserID]
+		_, roleExists := t.Roles[userRole.RoleID]
+		if !userExists || !roleExists {
+			return errors.Wrap(gorm.ErrForeignKeyViolated, "[UserMemoryRepository.AssignRole]: Error assigning role")
+		}
+
+		t.UserRoles[key] = models.UserRole{UserID: userRole.UserID, RoleID: userRole.RoleID}
+		return nil
+	})
+}
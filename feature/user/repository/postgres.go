@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
+	"github.com/pubestpubest/pos-backend/request"
 	"gorm.io/gorm"
 )
 
@@ -16,52 +21,63 @@ func NewUserRepository(db *gorm.DB) domain.UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) GetAllUsers() ([]*models.User, error) {
-	var users []*models.User
-	if err := r.db.Order("username ASC").Find(&users).Error; err != nil {
-		return nil, errors.Wrap(err, "[UserRepository.GetAllUsers]: Error getting users")
+func (r *userRepository) GetUsers(ctx context.Context, query *request.UserListQuery, page domain.PageParams) (domain.Page[models.User], error) {
+	db := database.Conn(ctx, r.db)
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	users, err := pagination.Find(db, page, userKeys)
+	if err != nil {
+		return domain.Page[models.User]{}, errors.Wrap(err, "[UserRepository.GetUsers]: Error getting users")
 	}
 	return users, nil
 }
 
-func (r *userRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
+func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("id = ?", id).First(&user).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[UserRepository.GetUserByID]: User not found")
+			return nil, errors.Wrap(domain.NotFoundError("User not found"), "[UserRepository.GetUserByID]")
 		}
 		return nil, errors.Wrap(err, "[UserRepository.GetUserByID]: Error querying database")
 	}
 	return &user, nil
 }
 
-func (r *userRepository) CreateUser(user *models.User) error {
-	if err := r.db.Create(user).Error; err != nil {
+func (r *userRepository) CreateUser(ctx context.Context, user *models.User) error {
+	if err := database.Conn(ctx, r.db).Create(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A user with this username already exists"), "[UserRepository.CreateUser]")
+		}
 		return errors.Wrap(err, "[UserRepository.CreateUser]: Error creating user")
 	}
 	return nil
 }
 
-func (r *userRepository) UpdateUser(user *models.User) error {
-	if err := r.db.Save(user).Error; err != nil {
+func (r *userRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	if err := database.Conn(ctx, r.db).Save(user).Error; err != nil {
 		return errors.Wrap(err, "[UserRepository.UpdateUser]: Error updating user")
 	}
 	return nil
 }
 
-func (r *userRepository) GetUserWithRoles(id uuid.UUID) (*models.User, error) {
+func (r *userRepository) GetUserWithRoles(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.Preload("Roles").Where("id = ?", id).First(&user).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Preload("Roles").Where("id = ?", id).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.Wrap(err, "[UserRepository.GetUserWithRoles]: User not found")
+			return nil, errors.Wrap(domain.NotFoundError("User not found"), "[UserRepository.GetUserWithRoles]")
 		}
 		return nil, errors.Wrap(err, "[UserRepository.GetUserWithRoles]: Error querying database")
 	}
 	return &user, nil
 }
 
-func (r *userRepository) AssignRole(userRole *models.UserRole) error {
-	if err := r.db.Create(userRole).Error; err != nil {
+func (r *userRepository) AssignRole(ctx context.Context, userRole *models.UserRole) error {
+	if err := database.Conn(ctx, r.db).Create(userRole).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("User already has this role"), "[UserRepository.AssignRole]")
+		}
 		return errors.Wrap(err, "[UserRepository.AssignRole]: Error assigning role")
 	}
 	return nil
@@ -1,61 +1,67 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
 	"github.com/pubestpubest/pos-backend/domain"
 	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/pagination"
 	"github.com/pubestpubest/pos-backend/request"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userUsecase struct {
 	userRepository domain.UserRepository
+	transactor     domain.Transactor
+	auditUsecase   domain.AuditUsecase
 }
 
-func NewUserUsecase(userRepository domain.UserRepository) domain.UserUsecase {
-	return &userUsecase{userRepository: userRepository}
+func NewUserUsecase(userRepository domain.UserRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.UserUsecase {
+	return &userUsecase{userRepository: userRepository, transactor: transactor, auditUsecase: auditUsecase}
 }
 
-func (u *userUsecase) GetAllUsers() ([]*response.UserResponse, error) {
-	users, err := u.userRepository.GetAllUsers()
+func (u *userUsecase) GetAllUsers(ctx context.Context, query *request.UserListQuery) (*response.Page[*response.UserResponse], error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.GetAllUsers")
+	defer span.End()
+
+	page, err := pagination.Parse(query.PageQuery, query.Sort, "username")
+	if err != nil {
+		return nil, errors.Wrap(err, "[UserUsecase.GetAllUsers]: Invalid page")
+	}
+	users, err := u.userRepository.GetUsers(ctx, query, page)
 	if err != nil {
 		return nil, errors.Wrap(err, "[UserUsecase.GetAllUsers]: Error getting users")
 	}
 
-	userResponses := make([]*response.UserResponse, len(users))
-	for i, user := range users {
-		userResponses[i] = &response.UserResponse{
-			ID:       user.ID,
-			Username: user.Username,
-			FullName: user.FullName,
-			Email:    user.Email,
-			Phone:    user.Phone,
-			Status:   user.Status,
-		}
+	userResponses := make([]*response.UserResponse, len(users.Items))
+	for i, user := range users.Items {
+		userResponses[i] = u.buildUserResponse(user)
 	}
 
-	return userResponses, nil
+	return &response.Page[*response.UserResponse]{Items: userResponses, NextCursor: users.NextCursor}, nil
 }
 
-func (u *userUsecase) GetUserByID(id uuid.UUID) (*response.UserResponse, error) {
-	user, err := u.userRepository.GetUserByID(id)
+func (u *userUsecase) GetUserByID(ctx context.Context, id uuid.UUID) (*response.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.GetUserByID")
+	defer span.End()
+
+	user, err := u.userRepository.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[UserUsecase.GetUserByID]: Error getting user")
 	}
 
-	return &response.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		FullName: user.FullName,
-		Email:    user.Email,
-		Phone:    user.Phone,
-		Status:   user.Status,
-	}, nil
+	return u.buildUserResponse(user), nil
 }
 
-func (u *userUsecase) CreateUser(req *request.UserCreateRequest) (*response.UserResponse, error) {
+func (u *userUsecase) CreateUser(ctx context.Context, req *request.UserCreateRequest) (*response.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.CreateUser")
+	defer span.End()
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -71,26 +77,32 @@ func (u *userUsecase) CreateUser(req *request.UserCreateRequest) (*response.User
 		Status:       req.Status,
 	}
 
-	if err := u.userRepository.CreateUser(user); err != nil {
-		return nil, errors.Wrap(err, "[UserUsecase.CreateUser]: Error creating user")
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.userRepository.CreateUser(ctx, user); err != nil {
+			return errors.Wrap(err, "[UserUsecase.CreateUser]: Error creating user")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityUser, user.ID.String(), nil, u.buildUserResponse(user)); err != nil {
+			return errors.Wrap(err, "[UserUsecase.CreateUser]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		FullName: user.FullName,
-		Email:    user.Email,
-		Phone:    user.Phone,
-		Status:   user.Status,
-	}, nil
+	return u.buildUserResponse(user), nil
 }
 
-func (u *userUsecase) UpdateUser(id uuid.UUID, req *request.UserUpdateRequest) (*response.UserResponse, error) {
+func (u *userUsecase) UpdateUser(ctx context.Context, id uuid.UUID, req *request.UserUpdateRequest) (*response.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserUsecase.UpdateUser")
+	defer span.End()
+
 	// Get existing user
-	user, err := u.userRepository.GetUserByID(id)
+	user, err := u.userRepository.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, errors.Wrap(err, "[UserUsecase.UpdateUser]: User not found")
 	}
+	before := u.buildUserResponse(user)
 
 	// Update fields
 	if req.FullName != nil {
@@ -106,23 +118,28 @@ func (u *userUsecase) UpdateUser(id uuid.UUID, req *request.UserUpdateRequest) (
 		user.Status = req.Status
 	}
 
-	if err := u.userRepository.UpdateUser(user); err != nil {
-		return nil, errors.Wrap(err, "[UserUsecase.UpdateUser]: Error updating user")
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.userRepository.UpdateUser(ctx, user); err != nil {
+			return errors.Wrap(err, "[UserUsecase.UpdateUser]: Error updating user")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityUser, user.ID.String(), before, u.buildUserResponse(user)); err != nil {
+			return errors.Wrap(err, "[UserUsecase.UpdateUser]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &response.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		FullName: user.FullName,
-		Email:    user.Email,
-		Phone:    user.Phone,
-		Status:   user.Status,
-	}, nil
+	return u.buildUserResponse(user), nil
 }
 
-func (u *userUsecase) AssignRoleToUser(userID uuid.UUID, roleID int) error {
+func (u *userUsecase) AssignRoleToUser(ctx context.Context, userID uuid.UUID, roleID int) error {
+	ctx, span := tracing.Start(ctx, "UserUsecase.AssignRoleToUser")
+	defer span.End()
+
 	// Check if user exists
-	_, err := u.userRepository.GetUserByID(userID)
+	_, err := u.userRepository.GetUserByID(ctx, userID)
 	if err != nil {
 		return errors.Wrap(err, "[UserUsecase.AssignRoleToUser]: User not found")
 	}
@@ -132,9 +149,26 @@ func (u *userUsecase) AssignRoleToUser(userID uuid.UUID, roleID int) error {
 		RoleID: roleID,
 	}
 
-	if err := u.userRepository.AssignRole(userRole); err != nil {
-		return errors.Wrap(err, "[UserUsecase.AssignRoleToUser]: Error assigning role")
-	}
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.userRepository.AssignRole(ctx, userRole); err != nil {
+			return errors.Wrap(err, "[UserUsecase.AssignRoleToUser]: Error assigning role")
+		}
+		after := map[string]int{"role_id": roleID}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionAssignRole, constant.AuditEntityUser, userID.String(), nil, after); err != nil {
+			return errors.Wrap(err, "[UserUsecase.AssignRoleToUser]: Error recording audit log")
+		}
+		return nil
+	})
+}
 
-	return nil
+// Helper function to build user response
+func (u *userUsecase) buildUserResponse(user *models.User) *response.UserResponse {
+	return &response.UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Phone:    user.Phone,
+		Status:   user.Status,
+	}
 }
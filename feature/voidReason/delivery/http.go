@@ -0,0 +1,65 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type voidReasonHandler struct {
+	voidReasonUsecase domain.VoidReasonUsecase
+}
+
+func NewVoidReasonHandler(voidReasonUsecase domain.VoidReasonUsecase) *voidReasonHandler {
+	return &voidReasonHandler{voidReasonUsecase: voidReasonUsecase}
+}
+
+func (h *voidReasonHandler) GetAllVoidReasons(c *gin.Context) {
+	reasons, err := h.voidReasonUsecase.GetAllVoidReasons(c.Request.Context())
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[VoidReasonHandler.GetAllVoidReasons]: Error getting void reasons"))
+		return
+	}
+	c.JSON(http.StatusOK, reasons)
+}
+
+func (h *voidReasonHandler) CreateVoidReason(c *gin.Context) {
+	var req request.VoidReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	reason, err := h.voidReasonUsecase.CreateVoidReason(c.Request.Context(), &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[VoidReasonHandler.CreateVoidReason]: Error creating void reason"))
+		return
+	}
+	c.JSON(http.StatusCreated, reason)
+}
+
+func (h *voidReasonHandler) UpdateVoidReason(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RenderError(c, domain.ValidationError("Invalid void reason ID", nil))
+		return
+	}
+
+	var req request.VoidReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RenderError(c, utils.BindingError(err, "Invalid request body"))
+		return
+	}
+
+	reason, err := h.voidReasonUsecase.UpdateVoidReason(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.RenderError(c, errors.Wrap(err, "[VoidReasonHandler.UpdateVoidReason]: Error updating void reason"))
+		return
+	}
+	c.JSON(http.StatusOK, reason)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+type voidReasonMemoryRepository struct {
+	store *memory.Store
+}
+
+func NewVoidReasonMemoryRepository(store *memory.Store) domain.VoidReasonRepository {
+	return &voidReasonMemoryRepository{store: store}
+}
+
+func (r *voidReasonMemoryRepository) GetAllVoidReasons(ctx context.Context) ([]*models.VoidReason, error) {
+	var reasons []*models.VoidReason
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		reasons = memory.Select(t.VoidReasons, nil)
+		memory.Sort(reasons,
+			func(a, b *models.VoidReason) int { return memory.CompareInt(a.DisplayOrder, b.DisplayOrder) },
+			func(a, b *models.VoidReason) int { return strings.Compare(a.Code, b.Code) },
+		)
+		return nil
+	})
+	return reasons, err
+}
+
+func (r *voidReasonMemoryRepository) GetVoidReasonByID(ctx context.Context, id uuid.UUID) (*models.VoidReason, error) {
+	var reason models.VoidReason
+	err := r.store.Read(ctx, func(t *memory.Tables) error {
+		found, ok := t.VoidReasons[id]
+		if !ok {
+			return errors.Wrap(domain.NotFoundError("Void reason not found"), "[VoidReasonMemoryRepository.GetVoidReasonByID]")
+		}
+		reason = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reason, nil
+}
+
+func (r *voidReasonMemoryRepository) CreateVoidReason(ctx context.Context, reason *models.VoidReason) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		if reason.ID == uuid.Nil {
+			reason.ID = uuid.New()
+		}
+		if reason.Active == nil {
+			active := true
+			reason.Active = &active
+		}
+		return r.save(t, reason, "[VoidReasonMemoryRepository.CreateVoidReason]")
+	})
+}
+
+func (r *voidReasonMemoryRepository) UpdateVoidReason(ctx context.Context, reason *models.VoidReason) error {
+	return r.store.Write(ctx, func(t *memory.Tables) error {
+		return r.save(t, reason, "[VoidReasonMemoryRepository.UpdateVoidReason]")
+	})
+}
+
+func (r *voidReasonMemoryRepository) save(t *memory.Tables, reason *models.VoidReason, op string) error {
+	if memory.Any(t.VoidReasons, func(other *models.VoidReason) bool {
+		return other.ID != reason.ID && other.Code == reason.Code
+	}) {
+		return errors.Wrap(domain.ConflictError("A void reason with this code already exists"), op)
+	}
+
+	t.VoidReasons[reason.ID] = *reason
+	return nil
+}
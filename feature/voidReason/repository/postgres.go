@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+)
+
+type voidReasonRepository struct {
+	db *gorm.DB
+}
+
+func NewVoidReasonRepository(db *gorm.DB) domain.VoidReasonRepository {
+	return &voidReasonRepository{db: db}
+}
+
+func (r *voidReasonRepository) GetAllVoidReasons(ctx context.Context) ([]*models.VoidReason, error) {
+	var reasons []*models.VoidReason
+	if err := database.Conn(ctx, r.db).Order("display_order ASC, code ASC").Find(&reasons).Error; err != nil {
+		return nil, errors.Wrap(err, "[VoidReasonRepository.GetAllVoidReasons]: Error querying database")
+	}
+	return reasons, nil
+}
+
+func (r *voidReasonRepository) GetVoidReasonByID(ctx context.Context, id uuid.UUID) (*models.VoidReason, error) {
+	var reason models.VoidReason
+	if err := database.Conn(ctx, r.db).Where("id = ?", id).First(&reason).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrap(domain.NotFoundError("Void reason not found"), "[VoidReasonRepository.GetVoidReasonByID]")
+		}
+		return nil, errors.Wrap(err, "[VoidReasonRepository.GetVoidReasonByID]: Error querying database")
+	}
+	return &reason, nil
+}
+
+func (r *voidReasonRepository) CreateVoidReason(ctx context.Context, reason *models.VoidReason) error {
+	if err := database.Conn(ctx, r.db).Create(reason).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A void reason with this code already exists"), "[VoidReasonRepository.CreateVoidReason]")
+		}
+		return errors.Wrap(err, "[VoidReasonRepository.CreateVoidReason]: Error creating void reason")
+	}
+	return nil
+}
+
+func (r *voidReasonRepository) UpdateVoidReason(ctx context.Context, reason *models.VoidReason) error {
+	if err := database.Conn(ctx, r.db).Save(reason).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.Wrap(domain.ConflictError("A void reason with this code already exists"), "[VoidReasonRepository.UpdateVoidReason]")
+		}
+		return errors.Wrap(err, "[VoidReasonRepository.UpdateVoidReason]: Error updating void reason")
+	}
+	return nil
+}
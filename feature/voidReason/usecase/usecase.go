@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/models"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+type voidReasonUsecase struct {
+	voidReasonRepository domain.VoidReasonRepository
+	transactor           domain.Transactor
+	auditUsecase         domain.AuditUsecase
+}
+
+func NewVoidReasonUsecase(voidReasonRepository domain.VoidReasonRepository, transactor domain.Transactor, auditUsecase domain.AuditUsecase) domain.VoidReasonUsecase {
+	return &voidReasonUsecase{voidReasonRepository: voidReasonRepository, transactor: transactor, auditUsecase: auditUsecase}
+}
+
+func (u *voidReasonUsecase) GetAllVoidReasons(ctx context.Context) (*response.Page[*response.VoidReasonResponse], error) {
+	ctx, span := tracing.Start(ctx, "VoidReasonUsecase.GetAllVoidReasons")
+	defer span.End()
+
+	reasons, err := u.voidReasonRepository.GetAllVoidReasons(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[VoidReasonUsecase.GetAllVoidReasons]: Error getting void reasons")
+	}
+
+	reasonResponses := make([]*response.VoidReasonResponse, len(reasons))
+	for i, reason := range reasons {
+		reasonResponses[i] = u.buildVoidReasonResponse(reason)
+	}
+
+	return response.SinglePage(reasonResponses), nil
+}
+
+func (u *voidReasonUsecase) CreateVoidReason(ctx context.Context, req *request.VoidReasonRequest) (*response.VoidReasonResponse, error) {
+	ctx, span := tracing.Start(ctx, "VoidReasonUsecase.CreateVoidReason")
+	defer span.End()
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	displayOrder := 0
+	if req.DisplayOrder != nil {
+		displayOrder = *req.DisplayOrder
+	}
+
+	reason := &models.VoidReason{
+		Code:         req.Code,
+		Label:        &req.Label,
+		Active:       &active,
+		DisplayOrder: &displayOrder,
+	}
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.voidReasonRepository.CreateVoidReason(ctx, reason); err != nil {
+			return errors.Wrap(err, "[VoidReasonUsecase.CreateVoidReason]: Error creating void reason")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionCreate, constant.AuditEntityVoidReason, reason.ID.String(), nil, u.buildVoidReasonResponse(reason)); err != nil {
+			return errors.Wrap(err, "[VoidReasonUsecase.CreateVoidReason]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.buildVoidReasonResponse(reason), nil
+}
+
+// UpdateVoidReason edits a reason code. Codes already used on voided orders and
+// cancelled items stay as recorded, so retire a code by deactivating it.
+func (u *voidReasonUsecase) UpdateVoidReason(ctx context.Context, id uuid.UUID, req *request.VoidReasonRequest) (*response.VoidReasonResponse, error) {
+	ctx, span := tracing.Start(ctx, "VoidReasonUsecase.UpdateVoidReason")
+	defer span.End()
+
+	reason, err := u.voidReasonRepository.GetVoidReasonByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "[VoidReasonUsecase.UpdateVoidReason]: Void reason not found")
+	}
+	before := u.buildVoidReasonResponse(reason)
+
+	reason.Code = req.Code
+	reason.Label = &req.Label
+	if req.Active != nil {
+		reason.Active = req.Active
+	}
+	if req.DisplayOrder != nil {
+		reason.DisplayOrder = req.DisplayOrder
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.voidReasonRepository.UpdateVoidReason(ctx, reason); err != nil {
+			return errors.Wrap(err, "[VoidReasonUsecase.UpdateVoidReason]: Error updating void reason")
+		}
+		if err := u.auditUsecase.Record(ctx, constant.AuditActionUpdate, constant.AuditEntityVoidReason, reason.ID.String(), before, u.buildVoidReasonResponse(reason)); err != nil {
+			return errors.Wrap(err, "[VoidReasonUsecase.UpdateVoidReason]: Error recording audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.buildVoidReasonResponse(reason), nil
+}
+
+// Helper function to build void reason response
+func (u *voidReasonUsecase) buildVoidReasonResponse(reason *models.VoidReason) *response.VoidReasonResponse {
+	return &response.VoidReasonResponse{
+		ID:           reason.ID,
+		Code:         reason.Code,
+		Label:        utils.DerefString(reason.Label),
+		Active:       utils.DerefBool(reason.Active),
+		DisplayOrder: utils.DerefInt(reason.DisplayOrder),
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SlowQueryThreshold is how long a statement may take before it is logged as a warning
+const SlowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger logs GORM statements with the fields of the request that ran them.
+// Slow statements are warnings, failed ones debug entries since the error is
+// logged again where it is handled, and every statement is traced. Bound
+// values are never logged, so neither are password hashes or tokens.
+type GormLogger struct{}
+
+var _ logger.Interface = GormLogger{}
+var _ gorm.ParamsFilter = GormLogger{}
+
+// LogMode is ignored; the logrus level decides what is logged
+func (l GormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (GormLogger) Info(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).Infof(format, args...)
+}
+
+func (GormLogger) Warn(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).Warnf(format, args...)
+}
+
+func (GormLogger) Error(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).Errorf(format, args...)
+}
+
+func (GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	slow := elapsed >= SlowQueryThreshold
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	if !slow && !failed && !log.IsLevelEnabled(log.TraceLevel) {
+		return
+	}
+
+	sql, rows := fc()
+	entry := FromContext(ctx).WithFields(log.Fields{
+		"sql":        sql,
+		"rows":       rows,
+		"elapsed_ms": elapsed.Milliseconds(),
+	})
+	switch {
+	case slow:
+		entry.Warn("[database]: Slow query")
+	case failed:
+		entry.WithError(err).Debug("[database]: Query failed")
+	default:
+		entry.Trace("[database]: Query")
+	}
+}
+
+// ParamsFilter drops the bound values so the logged SQL keeps its placeholders
+func (GormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	return sql, nil
+}
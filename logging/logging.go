@@ -0,0 +1,81 @@
+// Package logging configures logrus and carries per-request fields on the
+// context, so every line logged while handling a request names the request,
+// the user and the route it belongs to.
+package logging
+
+import (
+	"context"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Fields every request carries once known
+const (
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+	FieldRoute     = "route"
+	FieldTraceID   = "trace_id"
+)
+
+// Configure sets the output format and minimum level of the standard logger.
+// Sensitive fields are redacted in either format.
+func Configure(format string, level log.Level) {
+	var formatter log.Formatter
+	switch format {
+	case FormatJSON:
+		formatter = &log.JSONFormatter{TimestampFormat: time.RFC3339Nano}
+	default:
+		formatter = &log.TextFormatter{
+			ForceColors:   true,
+			FullTimestamp: true,
+		}
+	}
+
+	log.SetOutput(os.Stderr)
+	log.SetFormatter(&redactingFormatter{next: formatter})
+	log.SetLevel(level)
+}
+
+type fieldsKey struct{}
+
+// WithFields returns a context whose log entries carry fields in addition to
+// those already on ctx
+func WithFields(ctx context.Context, fields log.Fields) context.Context {
+	merged := make(log.Fields, len(fields))
+	if existing, ok := ctx.Value(fieldsKey{}).(log.Fields); ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FromContext returns an entry with the fields stored on ctx. Outside a
+// request it is the standard logger with no extra fields.
+func FromContext(ctx context.Context) *log.Entry {
+	entry := log.NewEntry(log.StandardLogger())
+	if fields, ok := ctx.Value(fieldsKey{}).(log.Fields); ok {
+		entry = entry.WithFields(fields)
+	}
+	return entry.WithContext(ctx)
+}
+
+// RequestID returns the ID of the request being handled on ctx, or "" outside one
+func RequestID(ctx context.Context) string {
+	if fields, ok := ctx.Value(fieldsKey{}).(log.Fields); ok {
+		if id, ok := fields[FieldRequestID].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are field, header and query parameter names whose values never
+// reach the log, compared case-insensitively
+var sensitiveKeys = map[string]bool{
+	"authorization":    true,
+	"cookie":           true,
+	"password":         true,
+	"old_password":     true,
+	"new_password":     true,
+	"pin":              true,
+	"secret":           true,
+	"token":            true,
+	"override_token":   true,
+	"x-override-token": true,
+}
+
+// IsSensitive reports whether values named key must be redacted
+func IsSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// RedactQuery returns rawQuery with the values of sensitive parameters replaced
+func RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	for key := range values {
+		if IsSensitive(key) {
+			values[key] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
+
+// redactingFormatter replaces sensitive fields before handing the entry on,
+// whichever layer added them
+type redactingFormatter struct {
+	next log.Formatter
+}
+
+func (f *redactingFormatter) Format(entry *log.Entry) ([]byte, error) {
+	var clean log.Fields
+	for key := range entry.Data {
+		if !IsSensitive(key) {
+			continue
+		}
+		if clean == nil {
+			clean = make(log.Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				clean[k] = v
+			}
+		}
+		clean[key] = redacted
+	}
+	if clean == nil {
+		return f.next.Format(entry)
+	}
+
+	copied := *entry
+	copied.Data = clean
+	return f.next.Format(&copied)
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// InstrumentDB times every statement run through db and exports the
+// connection pool statistics of its sql.DB
+func (m *Metrics) InstrumentDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errors.Wrap(err, "[Metrics.InstrumentDB]: Error getting connection pool")
+	}
+	if err := m.registry.Register(collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name())); err != nil {
+		return errors.Wrap(err, "[Metrics.InstrumentDB]: Error registering pool collector")
+	}
+
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, p := range processors {
+		if err := p.before("metrics:before_"+p.operation, startQueryTimer); err != nil {
+			return errors.Wrapf(err, "[Metrics.InstrumentDB]: Error registering %s callback", p.operation)
+		}
+		if err := p.after("metrics:after_"+p.operation, m.observeQuery(p.operation)); err != nil {
+			return errors.Wrapf(err, "[Metrics.InstrumentDB]: Error registering %s callback", p.operation)
+		}
+	}
+
+	return nil
+}
+
+func startQueryTimer(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (m *Metrics) observeQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		// Raw SQL has no model, and so no table to report
+		table := db.Statement.Table
+		if table == "" {
+			table = "none"
+		}
+		m.dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,146 @@
+// Package metrics collects the Prometheus metrics served on /metrics: HTTP
+// latency, database timings and pool usage, and the business events reported
+// by the usecases. Each Metrics has its own registry, so several applications
+// can live in one process.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+)
+
+const namespace = "pos"
+
+// Metrics implements domain.Metrics
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequestDuration *prometheus.HistogramVec
+	dbQueryDuration     *prometheus.HistogramVec
+	authFailures        *prometheus.CounterVec
+	ordersOpened        *prometheus.CounterVec
+	ordersClosed        *prometheus.CounterVec
+	ordersReopened      prometheus.Counter
+	voids               *prometheus.CounterVec
+	payments            *prometheus.CounterVec
+	paymentAmount       *prometheus.CounterVec
+}
+
+var _ domain.Metrics = (*Metrics)(nil)
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to handle HTTP requests, by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Time taken by GORM statements, by operation and table.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_failures_total",
+			Help:      "Rejected logins and sessions, by reason.",
+		}, []string{"reason"}),
+		ordersOpened: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_opened_total",
+			Help:      "Orders opened, by source.",
+		}, []string{"source"}),
+		ordersClosed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_closed_total",
+			Help:      "Orders closed, by final status (paid or void).",
+		}, []string{"status"}),
+		ordersReopened: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_reopened_total",
+			Help:      "Paid orders reopened with a manager override.",
+		}),
+		voids: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "voids_total",
+			Help:      "Voided orders and cancelled items, by kind and reason code.",
+		}, []string{"kind", "reason"}),
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payments_total",
+			Help:      "Payments recorded, by method and status.",
+		}, []string{"method", "status"}),
+		paymentAmount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payment_amount_baht_total",
+			Help:      "Baht taken by succeeded payments, by method.",
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequestDuration,
+		m.dbQueryDuration,
+		m.authFailures,
+		m.ordersOpened,
+		m.ordersClosed,
+		m.ordersReopened,
+		m.voids,
+		m.payments,
+		m.paymentAmount,
+	)
+
+	return m
+}
+
+// Handler serves every registered metric in the Prometheus text format. A
+// collector that fails, such as one whose query timed out, is reported in the
+// response without hiding the others.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+		Registry:      m.registry,
+	})
+}
+
+// ObserveHTTPRequest records one handled request. route is the route pattern,
+// not the path, so ids do not create a series per request.
+func (m *Metrics) ObserveHTTPRequest(method string, route string, status string, seconds float64) {
+	m.httpRequestDuration.WithLabelValues(method, route, status).Observe(seconds)
+}
+
+func (m *Metrics) OrderOpened(source string) {
+	m.ordersOpened.WithLabelValues(source).Inc()
+}
+
+func (m *Metrics) OrderClosed(status string) {
+	m.ordersClosed.WithLabelValues(status).Inc()
+}
+
+func (m *Metrics) OrderReopened() {
+	m.ordersReopened.Inc()
+}
+
+func (m *Metrics) Voided(kind string, reasonCode string) {
+	m.voids.WithLabelValues(kind, reasonCode).Inc()
+}
+
+func (m *Metrics) PaymentRecorded(method string, status string, amountBaht int64) {
+	m.payments.WithLabelValues(method, status).Inc()
+	if status == constant.PaymentStatusSucceeded {
+		m.paymentAmount.WithLabelValues(method).Add(float64(amountBaht))
+	}
+}
+
+func (m *Metrics) AuthFailed(reason string) {
+	m.authFailures.WithLabelValues(reason).Inc()
+}
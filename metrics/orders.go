@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// openOrdersTimeout bounds the query run on each scrape
+const openOrdersTimeout = 5 * time.Second
+
+// OpenOrderCounter returns the number of open orders keyed by area name
+type OpenOrderCounter func(ctx context.Context) (map[string]int64, error)
+
+// openOrdersCollector queries the open orders when scraped rather than keeping
+// a gauge up to date, so the value is right even after a restart
+type openOrdersCollector struct {
+	desc  *prometheus.Desc
+	count OpenOrderCounter
+}
+
+// RegisterOpenOrders exports the open orders per area, counted by count on
+// every scrape
+func (m *Metrics) RegisterOpenOrders(count OpenOrderCounter) error {
+	collector := &openOrdersCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_orders"),
+			"Orders currently open, by area of their table.",
+			[]string{"area"}, nil,
+		),
+		count: count,
+	}
+	if err := m.registry.Register(collector); err != nil {
+		return errors.Wrap(err, "[Metrics.RegisterOpenOrders]: Error registering collector")
+	}
+	return nil
+}
+
+func (c *openOrdersCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *openOrdersCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), openOrdersTimeout)
+	defer cancel()
+
+	counts, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, errors.Wrap(err, "[openOrdersCollector.Collect]: Error counting open orders"))
+		return
+	}
+	for area, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), area)
+	}
+}
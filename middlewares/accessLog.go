@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/pos-backend/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// AccessLog writes one entry per request once it has been handled. Headers are
+// not logged and sensitive query parameters are redacted.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		entry := logging.FromContext(c.Request.Context()).WithFields(log.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"query":      logging.RedactQuery(c.Request.URL.RawQuery),
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"bytes":      c.Writer.Size(),
+			"client_ip":  c.ClientIP(),
+			"user_agent": c.Request.UserAgent(),
+		})
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("[AccessLog]: Request failed")
+		case status >= http.StatusBadRequest:
+			entry.Warn("[AccessLog]: Request rejected")
+		default:
+			entry.Info("[AccessLog]: Request handled")
+		}
+	}
+}
@@ -1,24 +1,38 @@
 package middlewares
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/pubestpubest/pos-backend/database"
-	authRepository "github.com/pubestpubest/pos-backend/feature/auth/repository"
-	authUsecase "github.com/pubestpubest/pos-backend/feature/auth/usecase"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/logging"
 	"github.com/pubestpubest/pos-backend/response"
+	"github.com/pubestpubest/pos-backend/tracing"
+	"github.com/pubestpubest/pos-backend/utils"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// Auth holds the middleware that authenticates requests and checks permissions.
+// It is built once and shared by every route group.
+type Auth struct {
+	authUsecase domain.AuthUsecase
+}
+
+func NewAuth(authUsecase domain.AuthUsecase) *Auth {
+	return &Auth{authUsecase: authUsecase}
+}
+
+// Authenticate resolves the bearer token to a user and puts the user and the
+// audit actor on the request
+func (m *Auth) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+			utils.RenderError(c, domain.UnauthorizedError("Authorization header required"))
 			return
 		}
 
@@ -29,13 +43,13 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Validate token
-		authRepo := authRepository.NewAuthRepository(database.DB)
-		authUc := authUsecase.NewAuthUsecase(authRepo)
-
-		user, err := authUc.GetUserByToken(token)
+		user, err := m.authUsecase.GetUserByToken(c.Request.Context(), token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			if domain.ErrorKindOf(err) == domain.ErrorKindInternal {
+				utils.RenderError(c, errors.Wrap(err, "[AuthMiddleware]: Error validating token"))
+				return
+			}
+			utils.RenderError(c, errors.Wrap(domain.UnauthorizedError("Invalid or expired token"), err.Error()))
 			return
 		}
 
@@ -50,26 +64,38 @@ func AuthMiddleware() gin.HandlerFunc {
 			Status:   user.Status,
 		})
 
+		// Carry the actor on the request context so audited changes know who made them,
+		// and name the user on every later log line
+		ctx := c.Request.Context()
+		actor := &domain.Actor{
+			UserID:    &user.ID,
+			ClientIP:  c.ClientIP(),
+			RequestID: logging.RequestID(ctx),
+		}
+		ctx = logging.WithFields(ctx, log.Fields{logging.FieldUserID: user.ID.String()})
+		trace.SpanFromContext(ctx).SetAttributes(tracing.UserID(user.ID))
+		c.Request = c.Request.WithContext(domain.WithActor(ctx, actor))
+
 		c.Next()
 	}
 }
 
-func RequirePermission(permissionCode string) gin.HandlerFunc {
+// RequirePermission must run after Authenticate
+func (m *Auth) RequirePermission(permissionCode string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("userID")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
+			utils.RenderError(c, domain.UnauthorizedError("Unauthorized"))
 			return
 		}
 
-		authRepo := authRepository.NewAuthRepository(database.DB)
-		authUc := authUsecase.NewAuthUsecase(authRepo)
-
-		hasPermission, err := authUc.VerifyPermission(userID.(uuid.UUID), permissionCode)
-		if err != nil || !hasPermission {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-			c.Abort()
+		hasPermission, err := m.authUsecase.VerifyPermission(c.Request.Context(), userID.(uuid.UUID), permissionCode)
+		if err != nil {
+			utils.RenderError(c, errors.Wrap(err, "[RequirePermission]: Error checking permissions"))
+			return
+		}
+		if !hasPermission {
+			utils.RenderError(c, domain.ForbiddenError("Insufficient permissions"))
 			return
 		}
 
@@ -1,10 +1,23 @@
 package middlewares
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/pos-backend/config"
+)
+
+func CORSMiddleware(cfg config.HTTPConfig) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(cfg.CORSOrigins))
+	for _, origin := range cfg.CORSOrigins {
+		allowed[origin] = true
+	}
 
-func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		if allowed["*"] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else if origin := c.GetHeader("Origin"); allowed[origin] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Add("Vary", "Origin")
+		}
 		c.Next()
 	}
 }
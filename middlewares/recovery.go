@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/logging"
+	"github.com/pubestpubest/pos-backend/utils"
+)
+
+// Recovery turns a panic into a 500 and logs it with the stack and the
+// request's fields, instead of gin's plain text dump. http.ErrAbortHandler is
+// passed on to the server, which drops the connection without a response.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+		logging.FromContext(c.Request.Context()).WithField("stack", string(debug.Stack())).Error("[Recovery]: Panic handling request")
+		utils.RenderError(c, errors.Errorf("[Recovery]: %v", recovered))
+	})
+}
@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// validRequestID limits IDs taken from clients, so a header cannot inject
+// text into the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID takes the X-Request-ID of the incoming request, or assigns one,
+// echoes it on the response and puts it and the route on the request context
+// for logging. It must run before any middleware that logs.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(constant.RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(constant.RequestIDHeader, requestID)
+
+		ctx := logging.WithFields(c.Request.Context(), log.Fields{
+			logging.FieldRequestID: requestID,
+			logging.FieldRoute:     c.FullPath(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pubestpubest/pos-backend/logging"
+)
+
+// untimedContextKey holds the request context as it was before RequestTimeout
+// put its deadline on it
+const untimedContextKey = "untimedContext"
+
+// RequestTimeout puts a deadline on the request context. Every usecase and
+// repository runs on that context, so queries still running at the deadline
+// are cancelled, just as they are when the client disconnects.
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		c.Set(untimedContextKey, c.Request.Context())
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// LongRequest lets a route that streams, such as an export, run past the
+// request and write timeouts. It lifts the connection's write deadline and
+// replaces the request deadline with limit, or none when limit is 0. The
+// request is still cancelled when the client disconnects. It replaces the
+// request context, so it goes before middleware that adds to it.
+func LongRequest(limit time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logging.FromContext(c.Request.Context()).WithError(err).Warn("[LongRequest]: Error clearing write deadline")
+		}
+
+		ctx := c.Request.Context()
+		if untimed, ok := c.Get(untimedContextKey); ok {
+			ctx = untimed.(context.Context)
+		}
+		if limit > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, limit)
+			defer cancel()
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
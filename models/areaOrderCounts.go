@@ -0,0 +1,8 @@
+package models
+
+// AreaOrderCount is a row of the open orders query, not a table. AreaName is nil
+// for orders whose table has no area, or that have no table.
+type AreaOrderCount struct {
+	AreaName *string `gorm:"column:area_name"`
+	Count    int64   `gorm:"column:count"`
+}
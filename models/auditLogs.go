@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLog struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	ActorID     *uuid.UUID `gorm:"type:uuid;column:actor_id;index"`
+	Action      string     `gorm:"type:varchar;not null;column:action"`
+	EntityType  string     `gorm:"type:varchar;not null;column:entity_type;index:idx_audit_logs_entity"`
+	EntityID    string     `gorm:"type:varchar;not null;column:entity_id;index:idx_audit_logs_entity"`
+	BeforeState *string    `gorm:"type:jsonb;column:before_state;comment:snapshot before the change"`
+	AfterState  *string    `gorm:"type:jsonb;column:after_state;comment:snapshot after the change"`
+	ClientIP    *string    `gorm:"type:varchar;column:client_ip"`
+	RequestID   *string    `gorm:"type:varchar;column:request_id"`
+	CreatedAt   time.Time  `gorm:"type:timestamp;default:now();column:created_at;index"`
+
+	Actor *User `gorm:"foreignKey:ActorID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+}
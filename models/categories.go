@@ -7,5 +7,7 @@ type Category struct {
 	Name         *string   `gorm:"type:varchar;uniqueIndex;column:name"`
 	DisplayOrder *int      `gorm:"column:display_order"`
 
-	MenuItems []MenuItem `gorm:"foreignKey:CategoryID"`
+	MenuItems      []MenuItem              `gorm:"foreignKey:CategoryID"`
+	Schedules      []MenuSchedule          `gorm:"foreignKey:CategoryID"`
+	ModifierGroups []CategoryModifierGroup `gorm:"foreignKey:CategoryID"`
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JournalExport records that a business day's journal entry was exported, with
+// the lines as they were exported
+type JournalExport struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	BusinessDate time.Time  `gorm:"type:date;uniqueIndex;not null;column:business_date"`
+	ExportedAt   time.Time  `gorm:"type:timestamp;default:now();column:exported_at"`
+	ExportedBy   *uuid.UUID `gorm:"type:uuid;column:exported_by"`
+	DebitBaht    int64      `gorm:"not null;column:debit_baht"`
+	CreditBaht   int64      `gorm:"not null;column:credit_baht"`
+
+	Exporter *User         `gorm:"foreignKey:ExportedBy;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
+	Lines    []JournalLine `gorm:"foreignKey:ExportID"`
+}
+
+type JournalLine struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	ExportID    uuid.UUID `gorm:"type:uuid;not null;column:export_id;index"`
+	LineNo      int       `gorm:"not null;column:line_no"`
+	Purpose     string    `gorm:"type:varchar;not null;column:purpose"`
+	AccountCode string    `gorm:"type:varchar;not null;column:account_code"`
+	AccountName *string   `gorm:"type:varchar;column:account_name"`
+	Description string    `gorm:"type:varchar;not null;column:description"`
+	DebitBaht   int64     `gorm:"not null;column:debit_baht"`
+	CreditBaht  int64     `gorm:"not null;column:credit_baht"`
+
+	Export *JournalExport `gorm:"foreignKey:ExportID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// JournalTotal is a row of the journal totals query, not a table. Kind is one
+// of the constant.JournalTotal kinds: Amount counts the open orders, or sums
+// baht for the rest. Categories and payment methods are keyed by their id and
+// method, nil for items without a category.
+type JournalTotal struct {
+	Kind   string  `gorm:"column:kind"`
+	Key    *string `gorm:"column:total_key"`
+	Label  *string `gorm:"column:total_label"`
+	Amount int64   `gorm:"column:amount"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "github.com/google/uuid"
+
+// LedgerAccount maps what a journal line records to an account of the chart of
+// accounts. Sales may be mapped per category; the sales account without a
+// category takes the rest.
+type LedgerAccount struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	Purpose    string     `gorm:"type:varchar;not null;column:purpose;comment:sales, vat_output, service_charge, discounts, cash, card, promptpay, refunds, over_short"`
+	CategoryID *uuid.UUID `gorm:"type:uuid;column:category_id;comment:sales of this category only"`
+	Code       string     `gorm:"type:varchar;not null;column:code"`
+	Name       *string    `gorm:"type:varchar;column:name"`
+
+	Category *Category `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
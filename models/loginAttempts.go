@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginAttempt struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	Username  string     `gorm:"type:varchar;not null;column:username;index"`
+	UserID    *uuid.UUID `gorm:"type:uuid;column:user_id;index"`
+	ClientIP  string     `gorm:"type:varchar;not null;column:client_ip;index"`
+	Result    string     `gorm:"type:varchar;not null;column:result;comment:success, failure, locked, blocked, unlocked"`
+	CreatedAt time.Time  `gorm:"type:timestamp;default:now();column:created_at;index"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+}
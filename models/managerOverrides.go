@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ManagerOverride struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	Token       string     `gorm:"type:varchar;unique;not null;column:token"`
+	Action      string     `gorm:"type:varchar;not null;column:action;comment:order.void, order.item_remove, order.discount, order.reopen"`
+	OrderID     uuid.UUID  `gorm:"type:uuid;not null;column:order_id;index"`
+	OrderItemID *uuid.UUID `gorm:"type:uuid;column:order_item_id"`
+	RequestedBy uuid.UUID  `gorm:"type:uuid;not null;column:requested_by"`
+	ApprovedBy  uuid.UUID  `gorm:"type:uuid;not null;column:approved_by"`
+	Reason      string     `gorm:"type:text;not null;column:reason"`
+	ExpiresAt   time.Time  `gorm:"type:timestamp;not null;column:expires_at"`
+	UsedAt      *time.Time `gorm:"type:timestamp;column:used_at"`
+	CreatedAt   time.Time  `gorm:"type:timestamp;default:now();column:created_at"`
+
+	Order     *Order `gorm:"foreignKey:OrderID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Requester *User  `gorm:"foreignKey:RequestedBy;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	Approver  *User  `gorm:"foreignKey:ApprovedBy;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+}
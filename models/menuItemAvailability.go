@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MenuItemAvailability is how much of a menu item the kitchen has left today.
+// An item without a row is available and its portions are not counted.
+type MenuItemAvailability struct {
+	MenuItemID        uuid.UUID  `gorm:"type:uuid;primaryKey;column:menu_item_id"`
+	Status            string     `gorm:"type:varchar;not null;column:status;comment:available, low_stock or sold_out"`
+	RemainingPortions *int       `gorm:"column:remaining_portions;comment:portions left, taken as items are ordered; null when not counted"`
+	UpdatedAt         time.Time  `gorm:"type:timestamp;default:now();column:updated_at"`
+	UpdatedBy         *uuid.UUID `gorm:"type:uuid;column:updated_by"`
+
+	MenuItem *MenuItem `gorm:"foreignKey:MenuItemID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Updater  *User     `gorm:"foreignKey:UpdatedBy;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
+}
@@ -11,5 +11,8 @@ type MenuItem struct {
 	Active     *bool      `gorm:"column:active;default:true"`
 	ImageURL   *string    `gorm:"type:text;column:image_url"`
 
-	Category *Category `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
+	Category       *Category               `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
+	Availability   *MenuItemAvailability   `gorm:"foreignKey:MenuItemID"`
+	Schedules      []MenuSchedule          `gorm:"foreignKey:MenuItemID"`
+	ModifierGroups []MenuItemModifierGroup `gorm:"foreignKey:MenuItemID"`
 }
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MenuSchedule puts a category or a menu item on the menu on some days of the
+// week, within a time window, optionally in one area only. A category or item
+// with schedules is on the menu only while one of them is open; one without
+// any always is.
+type MenuSchedule struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	Name        *string    `gorm:"type:varchar;column:name"`
+	CategoryID  *uuid.UUID `gorm:"type:uuid;column:category_id;index"`
+	MenuItemID  *uuid.UUID `gorm:"type:uuid;column:menu_item_id;index"`
+	AreaID      *uuid.UUID `gorm:"type:uuid;column:area_id;comment:open in this area only"`
+	Weekdays    int        `gorm:"not null;column:weekdays;comment:bit n set to open on time.Weekday n, Sunday being 0"`
+	StartMinute *int       `gorm:"column:start_minute;comment:minutes after midnight the window opens; null for all day"`
+	EndMinute   *int       `gorm:"column:end_minute;comment:minutes after midnight the window closes, the next day when not after the start"`
+
+	Category   *Category               `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	MenuItem   *MenuItem               `gorm:"foreignKey:MenuItemID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Area       *Area                   `gorm:"foreignKey:AreaID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	Exceptions []MenuScheduleException `gorm:"foreignKey:ScheduleID"`
+}
+
+// MenuScheduleException opens or closes a schedule for a whole date, whatever
+// its weekdays and window say
+type MenuScheduleException struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	ScheduleID uuid.UUID `gorm:"type:uuid;not null;column:schedule_id;index"`
+	Date       time.Time `gorm:"type:date;not null;column:date"`
+	Open       bool      `gorm:"not null;column:open"`
+
+	Schedule *MenuSchedule `gorm:"foreignKey:ScheduleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
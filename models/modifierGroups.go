@@ -0,0 +1,47 @@
+package models
+
+import "github.com/google/uuid"
+
+// ModifierGroup offers modifiers as one choice, such as a spice level to pick
+// one of or extras to pick up to three of. Menu items take the groups linked to
+// them and to their category.
+type ModifierGroup struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	Name          *string   `gorm:"type:varchar;uniqueIndex;column:name"`
+	MinSelections int       `gorm:"not null;default:0;column:min_selections"`
+	MaxSelections *int      `gorm:"column:max_selections;comment:null for no limit"`
+	SortOrder     int       `gorm:"not null;default:0;column:sort_order"`
+
+	Options    []ModifierGroupOption   `gorm:"foreignKey:ModifierGroupID"`
+	MenuItems  []MenuItemModifierGroup `gorm:"foreignKey:ModifierGroupID"`
+	Categories []CategoryModifierGroup `gorm:"foreignKey:ModifierGroupID"`
+}
+
+// ModifierGroupOption is a modifier offered by a group
+type ModifierGroupOption struct {
+	ModifierGroupID uuid.UUID `gorm:"type:uuid;not null;primaryKey;column:modifier_group_id"`
+	ModifierID      uuid.UUID `gorm:"type:uuid;not null;primaryKey;column:modifier_id"`
+	SortOrder       int       `gorm:"not null;default:0;column:sort_order"`
+	IsDefault       bool      `gorm:"not null;default:false;column:is_default;comment:selected when an order item names no modifiers"`
+
+	ModifierGroup *ModifierGroup `gorm:"foreignKey:ModifierGroupID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Modifier      *Modifier      `gorm:"foreignKey:ModifierID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// MenuItemModifierGroup offers a group on a menu item
+type MenuItemModifierGroup struct {
+	MenuItemID      uuid.UUID `gorm:"type:uuid;not null;primaryKey;column:menu_item_id"`
+	ModifierGroupID uuid.UUID `gorm:"type:uuid;not null;primaryKey;column:modifier_group_id"`
+
+	MenuItem      *MenuItem      `gorm:"foreignKey:MenuItemID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ModifierGroup *ModifierGroup `gorm:"foreignKey:ModifierGroupID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// CategoryModifierGroup offers a group on every menu item of a category
+type CategoryModifierGroup struct {
+	CategoryID      uuid.UUID `gorm:"type:uuid;not null;primaryKey;column:category_id"`
+	ModifierGroupID uuid.UUID `gorm:"type:uuid;not null;primaryKey;column:modifier_group_id"`
+
+	Category      *Category      `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ModifierGroup *ModifierGroup `gorm:"foreignKey:ModifierGroupID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
@@ -1,18 +1,27 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type OrderItem struct {
-	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
-	OrderID       uuid.UUID `gorm:"type:uuid;not null;column:order_id"`
-	MenuItemID    uuid.UUID `gorm:"type:uuid;not null;column:menu_item_id"`
-	Quantity      int       `gorm:"column:quantity"`
-	UnitPriceBaht int64     `gorm:"column:unit_price_baht"`
-	LineTotalBaht int64     `gorm:"column:line_total_baht"`
-	Note          *string   `gorm:"type:text;column:note"`
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	OrderID       uuid.UUID  `gorm:"type:uuid;not null;column:order_id"`
+	MenuItemID    uuid.UUID  `gorm:"type:uuid;not null;column:menu_item_id"`
+	Quantity      int        `gorm:"column:quantity"`
+	UnitPriceBaht int64      `gorm:"column:unit_price_baht"`
+	LineTotalBaht int64      `gorm:"column:line_total_baht"`
+	Note          *string    `gorm:"type:text;column:note"`
+	SentAt        *time.Time `gorm:"type:timestamp;column:sent_at;comment:when the item was sent to the kitchen"`
+	CancelledAt   *time.Time `gorm:"type:timestamp;column:cancelled_at"`
+	CancelledBy   *uuid.UUID `gorm:"type:uuid;column:cancelled_by"`
+	CancelReason  *string    `gorm:"type:varchar;column:cancel_reason;comment:void reason code"`
 
-	Order    *Order    `gorm:"foreignKey:OrderID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	MenuItem *MenuItem `gorm:"foreignKey:MenuItemID;references:ID;constraint:OnUpdate:RESTRICT,OnDelete:RESTRICT"`
+	Order     *Order    `gorm:"foreignKey:OrderID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	MenuItem  *MenuItem `gorm:"foreignKey:MenuItemID;references:ID;constraint:OnUpdate:RESTRICT,OnDelete:RESTRICT"`
+	Canceller *User     `gorm:"foreignKey:CancelledBy;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
 
 	Modifiers []OrderItemModifier `gorm:"foreignKey:OrderItemID"`
 }
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderSummary is a row of the order list query, not a table. It carries the
+// counts and sums a list shows without loading the order's items. ItemCount
+// adds up the quantities of items that are not cancelled and PaidBaht the
+// succeeded payments.
+type OrderSummary struct {
+	ID           uuid.UUID  `gorm:"column:id"`
+	TableID      *uuid.UUID `gorm:"column:table_id"`
+	TableName    *string    `gorm:"column:table_name"`
+	Status       *string    `gorm:"column:status"`
+	Source       *string    `gorm:"column:source"`
+	OpenedBy     *uuid.UUID `gorm:"column:opened_by"`
+	OpenerName   *string    `gorm:"column:opener_name"`
+	ItemCount    int64      `gorm:"column:item_count"`
+	TotalBaht    int64      `gorm:"column:total_baht"`
+	PaidBaht     int64      `gorm:"column:paid_baht"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	BusinessDate *time.Time `gorm:"column:business_date"`
+	ClosedAt     *time.Time `gorm:"column:closed_at"`
+	VoidedAt     *time.Time `gorm:"column:voided_at"`
+}
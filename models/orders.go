@@ -17,10 +17,15 @@ type Order struct {
 	TotalBaht    *int64     `gorm:"column:total_baht"`
 	Note         *string    `gorm:"type:text;column:note"`
 	CreatedAt    time.Time  `gorm:"type:timestamp;default:now();column:created_at"`
+	BusinessDate *time.Time `gorm:"type:date;column:business_date;comment:business day the order was opened on"`
 	ClosedAt     *time.Time `gorm:"type:timestamp;column:closed_at"`
+	VoidReason   *string    `gorm:"type:varchar;column:void_reason;comment:void reason code"`
+	VoidedBy     *uuid.UUID `gorm:"type:uuid;column:voided_by"`
+	VoidedAt     *time.Time `gorm:"type:timestamp;column:voided_at"`
 
 	Table    *DiningTable `gorm:"foreignKey:TableID;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
 	Opener   *User        `gorm:"foreignKey:OpenedBy;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
+	Voider   *User        `gorm:"foreignKey:VoidedBy;references:ID;constraint:OnUpdate:SET NULL,OnDelete:SET NULL"`
 	Items    []OrderItem  `gorm:"foreignKey:OrderID"`
 	Payments []Payment    `gorm:"foreignKey:OrderID"`
 }
@@ -0,0 +1,13 @@
+package models
+
+// SalesTotal is a row of the sales report queries, not a table. It sums the paid
+// orders of one group: GrossBaht before discounts and SalesBaht after them, both
+// including VAT. Key is nil for orders outside any group, such as orders on a
+// table without an area.
+type SalesTotal struct {
+	Key        *string `gorm:"column:group_key"`
+	Label      *string `gorm:"column:group_label"`
+	OrderCount int64   `gorm:"column:order_count"`
+	GrossBaht  int64   `gorm:"column:gross_baht"`
+	SalesBaht  int64   `gorm:"column:sales_baht"`
+}
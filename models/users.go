@@ -10,6 +10,7 @@ type User struct {
 	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
 	Username     string    `gorm:"type:varchar;unique;not null;column:username"`
 	PasswordHash string    `gorm:"type:text;not null;column:password_hash"`
+	PinHash      *string   `gorm:"type:text;column:pin_hash;comment:short numeric PIN for approvals at the terminal"`
 	FullName     *string   `gorm:"type:varchar;column:full_name"`
 	Email        *string   `gorm:"type:varchar;column:email"`
 	Phone        *string   `gorm:"type:varchar;column:phone"`
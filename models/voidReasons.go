@@ -0,0 +1,11 @@
+package models
+
+import "github.com/google/uuid"
+
+type VoidReason struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey;column:id"`
+	Code         string    `gorm:"type:varchar;uniqueIndex;not null;column:code"`
+	Label        *string   `gorm:"type:varchar;column:label"`
+	Active       *bool     `gorm:"column:active;default:true"`
+	DisplayOrder *int      `gorm:"column:display_order"`
+}
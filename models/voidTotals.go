@@ -0,0 +1,14 @@
+package models
+
+import "github.com/google/uuid"
+
+// VoidTotal is a row of the void report query, not a table. It sums voided orders
+// and cancelled items for one reason code and acting user.
+type VoidTotal struct {
+	ReasonCode  string     `gorm:"column:reason_code"`
+	ReasonLabel *string    `gorm:"column:reason_label"`
+	ActorID     *uuid.UUID `gorm:"column:actor_id"`
+	ActorName   *string    `gorm:"column:actor_name"`
+	Count       int64      `gorm:"column:count"`
+	ValueBaht   int64      `gorm:"column:value_baht"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Void is a row of the void list query, not a table. It is a voided order or a
+// cancelled item; ID is the id of either, and the menu item and quantity are
+// only set for items.
+type Void struct {
+	ID           uuid.UUID  `gorm:"column:id"`
+	Kind         string     `gorm:"column:kind"`
+	OrderID      uuid.UUID  `gorm:"column:order_id"`
+	TableName    *string    `gorm:"column:table_name"`
+	MenuItemName *string    `gorm:"column:menu_item_name"`
+	Quantity     *int64     `gorm:"column:quantity"`
+	ValueBaht    int64      `gorm:"column:value_baht"`
+	ReasonCode   *string    `gorm:"column:reason_code"`
+	ReasonLabel  *string    `gorm:"column:reason_label"`
+	VoidedBy     *uuid.UUID `gorm:"column:voided_by"`
+	VoiderName   *string    `gorm:"column:voider_name"`
+	VoidedAt     time.Time  `gorm:"column:voided_at"`
+	BusinessDate *time.Time `gorm:"column:business_date"`
+}
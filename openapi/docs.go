@@ -0,0 +1,30 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+// Serve registers the document at openapi.json and a page that renders it at
+// docs. Both are public and neither is part of the document.
+func (r *Router) Serve(doc *Document) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Wrap(err, "[openapi]: Error encoding document")
+	}
+
+	r.Undocumented(http.MethodGet, "/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	})
+	r.Undocumented(http.MethodGet, "/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+	})
+	return nil
+}
@@ -0,0 +1,292 @@
+// Package openapi documents the HTTP API as an OpenAPI 3 document. Routes are
+// registered through a Router, which records what each one binds, renders and
+// requires next to the gin handler, and the document is built from those
+// records and the request and response structs they name.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// BearerAuth names the security scheme of authenticated operations
+const BearerAuth = "bearerAuth"
+
+// Operation describes one route
+type Operation struct {
+	Summary     string
+	Description string
+	// Query is the struct the handler binds the query string into
+	Query any
+	// Body is the struct the handler binds the JSON body into
+	Body any
+	// Response is what the handler renders as JSON on success
+	Response any
+	// Files are the media types of a file the handler streams on success. With
+	// a Response as well, the handler renders either, as the client asks.
+	Files []string
+	// Status of the success response, 200 when zero
+	Status int
+	// Permission is required on top of the permissions of the router
+	Permission string
+	// Params describes path parameters that are not UUIDs
+	Params map[string]*Schema
+	// Headers are the optional request headers the handler reads, besides
+	// Authorization
+	Headers []string
+}
+
+// route is an operation as registered by a Router
+type route struct {
+	Operation
+	method        string
+	path          string
+	tag           string
+	authenticated bool
+	permissions   []string
+}
+
+func (r *route) key() string {
+	return r.method + " " + r.path
+}
+
+func (r *route) status() int {
+	if r.Status == 0 {
+		return http.StatusOK
+	}
+	return r.Status
+}
+
+// Spec collects the documented routes
+type Spec struct {
+	info     Info
+	routes   map[string]*route
+	excluded map[string]bool
+}
+
+func NewSpec(info Info) *Spec {
+	return &Spec{info: info, routes: map[string]*route{}, excluded: map[string]bool{}}
+}
+
+// handlerName matches the name gin reports for a method value handler
+var handlerName = regexp.MustCompile(`^(.+)\.\(\*?(\w+)\)\.(\w+)-fm$`)
+
+// Document builds the document from the documented routes. registered are the
+// routes gin serves; every one under a documented prefix must have been
+// registered through a Router, and every documented route must be served.
+func (s *Spec) Document(registered gin.RoutesInfo) (*Document, error) {
+	if err := s.match(registered); err != nil {
+		return nil, err
+	}
+
+	schemas := newSchemas()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    s.info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				BearerAuth: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "Session token returned by POST /v1/auth/login",
+				},
+			},
+		},
+	}
+
+	errorSchema, err := schemas.schemaOf(reflect.TypeOf(response.ErrorResponse{}))
+	if err != nil {
+		return nil, err
+	}
+
+	operationIDs := map[string]string{}
+	for _, key := range s.keys() {
+		r := s.routes[key]
+		op, err := r.document(schemas, errorSchema, registered)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[openapi]: %s", key)
+		}
+		if other, ok := operationIDs[op.OperationID]; ok {
+			return nil, errors.Errorf("[openapi]: %s and %s are both handled by %s", other, key, op.OperationID)
+		}
+		operationIDs[op.OperationID] = key
+
+		path := openAPIPath(r.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(r.method)] = op
+	}
+	doc.Components.Schemas = schemas.components
+
+	return doc, nil
+}
+
+// match checks that the documented routes and the registered ones are the same
+func (s *Spec) match(registered gin.RoutesInfo) error {
+	prefixes := map[string]bool{}
+	for _, r := range s.routes {
+		prefixes[strings.SplitN(strings.TrimPrefix(r.path, "/"), "/", 2)[0]] = true
+	}
+
+	var problems []string
+	served := map[string]bool{}
+	for _, info := range registered {
+		key := info.Method + " " + info.Path
+		served[key] = true
+		if s.routes[key] != nil || s.excluded[key] {
+			continue
+		}
+		if prefixes[strings.SplitN(strings.TrimPrefix(info.Path, "/"), "/", 2)[0]] {
+			problems = append(problems, key+" is served but not documented")
+		}
+	}
+	for key := range s.routes {
+		if !served[key] {
+			problems = append(problems, key+" is documented but not served")
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.Errorf("[openapi]: Routes and document disagree: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s *Spec) keys() []string {
+	keys := make([]string, 0, len(s.routes))
+	for key := range s.routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *route) document(schemas *schemas, errorSchema *Schema, registered gin.RoutesInfo) (*OperationObject, error) {
+	handler := ""
+	for _, info := range registered {
+		if info.Method == r.method && info.Path == r.path {
+			handler = info.Handler
+		}
+	}
+	match := handlerName.FindStringSubmatch(handler)
+	if match == nil {
+		return nil, errors.Errorf("[openapi]: Handler %s is not a handler method", handler)
+	}
+
+	op := &OperationObject{
+		OperationID: match[3],
+		Summary:     r.Summary,
+		Description: r.Description,
+		Responses:   map[string]Response{},
+		Security:    []map[string][]string{},
+	}
+	if r.tag != "" {
+		op.Tags = []string{r.tag}
+	}
+
+	for _, name := range pathParams(r.path) {
+		schema := r.Params[name]
+		if schema == nil {
+			schema = &Schema{Type: "string", Format: "uuid"}
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	for _, name := range r.Headers {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "header", Schema: &Schema{Type: "string"}})
+	}
+	if r.Query != nil {
+		params, err := schemas.queryParameters(reflect.TypeOf(r.Query))
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = append(op.Parameters, params...)
+	}
+	if r.Body != nil {
+		schema, err := schemas.schemaOf(reflect.TypeOf(r.Body))
+		if err != nil {
+			return nil, err
+		}
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(schema)}
+	}
+
+	success := Response{Description: http.StatusText(r.status())}
+	if r.Response != nil {
+		schema, err := schemas.schemaOf(reflect.TypeOf(r.Response))
+		if err != nil {
+			return nil, err
+		}
+		success.Content = jsonContent(schema)
+	}
+	if len(r.Files) > 0 {
+		if success.Content == nil {
+			success.Content = map[string]MediaType{}
+		}
+		for _, mediaType := range r.Files {
+			success.Content[mediaType] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	}
+	op.Responses[strconv.Itoa(r.status())] = success
+
+	errorResponse := func(status int) {
+		op.Responses[strconv.Itoa(status)] = Response{Description: http.StatusText(status), Content: jsonContent(errorSchema)}
+	}
+	if len(op.Parameters) > 0 || r.Body != nil {
+		errorResponse(http.StatusBadRequest)
+	}
+	if r.authenticated {
+		op.Security = []map[string][]string{{BearerAuth: {}}}
+		errorResponse(http.StatusUnauthorized)
+	}
+	if len(r.permissions) > 0 {
+		op.Permission = strings.Join(r.permissions, " ")
+		requires := "Requires the " + strings.Join(r.permissions, " and ") + " permission."
+		if op.Description == "" {
+			op.Description = requires
+		} else {
+			op.Description += "\n\n" + requires
+		}
+		errorResponse(http.StatusForbidden)
+	}
+	if len(pathParams(r.path)) > 0 {
+		errorResponse(http.StatusNotFound)
+	}
+	op.Responses["default"] = Response{Description: "Error", Content: jsonContent(errorSchema)}
+
+	return op, nil
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// pathParams lists the names of the :params in a gin path
+func pathParams(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+// openAPIPath turns /orders/:id into /orders/{id}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,125 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Guard authenticates requests and checks permissions
+type Guard interface {
+	Authenticate() gin.HandlerFunc
+	RequirePermission(code string) gin.HandlerFunc
+}
+
+// Router registers routes on a gin group and documents them at the same time.
+// The authentication and permission middleware are added from the same
+// declaration that documents them, so the two cannot drift apart.
+type Router struct {
+	group         *gin.RouterGroup
+	spec          *Spec
+	guard         Guard
+	tag           string
+	authenticated bool
+	permissions   []string
+}
+
+func NewRouter(group *gin.RouterGroup, spec *Spec, guard Guard) *Router {
+	return &Router{group: group, spec: spec, guard: guard}
+}
+
+// Group returns a router for the routes under relativePath, listed under tag
+func (r *Router) Group(relativePath, tag string) *Router {
+	group := *r
+	group.group = r.group.Group(relativePath)
+	group.tag = tag
+	return &group
+}
+
+// Use returns a router whose routes run middleware first. Middleware that
+// does not change what the document says, such as a timeout, goes here.
+func (r *Router) Use(middleware ...gin.HandlerFunc) *Router {
+	group := *r
+	group.group = r.group.Group("", middleware...)
+	return &group
+}
+
+// Authenticated returns a router whose routes need a signed-in user
+func (r *Router) Authenticated() *Router {
+	if r.authenticated {
+		return r
+	}
+	group := *r
+	group.group = r.group.Group("", r.guard.Authenticate())
+	group.authenticated = true
+	return &group
+}
+
+// RequirePermission returns a router whose routes need a signed-in user whose
+// role grants code
+func (r *Router) RequirePermission(code string) *Router {
+	group := *r.Authenticated()
+	group.group = group.group.Group("", r.guard.RequirePermission(code))
+	group.permissions = append(append([]string{}, group.permissions...), code)
+	return &group
+}
+
+func (r *Router) GET(relativePath string, handler gin.HandlerFunc, op Operation) {
+	r.Handle(http.MethodGet, relativePath, handler, op)
+}
+
+func (r *Router) POST(relativePath string, handler gin.HandlerFunc, op Operation) {
+	r.Handle(http.MethodPost, relativePath, handler, op)
+}
+
+func (r *Router) PUT(relativePath string, handler gin.HandlerFunc, op Operation) {
+	r.Handle(http.MethodPut, relativePath, handler, op)
+}
+
+func (r *Router) DELETE(relativePath string, handler gin.HandlerFunc, op Operation) {
+	r.Handle(http.MethodDelete, relativePath, handler, op)
+}
+
+// Handle registers handler and documents it with op. A permission on op is
+// checked before the handler runs.
+func (r *Router) Handle(method, relativePath string, handler gin.HandlerFunc, op Operation) {
+	target := r
+	if op.Permission != "" {
+		target = r.RequirePermission(op.Permission)
+	}
+	target.group.Handle(method, relativePath, handler)
+
+	rt := &route{
+		Operation:     op,
+		method:        method,
+		path:          joinPath(target.group.BasePath(), relativePath),
+		tag:           target.tag,
+		authenticated: target.authenticated,
+		permissions:   target.permissions,
+	}
+	if _, ok := r.spec.routes[rt.key()]; ok {
+		panic(fmt.Sprintf("openapi: %s is documented twice", rt.key()))
+	}
+	r.spec.routes[rt.key()] = rt
+}
+
+// Undocumented registers handlers for a route that is deliberately left out
+// of the document
+func (r *Router) Undocumented(method, relativePath string, handlers ...gin.HandlerFunc) {
+	r.group.Handle(method, relativePath, handlers...)
+	r.spec.excluded[method+" "+joinPath(r.group.BasePath(), relativePath)] = true
+}
+
+// joinPath joins the way gin does, keeping a trailing slash
+func joinPath(base, relativePath string) string {
+	if relativePath == "" {
+		return base
+	}
+	joined := path.Join(base, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && joined[len(joined)-1] != '/' {
+		return joined + "/"
+	}
+	return joined
+}
@@ -0,0 +1,346 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// numericPattern is what the validator's numeric rule accepts
+const numericPattern = `^[-+]?[0-9]+(?:\.[0-9]+)?$`
+
+// schemas turns Go types into schemas. Named structs are described once under
+// components and referenced from everywhere else.
+type schemas struct {
+	components map[string]*Schema
+	types      map[string]reflect.Type
+}
+
+func newSchemas() *schemas {
+	return &schemas{components: map[string]*Schema{}, types: map[string]reflect.Type{}}
+}
+
+func (s *schemas) schemaOf(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		schema, err := s.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(schema), nil
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}, nil
+	case rawMessageType:
+		// Any JSON value
+		return &Schema{Nullable: true}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}, nil
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := s.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, errors.Errorf("[openapi]: Map %s has non-string keys", t)
+		}
+		values, err := s.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return s.structRef(t)
+	}
+	return nil, errors.Errorf("[openapi]: Type %s cannot be described", t)
+}
+
+// structRef describes t under components the first time it is seen and
+// returns a reference to it
+func (s *schemas) structRef(t reflect.Type) (*Schema, error) {
+	name := componentName(t)
+	if name == "" {
+		return s.structSchema(t)
+	}
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if seen, ok := s.types[name]; ok {
+		if seen != t {
+			return nil, errors.Errorf("[openapi]: %s and %s share the schema name %s", seen, t, name)
+		}
+		return ref, nil
+	}
+
+	// Registered before the fields so recursive types refer to themselves
+	s.types[name] = t
+	schema, err := s.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	s.components[name] = schema
+	return ref, nil
+}
+
+// componentName names the schema of a struct. Instances of generic types are
+// named after their type arguments, so Page[response.OrderResponse] becomes
+// OrderResponsePage.
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	open := strings.Index(name, "[")
+	if open < 0 {
+		return name
+	}
+	var args strings.Builder
+	for _, arg := range strings.Split(name[open+1:len(name)-1], ",") {
+		arg = strings.TrimLeft(arg, "*[]")
+		args.WriteString(arg[strings.LastIndex(arg, ".")+1:])
+	}
+	return args.String() + name[:open]
+}
+
+func (s *schemas) structSchema(t reflect.Type) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	err := eachField(t, "json", func(name string, field reflect.StructField) error {
+		fieldSchema, err := s.schemaOf(field.Type)
+		if err != nil {
+			return errors.Wrapf(err, "[openapi]: Field %s.%s", t, field.Name)
+		}
+		fieldSchema, required := applyBinding(fieldSchema, field)
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = fieldSchema
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// queryParameters describes the fields of a query struct bound with
+// ShouldBindQuery
+func (s *schemas) queryParameters(t reflect.Type) ([]Parameter, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, errors.Errorf("[openapi]: Query %s is not a struct", t)
+	}
+
+	var params []Parameter
+	err := eachField(t, "form", func(name string, field reflect.StructField) error {
+		schema, err := s.schemaOf(field.Type)
+		if err != nil {
+			return errors.Wrapf(err, "[openapi]: Query field %s.%s", t, field.Name)
+		}
+		// A missing parameter is already absent, so it is never null
+		schema.Nullable = false
+		if field.Tag.Get("time_format") == "2006-01-02" {
+			schema.Format = "date"
+		}
+		schema, required := applyBinding(schema, field)
+		params = append(params, Parameter{Name: name, In: "query", Required: required, Schema: schema})
+		return nil
+	})
+	return params, err
+}
+
+// eachField calls fn with the name each exported field of t is bound by under
+// tag, flattening embedded structs the way encoding/json and gin do
+func eachField(t reflect.Type, tag string, fn func(name string, field reflect.StructField) error) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := eachField(embedded, tag, fn); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if err := fn(name, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyBinding narrows schema by the validator rules in the field's binding
+// tag and reports whether the field is required
+func applyBinding(schema *Schema, field reflect.StructField) (*Schema, bool) {
+	tag := field.Tag.Get("binding")
+	if tag == "" {
+		return schema, false
+	}
+
+	kind := field.Type.Kind()
+	if kind == reflect.Pointer {
+		kind = field.Type.Elem().Kind()
+	}
+	// Struct and time fields are references or fixed formats; only required applies
+	target := schema
+	if schema.Ref != "" || len(schema.AllOf) > 0 {
+		target = nil
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			// Later rules apply to the elements
+			break
+		}
+		if name == "required" {
+			required = true
+			continue
+		}
+		if target != nil {
+			applyRule(target, kind, name, param)
+		}
+	}
+	return schema, required
+}
+
+func applyRule(schema *Schema, kind reflect.Kind, name, param string) {
+	switch name {
+	case "min", "gte":
+		bound(schema, kind, param, false, true)
+	case "max", "lte":
+		bound(schema, kind, param, false, false)
+	case "gt":
+		bound(schema, kind, param, true, true)
+	case "lt":
+		bound(schema, kind, param, true, false)
+	case "len":
+		bound(schema, kind, param, false, true)
+		bound(schema, kind, param, false, false)
+	case "oneof":
+		for _, value := range strings.Fields(param) {
+			schema.Enum = append(schema.Enum, enumValue(kind, value))
+		}
+	case "uuid":
+		schema.Format = "uuid"
+	case "email":
+		schema.Format = "email"
+	case "url", "uri":
+		schema.Format = "uri"
+	case "numeric":
+		schema.Pattern = numericPattern
+	}
+}
+
+// bound sets the lower or upper limit param on what kind measures: the value
+// of numbers, the length of strings and the element count of slices
+func bound(schema *Schema, kind reflect.Kind, param string, exclusive, lower bool) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	count := int(value)
+
+	switch kind {
+	case reflect.String:
+		if exclusive {
+			count = nextCount(count, lower)
+		}
+		if lower {
+			schema.MinLength = &count
+		} else {
+			schema.MaxLength = &count
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if exclusive {
+			count = nextCount(count, lower)
+		}
+		if lower {
+			schema.MinItems = &count
+		} else {
+			schema.MaxItems = &count
+		}
+	default:
+		if lower {
+			schema.Minimum = &value
+			schema.ExclusiveMinimum = exclusive
+		} else {
+			schema.Maximum = &value
+			schema.ExclusiveMaximum = exclusive
+		}
+	}
+}
+
+func nextCount(count int, lower bool) int {
+	if lower {
+		return count + 1
+	}
+	return count - 1
+}
+
+func enumValue(kind reflect.Kind, value string) any {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// nullable marks schema as also accepting null. References cannot carry
+// siblings in OpenAPI 3.0, so they are wrapped.
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	schema.Nullable = true
+	return schema
+}
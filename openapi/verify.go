@@ -0,0 +1,594 @@
+package openapi
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// successStatus are the http constants handlers answer with on success
+var successStatus = map[string]int{
+	"StatusOK":        http.StatusOK,
+	"StatusCreated":   http.StatusCreated,
+	"StatusAccepted":  http.StatusAccepted,
+	"StatusNoContent": http.StatusNoContent,
+}
+
+// queryReaders read single query parameters instead of binding a struct
+var queryReaders = map[string]bool{"Query": true, "DefaultQuery": true, "GetQuery": true, "QueryArray": true, "QueryMap": true}
+
+// Verify reads the source of every documented handler in the module at dir and
+// reports where a handler and its operation disagree: the structs bound from
+// the body and the query string, the path parameters read and how they are
+// parsed, and the status and type of what is rendered.
+func (s *Spec) Verify(registered gin.RoutesInfo, dir string) error {
+	module, err := modulePath(dir)
+	if err != nil {
+		return err
+	}
+	src := &source{dir: dir, module: module, fset: token.NewFileSet(), packages: map[string][]*ast.File{}}
+
+	handlers := map[string]string{}
+	for _, info := range registered {
+		handlers[info.Method+" "+info.Path] = info.Handler
+	}
+
+	var problems []string
+	for _, key := range s.keys() {
+		for _, problem := range src.verify(s.routes[key], handlers[key]) {
+			problems = append(problems, key+": "+problem)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.Errorf("[openapi]: Handlers and document disagree:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func modulePath(dir string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", errors.Wrap(err, "[openapi]: Error opening go.mod")
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+	return "", errors.Errorf("[openapi]: No module line in %s", filepath.Join(dir, "go.mod"))
+}
+
+// source parses the packages of one module on demand
+type source struct {
+	dir      string
+	module   string
+	fset     *token.FileSet
+	packages map[string][]*ast.File
+}
+
+func (s *source) files(importPath string) ([]*ast.File, error) {
+	if files, ok := s.packages[importPath]; ok {
+		return files, nil
+	}
+	rel, ok := strings.CutPrefix(importPath, s.module)
+	if !ok {
+		return nil, errors.Errorf("[openapi]: Package %s is outside module %s", importPath, s.module)
+	}
+	pkgDir := filepath.Join(s.dir, filepath.FromSlash(strings.TrimPrefix(rel, "/")))
+
+	entries, err := os.ReadDir(pkgDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[openapi]: Error reading package %s", importPath)
+	}
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(s.fset, filepath.Join(pkgDir, name), nil, 0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[openapi]: Error parsing %s", name)
+		}
+		files = append(files, file)
+	}
+	s.packages[importPath] = files
+	return files, nil
+}
+
+func (s *source) verify(r *route, handler string) []string {
+	match := handlerName.FindStringSubmatch(handler)
+	if match == nil {
+		return []string{"handler " + handler + " is not a handler method"}
+	}
+	importPath, recv, method := match[1], match[2], match[3]
+
+	files, err := s.files(importPath)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	fn, file := findMethod(files, recv, method)
+	if fn == nil {
+		return []string{"method " + recv + "." + method + " not found in " + importPath}
+	}
+
+	facts, problems := s.inspect(files, file, fn)
+	problems = append(problems, compareBound("body", facts.body, r.Body)...)
+	problems = append(problems, compareBound("query", facts.query, r.Query)...)
+
+	documented := map[string]bool{}
+	for _, name := range pathParams(r.path) {
+		documented[name] = true
+	}
+	for _, name := range sortedKeys(facts.params) {
+		if !documented[name] {
+			problems = append(problems, "reads path parameter "+name+" that the route does not have")
+			continue
+		}
+		schema := r.Params[name]
+		if schema == nil {
+			schema = &Schema{Type: "string", Format: "uuid"}
+		}
+		documentedKind := "string"
+		switch {
+		case schema.Type == "integer":
+			documentedKind = "integer"
+		case schema.Format == "uuid":
+			documentedKind = "uuid"
+		}
+		if facts.params[name] != documentedKind {
+			problems = append(problems, "parses path parameter "+name+" as "+facts.params[name]+" but it is documented as "+documentedKind)
+		}
+	}
+	headers := map[string]bool{}
+	for _, name := range r.Headers {
+		headers[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, name := range facts.headers {
+		name = http.CanonicalHeaderKey(name)
+		if !headers[name] && !(name == "Authorization" && r.authenticated) {
+			problems = append(problems, "reads header "+name+" that is not documented")
+		}
+	}
+	for _, name := range facts.queryReads {
+		problems = append(problems, "reads query parameter "+name+" outside the bound query struct")
+	}
+
+	if len(facts.renders) == 0 {
+		problems = append(problems, "renders no success response")
+	}
+	for _, rendered := range facts.renders {
+		if rendered.status != r.status() {
+			problems = append(problems, "answers "+strconv.Itoa(rendered.status)+" but "+strconv.Itoa(r.status())+" is documented")
+		}
+		// A file is streamed after a bare status
+		if rendered.typ == "" && len(r.Files) > 0 {
+			continue
+		}
+		if want := typeName(r.Response); rendered.typ != want {
+			problems = append(problems, "renders "+orNothing(rendered.typ)+" but "+orNothing(want)+" is documented")
+		}
+	}
+	return problems
+}
+
+// handlerFacts is what a handler's source shows about its contract
+type handlerFacts struct {
+	body       string
+	query      string
+	params     map[string]string
+	headers    []string
+	queryReads []string
+	renders    []rendered
+}
+
+type rendered struct {
+	status int
+	typ    string
+}
+
+func (s *source) inspect(files []*ast.File, file *ast.File, fn *ast.FuncDecl) (handlerFacts, []string) {
+	facts := handlerFacts{params: map[string]string{}}
+	var problems []string
+
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 {
+		return facts, []string{"handler does not take a single *gin.Context"}
+	}
+	ctxName := params[0].Names[0].Name
+	isCtxCall := func(call *ast.CallExpr) (string, bool) {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		x, ok := sel.X.(*ast.Ident)
+		return sel.Sel.Name, ok && x.Name == ctxName
+	}
+
+	vars := map[string]string{}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				if n.Type != nil {
+					vars[name.Name] = exprType(n.Type)
+				}
+			}
+		case *ast.CallExpr:
+			// A path parameter is parsed by the call it is passed to
+			for _, arg := range n.Args {
+				if call, ok := arg.(*ast.CallExpr); ok {
+					if name, ok := isCtxCall(call); ok && name == "Param" {
+						facts.params[stringArg(call)] = parseKind(n.Fun)
+					}
+				}
+			}
+
+			name, ok := isCtxCall(n)
+			if !ok {
+				return true
+			}
+			switch {
+			case name == "Param":
+				if _, ok := facts.params[stringArg(n)]; !ok {
+					facts.params[stringArg(n)] = "string"
+				}
+			case name == "ShouldBindJSON" || name == "BindJSON" || name == "ShouldBind":
+				facts.body = boundType(n, vars)
+			case name == "ShouldBindQuery" || name == "BindQuery":
+				facts.query = boundType(n, vars)
+			case name == "GetHeader":
+				header, err := s.constant(files, file, n)
+				if err != nil {
+					problems = append(problems, err.Error())
+					return true
+				}
+				facts.headers = append(facts.headers, header)
+			case queryReaders[name]:
+				facts.queryReads = append(facts.queryReads, stringArg(n))
+			case name == "JSON" || name == "Status":
+				status, err := statusOf(n.Args[0])
+				if err != nil {
+					problems = append(problems, err.Error())
+					return true
+				}
+				r := rendered{status: status}
+				if name == "JSON" && len(n.Args) == 2 {
+					typ, err := s.valueType(files, file, fn, n.Args[1])
+					if err != nil {
+						problems = append(problems, err.Error())
+						return true
+					}
+					r.typ = typ
+				}
+				facts.renders = append(facts.renders, r)
+			}
+		}
+		return true
+	})
+	return facts, problems
+}
+
+// valueType works out the type of a rendered value: a composite literal, or a
+// variable assigned from a method of one of the handler's dependencies
+func (s *source) valueType(files []*ast.File, file *ast.File, fn *ast.FuncDecl, value ast.Expr) (string, error) {
+	if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		value = unary.X
+	}
+	if lit, ok := value.(*ast.CompositeLit); ok {
+		return exprType(lit.Type), nil
+	}
+	ident, ok := value.(*ast.Ident)
+	if !ok {
+		return "", errors.Errorf("cannot tell the type of the rendered %s", types.ExprString(value))
+	}
+
+	var call *ast.CallExpr
+	index := 0
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || call != nil || len(assign.Rhs) != 1 {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			if id, ok := lhs.(*ast.Ident); ok && id.Name == ident.Name {
+				call, _ = assign.Rhs[0].(*ast.CallExpr)
+				index = i
+			}
+		}
+		return true
+	})
+	if call == nil {
+		return "", errors.Errorf("cannot tell the type of the rendered %s", ident.Name)
+	}
+
+	// h.dependency.Method(...)
+	method, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", errors.Errorf("cannot tell the type of the rendered %s", ident.Name)
+	}
+	dependency, ok := method.X.(*ast.SelectorExpr)
+	if !ok {
+		return "", errors.Errorf("cannot tell the type of the rendered %s", ident.Name)
+	}
+	fieldType, fieldFile := findField(files, receiverName(fn), dependency.Sel.Name)
+	iface, ok := fieldType.(*ast.SelectorExpr)
+	if !ok {
+		return "", errors.Errorf("cannot tell the type of %s", dependency.Sel.Name)
+	}
+	pkg, ok := iface.X.(*ast.Ident)
+	if !ok {
+		return "", errors.Errorf("cannot tell the type of %s", dependency.Sel.Name)
+	}
+	ifaceFiles, err := s.files(importPathOf(fieldFile, pkg.Name))
+	if err != nil {
+		return "", err
+	}
+	results := findInterfaceMethod(ifaceFiles, iface.Sel.Name, method.Sel.Name)
+	if index >= len(results) {
+		return "", errors.Errorf("cannot find %s.%s", iface.Sel.Name, method.Sel.Name)
+	}
+	return exprType(results[index]), nil
+}
+
+// constant returns the string passed to call, resolving a constant from
+// another package of the module
+func (s *source) constant(files []*ast.File, file *ast.File, call *ast.CallExpr) (string, error) {
+	if len(call.Args) != 1 {
+		return "", errors.New("cannot tell the argument of " + types.ExprString(call))
+	}
+	switch arg := call.Args[0].(type) {
+	case *ast.BasicLit:
+		return strconv.Unquote(arg.Value)
+	case *ast.SelectorExpr:
+		pkg, ok := arg.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		constFiles, err := s.files(importPathOf(file, pkg.Name))
+		if err != nil {
+			return "", err
+		}
+		if value, ok := findConst(constFiles, arg.Sel.Name); ok {
+			return value, nil
+		}
+	}
+	return "", errors.New("cannot tell the value of " + types.ExprString(call.Args[0]))
+}
+
+func findConst(files []*ast.File, name string) (string, bool) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, ident := range valueSpec.Names {
+					if ident.Name != name || i >= len(valueSpec.Values) {
+						continue
+					}
+					if lit, ok := valueSpec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						value, err := strconv.Unquote(lit.Value)
+						return value, err == nil
+					}
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func findMethod(files []*ast.File, recv, method string) (*ast.FuncDecl, *ast.File) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Name.Name == method && receiverName(fn) == recv {
+				return fn, file
+			}
+		}
+	}
+	return nil, nil
+}
+
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func findField(files []*ast.File, structName, fieldName string) (ast.Expr, *ast.File) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok || typeSpec.Name.Name != structName {
+					continue
+				}
+				st, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					for _, name := range field.Names {
+						if name.Name == fieldName {
+							return field.Type, file
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// findInterfaceMethod returns the result types of a method of an interface
+func findInterfaceMethod(files []*ast.File, ifaceName, methodName string) []ast.Expr {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok || typeSpec.Name.Name != ifaceName {
+					continue
+				}
+				iface, ok := typeSpec.Type.(*ast.InterfaceType)
+				if !ok {
+					continue
+				}
+				for _, m := range iface.Methods.List {
+					if len(m.Names) == 0 || m.Names[0].Name != methodName {
+						continue
+					}
+					fnType := m.Type.(*ast.FuncType)
+					var results []ast.Expr
+					if fnType.Results != nil {
+						for _, result := range fnType.Results.List {
+							for range max(1, len(result.Names)) {
+								results = append(results, result.Type)
+							}
+						}
+					}
+					return results
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// importPathOf resolves a package name used in file to its import path
+func importPathOf(file *ast.File, name string) string {
+	if file == nil {
+		return ""
+	}
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil && imp.Name.Name == name {
+			return path
+		}
+		if imp.Name == nil && path[strings.LastIndex(path, "/")+1:] == name {
+			return path
+		}
+	}
+	return ""
+}
+
+func boundType(call *ast.CallExpr, vars map[string]string) string {
+	if len(call.Args) != 1 {
+		return ""
+	}
+	arg := call.Args[0]
+	if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		arg = unary.X
+	}
+	if ident, ok := arg.(*ast.Ident); ok {
+		return vars[ident.Name]
+	}
+	return ""
+}
+
+func parseKind(fun ast.Expr) string {
+	switch types.ExprString(fun) {
+	case "uuid.Parse", "uuid.MustParse":
+		return "uuid"
+	case "strconv.Atoi", "strconv.ParseInt", "strconv.ParseUint":
+		return "integer"
+	}
+	return "string"
+}
+
+func statusOf(expr ast.Expr) (int, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return strconv.Atoi(e.Value)
+	case *ast.SelectorExpr:
+		if status, ok := successStatus[e.Sel.Name]; ok {
+			return status, nil
+		}
+	}
+	return 0, errors.Errorf("cannot tell the status %s", types.ExprString(expr))
+}
+
+func stringArg(call *ast.CallExpr) string {
+	if len(call.Args) == 0 {
+		return ""
+	}
+	if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+		value, _ := strconv.Unquote(lit.Value)
+		return value
+	}
+	return types.ExprString(call.Args[0])
+}
+
+// exprType names a type the way typeName does, ignoring pointers
+func exprType(expr ast.Expr) string {
+	return strings.ReplaceAll(types.ExprString(expr), "*", "")
+}
+
+// importPaths matches the package paths reflect writes in type arguments
+var importPaths = regexp.MustCompile(`(?:[\w.-]+/)+`)
+
+// typeName names the type of v as it is written in source, ignoring pointers
+func typeName(v any) string {
+	if v == nil {
+		return ""
+	}
+	return strings.ReplaceAll(importPaths.ReplaceAllString(reflect.TypeOf(v).String(), ""), "*", "")
+}
+
+func compareBound(what, bound string, documented any) []string {
+	want := typeName(documented)
+	if bound == want {
+		return nil
+	}
+	return []string{"binds the " + what + " into " + orNothing(bound) + " but " + orNothing(want) + " is documented"}
+}
+
+func orNothing(typ string) string {
+	if typ == "" {
+		return "nothing"
+	}
+	return typ
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
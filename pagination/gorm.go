@@ -0,0 +1,34 @@
+package pagination
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"gorm.io/gorm"
+)
+
+// Find loads one page of the rows db selects, ordered by the sort of params
+func Find[T any](db *gorm.DB, params domain.PageParams, keys Keys[T]) (domain.Page[T], error) {
+	key, after, err := keys.key(params)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+
+	direction, compare := "ASC", ">"
+	if params.Desc {
+		direction, compare = "DESC", "<"
+	}
+	if params.After != nil {
+		db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", key.Expr, keys.IDColumn, compare), after, params.After.ID)
+	}
+
+	var rows []*T
+	err = db.Order(fmt.Sprintf("%s %s, %s %s", key.Expr, direction, keys.IDColumn, direction)).
+		Limit(params.Limit + 1).
+		Find(&rows).Error
+	if err != nil {
+		return domain.Page[T]{}, errors.Wrap(err, "[pagination.Find]: Error querying database")
+	}
+	return newPage(rows, params, key, keys), nil
+}
@@ -0,0 +1,131 @@
+// Package pagination pages collections by keyset: a page ends with a cursor
+// holding the sort value and id of its last row, and the next page starts
+// strictly after that pair. Unlike offsets, rows inserted while a client walks
+// the pages neither repeat nor go missing.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/request"
+)
+
+const (
+	// DefaultLimit is the page size when the client does not ask for one
+	DefaultLimit = 100
+	// MaxLimit matches the binding on request.PageQuery
+	MaxLimit = 500
+)
+
+// Parse reads a page request. sort names a field, prefixed with - for
+// descending order; fallback is used when it is empty.
+func Parse(page request.PageQuery, sort string, fallback string) (domain.PageParams, error) {
+	if sort == "" {
+		sort = fallback
+	}
+	params := domain.PageParams{
+		Sort:  strings.TrimPrefix(sort, "-"),
+		Desc:  strings.HasPrefix(sort, "-"),
+		Limit: page.Limit,
+	}
+	if params.Limit <= 0 {
+		params.Limit = DefaultLimit
+	}
+	if params.Limit > MaxLimit {
+		params.Limit = MaxLimit
+	}
+	if page.Cursor == "" {
+		return params, nil
+	}
+
+	cursor, err := decode(page.Cursor)
+	if err != nil {
+		return domain.PageParams{}, errors.Wrap(invalidCursor("The cursor is not valid"), "[pagination.Parse]")
+	}
+	if cursor.Sort != params.Sort || cursor.Desc != params.Desc {
+		return domain.PageParams{}, errors.Wrap(invalidCursor("The cursor belongs to a different sort"), "[pagination.Parse]")
+	}
+	params.After = cursor
+	return params, nil
+}
+
+func invalidCursor(message string) *domain.Error {
+	return domain.ValidationError(message, map[string]string{"cursor": "invalid"})
+}
+
+func encode(c *domain.PageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(s string) (*domain.PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor domain.PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.Sort == "" || len(cursor.Value) == 0 {
+		return nil, errors.New("incomplete cursor")
+	}
+	return &cursor, nil
+}
+
+// Key is a field a collection can be sorted by
+type Key[T any] struct {
+	// Expr is the SQL expression ordered on. It must never be NULL, so
+	// nullable columns are wrapped in COALESCE.
+	Expr string
+	// Value reads the same value from a loaded row, as a time.Time, int64 or
+	// string that is never nil
+	Value func(row *T) any
+}
+
+// Keys are the sortable fields of a collection, tied by the row id
+type Keys[T any] struct {
+	// IDColumn is the SQL column holding the id
+	IDColumn string
+	ID       func(row *T) uuid.UUID
+	Sorts    map[string]Key[T]
+}
+
+// key returns the sort key of params and the value of its cursor, decoded to
+// the type the key holds
+func (k Keys[T]) key(params domain.PageParams) (Key[T], any, error) {
+	key, ok := k.Sorts[params.Sort]
+	if !ok {
+		return Key[T]{}, nil, errors.Wrap(domain.ValidationError("Unknown sort", map[string]string{"sort": "oneof"}), "[pagination]")
+	}
+	if params.After == nil {
+		return key, nil, nil
+	}
+	value := reflect.New(reflect.TypeOf(key.Value(new(T))))
+	if err := json.Unmarshal(params.After.Value, value.Interface()); err != nil {
+		return Key[T]{}, nil, errors.Wrap(invalidCursor("The cursor is not valid"), "[pagination]")
+	}
+	return key, value.Elem().Interface(), nil
+}
+
+// newPage cuts rows, fetched with one extra row, to the limit and points the
+// cursor at the last row kept when the extra one shows that more follow
+func newPage[T any](rows []*T, params domain.PageParams, key Key[T], keys Keys[T]) domain.Page[T] {
+	if rows == nil {
+		rows = []*T{}
+	}
+	if len(rows) <= params.Limit {
+		return domain.Page[T]{Items: rows}
+	}
+	rows = rows[:params.Limit]
+	last := rows[len(rows)-1]
+	value, _ := json.Marshal(key.Value(last))
+	next := encode(&domain.PageCursor{Sort: params.Sort, Desc: params.Desc, Value: value, ID: keys.ID(last)})
+	return domain.Page[T]{Items: rows, NextCursor: &next}
+}
@@ -0,0 +1,54 @@
+package pagination
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pubestpubest/pos-backend/domain"
+)
+
+// Slice pages rows held in memory the way Find pages a query
+func Slice[T any](rows []*T, params domain.PageParams, keys Keys[T]) (domain.Page[T], error) {
+	key, after, err := keys.key(params)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+
+	order := func(value any, id string, row *T) int {
+		c := compare(value, key.Value(row))
+		if c == 0 {
+			c = strings.Compare(id, keys.ID(row).String())
+		}
+		if params.Desc {
+			return -c
+		}
+		return c
+	}
+	slices.SortStableFunc(rows, func(a, b *T) int {
+		return order(key.Value(a), keys.ID(a).String(), b)
+	})
+
+	if params.After != nil {
+		id := params.After.ID.String()
+		rows = slices.DeleteFunc(rows, func(row *T) bool { return order(after, id, row) >= 0 })
+	}
+	if len(rows) > params.Limit+1 {
+		rows = rows[:params.Limit+1]
+	}
+	return newPage(rows, params, key, keys), nil
+}
+
+// compare orders two values of a key like the database does
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	case string:
+		return strings.Compare(a, b.(string))
+	}
+	return 0
+}
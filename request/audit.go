@@ -0,0 +1,14 @@
+package request
+
+import "time"
+
+// AuditLogQuery filters audit entries, newest first
+type AuditLogQuery struct {
+	PageQuery
+	ActorID    string     `form:"actor_id" binding:"omitempty,uuid"`
+	Action     string     `form:"action"`
+	EntityType string     `form:"entity_type"`
+	EntityID   string     `form:"entity_id"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
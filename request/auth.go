@@ -9,3 +9,19 @@ type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=6"`
 }
+
+// ResetPasswordRequest is used by operators to set a password without the old one
+type ResetPasswordRequest struct {
+	Username    string `json:"username" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+type SetPinRequest struct {
+	Password string `json:"password" binding:"required"`
+	Pin      string `json:"pin" binding:"required,numeric,min=4,max=8"`
+}
+
+// LoginHistoryQuery pages a user's login attempts, newest first
+type LoginHistoryQuery struct {
+	PageQuery
+}
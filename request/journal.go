@@ -0,0 +1,28 @@
+package request
+
+import "github.com/google/uuid"
+
+// LedgerAccountRequest maps one purpose to an account. CategoryID narrows a
+// sales account to the sales of one category.
+type LedgerAccountRequest struct {
+	Purpose    string     `json:"purpose" binding:"required,oneof=sales vat_output service_charge discounts cash card promptpay refunds over_short"`
+	CategoryID *uuid.UUID `json:"category_id"`
+	Code       string     `json:"code" binding:"required"`
+	Name       string     `json:"name"`
+}
+
+// LedgerAccountsRequest replaces the whole chart-of-accounts mapping
+type LedgerAccountsRequest struct {
+	Accounts []LedgerAccountRequest `json:"accounts" binding:"dive"`
+}
+
+// JournalQuery picks how a journal entry is rendered, JSON unless asked otherwise
+type JournalQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// JournalExportListQuery pages the exported business days
+type JournalExportListQuery struct {
+	PageQuery
+	Sort string `form:"sort" binding:"omitempty,oneof=business_date -business_date"`
+}
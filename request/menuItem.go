@@ -10,3 +10,17 @@ type MenuItemRequest struct {
 	Active     *bool      `json:"active"`
 	ImageURL   *string    `json:"image_url"`
 }
+
+type MenuItemListQuery struct {
+	PageQuery
+	Sort       string `form:"sort" binding:"omitempty,oneof=name -name price_baht -price_baht"`
+	CategoryID string `form:"category_id" binding:"omitempty,uuid"`
+	Active     *bool  `form:"active"`
+}
+
+// MenuItemAvailabilityRequest replaces an item's availability. Leaving out
+// remaining_portions stops counting its portions.
+type MenuItemAvailabilityRequest struct {
+	Status            string `json:"status" binding:"required,oneof=available low_stock sold_out"`
+	RemainingPortions *int   `json:"remaining_portions" binding:"omitempty,min=0"`
+}
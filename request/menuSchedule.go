@@ -0,0 +1,40 @@
+package request
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MenuScheduleRequest puts either a category or a menu item on the menu on
+// days, from start to end in the restaurant's time zone. Without start and end
+// it is open all day; an end not after the start is on the next day.
+type MenuScheduleRequest struct {
+	Name       *string                        `json:"name"`
+	CategoryID *uuid.UUID                     `json:"category_id"`
+	MenuItemID *uuid.UUID                     `json:"menu_item_id"`
+	AreaID     *uuid.UUID                     `json:"area_id"`
+	Days       []string                       `json:"days" binding:"required,min=1,dive,oneof=sun mon tue wed thu fri sat"`
+	Start      *string                        `json:"start" binding:"omitempty,datetime=15:04"`
+	End        *string                        `json:"end" binding:"omitempty,datetime=15:04"`
+	Exceptions []MenuScheduleExceptionRequest `json:"exceptions" binding:"dive"`
+}
+
+// MenuScheduleExceptionRequest opens or closes a schedule for a whole date
+type MenuScheduleExceptionRequest struct {
+	Date string `json:"date" binding:"required,datetime=2006-01-02"`
+	Open *bool  `json:"open" binding:"required"`
+}
+
+type MenuScheduleListQuery struct {
+	CategoryID string `form:"category_id" binding:"omitempty,uuid"`
+	MenuItemID string `form:"menu_item_id" binding:"omitempty,uuid"`
+}
+
+// MenuQuery picks when, and in which area or at which table, to show the menu.
+// It is now and anywhere unless given.
+type MenuQuery struct {
+	At      *time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+	AreaID  string     `form:"area_id" binding:"omitempty,uuid"`
+	TableID string     `form:"table_id" binding:"omitempty,uuid"`
+}
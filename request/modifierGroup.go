@@ -0,0 +1,20 @@
+package request
+
+import "github.com/google/uuid"
+
+// ModifierGroupRequest offers options, in order, on the menu items and the
+// categories given. Leave max_selections out for no limit.
+type ModifierGroupRequest struct {
+	Name          string                       `json:"name" binding:"required"`
+	MinSelections int                          `json:"min_selections" binding:"min=0"`
+	MaxSelections *int                         `json:"max_selections" binding:"omitempty,min=1"`
+	SortOrder     int                          `json:"sort_order"`
+	Options       []ModifierGroupOptionRequest `json:"options" binding:"required,min=1,dive"`
+	MenuItemIDs   []uuid.UUID                  `json:"menu_item_ids"`
+	CategoryIDs   []uuid.UUID                  `json:"category_ids"`
+}
+
+type ModifierGroupOptionRequest struct {
+	ModifierID uuid.UUID `json:"modifier_id" binding:"required"`
+	Default    bool      `json:"default"`
+}
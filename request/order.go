@@ -1,6 +1,10 @@
 package request
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type OrderCreateRequest struct {
 	TableID  uuid.UUID `json:"table_id" binding:"required"`
@@ -19,3 +23,73 @@ type AddOrderItemRequest struct {
 type UpdateOrderItemQuantityRequest struct {
 	Quantity int `json:"quantity" binding:"required,min=1"`
 }
+
+type ApplyDiscountRequest struct {
+	DiscountBaht *int64 `json:"discount_baht" binding:"required,min=0"`
+}
+
+type VoidOrderRequest struct {
+	ReasonCode string `json:"reason_code" binding:"required"`
+}
+
+type CancelOrderItemRequest struct {
+	ReasonCode string `json:"reason_code" binding:"required"`
+}
+
+type VoidReportQuery struct {
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// OrderFilter selects orders. From is inclusive and To exclusive, both on
+// created_at.
+type OrderFilter struct {
+	Status   string     `form:"status" binding:"omitempty,oneof=open paid void"`
+	TableID  string     `form:"table_id" binding:"omitempty,uuid"`
+	AreaID   string     `form:"area_id" binding:"omitempty,uuid"`
+	Source   string     `form:"source" binding:"omitempty,oneof=staff customer"`
+	OpenedBy string     `form:"opened_by" binding:"omitempty,uuid"`
+	From     *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// OrderListQuery filters and pages orders. Expand=items adds each order's
+// items to its summary.
+type OrderListQuery struct {
+	PageQuery
+	OrderFilter
+	Sort   string `form:"sort" binding:"omitempty,oneof=created_at -created_at total_baht -total_baht"`
+	Expand string `form:"expand" binding:"omitempty,oneof=items"`
+}
+
+// OrderExportQuery exports the orders OrderListQuery would list, one line per
+// item with its modifiers
+type OrderExportQuery struct {
+	OrderFilter
+	Sort   string `form:"sort" binding:"omitempty,oneof=created_at -created_at total_baht -total_baht"`
+	Format string `form:"format" binding:"required,oneof=csv xlsx"`
+}
+
+// VoidFilter selects voided orders and cancelled items. From is inclusive and
+// To exclusive, both on the time of the void.
+type VoidFilter struct {
+	Kind       string     `form:"kind" binding:"omitempty,oneof=order item"`
+	ReasonCode string     `form:"reason_code"`
+	VoidedBy   string     `form:"voided_by" binding:"omitempty,uuid"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// VoidListQuery filters and pages voids, newest first unless sorted
+type VoidListQuery struct {
+	PageQuery
+	VoidFilter
+	Sort string `form:"sort" binding:"omitempty,oneof=voided_at -voided_at value_baht -value_baht"`
+}
+
+// VoidExportQuery exports the voids VoidListQuery would list
+type VoidExportQuery struct {
+	VoidFilter
+	Sort   string `form:"sort" binding:"omitempty,oneof=voided_at -voided_at value_baht -value_baht"`
+	Format string `form:"format" binding:"required,oneof=csv xlsx"`
+}
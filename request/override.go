@@ -0,0 +1,12 @@
+package request
+
+import "github.com/google/uuid"
+
+type OverrideRequest struct {
+	Action           string     `json:"action" binding:"required,oneof=order.void order.item_remove order.discount order.reopen"`
+	OrderID          uuid.UUID  `json:"order_id" binding:"required"`
+	OrderItemID      *uuid.UUID `json:"order_item_id"`
+	ApproverUsername string     `json:"approver_username" binding:"required"`
+	ApproverSecret   string     `json:"approver_secret" binding:"required"`
+	Reason           string     `json:"reason" binding:"required"`
+}
@@ -0,0 +1,8 @@
+package request
+
+// PageQuery selects one page of a collection. Cursor is the next_cursor of the
+// previous page; it is only valid with the sort that produced it.
+type PageQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
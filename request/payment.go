@@ -1,6 +1,10 @@
 package request
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type PaymentRequest struct {
 	OrderID     uuid.UUID `json:"order_id" binding:"required"`
@@ -9,3 +13,28 @@ type PaymentRequest struct {
 	Provider    *string   `json:"provider"`
 	ProviderRef *string   `json:"provider_ref"`
 }
+
+// PaymentFilter selects payments. From is inclusive and To exclusive, both on
+// created_at.
+type PaymentFilter struct {
+	Method string     `form:"method" binding:"omitempty,oneof=cash card promptpay"`
+	Status string     `form:"status" binding:"omitempty,oneof=succeeded pending failed"`
+	From   *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	// OrderID is set from the path of /orders/:id/payments
+	OrderID *uuid.UUID `form:"-"`
+}
+
+// PaymentListQuery filters and pages payments
+type PaymentListQuery struct {
+	PageQuery
+	PaymentFilter
+	Sort string `form:"sort" binding:"omitempty,oneof=created_at -created_at amount_baht -amount_baht"`
+}
+
+// PaymentExportQuery exports the payments PaymentListQuery would list
+type PaymentExportQuery struct {
+	PaymentFilter
+	Sort   string `form:"sort" binding:"omitempty,oneof=created_at -created_at amount_baht -amount_baht"`
+	Format string `form:"format" binding:"required,oneof=csv xlsx"`
+}
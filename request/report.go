@@ -0,0 +1,18 @@
+package request
+
+import "time"
+
+// SalesReportQuery picks a sales report over the business dates from through
+// to, both included
+type SalesReportQuery struct {
+	By   string     `form:"by" binding:"required,oneof=day hour category menu_item modifier payment_method area table staff source"`
+	From *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// SalesReportExportQuery exports the rows of the sales report
+// SalesReportQuery picks
+type SalesReportExportQuery struct {
+	SalesReportQuery
+	Format string `form:"format" binding:"required,oneof=csv xlsx"`
+}
@@ -19,3 +19,9 @@ type UserUpdateRequest struct {
 type AssignRoleRequest struct {
 	RoleID int `json:"role_id" binding:"required"`
 }
+
+type UserListQuery struct {
+	PageQuery
+	Sort   string `form:"sort" binding:"omitempty,oneof=username -username created_at -created_at"`
+	Status string `form:"status" binding:"omitempty,oneof=active locked"`
+}
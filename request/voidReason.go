@@ -0,0 +1,8 @@
+package request
+
+type VoidReasonRequest struct {
+	Code         string `json:"code" binding:"required"`
+	Label        string `json:"label" binding:"required"`
+	Active       *bool  `json:"active"`
+	DisplayOrder *int   `json:"display_order"`
+}
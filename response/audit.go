@@ -0,0 +1,22 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLogResponse struct {
+	ID          uuid.UUID       `json:"id"`
+	ActorID     *uuid.UUID      `json:"actor_id"`
+	ActorName   string          `json:"actor_name"`
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	BeforeState json.RawMessage `json:"before"`
+	AfterState  json.RawMessage `json:"after"`
+	ClientIP    string          `json:"client_ip"`
+	RequestID   string          `json:"request_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
@@ -2,6 +2,8 @@ package response
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type AuthResponse struct {
@@ -14,3 +16,16 @@ type AuthResponse struct {
 type PermissionCheckResponse struct {
 	HasPermission bool `json:"has_permission"`
 }
+
+type LoginAttemptResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	ClientIP  string    `json:"client_ip"`
+	Result    string    `json:"result"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MeResponse struct {
+	User        UserResponse `json:"user"`
+	Permissions []string     `json:"permissions"`
+}
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LedgerAccountResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Purpose    string     `json:"purpose"`
+	CategoryID *uuid.UUID `json:"category_id"`
+	Code       string     `json:"code"`
+	Name       string     `json:"name"`
+}
+
+// JournalEntryResponse is the balanced journal entry of one business day.
+// Export is null until the day has been exported.
+type JournalEntryResponse struct {
+	BusinessDate string                 `json:"business_date"`
+	DebitBaht    int64                  `json:"debit_baht"`
+	CreditBaht   int64                  `json:"credit_baht"`
+	Lines        []JournalLineResponse  `json:"lines"`
+	Export       *JournalExportResponse `json:"export"`
+}
+
+// JournalLineResponse debits or credits one account; the other side is zero
+type JournalLineResponse struct {
+	LineNo      int    `json:"line_no"`
+	Purpose     string `json:"purpose"`
+	AccountCode string `json:"account_code"`
+	AccountName string `json:"account_name"`
+	Description string `json:"description"`
+	DebitBaht   int64  `json:"debit_baht"`
+	CreditBaht  int64  `json:"credit_baht"`
+}
+
+// JournalExportResponse records when and by whom a business day was exported
+type JournalExportResponse struct {
+	BusinessDate string     `json:"business_date"`
+	ExportedAt   time.Time  `json:"exported_at"`
+	ExportedBy   *uuid.UUID `json:"exported_by"`
+	DebitBaht    int64      `json:"debit_baht"`
+	CreditBaht   int64      `json:"credit_baht"`
+}
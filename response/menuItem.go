@@ -1,6 +1,10 @@
 package response
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type MenuItemResponse struct {
 	ID         uuid.UUID `json:"id"`
@@ -9,4 +13,24 @@ type MenuItemResponse struct {
 	Active     bool      `json:"active"`
 	ImageURL   string    `json:"image_url"`
 	CategoryID uuid.UUID `json:"category_id"`
+	// Availability is available, low_stock or sold_out
+	Availability      string `json:"availability"`
+	RemainingPortions *int   `json:"remaining_portions"`
+	// Offered is whether the schedules of the item and its category put it on
+	// the menu at the time asked for, now unless given
+	Offered bool `json:"offered"`
+	// ModifierGroups are the choices offered on the item, its own and its
+	// category's, in order
+	ModifierGroups []MenuItemModifierGroupResponse `json:"modifier_groups"`
+}
+
+// MenuItemAvailabilityResponse is what terminals and menus need to tell
+// whether an item can be ordered
+type MenuItemAvailabilityResponse struct {
+	MenuItemID uuid.UUID `json:"menu_item_id"`
+	Active     bool      `json:"active"`
+	// Status is available, low_stock or sold_out
+	Status            string     `json:"status"`
+	RemainingPortions *int       `json:"remaining_portions"`
+	UpdatedAt         *time.Time `json:"updated_at"`
 }
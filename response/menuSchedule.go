@@ -0,0 +1,20 @@
+package response
+
+import "github.com/google/uuid"
+
+type MenuScheduleResponse struct {
+	ID         uuid.UUID                       `json:"id"`
+	Name       string                          `json:"name"`
+	CategoryID *uuid.UUID                      `json:"category_id"`
+	MenuItemID *uuid.UUID                      `json:"menu_item_id"`
+	AreaID     *uuid.UUID                      `json:"area_id"`
+	Days       []string                        `json:"days"`
+	Start      *string                         `json:"start"`
+	End        *string                         `json:"end"`
+	Exceptions []MenuScheduleExceptionResponse `json:"exceptions"`
+}
+
+type MenuScheduleExceptionResponse struct {
+	Date string `json:"date"`
+	Open bool   `json:"open"`
+}
@@ -0,0 +1,6 @@
+package response
+
+// MessageResponse confirms an action that has nothing else to return
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import "github.com/google/uuid"
+
+type ModifierGroupResponse struct {
+	ID            uuid.UUID                     `json:"id"`
+	Name          string                        `json:"name"`
+	MinSelections int                           `json:"min_selections"`
+	MaxSelections *int                          `json:"max_selections"`
+	SortOrder     int                           `json:"sort_order"`
+	Options       []ModifierGroupOptionResponse `json:"options"`
+	MenuItemIDs   []uuid.UUID                   `json:"menu_item_ids"`
+	CategoryIDs   []uuid.UUID                   `json:"category_ids"`
+}
+
+type ModifierGroupOptionResponse struct {
+	ModifierID     uuid.UUID `json:"modifier_id"`
+	Name           string    `json:"name"`
+	PriceDeltaBaht int64     `json:"price_delta_baht"`
+	Default        bool      `json:"default"`
+}
+
+// MenuItemModifierGroupResponse is a group as offered on a menu item
+type MenuItemModifierGroupResponse struct {
+	ID            uuid.UUID                     `json:"id"`
+	Name          string                        `json:"name"`
+	MinSelections int                           `json:"min_selections"`
+	MaxSelections *int                          `json:"max_selections"`
+	Options       []ModifierGroupOptionResponse `json:"options"`
+}
@@ -18,10 +18,36 @@ type OrderResponse struct {
 	TotalBaht    int64               `json:"total_baht"`
 	Note         string              `json:"note"`
 	CreatedAt    time.Time           `json:"created_at"`
+	BusinessDate *string             `json:"business_date"`
 	ClosedAt     *time.Time          `json:"closed_at"`
+	VoidReason   string              `json:"void_reason"`
+	VoidedBy     *uuid.UUID          `json:"voided_by"`
+	VoidedAt     *time.Time          `json:"voided_at"`
 	Items        []OrderItemResponse `json:"items"`
 }
 
+// OrderSummaryResponse is an order as lists show it. Age runs from opening
+// until the order was closed or voided, or until now while it is open. Items
+// is only present when the list was asked to expand=items.
+type OrderSummaryResponse struct {
+	ID           uuid.UUID           `json:"id"`
+	TableID      uuid.UUID           `json:"table_id"`
+	TableName    string              `json:"table_name"`
+	Status       string              `json:"status"`
+	Source       string              `json:"source"`
+	OpenedBy     uuid.UUID           `json:"opened_by"`
+	OpenerName   string              `json:"opener_name"`
+	ItemCount    int64               `json:"item_count"`
+	TotalBaht    int64               `json:"total_baht"`
+	PaidBaht     int64               `json:"paid_baht"`
+	BalanceBaht  int64               `json:"balance_baht"`
+	AgeSeconds   int64               `json:"age_seconds"`
+	CreatedAt    time.Time           `json:"created_at"`
+	BusinessDate *string             `json:"business_date"`
+	ClosedAt     *time.Time          `json:"closed_at"`
+	Items        []OrderItemResponse `json:"items,omitempty"`
+}
+
 type OrderItemResponse struct {
 	ID            uuid.UUID                   `json:"id"`
 	MenuItemID    uuid.UUID                   `json:"menu_item_id"`
@@ -30,6 +56,10 @@ type OrderItemResponse struct {
 	UnitPriceBaht int64                       `json:"unit_price_baht"`
 	LineTotalBaht int64                       `json:"line_total_baht"`
 	Note          string                      `json:"note"`
+	SentAt        *time.Time                  `json:"sent_at"`
+	CancelledAt   *time.Time                  `json:"cancelled_at"`
+	CancelledBy   *uuid.UUID                  `json:"cancelled_by"`
+	CancelReason  string                      `json:"cancel_reason"`
 	Modifiers     []OrderItemModifierResponse `json:"modifiers"`
 }
 
@@ -38,3 +68,44 @@ type OrderItemModifierResponse struct {
 	ModifierName   string    `json:"modifier_name"`
 	PriceDeltaBaht int64     `json:"price_delta_baht"`
 }
+
+// VoidResponse is a voided order or a cancelled item. ID is the id of either;
+// the menu item and quantity are only set for items.
+type VoidResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	Kind         string     `json:"kind"`
+	OrderID      uuid.UUID  `json:"order_id"`
+	TableName    string     `json:"table_name"`
+	MenuItemName string     `json:"menu_item_name"`
+	Quantity     *int64     `json:"quantity"`
+	ValueBaht    int64      `json:"value_baht"`
+	ReasonCode   string     `json:"reason_code"`
+	ReasonLabel  string     `json:"reason_label"`
+	VoidedBy     *uuid.UUID `json:"voided_by"`
+	VoiderName   string     `json:"voider_name"`
+	VoidedAt     time.Time  `json:"voided_at"`
+	BusinessDate *string    `json:"business_date"`
+}
+
+type VoidReportResponse struct {
+	From           time.Time                  `json:"from"`
+	To             time.Time                  `json:"to"`
+	TotalCount     int64                      `json:"total_count"`
+	TotalValueBaht int64                      `json:"total_value_baht"`
+	ByReason       []VoidReportReasonResponse `json:"by_reason"`
+	ByStaff        []VoidReportStaffResponse  `json:"by_staff"`
+}
+
+type VoidReportReasonResponse struct {
+	ReasonCode  string `json:"reason_code"`
+	ReasonLabel string `json:"reason_label"`
+	Count       int64  `json:"count"`
+	ValueBaht   int64  `json:"value_baht"`
+}
+
+type VoidReportStaffResponse struct {
+	UserID    *uuid.UUID `json:"user_id"`
+	FullName  string     `json:"full_name"`
+	Count     int64      `json:"count"`
+	ValueBaht int64      `json:"value_baht"`
+}
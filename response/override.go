@@ -0,0 +1,23 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OverrideResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Token         string     `json:"token,omitempty"`
+	Action        string     `json:"action"`
+	OrderID       uuid.UUID  `json:"order_id"`
+	OrderItemID   *uuid.UUID `json:"order_item_id"`
+	RequestedBy   uuid.UUID  `json:"requested_by"`
+	RequesterName string     `json:"requester_name"`
+	ApprovedBy    uuid.UUID  `json:"approved_by"`
+	ApproverName  string     `json:"approver_name"`
+	Reason        string     `json:"reason"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	UsedAt        *time.Time `json:"used_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package response
+
+// Page is one page of a collection. NextCursor is null on the last page.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// SinglePage wraps a collection that is always returned whole
+func SinglePage[T any](items []T) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{Items: items}
+}
@@ -0,0 +1,30 @@
+package response
+
+// SalesReportResponse is one sales report over the business dates from through
+// to. Totals cover the whole range; rows can add up to more orders than it, since
+// one order can hold items of several categories or be paid by several methods.
+type SalesReportResponse struct {
+	From       string                   `json:"from"`
+	To         string                   `json:"to"`
+	By         string                   `json:"by"`
+	VATPercent float64                  `json:"vat_percent"`
+	Totals     SalesFiguresResponse     `json:"totals"`
+	Rows       []SalesReportRowResponse `json:"rows"`
+}
+
+type SalesFiguresResponse struct {
+	OrderCount        int64 `json:"order_count"`
+	GrossBaht         int64 `json:"gross_baht"`
+	DiscountBaht      int64 `json:"discount_baht"`
+	VATBaht           int64 `json:"vat_baht"`
+	NetBaht           int64 `json:"net_baht"`
+	AverageTicketBaht int64 `json:"average_ticket_baht"`
+}
+
+type SalesReportRowResponse struct {
+	// Key is the business date, local hour, id, method or source grouped on; it
+	// is null for orders outside any group
+	Key   *string `json:"key"`
+	Label string  `json:"label"`
+	SalesFiguresResponse
+}
@@ -0,0 +1,11 @@
+package response
+
+import "github.com/google/uuid"
+
+type VoidReasonResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Code         string    `json:"code"`
+	Label        string    `json:"label"`
+	Active       bool      `json:"active"`
+	DisplayOrder int       `json:"display_order"`
+}
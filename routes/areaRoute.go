@@ -1,26 +1,24 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/domain"
 	areaHandler "github.com/pubestpubest/pos-backend/feature/area/delivery"
-	areaRepository "github.com/pubestpubest/pos-backend/feature/area/repository"
-	areaUsecase "github.com/pubestpubest/pos-backend/feature/area/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func AreaRoutes(v1 *gin.RouterGroup) {
-	areaRepository := areaRepository.NewAreaRepository(database.DB)
-	areaUsecase := areaUsecase.NewAreaUsecase(areaRepository)
+func AreaRoutes(v1 *openapi.Router, areaUsecase domain.AreaUsecase) {
 	areaHandler := areaHandler.NewAreaHandler(areaUsecase)
 
-	areaRoutes := v1.Group("/areas")
-	areaRoutes.Use(middlewares.AuthMiddleware())
+	areaRoutes := v1.Group("/areas", "Areas").Authenticated()
 	{
-		areaRoutes.GET("", areaHandler.GetAllAreas)
-		areaRoutes.GET("/:id", areaHandler.GetAreaByID)
-		areaRoutes.POST("", areaHandler.CreateArea)
-		areaRoutes.PUT("/:id", areaHandler.UpdateArea)
-		areaRoutes.DELETE("/:id", areaHandler.DeleteArea)
+		areaRoutes.GET("", areaHandler.GetAllAreas, openapi.Operation{Summary: "List areas", Response: response.Page[response.AreaResponse]{}})
+		areaRoutes.GET("/:id", areaHandler.GetAreaByID, openapi.Operation{Summary: "Get an area", Response: response.AreaResponse{}})
+		areaRoutes.POST("", areaHandler.CreateArea, openapi.Operation{Summary: "Create an area", Body: request.AreaRequest{}, Response: response.AreaResponse{}, Status: http.StatusCreated})
+		areaRoutes.PUT("/:id", areaHandler.UpdateArea, openapi.Operation{Summary: "Update an area", Body: request.AreaRequest{}, Response: response.AreaResponse{}})
+		areaRoutes.DELETE("/:id", areaHandler.DeleteArea, openapi.Operation{Summary: "Delete an area", Response: response.MessageResponse{}})
 	}
 }
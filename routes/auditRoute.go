@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	auditHandler "github.com/pubestpubest/pos-backend/feature/audit/delivery"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+func AuditRoutes(v1 *openapi.Router, auditUsecase domain.AuditUsecase) {
+	auditHandler := auditHandler.NewAuditHandler(auditUsecase)
+
+	auditRoutes := v1.Group("/audit", "Audit").RequirePermission(constant.AuditPermission)
+	{
+		auditRoutes.GET("", auditHandler.GetAuditLogs, openapi.Operation{
+			Summary:  "Search the audit log",
+			Query:    request.AuditLogQuery{},
+			Response: response.Page[response.AuditLogResponse]{},
+		})
+	}
+}
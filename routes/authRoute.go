@@ -1,31 +1,41 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
 	authHandler "github.com/pubestpubest/pos-backend/feature/auth/delivery"
-	authRepository "github.com/pubestpubest/pos-backend/feature/auth/repository"
-	authUsecase "github.com/pubestpubest/pos-backend/feature/auth/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func AuthRoutes(v1 *gin.RouterGroup) {
-	authRepository := authRepository.NewAuthRepository(database.DB)
-	authUsecase := authUsecase.NewAuthUsecase(authRepository)
+func AuthRoutes(v1 *openapi.Router, authUsecase domain.AuthUsecase) {
 	authHandler := authHandler.NewAuthHandler(authUsecase)
 
-	authRoutes := v1.Group("/auth")
+	authRoutes := v1.Group("/auth", "Auth")
 	{
 		// Public routes
-		authRoutes.POST("/login", authHandler.Login)
+		authRoutes.POST("/login", authHandler.Login, openapi.Operation{
+			Summary:     "Log in",
+			Description: "Returns a session token to send as a bearer token. Repeated failures lock the account.",
+			Body:        request.LoginRequest{},
+			Response:    response.AuthResponse{},
+		})
 
 		// Protected routes
-		protected := authRoutes.Group("")
-		protected.Use(middlewares.AuthMiddleware())
+		protected := authRoutes.Authenticated()
 		{
-			protected.POST("/logout", authHandler.Logout)
-			protected.POST("/change-password", authHandler.ChangePassword)
-			protected.GET("/me", authHandler.GetMe)
+			protected.POST("/logout", authHandler.Logout, openapi.Operation{Summary: "End the current session", Response: response.MessageResponse{}})
+			protected.POST("/change-password", authHandler.ChangePassword, openapi.Operation{Summary: "Change the caller's password", Body: request.ChangePasswordRequest{}, Response: response.MessageResponse{}})
+			protected.POST("/pin", authHandler.SetPin, openapi.Operation{Summary: "Set the caller's PIN", Body: request.SetPinRequest{}, Response: response.MessageResponse{}})
+			protected.GET("/me", authHandler.GetMe, openapi.Operation{Summary: "Get the caller and their permissions", Response: response.MeResponse{}})
+		}
+
+		// Admin routes
+		admin := authRoutes.Group("/users/:id", "Auth").RequirePermission(constant.UserManagePermission)
+		{
+			admin.POST("/unlock", authHandler.UnlockUser, openapi.Operation{Summary: "Unlock a locked account", Response: response.MessageResponse{}})
+			admin.GET("/login-history", authHandler.GetLoginHistory, openapi.Operation{Summary: "List a user's login attempts", Query: request.LoginHistoryQuery{}, Response: response.Page[response.LoginAttemptResponse]{}})
 		}
 	}
 }
@@ -1,26 +1,24 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/domain"
 	categoryHandler "github.com/pubestpubest/pos-backend/feature/category/delivery"
-	categoryRepository "github.com/pubestpubest/pos-backend/feature/category/repository"
-	categoryUsecase "github.com/pubestpubest/pos-backend/feature/category/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func CategoryRoutes(v1 *gin.RouterGroup) {
-	categoryRepository := categoryRepository.NewCategoryRepository(database.DB)
-	categoryUsecase := categoryUsecase.NewCategoryUsecase(categoryRepository)
+func CategoryRoutes(v1 *openapi.Router, categoryUsecase domain.CategoryUsecase) {
 	categoryHandler := categoryHandler.NewCategoryHandler(categoryUsecase)
 
-	categoryRoutes := v1.Group("/categories")
-	categoryRoutes.Use(middlewares.AuthMiddleware())
+	categoryRoutes := v1.Group("/categories", "Categories").Authenticated()
 	{
-		categoryRoutes.GET("", categoryHandler.GetAllCategories)
-		categoryRoutes.GET("/:id", categoryHandler.GetCategoryByID)
-		categoryRoutes.POST("", categoryHandler.CreateCategory)
-		categoryRoutes.PUT("/:id", categoryHandler.UpdateCategory)
-		categoryRoutes.DELETE("/:id", categoryHandler.DeleteCategory)
+		categoryRoutes.GET("", categoryHandler.GetAllCategories, openapi.Operation{Summary: "List categories", Response: response.Page[response.CategoryResponse]{}})
+		categoryRoutes.GET("/:id", categoryHandler.GetCategoryByID, openapi.Operation{Summary: "Get a category", Response: response.CategoryResponse{}})
+		categoryRoutes.POST("", categoryHandler.CreateCategory, openapi.Operation{Summary: "Create a category", Body: request.CategoryRequest{}, Response: response.CategoryResponse{}, Status: http.StatusCreated})
+		categoryRoutes.PUT("/:id", categoryHandler.UpdateCategory, openapi.Operation{Summary: "Update a category", Body: request.CategoryRequest{}, Response: response.CategoryResponse{}})
+		categoryRoutes.DELETE("/:id", categoryHandler.DeleteCategory, openapi.Operation{Summary: "Delete a category", Response: response.MessageResponse{}})
 	}
 }
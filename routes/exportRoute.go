@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	exportHandler "github.com/pubestpubest/pos-backend/feature/export/delivery"
+	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/spreadsheet"
+)
+
+const exportDescription = "Streams every match as CSV (UTF-8 with a byte order mark) or XLSX, in the restaurant's time zone. Errors found before the first row are answered as JSON; a failure after it drops the connection."
+
+// ExportRoutes registers the exports, which may run for up to timeout
+func ExportRoutes(v1 *openapi.Router, exportUsecase domain.ExportUsecase, timeout time.Duration) {
+	exportHandler := exportHandler.NewExportHandler(exportUsecase)
+
+	exportRoutes := v1.Group("/exports", "Exports").Use(middlewares.LongRequest(timeout)).RequirePermission(constant.ReportPermission)
+	{
+		exportRoutes.GET("/orders", exportHandler.ExportOrders, openapi.Operation{
+			Summary:     "Export orders, a line per item",
+			Description: "Takes the filters and sort of GET /v1/orders. Each item is a line with its modifiers, repeating its order's figures; an order without items is a line of its own. " + exportDescription,
+			Query:       request.OrderExportQuery{},
+			Files:       spreadsheet.MediaTypes,
+		})
+		exportRoutes.GET("/payments", exportHandler.ExportPayments, openapi.Operation{
+			Summary:     "Export payments",
+			Description: "Takes the filters and sort of GET /v1/payments. " + exportDescription,
+			Query:       request.PaymentExportQuery{},
+			Files:       spreadsheet.MediaTypes,
+		})
+		exportRoutes.GET("/voids", exportHandler.ExportVoids, openapi.Operation{
+			Summary:     "Export voided orders and cancelled items",
+			Description: "Takes the filters and sort of GET /v1/orders/voids. " + exportDescription,
+			Query:       request.VoidExportQuery{},
+			Files:       spreadsheet.MediaTypes,
+		})
+		exportRoutes.GET("/sales", exportHandler.ExportSalesReport, openapi.Operation{
+			Summary:     "Export a sales report",
+			Description: "The rows of GET /v1/reports/sales followed by a Total line. " + exportDescription,
+			Query:       request.SalesReportExportQuery{},
+			Files:       spreadsheet.MediaTypes,
+		})
+	}
+}
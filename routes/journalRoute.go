@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	journalHandler "github.com/pubestpubest/pos-backend/feature/journal/delivery"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+// businessDateParam documents a business date in the path
+var businessDateParam = map[string]*openapi.Schema{"date": {Type: "string", Format: "date"}}
+
+const journalDescription = "Paid orders of the business date are posted as one balanced entry: payments debited by method and discounts net of VAT, sales credited net of VAT by category and VAT to output VAT, with payments over or short of the orders' totals to over/short. format=csv answers a CSV file of the lines instead of JSON."
+
+func JournalRoutes(v1 *openapi.Router, journalUsecase domain.JournalUsecase) {
+	journalHandler := journalHandler.NewJournalHandler(journalUsecase)
+
+	journalRoutes := v1.Group("/journal", "Journal").RequirePermission(constant.JournalPermission)
+	{
+		journalRoutes.GET("/accounts", journalHandler.GetLedgerAccounts, openapi.Operation{
+			Summary:  "List the chart-of-accounts mapping",
+			Response: response.Page[response.LedgerAccountResponse]{},
+		})
+		journalRoutes.PUT("/accounts", journalHandler.ReplaceLedgerAccounts, openapi.Operation{
+			Summary:     "Replace the chart-of-accounts mapping",
+			Description: "Maps each purpose to one account. Sales may also be mapped per category; categories without an account of their own post to the sales account without a category.",
+			Body:        request.LedgerAccountsRequest{},
+			Response:    response.Page[response.LedgerAccountResponse]{},
+		})
+		journalRoutes.GET("/entries/:date", journalHandler.GetJournalEntry, openapi.Operation{
+			Summary:     "Get the journal entry of a business date",
+			Description: "The lines as exported once the date has been, or as its orders stand until then. " + journalDescription,
+			Query:       request.JournalQuery{},
+			Params:      businessDateParam,
+			Response:    response.JournalEntryResponse{},
+			Files:       []string{"text/csv"},
+		})
+		journalRoutes.POST("/exports/:date", journalHandler.ExportJournalEntry, openapi.Operation{
+			Summary:     "Export the journal entry of a business date",
+			Description: "Records the entry of a business date that is over and has no open orders. Each date is exported once; exporting it again is a conflict. " + journalDescription,
+			Query:       request.JournalQuery{},
+			Params:      businessDateParam,
+			Response:    response.JournalEntryResponse{},
+			Files:       []string{"text/csv"},
+			Status:      http.StatusCreated,
+		})
+		journalRoutes.GET("/exports", journalHandler.GetJournalExports, openapi.Operation{
+			Summary:  "List the exported business dates",
+			Query:    request.JournalExportListQuery{},
+			Response: response.Page[response.JournalExportResponse]{},
+		})
+	}
+}
@@ -1,27 +1,55 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
 	menuItemHandler "github.com/pubestpubest/pos-backend/feature/menuItem/delivery"
-	menuItemRepository "github.com/pubestpubest/pos-backend/feature/menuItem/repository"
-	menuItemUsecase "github.com/pubestpubest/pos-backend/feature/menuItem/usecase"
 	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func MenuItemRoutes(v1 *gin.RouterGroup) {
-	menuItemRepository := menuItemRepository.NewMenuItemRepository(database.DB)
-	menuItemUsecase := menuItemUsecase.NewMenuItemUsecase(menuItemRepository)
+func MenuItemRoutes(v1 *openapi.Router, menuItemUsecase domain.MenuItemUsecase) {
 	menuItemHandler := menuItemHandler.NewMenuItemHandler(menuItemUsecase)
 
-	menuItemRoutes := v1.Group("/menu-items")
-	menuItemRoutes.Use(middlewares.AuthMiddleware())
+	menuItemRoutes := v1.Group("/menu-items", "Menu items")
 	{
-		menuItemRoutes.GET("", menuItemHandler.GetAllMenuItems)
-		menuItemRoutes.GET("/modifiers", menuItemHandler.GetAvailableModifiers)
-		menuItemRoutes.GET("/:id", menuItemHandler.GetMenuItemByID)
-		menuItemRoutes.POST("", menuItemHandler.CreateMenuItem)
-		menuItemRoutes.PUT("/:id", menuItemHandler.UpdateMenuItem)
-		menuItemRoutes.DELETE("/:id", menuItemHandler.DeleteMenuItem)
+		// Public routes, for the menus guests open from a table's QR code
+		menuItemRoutes.GET("/menu", menuItemHandler.GetMenu, openapi.Operation{
+			Summary:     "List the menu items offered",
+			Description: "Active menu items whose day-part schedules, and those of their category, are open at the given time (now by default) in the restaurant's time zone. Items scheduled for some areas only are listed when one of them is given by area_id, or by table_id for a table in it.",
+			Query:       request.MenuQuery{},
+			Response:    response.Page[response.MenuItemResponse]{},
+		})
+		menuItemRoutes.GET("/availability", menuItemHandler.GetAvailability, openapi.Operation{Summary: "List whether each menu item can be ordered", Response: response.Page[response.MenuItemAvailabilityResponse]{}})
+		// The stream stays open past the request and write timeouts
+		menuItemRoutes.Use(middlewares.LongRequest(0)).GET("/availability/stream", menuItemHandler.StreamAvailability, openapi.Operation{
+			Summary:     "Watch whether each menu item can be ordered",
+			Description: "Server-sent events named availability, each a menu item availability: one per item on connect, then one per change. A ping comment is sent every 15 seconds while nothing changes. The stream stays open until the server shuts down; reconnect to continue.",
+			Files:       []string{"text/event-stream"},
+		})
+
+		protected := menuItemRoutes.Authenticated()
+		{
+			protected.GET("", menuItemHandler.GetAllMenuItems, openapi.Operation{Summary: "List menu items", Query: request.MenuItemListQuery{}, Response: response.Page[response.MenuItemResponse]{}})
+			protected.GET("/modifiers", menuItemHandler.GetAvailableModifiers, openapi.Operation{
+				Summary:     "List every modifier",
+				Description: "An item only takes the modifiers of its modifier_groups.",
+				Response:    response.Page[response.ModifierResponse]{},
+			})
+			protected.GET("/:id", menuItemHandler.GetMenuItemByID, openapi.Operation{Summary: "Get a menu item", Response: response.MenuItemResponse{}})
+			protected.POST("", menuItemHandler.CreateMenuItem, openapi.Operation{Summary: "Create a menu item", Body: request.MenuItemRequest{}, Response: response.MenuItemResponse{}, Status: http.StatusCreated})
+			protected.PUT("/:id", menuItemHandler.UpdateMenuItem, openapi.Operation{Summary: "Update a menu item", Body: request.MenuItemRequest{}, Response: response.MenuItemResponse{}})
+			protected.DELETE("/:id", menuItemHandler.DeleteMenuItem, openapi.Operation{Summary: "Delete a menu item", Response: response.MessageResponse{}})
+			protected.RequirePermission(constant.MenuAvailabilityPermission).PUT("/:id/availability", menuItemHandler.SetAvailability, openapi.Operation{
+				Summary:     "Set whether a menu item can be ordered",
+				Description: "Marks the item available, low on stock or sold out. remaining_portions counts its portions down as it is ordered; the item is sold out at zero. Leave it out to stop counting.",
+				Body:        request.MenuItemAvailabilityRequest{},
+				Response:    response.MenuItemAvailabilityResponse{},
+			})
+		}
 	}
 }
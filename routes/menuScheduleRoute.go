@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	menuScheduleHandler "github.com/pubestpubest/pos-backend/feature/menuSchedule/delivery"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+func MenuScheduleRoutes(v1 *openapi.Router, menuScheduleUsecase domain.MenuScheduleUsecase) {
+	menuScheduleHandler := menuScheduleHandler.NewMenuScheduleHandler(menuScheduleUsecase)
+
+	menuScheduleRoutes := v1.Group("/menu-schedules", "Menu schedules").Authenticated()
+	{
+		menuScheduleRoutes.GET("", menuScheduleHandler.GetMenuSchedules, openapi.Operation{Summary: "List menu schedules", Query: request.MenuScheduleListQuery{}, Response: response.Page[response.MenuScheduleResponse]{}})
+		menuScheduleRoutes.GET("/:id", menuScheduleHandler.GetMenuScheduleByID, openapi.Operation{Summary: "Get a menu schedule", Response: response.MenuScheduleResponse{}})
+
+		manage := menuScheduleRoutes.RequirePermission(constant.MenuManagePermission)
+		{
+			manage.POST("", menuScheduleHandler.CreateMenuSchedule, openapi.Operation{
+				Summary:     "Create a menu schedule",
+				Description: "Puts a category or a menu item on the menu on days, from start to end in the restaurant's time zone, optionally in one area only. An end not after the start closes the next day. Exceptions open or close the schedule for whole dates.",
+				Body:        request.MenuScheduleRequest{},
+				Response:    response.MenuScheduleResponse{},
+				Status:      http.StatusCreated,
+			})
+			manage.PUT("/:id", menuScheduleHandler.UpdateMenuSchedule, openapi.Operation{Summary: "Update a menu schedule", Body: request.MenuScheduleRequest{}, Response: response.MenuScheduleResponse{}})
+			manage.DELETE("/:id", menuScheduleHandler.DeleteMenuSchedule, openapi.Operation{Summary: "Delete a menu schedule", Response: response.MessageResponse{}})
+		}
+	}
+}
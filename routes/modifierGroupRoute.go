@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	modifierGroupHandler "github.com/pubestpubest/pos-backend/feature/modifierGroup/delivery"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+func ModifierGroupRoutes(v1 *openapi.Router, modifierGroupUsecase domain.ModifierGroupUsecase) {
+	modifierGroupHandler := modifierGroupHandler.NewModifierGroupHandler(modifierGroupUsecase)
+
+	modifierGroupRoutes := v1.Group("/modifier-groups", "Modifier groups").Authenticated()
+	{
+		modifierGroupRoutes.GET("", modifierGroupHandler.GetAllModifierGroups, openapi.Operation{Summary: "List modifier groups", Response: response.Page[response.ModifierGroupResponse]{}})
+		modifierGroupRoutes.GET("/:id", modifierGroupHandler.GetModifierGroupByID, openapi.Operation{Summary: "Get a modifier group", Response: response.ModifierGroupResponse{}})
+
+		manage := modifierGroupRoutes.RequirePermission(constant.MenuManagePermission)
+		{
+			manage.POST("", modifierGroupHandler.CreateModifierGroup, openapi.Operation{
+				Summary:     "Create a modifier group",
+				Description: "Offers modifiers, in the order given, on the menu items and categories given. An order item must choose between min_selections and max_selections of them; leave max_selections out for no limit. Default options are chosen when an order item names no modifiers.",
+				Body:        request.ModifierGroupRequest{},
+				Response:    response.ModifierGroupResponse{},
+				Status:      http.StatusCreated,
+			})
+			manage.PUT("/:id", modifierGroupHandler.UpdateModifierGroup, openapi.Operation{Summary: "Update a modifier group", Body: request.ModifierGroupRequest{}, Response: response.ModifierGroupResponse{}})
+			manage.DELETE("/:id", modifierGroupHandler.DeleteModifierGroup, openapi.Operation{Summary: "Delete a modifier group", Response: response.MessageResponse{}})
+		}
+	}
+}
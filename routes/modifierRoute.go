@@ -1,26 +1,24 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/domain"
 	modifierHandler "github.com/pubestpubest/pos-backend/feature/modifier/delivery"
-	modifierRepository "github.com/pubestpubest/pos-backend/feature/modifier/repository"
-	modifierUsecase "github.com/pubestpubest/pos-backend/feature/modifier/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func ModifierRoutes(v1 *gin.RouterGroup) {
-	modifierRepository := modifierRepository.NewModifierRepository(database.DB)
-	modifierUsecase := modifierUsecase.NewModifierUsecase(modifierRepository)
+func ModifierRoutes(v1 *openapi.Router, modifierUsecase domain.ModifierUsecase) {
 	modifierHandler := modifierHandler.NewModifierHandler(modifierUsecase)
 
-	modifierRoutes := v1.Group("/modifiers")
-	modifierRoutes.Use(middlewares.AuthMiddleware())
+	modifierRoutes := v1.Group("/modifiers", "Modifiers").Authenticated()
 	{
-		modifierRoutes.GET("", modifierHandler.GetAllModifiers)
-		modifierRoutes.GET("/:id", modifierHandler.GetModifierByID)
-		modifierRoutes.POST("", modifierHandler.CreateModifier)
-		modifierRoutes.PUT("/:id", modifierHandler.UpdateModifier)
-		modifierRoutes.DELETE("/:id", modifierHandler.DeleteModifier)
+		modifierRoutes.GET("", modifierHandler.GetAllModifiers, openapi.Operation{Summary: "List modifiers", Response: response.Page[response.ModifierResponse]{}})
+		modifierRoutes.GET("/:id", modifierHandler.GetModifierByID, openapi.Operation{Summary: "Get a modifier", Response: response.ModifierResponse{}})
+		modifierRoutes.POST("", modifierHandler.CreateModifier, openapi.Operation{Summary: "Create a modifier", Body: request.ModifierRequest{}, Response: response.ModifierResponse{}, Status: http.StatusCreated})
+		modifierRoutes.PUT("/:id", modifierHandler.UpdateModifier, openapi.Operation{Summary: "Update a modifier", Body: request.ModifierRequest{}, Response: response.ModifierResponse{}})
+		modifierRoutes.DELETE("/:id", modifierHandler.DeleteModifier, openapi.Operation{Summary: "Delete a modifier", Response: response.MessageResponse{}})
 	}
 }
@@ -1,37 +1,95 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
 	orderHandler "github.com/pubestpubest/pos-backend/feature/order/delivery"
-	orderRepository "github.com/pubestpubest/pos-backend/feature/order/repository"
-	orderUsecase "github.com/pubestpubest/pos-backend/feature/order/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func OrderRoutes(v1 *gin.RouterGroup) {
-	orderRepository := orderRepository.NewOrderRepository(database.DB)
-	orderUsecase := orderUsecase.NewOrderUsecase(orderRepository)
+// overrideHeaders carry the manager override token of actions that need one
+var overrideHeaders = []string{constant.OverrideTokenHeader}
+
+const overrideDescription = "Needs a single-use manager override for this action, issued by POST /v1/overrides, in X-Override-Token."
+
+func OrderRoutes(v1 *openapi.Router, orderUsecase domain.OrderUsecase) {
 	orderHandler := orderHandler.NewOrderHandler(orderUsecase)
 
-	orderRoutes := v1.Group("/orders")
-	orderRoutes.Use(middlewares.AuthMiddleware())
+	orderRoutes := v1.Group("/orders", "Orders").Authenticated()
 	{
-		orderRoutes.GET("", orderHandler.GetAllOrders)
-		orderRoutes.GET("/open", orderHandler.GetOpenOrders)
-		orderRoutes.GET("/:id", orderHandler.GetOrderByID)
-		orderRoutes.POST("", orderHandler.CreateOrder)
-		orderRoutes.POST("/:id/items", orderHandler.AddItemToOrder)
-		orderRoutes.DELETE("/:id/items/:item_id", orderHandler.RemoveItemFromOrder)
-		orderRoutes.PUT("/:id/items/:item_id/quantity", orderHandler.UpdateOrderItemQuantity)
-		orderRoutes.PUT("/:id/close", orderHandler.CloseOrder)
-		orderRoutes.PUT("/:id/void", orderHandler.VoidOrder)
+		orderRoutes.GET("", orderHandler.GetAllOrders, openapi.Operation{Summary: "List orders", Query: request.OrderListQuery{}, Response: response.Page[response.OrderSummaryResponse]{}})
+		orderRoutes.GET("/open", orderHandler.GetOpenOrders, openapi.Operation{
+			Summary:     "List open orders",
+			Description: "The status filter is ignored.",
+			Query:       request.OrderListQuery{},
+			Response:    response.Page[response.OrderSummaryResponse]{},
+		})
+		orderRoutes.GET("/void-report", orderHandler.GetVoidReport, openapi.Operation{
+			Summary:    "Summarise voids by reason and staff",
+			Query:      request.VoidReportQuery{},
+			Response:   response.VoidReportResponse{},
+			Permission: constant.VoidReportPermission,
+		})
+		orderRoutes.GET("/voids", orderHandler.GetVoids, openapi.Operation{
+			Summary:     "List voided orders and cancelled items",
+			Description: "Each is listed at its own void time, so an item cancelled before its order was voided appears on its own. Newest first unless sorted.",
+			Query:       request.VoidListQuery{},
+			Response:    response.Page[response.VoidResponse]{},
+			Permission:  constant.VoidReportPermission,
+		})
+		orderRoutes.GET("/:id", orderHandler.GetOrderByID, openapi.Operation{Summary: "Get an order", Response: response.OrderResponse{}})
+		orderRoutes.POST("", orderHandler.CreateOrder, openapi.Operation{Summary: "Open an order on a table", Body: request.OrderCreateRequest{}, Response: response.OrderResponse{}, Status: http.StatusCreated})
+		orderRoutes.POST("/:id/items", orderHandler.AddItemToOrder, openapi.Operation{Summary: "Add an item to an order", Body: request.AddOrderItemRequest{}, Response: response.OrderResponse{}})
+		orderRoutes.PUT("/:id/items/:item_id/cancel", orderHandler.CancelOrderItem, openapi.Operation{
+			Summary:     "Cancel an item",
+			Description: "Items already sent to the kitchen need an override. " + overrideDescription,
+			Body:        request.CancelOrderItemRequest{},
+			Response:    response.OrderResponse{},
+			Headers:     overrideHeaders,
+		})
+		orderRoutes.PUT("/:id/items/:item_id/quantity", orderHandler.UpdateOrderItemQuantity, openapi.Operation{
+			Summary:     "Change an item's quantity",
+			Description: "Lowering the quantity of an item already sent to the kitchen needs an override. " + overrideDescription,
+			Body:        request.UpdateOrderItemQuantityRequest{},
+			Response:    response.OrderResponse{},
+			Headers:     overrideHeaders,
+		})
+		orderRoutes.PUT("/:id/send", orderHandler.SendOrderToKitchen, openapi.Operation{Summary: "Send unsent items to the kitchen", Response: response.OrderResponse{}})
+		orderRoutes.PUT("/:id/discount", orderHandler.ApplyDiscount, openapi.Operation{
+			Summary:     "Apply a discount",
+			Description: overrideDescription,
+			Body:        request.ApplyDiscountRequest{},
+			Response:    response.OrderResponse{},
+			Headers:     overrideHeaders,
+		})
+		orderRoutes.PUT("/:id/close", orderHandler.CloseOrder, openapi.Operation{Summary: "Close a fully paid order", Response: response.OrderResponse{}})
+		orderRoutes.PUT("/:id/reopen", orderHandler.ReopenOrder, openapi.Operation{
+			Summary:     "Reopen a closed order",
+			Description: overrideDescription,
+			Response:    response.OrderResponse{},
+			Headers:     overrideHeaders,
+		})
+		orderRoutes.PUT("/:id/void", orderHandler.VoidOrder, openapi.Operation{
+			Summary:     "Void an order",
+			Description: overrideDescription,
+			Body:        request.VoidOrderRequest{},
+			Response:    response.MessageResponse{},
+			Headers:     overrideHeaders,
+		})
 	}
 
 	// Table-specific routes
-	tableOrderRoutes := v1.Group("/tables/:id/orders")
-	tableOrderRoutes.Use(middlewares.AuthMiddleware())
+	tableOrderRoutes := v1.Group("/tables/:id/orders", "Orders").Authenticated()
 	{
-		tableOrderRoutes.GET("", orderHandler.GetOrdersByTable)
+		tableOrderRoutes.GET("", orderHandler.GetOrdersByTable, openapi.Operation{
+			Summary:     "List a table's orders",
+			Description: "The table_id filter is ignored.",
+			Query:       request.OrderListQuery{},
+			Response:    response.Page[response.OrderSummaryResponse]{},
+		})
 	}
 }
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/domain"
+	overrideHandler "github.com/pubestpubest/pos-backend/feature/override/delivery"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+func OverrideRoutes(v1 *openapi.Router, overrideUsecase domain.OverrideUsecase) {
+	overrideHandler := overrideHandler.NewOverrideHandler(overrideUsecase)
+
+	overrideRoutes := v1.Group("/overrides", "Overrides").Authenticated()
+	{
+		overrideRoutes.POST("", overrideHandler.IssueOverride, openapi.Operation{
+			Summary:     "Issue a manager override",
+			Description: "A manager approves one action on one order with their credentials. The token returned is sent by the requesting staff member in X-Override-Token.",
+			Body:        request.OverrideRequest{},
+			Response:    response.OverrideResponse{},
+			Status:      http.StatusCreated,
+		})
+	}
+
+	// Order-specific override routes
+	orderOverrideRoutes := v1.Group("/orders/:id/overrides", "Overrides").Authenticated()
+	{
+		orderOverrideRoutes.GET("", overrideHandler.GetOverridesByOrder, openapi.Operation{Summary: "List an order's overrides", Response: response.Page[response.OverrideResponse]{}})
+	}
+}
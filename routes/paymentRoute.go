@@ -1,32 +1,29 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/domain"
 	paymentHandler "github.com/pubestpubest/pos-backend/feature/payment/delivery"
-	paymentRepository "github.com/pubestpubest/pos-backend/feature/payment/repository"
-	paymentUsecase "github.com/pubestpubest/pos-backend/feature/payment/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func PaymentRoutes(v1 *gin.RouterGroup) {
-	paymentRepository := paymentRepository.NewPaymentRepository(database.DB)
-	paymentUsecase := paymentUsecase.NewPaymentUsecase(paymentRepository)
+func PaymentRoutes(v1 *openapi.Router, paymentUsecase domain.PaymentUsecase) {
 	paymentHandler := paymentHandler.NewPaymentHandler(paymentUsecase)
 
-	paymentRoutes := v1.Group("/payments")
-	paymentRoutes.Use(middlewares.AuthMiddleware())
+	paymentRoutes := v1.Group("/payments", "Payments").Authenticated()
 	{
-		paymentRoutes.GET("", paymentHandler.GetAllPayments)
-		paymentRoutes.GET("/:id", paymentHandler.GetPaymentByID)
-		paymentRoutes.POST("", paymentHandler.ProcessPayment)
-		paymentRoutes.GET("/methods", paymentHandler.GetPaymentMethods)
+		paymentRoutes.GET("", paymentHandler.GetAllPayments, openapi.Operation{Summary: "List payments", Query: request.PaymentListQuery{}, Response: response.Page[response.PaymentResponse]{}})
+		paymentRoutes.GET("/:id", paymentHandler.GetPaymentByID, openapi.Operation{Summary: "Get a payment", Response: response.PaymentResponse{}})
+		paymentRoutes.POST("", paymentHandler.ProcessPayment, openapi.Operation{Summary: "Record a payment against an open order", Body: request.PaymentRequest{}, Response: response.PaymentResponse{}, Status: http.StatusCreated})
+		paymentRoutes.GET("/methods", paymentHandler.GetPaymentMethods, openapi.Operation{Summary: "List payment methods", Response: response.Page[response.PaymentMethodResponse]{}})
 	}
 
 	// Order-specific payment routes
-	orderPaymentRoutes := v1.Group("/orders/:id/payments")
-	orderPaymentRoutes.Use(middlewares.AuthMiddleware())
+	orderPaymentRoutes := v1.Group("/orders/:id/payments", "Payments").Authenticated()
 	{
-		orderPaymentRoutes.GET("", paymentHandler.GetPaymentsByOrder)
+		orderPaymentRoutes.GET("", paymentHandler.GetPaymentsByOrder, openapi.Operation{Summary: "List an order's payments", Query: request.PaymentListQuery{}, Response: response.Page[response.PaymentResponse]{}})
 	}
 }
@@ -1,22 +1,17 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
 	permissionHandler "github.com/pubestpubest/pos-backend/feature/permission/delivery"
-	permissionRepository "github.com/pubestpubest/pos-backend/feature/permission/repository"
-	permissionUsecase "github.com/pubestpubest/pos-backend/feature/permission/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func PermissionRoutes(v1 *gin.RouterGroup) {
-	permissionRepository := permissionRepository.NewPermissionRepository(database.DB)
-	permissionUsecase := permissionUsecase.NewPermissionUsecase(permissionRepository)
+func PermissionRoutes(v1 *openapi.Router, permissionUsecase domain.PermissionUsecase) {
 	permissionHandler := permissionHandler.NewPermissionHandler(permissionUsecase)
 
-	permissionRoutes := v1.Group("/permissions")
-	permissionRoutes.Use(middlewares.AuthMiddleware())
+	permissionRoutes := v1.Group("/permissions", "Roles").Authenticated()
 	{
-		permissionRoutes.GET("", permissionHandler.GetAllPermissions)
+		permissionRoutes.GET("", permissionHandler.GetAllPermissions, openapi.Operation{Summary: "List permissions", Response: response.Page[response.PermissionResponse]{}})
 	}
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	reportHandler "github.com/pubestpubest/pos-backend/feature/report/delivery"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+func ReportRoutes(v1 *openapi.Router, reportUsecase domain.ReportUsecase) {
+	reportHandler := reportHandler.NewReportHandler(reportUsecase)
+
+	reportRoutes := v1.Group("/reports", "Reports").RequirePermission(constant.ReportPermission)
+	{
+		reportRoutes.GET("/sales", reportHandler.GetSalesReport, openapi.Operation{
+			Summary:     "Summarise paid orders",
+			Description: "Gross, discounts, VAT, net, order count and average ticket of the orders paid on the business dates from through to, grouped by business date or hour of payment, category, menu item, modifier, payment method, area, table, opening staff member or order source.",
+			Query:       request.SalesReportQuery{},
+			Response:    response.SalesReportResponse{},
+		})
+	}
+}
@@ -1,23 +1,22 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
 	roleHandler "github.com/pubestpubest/pos-backend/feature/role/delivery"
-	roleRepository "github.com/pubestpubest/pos-backend/feature/role/repository"
-	roleUsecase "github.com/pubestpubest/pos-backend/feature/role/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func RoleRoutes(v1 *gin.RouterGroup) {
-	roleRepository := roleRepository.NewRoleRepository(database.DB)
-	roleUsecase := roleUsecase.NewRoleUsecase(roleRepository)
+func RoleRoutes(v1 *openapi.Router, roleUsecase domain.RoleUsecase) {
 	roleHandler := roleHandler.NewRoleHandler(roleUsecase)
 
-	roleRoutes := v1.Group("/roles")
-	roleRoutes.Use(middlewares.AuthMiddleware())
+	roleRoutes := v1.Group("/roles", "Roles").Authenticated()
 	{
-		roleRoutes.GET("", roleHandler.GetAllRoles)
-		roleRoutes.GET("/:id", roleHandler.GetRoleWithPermissions)
+		roleRoutes.GET("", roleHandler.GetAllRoles, openapi.Operation{Summary: "List roles", Response: response.Page[response.RoleResponse]{}})
+		roleRoutes.GET("/:id", roleHandler.GetRoleWithPermissions, openapi.Operation{
+			Summary:  "Get a role with its permissions",
+			Response: response.RoleResponse{},
+			Params:   map[string]*openapi.Schema{"id": {Type: "integer"}},
+		})
 	}
 }
@@ -1,24 +1,20 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"github.com/pubestpubest/pos-backend/domain"
 	tableHandler "github.com/pubestpubest/pos-backend/feature/table/delivery"
-	tableRepository "github.com/pubestpubest/pos-backend/feature/table/repository"
-	tableUsecase "github.com/pubestpubest/pos-backend/feature/table/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func TableRoutes(v1 *gin.RouterGroup) {
-	tableRepository := tableRepository.NewTableRepository(database.DB)
-	tableUsecase := tableUsecase.NewTableUsecase(tableRepository)
+func TableRoutes(v1 *openapi.Router, tableUsecase domain.TableUsecase) {
 	tableHandler := tableHandler.NewTableHandler(tableUsecase)
 
-	tableRoutes := v1.Group("/tables")
-	tableRoutes.Use(middlewares.AuthMiddleware())
+	tableRoutes := v1.Group("/tables", "Tables").Authenticated()
 	{
-		tableRoutes.GET("", tableHandler.GetAllTables)
-		tableRoutes.GET("/:id", tableHandler.GetTableByID)
-		tableRoutes.PUT("/:id/status", tableHandler.UpdateTableStatus)
+		tableRoutes.GET("", tableHandler.GetAllTables, openapi.Operation{Summary: "List tables", Response: response.Page[response.TableResponse]{}})
+		tableRoutes.GET("/:id", tableHandler.GetTableByID, openapi.Operation{Summary: "Get a table", Response: response.TableResponse{}})
+		tableRoutes.PUT("/:id/status", tableHandler.UpdateTableStatus, openapi.Operation{Summary: "Set a table's status", Body: request.UpdateTableStatusRequest{}, Response: response.MessageResponse{}})
 	}
 }
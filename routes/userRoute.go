@@ -1,26 +1,24 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pubestpubest/pos-backend/database"
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/domain"
 	userHandler "github.com/pubestpubest/pos-backend/feature/user/delivery"
-	userRepository "github.com/pubestpubest/pos-backend/feature/user/repository"
-	userUsecase "github.com/pubestpubest/pos-backend/feature/user/usecase"
-	"github.com/pubestpubest/pos-backend/middlewares"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
 )
 
-func UserRoutes(v1 *gin.RouterGroup) {
-	userRepository := userRepository.NewUserRepository(database.DB)
-	userUsecase := userUsecase.NewUserUsecase(userRepository)
+func UserRoutes(v1 *openapi.Router, userUsecase domain.UserUsecase) {
 	userHandler := userHandler.NewUserHandler(userUsecase)
 
-	userRoutes := v1.Group("/users")
-	userRoutes.Use(middlewares.AuthMiddleware())
+	userRoutes := v1.Group("/users", "Users").Authenticated()
 	{
-		userRoutes.GET("", userHandler.GetAllUsers)
-		userRoutes.GET("/:id", userHandler.GetUserByID)
-		userRoutes.POST("", userHandler.CreateUser)
-		userRoutes.PUT("/:id", userHandler.UpdateUser)
-		userRoutes.POST("/:id/roles", userHandler.AssignRole)
+		userRoutes.GET("", userHandler.GetAllUsers, openapi.Operation{Summary: "List users", Query: request.UserListQuery{}, Response: response.Page[response.UserResponse]{}})
+		userRoutes.GET("/:id", userHandler.GetUserByID, openapi.Operation{Summary: "Get a user", Response: response.UserResponse{}})
+		userRoutes.POST("", userHandler.CreateUser, openapi.Operation{Summary: "Create a user", Body: request.UserCreateRequest{}, Response: response.UserResponse{}, Status: http.StatusCreated})
+		userRoutes.PUT("/:id", userHandler.UpdateUser, openapi.Operation{Summary: "Update a user", Body: request.UserUpdateRequest{}, Response: response.UserResponse{}})
+		userRoutes.POST("/:id/roles", userHandler.AssignRole, openapi.Operation{Summary: "Assign a role to a user", Body: request.AssignRoleRequest{}, Response: response.MessageResponse{}})
 	}
 }
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/pubestpubest/pos-backend/constant"
+	"github.com/pubestpubest/pos-backend/domain"
+	voidReasonHandler "github.com/pubestpubest/pos-backend/feature/voidReason/delivery"
+	"github.com/pubestpubest/pos-backend/openapi"
+	"github.com/pubestpubest/pos-backend/request"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+func VoidReasonRoutes(v1 *openapi.Router, voidReasonUsecase domain.VoidReasonUsecase) {
+	voidReasonHandler := voidReasonHandler.NewVoidReasonHandler(voidReasonUsecase)
+
+	voidReasonRoutes := v1.Group("/void-reasons", "Void reasons").Authenticated()
+	{
+		voidReasonRoutes.GET("", voidReasonHandler.GetAllVoidReasons, openapi.Operation{Summary: "List void reasons", Response: response.Page[response.VoidReasonResponse]{}})
+		// Managers who approve voids maintain the list of reasons
+		voidReasonRoutes.POST("", voidReasonHandler.CreateVoidReason, openapi.Operation{
+			Summary:    "Create a void reason",
+			Body:       request.VoidReasonRequest{},
+			Response:   response.VoidReasonResponse{},
+			Status:     http.StatusCreated,
+			Permission: constant.OverridePermission,
+		})
+		voidReasonRoutes.PUT("/:id", voidReasonHandler.UpdateVoidReason, openapi.Operation{
+			Summary:    "Update a void reason",
+			Body:       request.VoidReasonRequest{},
+			Response:   response.VoidReasonResponse{},
+			Permission: constant.OverridePermission,
+		})
+	}
+}
@@ -16,18 +16,22 @@ var SeedPermissions = []SeedPermission{
 	{Code: "order.create", Description: "Create orders"},
 	{Code: "order.update", Description: "Update orders"},
 	{Code: "order.pay", Description: "Take payments"},
+	{Code: "order.override", Description: "Approve voids, discounts, reopens and sent item removals"},
 	{Code: "menu.manage", Description: "CRUD menu & modifiers"},
+	{Code: "menu.availability", Description: "Mark menu items sold out or low on stock and count their portions"},
 	{Code: "table.manage", Description: "CRUD tables/areas"},
 	{Code: "user.manage", Description: "Manage users & roles"},
 	{Code: "report.view", Description: "View reports/dashboard"},
+	{Code: "audit.view", Description: "View audit log"},
+	{Code: "journal.export", Description: "Map ledger accounts and export the accounting journal"},
 }
 
 var SeedRolePermissions = map[string][]string{
-	"owner":   {"order.create", "order.update", "order.pay", "menu.manage", "table.manage", "user.manage", "report.view"},
-	"manager": {"order.create", "order.update", "order.pay", "menu.manage", "table.manage", "report.view"},
+	"owner":   {"order.create", "order.update", "order.pay", "order.override", "menu.manage", "menu.availability", "table.manage", "user.manage", "report.view", "audit.view", "journal.export"},
+	"manager": {"order.create", "order.update", "order.pay", "order.override", "menu.manage", "menu.availability", "table.manage", "report.view"},
 	"cashier": {"order.pay", "report.view"},
-	"waiter":  {"order.create", "order.update"},
-	"kitchen": {"order.update"},
+	"waiter":  {"order.create", "order.update", "menu.availability"},
+	"kitchen": {"order.update", "menu.availability"},
 }
 
 // Admin user
@@ -107,6 +111,23 @@ var SeedUsers = []SeedUser{
 	},
 }
 
+// Reason codes offered for voids and item cancellations
+type SeedVoidReason struct {
+	Code         string
+	Label        string
+	DisplayOrder int
+}
+
+var SeedVoidReasons = []SeedVoidReason{
+	{"customer_changed_mind", "Customer changed mind", 1},
+	{"entered_in_error", "Entered in error", 2},
+	{"kitchen_error", "Kitchen error", 3},
+	{"quality_issue", "Quality issue", 4},
+	{"long_wait", "Long wait", 5},
+	{"walkout", "Customer walked out", 6},
+	{"staff_meal", "Staff meal", 7},
+}
+
 // Venue layout
 var SeedAreas = []string{"Main Hall", "Patio", "Bar"}
 
@@ -187,6 +208,19 @@ var SeedModifiers = []SeedModifier{
 	{"+เผ็ดมาก", 0},
 }
 
+// กลุ่มตัวเลือก: สั่งได้ Min ถึง Max ตัว (Max 0 = ไม่จำกัด) เฉพาะหมวดที่ระบุ
+type SeedModifierGroup struct {
+	Name       string
+	Min, Max   int
+	Modifiers  []string
+	Categories []string
+}
+
+var SeedModifierGroups = []SeedModifierGroup{
+	{"ความเผ็ด", 0, 1, []string{"-ไม่เผ็ด", "+เผ็ดมาก"}, []string{"จานเดียว", "เส้น", "กับข้าว"}},
+	{"เพิ่มเติม", 0, 3, []string{"+ไข่ดาว", "+ไข่เจียว", "+เพิ่มเนื้อ/หมู", "+เพิ่มข้าว"}, []string{"จานเดียว", "เส้น"}},
+}
+
 // ตัวอย่างออเดอร์/จ่ายเงินสำหรับ development
 type SeedSampleOrder struct {
 	TableName string
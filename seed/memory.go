@@ -0,0 +1,191 @@
+package seed
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
+	"github.com/pubestpubest/pos-backend/database/memory"
+	"github.com/pubestpubest/pos-backend/models"
+)
+
+// Memory loads the data Runner seeds into an empty memory store. The hashes in
+// data.go are placeholders that are normally replaced with `user reset-password`,
+// which a store that only lives as long as the process cannot be, so every seeded
+// user gets passwordHash instead.
+func Memory(ctx context.Context, store *memory.Store, env string, passwordHash string, calendar businessday.Calendar) error {
+	err := store.Write(ctx, func(t *memory.Tables) error {
+		roleIDs := make(map[string]int, len(SeedRoles))
+		for _, name := range SeedRoles {
+			t.LastRoleID++
+			t.Roles[t.LastRoleID] = models.Role{ID: t.LastRoleID, Name: name}
+			roleIDs[name] = t.LastRoleID
+		}
+
+		permissionIDs := make(map[string]int, len(SeedPermissions))
+		for _, sp := range SeedPermissions {
+			t.LastPermissionID++
+			t.Permissions[t.LastPermissionID] = models.Permission{ID: t.LastPermissionID, Code: sp.Code, Description: ptr(sp.Description)}
+			permissionIDs[sp.Code] = t.LastPermissionID
+		}
+
+		for roleName, codes := range SeedRolePermissions {
+			for _, code := range codes {
+				key := memory.RolePermissionKey{RoleID: roleIDs[roleName], PermissionID: permissionIDs[code]}
+				t.RolePermissions[key] = models.RolePermission{RoleID: key.RoleID, PermissionID: key.PermissionID}
+			}
+		}
+
+		now := time.Now()
+		addUser := func(user models.User, roleName string) {
+			user.ID = uuid.New()
+			user.PasswordHash = passwordHash
+			user.CreatedAt, user.UpdatedAt = now, now
+			t.Users[user.ID] = user
+			t.UserRoles[memory.UserRoleKey{UserID: user.ID, RoleID: roleIDs[roleName]}] = models.UserRole{UserID: user.ID, RoleID: roleIDs[roleName]}
+		}
+		addUser(models.User{
+			Username: SeedAdminUser.Username,
+			FullName: ptr(SeedAdminUser.FullName),
+			Email:    ptr(SeedAdminUser.Email),
+			Status:   ptr(SeedAdminUser.Status),
+		}, "owner")
+		for _, su := range SeedUsers {
+			addUser(models.User{
+				Username: su.Username,
+				FullName: ptr(su.FullName),
+				Email:    ptr(su.Email),
+				Phone:    ptr(su.Phone),
+				Status:   ptr(su.Status),
+			}, su.RoleName)
+		}
+
+		for _, vr := range SeedVoidReasons {
+			id := uuid.New()
+			t.VoidReasons[id] = models.VoidReason{
+				ID:           id,
+				Code:         vr.Code,
+				Label:        ptr(vr.Label),
+				Active:       ptrBool(true),
+				DisplayOrder: ptrInt(vr.DisplayOrder),
+			}
+		}
+
+		if env == "development" {
+			loadMemorySamples(t, now, calendar.Date(now))
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "[seed.Memory]: Error seeding memory store")
+	}
+	return nil
+}
+
+func loadMemorySamples(t *memory.Tables, now time.Time, businessDate time.Time) {
+	areaIDs := make(map[string]uuid.UUID, len(SeedAreas))
+	for _, name := range SeedAreas {
+		id := uuid.New()
+		t.Areas[id] = models.Area{ID: id, Name: ptr(name)}
+		areaIDs[name] = id
+	}
+
+	tableIDs := make(map[string]uuid.UUID, len(SeedTables))
+	for _, st := range SeedTables {
+		id := uuid.New()
+		t.DiningTables[id] = models.DiningTable{
+			ID:     id,
+			AreaID: ptr(areaIDs[st.AreaName]),
+			Name:   ptr(st.Name),
+			Seats:  ptrInt(st.Seats),
+			Status: ptr("free"),
+			QRSlug: ptr(st.Slug),
+		}
+		tableIDs[st.Name] = id
+	}
+
+	categoryIDs := make(map[string]uuid.UUID, len(SeedCategories))
+	for _, c := range SeedCategories {
+		id := uuid.New()
+		t.Categories[id] = models.Category{ID: id, Name: ptr(c.Name), DisplayOrder: ptrInt(c.DisplayOrder)}
+		categoryIDs[c.Name] = id
+	}
+
+	menuItems := make(map[string]models.MenuItem, len(SeedMenuItems))
+	for _, it := range SeedMenuItems {
+		item := models.MenuItem{
+			ID:         uuid.New(),
+			CategoryID: ptr(categoryIDs[it.CategoryName]),
+			Name:       ptr(it.Name),
+			SKU:        ptr(it.SKU),
+			PriceBaht:  ptrI64(int64(it.PriceBaht)),
+			Active:     ptrBool(true),
+		}
+		t.MenuItems[item.ID] = item
+		menuItems[it.SKU] = item
+	}
+
+	modifierIDs := make(map[string]uuid.UUID, len(SeedModifiers))
+	for _, m := range SeedModifiers {
+		id := uuid.New()
+		t.Modifiers[id] = models.Modifier{ID: id, Name: ptr(m.Name), PriceDeltaBaht: ptrI64(int64(m.DeltaBaht))}
+		modifierIDs[m.Name] = id
+	}
+
+	for i, g := range SeedModifierGroups {
+		group := models.ModifierGroup{ID: uuid.New(), Name: ptr(g.Name), MinSelections: g.Min, SortOrder: i}
+		if g.Max > 0 {
+			group.MaxSelections = ptrInt(g.Max)
+		}
+		t.ModifierGroups[group.ID] = group
+		for j, name := range g.Modifiers {
+			key := memory.ModifierGroupOptionKey{ModifierGroupID: group.ID, ModifierID: modifierIDs[name]}
+			t.ModifierGroupOptions[key] = models.ModifierGroupOption{ModifierGroupID: group.ID, ModifierID: modifierIDs[name], SortOrder: j}
+		}
+		for _, name := range g.Categories {
+			key := memory.CategoryModifierGroupKey{CategoryID: categoryIDs[name], ModifierGroupID: group.ID}
+			t.CategoryModifierGroups[key] = models.CategoryModifierGroup{CategoryID: categoryIDs[name], ModifierGroupID: group.ID}
+		}
+	}
+
+	// The same paid sample order as seedSampleOrders
+	item := menuItems[SeedDevSampleOrder.ItemSKU]
+	lineTotal := int64(SeedDevSampleOrder.Quantity) * *item.PriceBaht
+	order := models.Order{
+		ID:           uuid.New(),
+		TableID:      ptr(tableIDs[SeedDevSampleOrder.TableName]),
+		Source:       ptr("staff"),
+		Status:       ptr("open"),
+		SubtotalBaht: ptrI64(lineTotal),
+		DiscountBaht: ptrI64(0),
+		TotalBaht:    ptrI64(lineTotal),
+		CreatedAt:    now,
+		BusinessDate: &businessDate,
+	}
+	t.Orders[order.ID] = order
+
+	orderItem := models.OrderItem{
+		ID:            uuid.New(),
+		OrderID:       order.ID,
+		MenuItemID:    item.ID,
+		Quantity:      SeedDevSampleOrder.Quantity,
+		UnitPriceBaht: *item.PriceBaht,
+		LineTotalBaht: lineTotal,
+	}
+	t.OrderItems[orderItem.ID] = orderItem
+	t.Inserted(orderItem.ID)
+
+	payment := models.Payment{
+		ID:           uuid.New(),
+		OrderID:      order.ID,
+		Method:       ptr("cash"),
+		AmountBaht:   lineTotal,
+		Currency:     ptr("THB"),
+		Status:       ptr("succeeded"),
+		CreatedAt:    now,
+		BusinessDate: &businessDate,
+	}
+	t.Payments[payment.ID] = payment
+}
@@ -3,11 +3,12 @@ package seed
 import (
 	"time"
 
+	"github.com/pubestpubest/pos-backend/businessday"
 	"github.com/pubestpubest/pos-backend/models"
 	"gorm.io/gorm"
 )
 
-func seedSampleOrders(tx *gorm.DB) error {
+func seedSampleOrders(tx *gorm.DB, calendar businessday.Calendar) error {
 	var t models.DiningTable
 	if err := tx.Where("name = ?", SeedDevSampleOrder.TableName).First(&t).Error; err != nil {
 		return err
@@ -22,6 +23,7 @@ func seedSampleOrders(tx *gorm.DB) error {
 		DiscountBaht: ptrI64(0),
 		TotalBaht:    ptrI64(12000),
 		CreatedAt:    now,
+		BusinessDate: ptr(calendar.Date(now)),
 	}
 	// idempotent by unique (table_id, created_at) is awkward; just skip if one open exists
 	var exists int64
@@ -66,6 +68,6 @@ func seedSampleOrders(tx *gorm.DB) error {
 	}
 
 	// payment in baht
-	p := models.Payment{OrderID: o.ID, Method: ptr("cash"), AmountBaht: *o.TotalBaht, Status: ptr("succeeded"), CreatedAt: now}
+	p := models.Payment{OrderID: o.ID, Method: ptr("cash"), AmountBaht: *o.TotalBaht, Status: ptr("succeeded"), CreatedAt: now, BusinessDate: o.BusinessDate}
 	return tx.Create(&p).Error
 }
@@ -78,6 +78,48 @@ func seedMenuItems(tx *gorm.DB) error {
 	return nil
 }
 
+func seedModifierGroups(tx *gorm.DB) error {
+	for i, g := range SeedModifierGroups {
+		rec := models.ModifierGroup{Name: ptr(g.Name), MinSelections: g.Min, SortOrder: i}
+		if g.Max > 0 {
+			rec.MaxSelections = ptrInt(g.Max)
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoNothing: true,
+		}).Create(&rec).Error; err != nil {
+			return err
+		}
+		// The group may have been seeded before, under another id
+		var group models.ModifierGroup
+		if err := tx.Where("name = ?", g.Name).First(&group).Error; err != nil {
+			return err
+		}
+
+		for j, name := range g.Modifiers {
+			var modifier models.Modifier
+			if err := tx.Where("name = ?", name).First(&modifier).Error; err != nil {
+				return err
+			}
+			option := models.ModifierGroupOption{ModifierGroupID: group.ID, ModifierID: modifier.ID, SortOrder: j}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&option).Error; err != nil {
+				return err
+			}
+		}
+		for _, name := range g.Categories {
+			var cat models.Category
+			if err := tx.Where("name = ?", name).First(&cat).Error; err != nil {
+				return err
+			}
+			link := models.CategoryModifierGroup{CategoryID: cat.ID, ModifierGroupID: group.ID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func seedModifiers(tx *gorm.DB) error {
 	for _, m := range SeedModifiers {
 		rec := models.Modifier{Name: &m.Name, PriceDeltaBaht: ptrI64(int64(m.DeltaBaht))}
@@ -4,12 +4,15 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/businessday"
 	"gorm.io/gorm"
 )
 
 type Runner struct {
 	DB  *gorm.DB
 	Env string // "prod" | "dev"
+	// Calendar dates the sample orders
+	Calendar businessday.Calendar
 }
 
 func (r *Runner) Run(ctx context.Context) error {
@@ -42,6 +45,10 @@ func (r *Runner) Run(ctx context.Context) error {
 			err = errors.Wrap(err, "[seed.Run]: Error seeding user roles")
 			return err
 		}
+		if err := seedVoidReasons(tx); err != nil {
+			err = errors.Wrap(err, "[seed.Run]: Error seeding void reasons")
+			return err
+		}
 
 		if r.Env == "development" {
 			if err := seedAreas(tx); err != nil {
@@ -64,7 +71,11 @@ func (r *Runner) Run(ctx context.Context) error {
 				err = errors.Wrap(err, "[seed.Run]: Error seeding modifiers")
 				return err
 			}
-			if err := seedSampleOrders(tx); err != nil {
+			if err := seedModifierGroups(tx); err != nil {
+				err = errors.Wrap(err, "[seed.Run]: Error seeding modifier groups")
+				return err
+			}
+			if err := seedSampleOrders(tx, r.Calendar); err != nil {
 				err = errors.Wrap(err, "[seed.Run]: Error seeding sample orders")
 				return err
 			}
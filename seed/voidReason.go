@@ -0,0 +1,27 @@
+package seed
+
+import (
+	"fmt"
+
+	"github.com/pubestpubest/pos-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func seedVoidReasons(tx *gorm.DB) error {
+	for _, vr := range SeedVoidReasons {
+		r := models.VoidReason{
+			Code:         vr.Code,
+			Label:        ptr(vr.Label),
+			Active:       ptrBool(true),
+			DisplayOrder: ptrInt(vr.DisplayOrder),
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoNothing: true,
+		}).Create(&r).Error; err != nil {
+			return fmt.Errorf("seedVoidReasons: %w", err)
+		}
+	}
+	return nil
+}
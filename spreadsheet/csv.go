@@ -0,0 +1,73 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// bom marks the file as UTF-8. Excel reads a CSV file without it in the
+// system code page, which garbles Thai text.
+const bom = "\uFEFF"
+
+type csvWriter struct {
+	w io.Writer
+	// csv is created by the first row
+	csv    *csv.Writer
+	record []string
+}
+
+func newCSV(w io.Writer) *csvWriter {
+	return &csvWriter{w: w}
+}
+
+func (w *csvWriter) Row(cells ...any) error {
+	if w.csv == nil {
+		if _, err := io.WriteString(w.w, bom); err != nil {
+			return errors.Wrap(err, "[spreadsheet.CSV]: Error writing")
+		}
+		w.csv = csv.NewWriter(w.w)
+	}
+
+	w.record = w.record[:0]
+	for _, cell := range cells {
+		w.record = append(w.record, csvCell(cell))
+	}
+	if err := w.csv.Write(w.record); err != nil {
+		return errors.Wrap(err, "[spreadsheet.CSV]: Error writing")
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return errors.Wrap(w.csv.Error(), "[spreadsheet.CSV]: Error writing")
+}
+
+func csvCell(cell any) string {
+	switch v := value(cell).(type) {
+	case nil:
+		return ""
+	case string:
+		// Spreadsheets run text starting with these as a formula; a leading
+		// quote keeps staff-typed notes from doing so
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value(cell))
+}
@@ -0,0 +1,78 @@
+// Package spreadsheet writes tables as CSV or XLSX files one row at a time, so
+// an export of any size holds no more than a row in memory. Cells are strings,
+// integers or floats, or pointers to them; nil is an empty cell.
+package spreadsheet
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Formats a table can be written in
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// Writer writes the rows of one table. Nothing reaches the underlying writer
+// before the first row, so a caller that fails before writing any can still
+// answer with an error instead.
+type Writer interface {
+	// Row writes one row
+	Row(cells ...any) error
+	// Close finishes the file. It does not close the underlying writer.
+	Close() error
+}
+
+// New returns a writer of format. sheet names the worksheet of an XLSX file.
+func New(format string, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSV(w), nil
+	case XLSX:
+		return newXLSX(w, sheet), nil
+	}
+	return nil, errors.Errorf("[spreadsheet.New]: Unknown format %q", format)
+}
+
+// ContentType is the media type of files of format
+func ContentType(format string) string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// value dereferences a pointer cell, turning a nil pointer into an empty cell
+func value(cell any) any {
+	switch v := cell.(type) {
+	case *string:
+		if v != nil {
+			return *v
+		}
+		return nil
+	case *int:
+		if v != nil {
+			return *v
+		}
+		return nil
+	case *int64:
+		if v != nil {
+			return *v
+		}
+		return nil
+	case *float64:
+		if v != nil {
+			return *v
+		}
+		return nil
+	}
+	return cell
+}
+
+// MediaTypes are the media types of every format, for documenting downloads
+var MediaTypes = []string{"text/csv", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}
@@ -0,0 +1,151 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The parts of a workbook besides its one worksheet. Strings are written
+// inline in the worksheet rather than into a shared string table, which would
+// have to be held in memory until the end.
+const (
+	xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	contentTypesXML = xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	rootRelsXML = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookRelsXML = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	workbookXML = xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	sheetStart = xmlHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetEnd   = `</sheetData></worksheet>`
+)
+
+// maxSheetName is the longest worksheet name Excel opens
+const maxSheetName = 31
+
+type xlsxWriter struct {
+	w     io.Writer
+	sheet string
+	zip   *zip.Writer
+	// buf buffers the worksheet part, which is created by the first row
+	buf *bufio.Writer
+	row int
+}
+
+func newXLSX(w io.Writer, sheet string) *xlsxWriter {
+	if len([]rune(sheet)) > maxSheetName {
+		sheet = string([]rune(sheet)[:maxSheetName])
+	}
+	return &xlsxWriter{w: w, sheet: sheet}
+}
+
+func (w *xlsxWriter) Row(cells ...any) error {
+	if w.zip == nil {
+		if err := w.start(); err != nil {
+			return errors.Wrap(err, "[spreadsheet.XLSX]: Error writing")
+		}
+	}
+
+	w.row++
+	fmt.Fprintf(w.buf, `<row r="%d">`, w.row)
+	for i, cell := range cells {
+		ref := column(i) + strconv.Itoa(w.row)
+		switch v := value(cell).(type) {
+		case nil:
+		case int:
+			fmt.Fprintf(w.buf, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case int64:
+			fmt.Fprintf(w.buf, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(w.buf, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			text, ok := v.(string)
+			if !ok {
+				text = fmt.Sprint(v)
+			}
+			fmt.Fprintf(w.buf, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			// EscapeText also replaces the control characters XML cannot hold
+			if err := xml.EscapeText(w.buf, []byte(text)); err != nil {
+				return errors.Wrap(err, "[spreadsheet.XLSX]: Error writing")
+			}
+			w.buf.WriteString(`</t></is></c>`)
+		}
+	}
+	if _, err := w.buf.WriteString(`</row>`); err != nil {
+		return errors.Wrap(err, "[spreadsheet.XLSX]: Error writing")
+	}
+	return nil
+}
+
+// start writes every part but the worksheet and opens the worksheet
+func (w *xlsxWriter) start() error {
+	w.zip = zip.NewWriter(w.w)
+
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(w.sheet)); err != nil {
+		return err
+	}
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, name.String())},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, part := range parts {
+		f, err := w.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := w.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	w.buf = bufio.NewWriter(sheet)
+	_, err = w.buf.WriteString(sheetStart)
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	if w.zip == nil {
+		return nil
+	}
+	w.buf.WriteString(sheetEnd)
+	if err := w.buf.Flush(); err != nil {
+		return errors.Wrap(err, "[spreadsheet.XLSX]: Error writing")
+	}
+	return errors.Wrap(w.zip.Close(), "[spreadsheet.XLSX]: Error writing")
+}
+
+// column names the column at index i: A to Z, then AA, AB and so on
+func column(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	spanKey       = "tracing:span"
+	parentCtxKey  = "tracing:parent_ctx"
+	attrRowsCount = attribute.Key("db.rows_affected")
+)
+
+// InstrumentDB opens a span for every statement run through db. Preloads run
+// inside the span of the query that loads them, so a slow association chain
+// shows up as the children of one query. Statements are recorded with their
+// placeholders, never with bound values.
+func InstrumentDB(db *gorm.DB) error {
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:before_create").Register, callbacks.Create().After("gorm:after_create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:after_query").Register},
+		{"update", callbacks.Update().Before("gorm:before_update").Register, callbacks.Update().After("gorm:after_update").Register},
+		{"delete", callbacks.Delete().Before("gorm:before_delete").Register, callbacks.Delete().After("gorm:after_delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	system := db.Dialector.Name()
+	for _, p := range processors {
+		if err := p.before("tracing:before_"+p.operation, startStatementSpan(p.operation, system)); err != nil {
+			return errors.Wrapf(err, "[tracing.InstrumentDB]: Error registering %s callback", p.operation)
+		}
+		if err := p.after("tracing:after_"+p.operation, endStatementSpan); err != nil {
+			return errors.Wrapf(err, "[tracing.InstrumentDB]: Error registering %s callback", p.operation)
+		}
+	}
+	return nil
+}
+
+func startStatementSpan(operation string, system string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			// Statements outside a traced request, such as migrations, are not recorded
+			return
+		}
+
+		name := "gorm." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		ctx, span := Start(ctx, name,
+			semconv.DBSystemKey.String(system),
+			semconv.DBOperationName(operation),
+			semconv.DBCollectionName(db.Statement.Table),
+		)
+		db.InstanceSet(spanKey, span)
+		db.InstanceSet(parentCtxKey, db.Statement.Context)
+		db.Statement.Context = ctx
+	}
+}
+
+func endStatementSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	if parent, ok := db.InstanceGet(parentCtxKey); ok {
+		db.Statement.Context = parent.(context.Context)
+	}
+
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attrRowsCount.Int64(db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+	span.End()
+}
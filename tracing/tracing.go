@@ -0,0 +1,120 @@
+// Package tracing exports OpenTelemetry spans for HTTP requests, usecase calls
+// and database statements to the exporter chosen in the configuration. With
+// the none exporter every span is a no-op.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/config"
+	"github.com/pubestpubest/pos-backend/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/pubestpubest/pos-backend"
+
+// Span attributes naming what a usecase call works on
+const (
+	AttrOrderID   = attribute.Key("pos.order.id")
+	AttrTableID   = attribute.Key("pos.table.id")
+	AttrUserID    = attribute.Key("pos.user.id")
+	AttrPaymentID = attribute.Key("pos.payment.id")
+)
+
+// Setup installs the tracer provider and W3C trace context propagation
+// described by cfg. The returned function flushes buffered spans and must be
+// called before the process exits.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	switch cfg.Exporter {
+	case config.TracingExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case config.TracingExporterStdout:
+		stdout, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, errors.Wrap(err, "[tracing.Setup]: Error creating stdout exporter")
+		}
+		exporter = stdout
+	case config.TracingExporterFile:
+		var err error
+		file, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, errors.Wrap(err, "[tracing.Setup]: Error opening trace file")
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, errors.Wrap(err, "[tracing.Setup]: Error creating file exporter")
+		}
+	case config.TracingExporterOTLP:
+		otlp, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+		if err != nil {
+			return nil, errors.Wrap(err, "[tracing.Setup]: Error creating OTLP exporter")
+		}
+		exporter = otlp
+	default:
+		return nil, errors.Errorf("[tracing.Setup]: Unknown exporter %q", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, errors.Wrap(err, "[tracing.Setup]: Error describing service")
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return errors.Wrap(err, "[tracing.Shutdown]: Error flushing spans")
+		}
+		return nil
+	}, nil
+}
+
+// Start begins a span named after the usecase or repository method, such as
+// "OrderUsecase.CreateOrder". The acting user is added from ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if actor := domain.ActorFromContext(ctx); actor.UserID != nil {
+		attrs = append(attrs, UserID(*actor.UserID))
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func OrderID(id uuid.UUID) attribute.KeyValue {
+	return AttrOrderID.String(id.String())
+}
+
+func TableID(id uuid.UUID) attribute.KeyValue {
+	return AttrTableID.String(id.String())
+}
+
+func UserID(id uuid.UUID) attribute.KeyValue {
+	return AttrUserID.String(id.String())
+}
+
+func PaymentID(id uuid.UUID) attribute.KeyValue {
+	return AttrPaymentID.String(id.String())
+}
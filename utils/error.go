@@ -1,8 +1,104 @@
 package utils
 
-import "strings"
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
 
-func StandardError(err error) string {
-	errorMessages := strings.Split(err.Error(), "]: ")
-	return errorMessages[len(errorMessages)-1]
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"github.com/pubestpubest/pos-backend/domain"
+	"github.com/pubestpubest/pos-backend/logging"
+	"github.com/pubestpubest/pos-backend/response"
+)
+
+var errorStatus = map[domain.ErrorKind]int{
+	domain.ErrorKindNotFound:           http.StatusNotFound,
+	domain.ErrorKindConflict:           http.StatusConflict,
+	domain.ErrorKindValidation:         http.StatusBadRequest,
+	domain.ErrorKindUnauthorized:       http.StatusUnauthorized,
+	domain.ErrorKindForbidden:          http.StatusForbidden,
+	domain.ErrorKindPreconditionFailed: http.StatusUnprocessableEntity,
+	domain.ErrorKindTooManyRequests:    http.StatusTooManyRequests,
+	domain.ErrorKindTimeout:            http.StatusServiceUnavailable,
+	domain.ErrorKindInternal:           http.StatusInternalServerError,
+}
+
+func init() {
+	// Report validation failures by JSON/form field name instead of Go field name
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name := strings.Split(field.Tag.Get(tag), ",")[0]
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	}
+}
+
+// statusClientClosedRequest is logged when the client went away before the
+// response; nobody receives it
+const statusClientClosedRequest = 499
+
+// RenderError logs err with the request's fields and aborts the request with the status and body for its
+// kind. Errors without a domain.Error in their chain are reported as internal
+// and their message is not shown to the client.
+func RenderError(c *gin.Context, err error) {
+	logger := logging.FromContext(c.Request.Context())
+
+	var domainErr *domain.Error
+	switch {
+	case errors.As(err, &domainErr):
+	case errors.Is(err, context.Canceled) && c.Request.Context().Err() != nil:
+		logger.Warn("[RenderError]: Client closed request: ", err)
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		domainErr = domain.NewError(domain.ErrorKindTimeout, "Request timed out")
+	default:
+		domainErr = domain.NewError(domain.ErrorKindInternal, "Internal server error")
+	}
+
+	status := errorStatus[domainErr.Kind]
+	if status >= http.StatusInternalServerError {
+		logger.Error(err)
+	} else {
+		logger.Warn(err)
+	}
+
+	c.AbortWithStatusJSON(status, response.ErrorResponse{
+		Error: response.ErrorBody{
+			Code:    string(domainErr.Kind),
+			Message: domainErr.Message,
+			Fields:  domainErr.Fields,
+		},
+	})
+}
+
+// BindingError turns a ShouldBind failure into a validation error listing the
+// rule each field broke
+func BindingError(err error, message string) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return errors.Wrap(domain.ValidationError(message, nil), err.Error())
+	}
+
+	fields := make(map[string]string, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule += "=" + fieldErr.Param()
+		}
+		fields[fieldErr.Field()] = rule
+	}
+	return domain.ValidationError(message, fields)
 }